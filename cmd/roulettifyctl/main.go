@@ -0,0 +1,333 @@
+// Command roulettifyctl manages a running Roulettify server through its
+// admin API: list rooms and players, tail room events, reset rooms, flip
+// feature flags, drain the server for deploys and re-encrypt provider tokens.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+const usage = `Usage: roulettifyctl [flags] <command> [args]
+
+Commands:
+  rooms                 list rooms
+  players <room>        list the players in a room
+  tail <room>           follow a room's event log
+  reset <room>          abandon a room's game and return everyone to the lobby
+  flags                 list feature flags
+  flag <name> on|off    switch a feature flag
+  drain [-wait]         stop new rooms and games; -wait blocks until running games finish
+  reencrypt             re-encrypt kept provider tokens with the primary TOKEN_VAULT_KEYS key
+
+Flags:
+`
+
+// client talks to the admin API
+type client struct {
+	server string
+	token  string
+	user   string
+	http   *http.Client
+}
+
+func main() {
+	server := flag.String("server", envOr("ROULETTIFY_URL", "http://localhost:8080"), "server base URL (ROULETTIFY_URL)")
+	token := flag.String("token", os.Getenv("ADMIN_TOKEN"), "admin token (ADMIN_TOKEN)")
+	user := flag.String("user", envOr("USER", "roulettifyctl"), "name recorded in the audit log")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *token == "" {
+		fail(fmt.Errorf("an admin token is required, set -token or ADMIN_TOKEN"))
+	}
+
+	c := &client{
+		server: strings.TrimRight(*server, "/"),
+		token:  *token,
+		user:   *user,
+		http:   &http.Client{Timeout: 15 * time.Second},
+	}
+	if err := c.run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		fail(err)
+	}
+}
+
+func (c *client) run(command string, args []string) error {
+	switch command {
+	case "rooms":
+		return c.rooms()
+	case "players":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: players <room>")
+		}
+		return c.players(args[0])
+	case "tail":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: tail <room>")
+		}
+		return c.tail(args[0])
+	case "reset":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: reset <room>")
+		}
+		return c.reset(args[0])
+	case "flags":
+		return c.flags()
+	case "flag":
+		if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+			return fmt.Errorf("usage: flag <name> on|off")
+		}
+		return c.setFlag(args[0], args[1] == "on")
+	case "drain":
+		fs := flag.NewFlagSet("drain", flag.ExitOnError)
+		wait := fs.Bool("wait", false, "block until running games finish")
+		fs.Parse(args)
+		return c.drain(*wait)
+	case "reencrypt":
+		return c.reencrypt()
+	default:
+		return fmt.Errorf("unknown command %q, run roulettifyctl -h for help", command)
+	}
+}
+
+func (c *client) rooms() error {
+	var resp struct {
+		Rooms []game.AdminRoomInfo `json:"rooms"`
+	}
+	if err := c.do(http.MethodGet, "/admin/rooms", nil, &resp); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATE\tROUND\tPLAYERS\tQUEUED\tLEADER")
+	for _, room := range resp.Rooms {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%d/%d\t%d\t%s\n", room.ID, room.Name, room.State,
+			room.CurrentRound, room.TotalRounds, room.PlayerCount, room.MaxPlayers, room.Queued, room.LeaderName)
+	}
+	return w.Flush()
+}
+
+func (c *client) players(roomID string) error {
+	var resp struct {
+		Rooms []game.AdminRoomInfo `json:"rooms"`
+	}
+	if err := c.do(http.MethodGet, "/admin/rooms", nil, &resp); err != nil {
+		return err
+	}
+
+	for _, room := range resp.Rooms {
+		if room.ID != roomID {
+			continue
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCORE\tREADY\tLEADER\tSPECTATOR\tCONNECTION")
+		for _, p := range room.Players {
+			connection := string(p.Quality)
+			if p.Disconnected {
+				connection = "disconnected"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%v\t%v\t%v\t%s\n", p.ID, p.Name, p.Score, p.IsReady, p.IsLeader, p.IsSpectator, connection)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("no room %q", roomID)
+}
+
+// tail polls the room's event log until interrupted
+func (c *client) tail(roomID string) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	var since int64
+	for {
+		var resp struct {
+			Events []game.RoomEvent `json:"events"`
+		}
+		path := fmt.Sprintf("/rooms/%s/events?since=%d", url.PathEscape(roomID), since)
+		if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+			return err
+		}
+		for _, event := range resp.Events {
+			since = event.Seq
+			line := fmt.Sprintf("%s  #%d  %s", event.At.Format(time.TimeOnly), event.Seq, event.Type)
+			if event.PlayerID != "" {
+				line += "  player=" + event.PlayerID
+			}
+			if len(event.Data) > 0 {
+				data, _ := json.Marshal(event.Data)
+				line += "  " + string(data)
+			}
+			fmt.Println(line)
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (c *client) reset(roomID string) error {
+	if err := c.do(http.MethodPost, "/admin/rooms/"+url.PathEscape(roomID)+"/reset", nil, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Room %s reset\n", roomID)
+	return nil
+}
+
+func (c *client) flags() error {
+	var resp struct {
+		Flags map[string]bool `json:"flags"`
+	}
+	if err := c.do(http.MethodGet, "/admin/flags", nil, &resp); err != nil {
+		return err
+	}
+	printFlags(resp.Flags)
+	return nil
+}
+
+func (c *client) setFlag(name string, enabled bool) error {
+	var resp struct {
+		Flags map[string]bool `json:"flags"`
+	}
+	if err := c.do(http.MethodPut, "/admin/flags/"+url.PathEscape(name), map[string]bool{"enabled": enabled}, &resp); err != nil {
+		return err
+	}
+	printFlags(resp.Flags)
+	return nil
+}
+
+func (c *client) drain(wait bool) error {
+	var resp struct {
+		Metrics map[string]interface{} `json:"metrics"`
+	}
+	if err := c.do(http.MethodPost, "/admin/drain", nil, &resp); err != nil {
+		return err
+	}
+	fmt.Printf("Draining: %v game(s) still running\n", resp.Metrics["active_games"])
+	if !wait {
+		return nil
+	}
+
+	for {
+		var health struct {
+			Metrics map[string]interface{} `json:"metrics"`
+		}
+		if err := c.do(http.MethodGet, "/health", nil, &health); err != nil {
+			return err
+		}
+		active, _ := health.Metrics["active_games"].(float64)
+		if active <= 0 {
+			fmt.Println("Drained: no games running")
+			return nil
+		}
+		fmt.Printf("Waiting on %d game(s)\n", int(active))
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (c *client) reencrypt() error {
+	var resp struct {
+		Reencrypted int `json:"reencrypted"`
+	}
+	if err := c.do(http.MethodPost, "/admin/tokens/reencrypt", nil, &resp); err != nil {
+		return err
+	}
+	fmt.Printf("Re-encrypted %d token(s) with the primary key\n", resp.Reencrypted)
+	return nil
+}
+
+// do sends an authenticated request and decodes the JSON response into out,
+// turning the API's {"error": ...} bodies into errors
+func (c *client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, c.server+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-Admin-User", c.user)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, path, apiErr.Error)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func printFlags(flags map[string]bool) {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		state := "on"
+		if !flags[name] {
+			state = "off"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", name, state)
+	}
+	w.Flush()
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "roulettifyctl:", err)
+	os.Exit(1)
+}
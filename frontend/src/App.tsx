@@ -6,7 +6,6 @@ interface Player {
   id: string
   name: string
   spotify_id: string
-  access_token?: string
 }
 
 function App() {
@@ -42,7 +41,6 @@ function App() {
               id: parsed.id,
               name: parsed.name,
               spotify_id: parsed.spotify_id,
-              access_token: parsed.access_token,
             })
             setIsAuthenticated(true)
           } catch (e) {
@@ -3,7 +3,6 @@ import { useState, useEffect, useRef } from 'react'
 interface Player {
   id: string
   name: string
-  access_token?: string
 }
 
 interface PlayerInfo {
@@ -89,7 +88,6 @@ export default function GameRoom({ roomId, player, onLeaveRoom }: GameRoomProps)
           room_id: roomId,
           player_id: player.id,
           player_name: player.name,
-          access_token: player.access_token || '',
         },
       }))
     }
@@ -46,6 +46,14 @@ export default function Lobby({ player, isAuthenticated, onJoinRoom }: LobbyProp
     window.location.href = '/auth/spotify'
   }
 
+  const handleLastFMAuth = () => {
+    window.location.href = '/auth/lastfm'
+  }
+
+  const handleDeezerAuth = () => {
+    window.location.href = '/auth/deezer'
+  }
+
   const handleJoinRoom = (roomId: string) => {
     if (!isJoining) {
       setIsJoining(true)
@@ -106,6 +114,20 @@ export default function Lobby({ player, isAuthenticated, onJoinRoom }: LobbyProp
                 <span className="text-xl tracking-wide">Continue with Spotify</span>
               </button>
 
+              <button
+                onClick={handleLastFMAuth}
+                className="w-full bg-white/5 hover:bg-white/10 border border-white/10 text-white font-semibold py-3 px-8 rounded-full transition-all"
+              >
+                Continue with Last.fm
+              </button>
+
+              <button
+                onClick={handleDeezerAuth}
+                className="w-full bg-white/5 hover:bg-white/10 border border-white/10 text-white font-semibold py-3 px-8 rounded-full transition-all"
+              >
+                Continue with Deezer
+              </button>
+
               <div className="grid grid-cols-1 gap-4 text-sm">
                 <div className="bg-yellow-500/10 border border-yellow-500/20 rounded-xl p-4 flex gap-3">
                   <span className="text-xl">⏳</span>
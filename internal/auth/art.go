@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxAlbumArtBytes bounds how much of a cover image is read
+const maxAlbumArtBytes = 2 << 20
+
+// albumArtHosts are the CDNs Spotify serves cover images from; nothing else
+// is fetched, so stored URLs can't point the server elsewhere
+var albumArtHosts = []string{".scdn.co", ".spotifycdn.com"}
+
+var artClient = &http.Client{Timeout: 5 * time.Second}
+
+// FetchAlbumArt downloads a track's cover image, returning it with its
+// content type
+func FetchAlbumArt(ctx context.Context, imageURL string) ([]byte, string, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil || parsed.Scheme != "https" || !allowedArtHost(parsed.Hostname()) {
+		return nil, "", fmt.Errorf("not a Spotify image: %q", imageURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := artClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("image request returned %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("unexpected content type %q", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAlbumArtBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxAlbumArtBytes {
+		return nil, "", fmt.Errorf("image is over %d bytes", maxAlbumArtBytes)
+	}
+	return data, contentType, nil
+}
+
+func allowedArtHost(host string) bool {
+	for _, suffix := range albumArtHosts {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// AudioQuality selects which variant of a preview the proxy serves
+type AudioQuality string
+
+const (
+	AudioQualityStandard AudioQuality = "standard"
+	AudioQualityLow      AudioQuality = "low"
+)
+
+// lowBitrate is the bitrate of the variant served to constrained clients
+const lowBitrate = "48k"
+
+// maxAudioCacheEntries bounds memory used by cached preview audio
+const maxAudioCacheEntries = 200
+
+// trackIDPattern matches Spotify track IDs and other providers' prefixed ones
+var trackIDPattern = regexp.MustCompile(`^([A-Za-z0-9]{22}|[a-z]+:[A-Za-z0-9]{1,32})$`)
+
+type audioEntry struct {
+	data      []byte
+	timestamp time.Time
+}
+
+// AudioCache proxies preview audio so every client streams from our server,
+// optionally transcoding to a low-bitrate variant for poor connections
+type AudioCache struct {
+	entries map[string]audioEntry
+	mu      sync.Mutex
+	client  *http.Client
+
+	ffmpegOnce sync.Once
+	ffmpegPath string
+}
+
+var audioCache = &AudioCache{
+	entries: make(map[string]audioEntry),
+	client:  &http.Client{Timeout: 15 * time.Second},
+}
+
+// Get returns cached audio, fetching and transcoding on a miss. resolve
+// finds the preview URL of a track that isn't cached.
+func (c *AudioCache) Get(ctx context.Context, trackID string, quality AudioQuality, resolve func(context.Context, string) (string, error)) ([]byte, error) {
+	if !trackIDPattern.MatchString(trackID) {
+		return nil, fmt.Errorf("invalid track id")
+	}
+
+	if quality == AudioQualityLow && c.ffmpeg() == "" {
+		quality = AudioQualityStandard
+	}
+
+	if data, ok := c.lookup(trackID, quality); ok {
+		return data, nil
+	}
+
+	original, ok := c.lookup(trackID, AudioQualityStandard)
+	if !ok {
+		var err error
+		original, err = c.download(ctx, trackID, resolve)
+		if err != nil {
+			return nil, err
+		}
+		c.store(trackID, AudioQualityStandard, original)
+	}
+
+	if quality == AudioQualityStandard {
+		return original, nil
+	}
+
+	transcoded, err := c.transcode(ctx, original)
+	if err != nil {
+		log.Printf("Failed to transcode preview for track %s: %v", trackID, err)
+		return original, nil
+	}
+	c.store(trackID, quality, transcoded)
+
+	return transcoded, nil
+}
+
+func (c *AudioCache) lookup(trackID string, quality AudioQuality) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[cacheKey(trackID, quality)]
+	if !exists || time.Since(entry.timestamp) > 24*time.Hour {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+func (c *AudioCache) store(trackID string, quality AudioQuality, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Evict the oldest entry once the cache is full
+	if len(c.entries) >= maxAudioCacheEntries {
+		oldestKey := ""
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if oldestKey == "" || entry.timestamp.Before(oldest) {
+				oldestKey = key
+				oldest = entry.timestamp
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+
+	c.entries[cacheKey(trackID, quality)] = audioEntry{
+		data:      data,
+		timestamp: time.Now(),
+	}
+}
+
+func (c *AudioCache) download(ctx context.Context, trackID string, resolve func(context.Context, string) (string, error)) ([]byte, error) {
+	previewURL, err := resolve(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("track %s: %w", trackID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, previewURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch preview: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (c *AudioCache) transcode(ctx context.Context, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.ffmpeg(),
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-ac", "1", "-b:a", lowBitrate,
+		"-f", "mp3", "pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(data)
+
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, stderr.String())
+	}
+
+	return out.Bytes(), nil
+}
+
+// ffmpeg returns the path of the ffmpeg binary, or "" if it isn't installed
+func (c *AudioCache) ffmpeg() string {
+	c.ffmpegOnce.Do(func() {
+		path, err := exec.LookPath("ffmpeg")
+		if err != nil {
+			log.Printf("ffmpeg not found, low-bitrate previews disabled")
+			return
+		}
+		c.ffmpegPath = path
+	})
+	return c.ffmpegPath
+}
+
+func cacheKey(trackID string, quality AudioQuality) string {
+	return trackID + ":" + string(quality)
+}
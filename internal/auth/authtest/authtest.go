@@ -0,0 +1,683 @@
+// Package authtest provides a fake Spotify for tests: the OAuth token
+// endpoint, the Web API calls the game makes and the embed page the preview
+// scraper reads, all served by an httptest server. It fakes Last.fm's web
+// auth and top tracks, and Deezer's sign-in, charts and tracks, too.
+package authtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// User is a fake Spotify account
+type User struct {
+	ID          string
+	DisplayName string
+	AvatarURL   string // profile picture, none when empty
+	TopTracks   []Track
+	SavedTracks []Track            // liked songs, most recent first
+	Recent      []Track            // recently played, most recent first
+	Playlists   map[string][]Track // by playlist ID
+	// Scrobbles are the user's Last.fm top tracks, most played first. Their
+	// Last.fm name is their ID.
+	Scrobbles []Track
+	// DeezerID is the user's numeric Deezer ID, and DeezerCharts their
+	// Deezer top tracks, whose IDs and artist IDs are numeric too
+	DeezerID     int64
+	DeezerCharts []Track
+}
+
+// Track is a fake Spotify track
+type Track struct {
+	ID         string
+	Name       string
+	ArtistID   string
+	ArtistName string
+	Markets    []string
+	Popularity int
+	Genres     []string // the artist's genres
+	Year       int      // the album's release year, 0 for none
+	// PreviewURL is served from the track's embed page; empty means the
+	// page has no preview
+	PreviewURL string
+}
+
+// Server is a fake Spotify. Codes and access tokens are derived from user
+// IDs: Code(id) exchanges for Token(id).
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	users map[string]*User
+}
+
+// NewServer starts a fake Spotify with the given users, points the auth
+// package at it and cleans both up when the test ends. Authenticators must be
+// created after this call.
+func NewServer(t testing.TB, users ...User) *Server {
+	t.Helper()
+
+	s := &Server{users: make(map[string]*User)}
+	for _, u := range users {
+		s.AddUser(u)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /api/token", s.handleToken)
+	mux.HandleFunc("GET /v1/me", s.handleMe)
+	mux.HandleFunc("GET /v1/me/top/tracks", s.handleTopTracks)
+	mux.HandleFunc("GET /v1/me/tracks", s.handleSavedTracks)
+	mux.HandleFunc("GET /v1/me/player/recently-played", s.handleRecentlyPlayed)
+	mux.HandleFunc("GET /v1/playlists/{id}/tracks", s.handlePlaylistTracks)
+	mux.HandleFunc("GET /v1/artists", s.handleArtists)
+	mux.HandleFunc("GET /v1/artists/{id}/top-tracks", s.handleArtistTopTracks)
+	mux.HandleFunc("GET /v1/search", s.handleSearch)
+	mux.HandleFunc("GET /embed/track/{id}", s.handleEmbed)
+	mux.HandleFunc("GET /lastfm/auth", s.handleLastFMAuth)
+	mux.HandleFunc("GET /lastfm/2.0/", s.handleLastFMAPI)
+	mux.HandleFunc("GET /deezer/oauth/auth.php", s.handleDeezerAuth)
+	mux.HandleFunc("GET /deezer/oauth/access_token.php", s.handleDeezerToken)
+	mux.HandleFunc("GET /deezer/api/user/me", s.handleDeezerMe)
+	mux.HandleFunc("GET /deezer/api/user/me/charts/tracks", s.handleDeezerCharts)
+	mux.HandleFunc("GET /deezer/api/track/{id}", s.handleDeezerTrack)
+	s.Server = httptest.NewServer(mux)
+
+	restore := auth.UseEndpoints(s.Endpoints())
+	t.Cleanup(func() {
+		restore()
+		s.Close()
+	})
+
+	return s
+}
+
+// Endpoints are the fake's URLs in the form the auth package expects
+func (s *Server) Endpoints() auth.Endpoints {
+	return auth.Endpoints{
+		AuthURL:  s.URL + "/authorize",
+		TokenURL: s.URL + "/api/token",
+		APIURL:   s.URL + "/v1/",
+		EmbedURL: s.URL + "/embed/track/",
+
+		LastFMAuthURL: s.URL + "/lastfm/auth",
+		LastFMAPIURL:  s.URL + "/lastfm/2.0/",
+
+		DeezerAuthURL:  s.URL + "/deezer/oauth/auth.php",
+		DeezerTokenURL: s.URL + "/deezer/oauth/access_token.php",
+		DeezerAPIURL:   s.URL + "/deezer/api/",
+	}
+}
+
+// AddUser registers or replaces a fake account
+func (s *Server) AddUser(u User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[u.ID] = &u
+}
+
+// Code is the authorization code that exchanges for the user's token
+func Code(userID string) string {
+	return "code-" + userID
+}
+
+// Token is the access token the fake accepts for the user
+func Token(userID string) string {
+	return "token-" + userID
+}
+
+// AppToken is the access token the fake issues for the app's own
+// credentials; it can search and look up artists but belongs to no user
+const AppToken = "token-app-credentials"
+
+// LastFMToken is the token Last.fm's web auth sends back for the user
+func LastFMToken(userID string) string {
+	return "lastfm-" + userID
+}
+
+// DeezerCode is the code Deezer's sign-in sends back for the user
+func DeezerCode(userID string) string {
+	return "deezer-code-" + userID
+}
+
+// DeezerToken is the Deezer access token the fake issues for the user
+func DeezerToken(userID string) string {
+	return "deezer-token-" + userID
+}
+
+// RefreshToken is the refresh token the fake issues with the user's token
+func RefreshToken(userID string) string {
+	return "refresh-" + userID
+}
+
+// handleAuthorize approves immediately, like a user who is already signed in
+// and has granted access. Pass ?user= to choose who signs in.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	redirect, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		http.Error(w, "missing redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	query := redirect.Query()
+	query.Set("code", Code(r.URL.Query().Get("user")))
+	query.Set("state", r.URL.Query().Get("state"))
+	redirect.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// handleToken exchanges a code, or renews a token with its refresh token
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	var userID string
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		writeJSON(w, map[string]interface{}{
+			"access_token": AppToken,
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+		return
+	case "authorization_code":
+		userID = strings.TrimPrefix(r.PostForm.Get("code"), "code-")
+	case "refresh_token":
+		userID = strings.TrimPrefix(r.PostForm.Get("refresh_token"), "refresh-")
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if s.user(userID) == nil {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"access_token":  Token(userID),
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": RefreshToken(userID),
+		"scope":         "user-top-read",
+	})
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	user := s.authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	images := make([]map[string]interface{}, 0, 1)
+	if user.AvatarURL != "" {
+		images = append(images, map[string]interface{}{"url": user.AvatarURL})
+	}
+	writeJSON(w, map[string]interface{}{
+		"id":           user.ID,
+		"display_name": user.DisplayName,
+		"uri":          "spotify:user:" + user.ID,
+		"images":       images,
+	})
+}
+
+func (s *Server) handleTopTracks(w http.ResponseWriter, r *http.Request) {
+	user := s.authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	items := make([]map[string]interface{}, len(user.TopTracks))
+	for i, track := range user.TopTracks {
+		items[i] = track.json()
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"href":  r.URL.String(),
+		"limit": 50,
+		"total": len(items),
+		"items": items,
+	})
+}
+
+func (s *Server) handleSavedTracks(w http.ResponseWriter, r *http.Request) {
+	user := s.authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	items := make([]map[string]interface{}, len(user.SavedTracks))
+	for i, track := range user.SavedTracks {
+		items[i] = map[string]interface{}{
+			"track":    track.json(),
+			"added_at": "2024-01-01T00:00:00Z",
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"href":  r.URL.String(),
+		"limit": 50,
+		"total": len(items),
+		"items": items,
+	})
+}
+
+func (s *Server) handleRecentlyPlayed(w http.ResponseWriter, r *http.Request) {
+	user := s.authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	items := make([]map[string]interface{}, len(user.Recent))
+	for i, track := range user.Recent {
+		items[i] = map[string]interface{}{
+			"track":     track.json(),
+			"played_at": "2024-01-01T00:00:00Z",
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"items": items})
+}
+
+func (s *Server) handlePlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	user := s.authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	playlist, ok := user.Playlists[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found")
+		return
+	}
+
+	items := make([]map[string]interface{}, len(playlist))
+	for i, track := range playlist {
+		item := track.json()
+		item["type"] = "track"
+		items[i] = map[string]interface{}{"track": item}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"href":  r.URL.String(),
+		"limit": 50,
+		"total": len(items),
+		"items": items,
+	})
+}
+
+// handleArtists looks up several artists, with the genres of the first of
+// their tracks found. Unknown artists come back as null, as on Spotify.
+func (s *Server) handleArtists(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeCatalogue(w, r) {
+		return
+	}
+
+	ids := strings.Split(r.URL.Query().Get("ids"), ",")
+	if len(ids) > 50 {
+		http.Error(w, "too many ids", http.StatusBadRequest)
+		return
+	}
+
+	artists := make([]interface{}, len(ids))
+	s.mu.Lock()
+	for i, id := range ids {
+		for _, user := range s.users {
+			for _, track := range user.TopTracks {
+				if track.ArtistID == id && artists[i] == nil {
+					genres := track.Genres
+					if genres == nil {
+						genres = []string{}
+					}
+					artists[i] = map[string]interface{}{"id": id, "name": track.ArtistName, "genres": genres}
+				}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{"artists": artists})
+}
+
+// handleArtistTopTracks returns every track by the artist across all users
+func (s *Server) handleArtistTopTracks(w http.ResponseWriter, r *http.Request) {
+	if s.authorize(w, r) == nil {
+		return
+	}
+
+	artistID := r.PathValue("id")
+	tracks := []map[string]interface{}{}
+	seen := make(map[string]bool)
+
+	s.mu.Lock()
+	for _, user := range s.users {
+		for _, track := range user.TopTracks {
+			if track.ArtistID == artistID && !seen[track.ID] {
+				seen[track.ID] = true
+				tracks = append(tracks, track.json())
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{"tracks": tracks})
+}
+
+func (s *Server) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	track, ok := s.track(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<html><body><script id=\"__NEXT_DATA__\" type=\"application/json\">")
+	if track.PreviewURL != "" {
+		fmt.Fprintf(w, `{"audioPreview":{"url":%q}}`, track.PreviewURL)
+	} else {
+		fmt.Fprint(w, `{}`)
+	}
+	fmt.Fprint(w, "</script></body></html>")
+}
+
+// authorize resolves the bearer token to a user, writing a 401 if it can't
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) *User {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	user := s.user(strings.TrimPrefix(token, "token-"))
+	if user == nil || token != Token(user.ID) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{"status": http.StatusUnauthorized, "message": "Invalid access token"},
+		})
+		return nil
+	}
+	return user
+}
+
+// authorizeCatalogue also lets the app's own token through, for calls
+// that don't need a user
+func (s *Server) authorizeCatalogue(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") == "Bearer "+AppToken {
+		return true
+	}
+	return s.authorize(w, r) != nil
+}
+
+// handleSearch finds a track by a query of the form track:"Name"
+// artist:"Artist", searching every user's tracks
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeCatalogue(w, r) {
+		return
+	}
+
+	items := []map[string]interface{}{}
+	var name, artist string
+	query := r.URL.Query().Get("q")
+	if _, err := fmt.Sscanf(query, "track:%q artist:%q", &name, &artist); err == nil {
+		s.mu.Lock()
+	search:
+		for _, user := range s.users {
+			for _, list := range user.lists() {
+				for _, track := range list {
+					if track.ID != "" && track.Name == name && track.ArtistName == artist {
+						items = append(items, track.json())
+						break search
+					}
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"tracks": map[string]interface{}{
+			"href":  r.URL.String(),
+			"limit": 1,
+			"total": len(items),
+			"items": items,
+		},
+	})
+}
+
+// handleLastFMAuth approves immediately, like handleAuthorize, sending the
+// user back to the callback with their token. Pass ?user= to choose who.
+func (s *Server) handleLastFMAuth(w http.ResponseWriter, r *http.Request) {
+	callback, err := url.Parse(r.URL.Query().Get("cb"))
+	if err != nil || callback.String() == "" || r.URL.Query().Get("api_key") == "" {
+		http.Error(w, "missing cb or api_key", http.StatusBadRequest)
+		return
+	}
+
+	query := callback.Query()
+	query.Set("token", LastFMToken(r.URL.Query().Get("user")))
+	callback.RawQuery = query.Encode()
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+// handleLastFMAPI serves auth.getSession and user.getTopTracks
+func (s *Server) handleLastFMAPI(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch query.Get("method") {
+	case "auth.getSession":
+		user := s.user(strings.TrimPrefix(query.Get("token"), "lastfm-"))
+		if user == nil || query.Get("api_sig") == "" {
+			writeJSON(w, map[string]interface{}{"error": 4, "message": "Invalid authentication token supplied"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"session": map[string]interface{}{"name": user.ID, "key": "sk-" + user.ID, "subscriber": 0},
+		})
+
+	case "user.getTopTracks":
+		user := s.user(query.Get("user"))
+		if user == nil {
+			writeJSON(w, map[string]interface{}{"error": 6, "message": "User not found"})
+			return
+		}
+		tracks := make([]map[string]interface{}, len(user.Scrobbles))
+		for i, track := range user.Scrobbles {
+			tracks[i] = map[string]interface{}{
+				"name":      track.Name,
+				"playcount": fmt.Sprint(100 - i),
+				"artist":    map[string]interface{}{"name": track.ArtistName},
+				"@attr":     map[string]interface{}{"rank": fmt.Sprint(i + 1)},
+			}
+		}
+		writeJSON(w, map[string]interface{}{
+			"toptracks": map[string]interface{}{
+				"track": tracks,
+				"@attr": map[string]interface{}{"user": user.ID, "period": query.Get("period")},
+			},
+		})
+
+	default:
+		writeJSON(w, map[string]interface{}{"error": 3, "message": "Invalid Method"})
+	}
+}
+
+// handleDeezerAuth approves immediately, like handleAuthorize, sending the
+// user back to the redirect URI with their code. Pass ?user= to choose who.
+func (s *Server) handleDeezerAuth(w http.ResponseWriter, r *http.Request) {
+	redirect, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil || redirect.String() == "" || r.URL.Query().Get("app_id") == "" {
+		http.Error(w, "missing redirect_uri or app_id", http.StatusBadRequest)
+		return
+	}
+
+	query := redirect.Query()
+	query.Set("code", DeezerCode(r.URL.Query().Get("user")))
+	redirect.RawQuery = query.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// handleDeezerToken exchanges a code. Like Deezer, a bad code gets a plain
+// text reply.
+func (s *Server) handleDeezerToken(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	user := s.user(strings.TrimPrefix(query.Get("code"), "deezer-code-"))
+	if user == nil || user.DeezerID == 0 || query.Get("app_id") == "" || query.Get("secret") == "" {
+		fmt.Fprint(w, "wrong code")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"access_token": DeezerToken(user.ID), "expires": 0})
+}
+
+func (s *Server) handleDeezerMe(w http.ResponseWriter, r *http.Request) {
+	user := s.authorizeDeezer(w, r)
+	if user == nil {
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"id":             user.DeezerID,
+		"name":           user.DisplayName,
+		"picture_medium": user.AvatarURL,
+		"type":           "user",
+	})
+}
+
+func (s *Server) handleDeezerCharts(w http.ResponseWriter, r *http.Request) {
+	user := s.authorizeDeezer(w, r)
+	if user == nil {
+		return
+	}
+	data := make([]map[string]interface{}, len(user.DeezerCharts))
+	for i, track := range user.DeezerCharts {
+		data[i] = track.deezerJSON()
+	}
+	writeJSON(w, map[string]interface{}{"data": data, "total": len(data)})
+}
+
+// handleDeezerTrack looks a track up in every user's charts; it needs no
+// token, as on Deezer
+func (s *Server) handleDeezerTrack(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		for _, track := range user.DeezerCharts {
+			if track.ID == r.PathValue("id") {
+				writeJSON(w, track.deezerJSON())
+				return
+			}
+		}
+	}
+	writeDeezerError(w, "DataException", "no data", 800)
+}
+
+// authorizeDeezer resolves the access_token parameter to a user, writing
+// Deezer's error if it can't
+func (s *Server) authorizeDeezer(w http.ResponseWriter, r *http.Request) *User {
+	token := r.URL.Query().Get("access_token")
+	user := s.user(strings.TrimPrefix(token, "deezer-token-"))
+	if user == nil || token != DeezerToken(user.ID) {
+		writeDeezerError(w, "OAuthException", "Invalid OAuth access token.", 300)
+		return nil
+	}
+	return user
+}
+
+func (s *Server) user(id string) *User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.users[id]
+}
+
+func (s *Server) track(id string) (Track, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		for _, list := range user.lists() {
+			for _, track := range list {
+				if track.ID == id {
+					return track, true
+				}
+			}
+		}
+	}
+	return Track{}, false
+}
+
+// lists are every list of the user's tracks
+func (u *User) lists() [][]Track {
+	lists := [][]Track{u.TopTracks, u.SavedTracks, u.Recent, u.Scrobbles}
+	for _, playlist := range u.Playlists {
+		lists = append(lists, playlist)
+	}
+	return lists
+}
+
+// json renders the track as a Spotify full track object
+func (t Track) json() map[string]interface{} {
+	markets := t.Markets
+	if markets == nil {
+		markets = []string{}
+	}
+
+	releaseDate := ""
+	if t.Year != 0 {
+		releaseDate = fmt.Sprintf("%d-06-01", t.Year)
+	}
+
+	return map[string]interface{}{
+		"id":   t.ID,
+		"name": t.Name,
+		"uri":  "spotify:track:" + t.ID,
+		"artists": []map[string]interface{}{
+			{"id": t.ArtistID, "name": t.ArtistName},
+		},
+		"album": map[string]interface{}{
+			"name":                   t.Name,
+			"images":                 []map[string]interface{}{{"url": "https://i.scdn.co/image/" + t.ID}},
+			"release_date":           releaseDate,
+			"release_date_precision": "day",
+		},
+		"available_markets": markets,
+		"popularity":        t.Popularity,
+	}
+}
+
+// deezerJSON renders the track as a Deezer track object
+func (t Track) deezerJSON() map[string]interface{} {
+	id, _ := strconv.ParseInt(t.ID, 10, 64)
+	artistID, _ := strconv.ParseInt(t.ArtistID, 10, 64)
+	return map[string]interface{}{
+		"id":       id,
+		"title":    t.Name,
+		"link":     "https://www.deezer.com/track/" + t.ID,
+		"duration": 200,
+		"rank":     t.Popularity,
+		"preview":  t.PreviewURL,
+		"artist":   map[string]interface{}{"id": artistID, "name": t.ArtistName},
+		"album":    map[string]interface{}{"title": t.Name, "cover_big": "https://e-cdns-images.dzcdn.net/images/cover/" + t.ID},
+		"type":     "track",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// writeDeezerError writes an error the way Deezer does, in a 200 response
+func writeDeezerError(w http.ResponseWriter, errType, message string, code int) {
+	writeJSON(w, map[string]interface{}{
+		"error": map[string]interface{}{"type": errType, "message": message, "code": code},
+	})
+}
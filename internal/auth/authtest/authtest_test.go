@@ -0,0 +1,384 @@
+package authtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"roulettify/internal/auth"
+)
+
+// TestFakeSpotifyOAuthFlow runs sign-in, profile, top tracks, preview
+// scraping and artist lookups against the fake
+func TestFakeSpotifyOAuthFlow(t *testing.T) {
+	NewServer(t, User{
+		ID:          "alice",
+		DisplayName: "Alice",
+		AvatarURL:   "https://i.scdn.co/image/alice",
+		TopTracks: []Track{
+			{ID: "authtestTrackOne000001", Name: "One", ArtistID: "artist-a", ArtistName: "Artist A",
+				PreviewURL: "https://p.scdn.co/mp3-preview/one"},
+			{ID: "authtestTrackTwo000002", Name: "Two", ArtistID: "artist-b", ArtistName: "Artist B"},
+		},
+	})
+
+	authenticator := auth.NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	authURL := authenticator.GetAuthURL("state-123") + "&user=alice"
+
+	noFollow := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noFollow.Get(authURL)
+	if err != nil {
+		t.Fatalf("Authorize request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	callback, _ := url.Parse(resp.Header.Get("Location"))
+	if callback.Query().Get("state") != "state-123" || callback.Query().Get("code") != Code("alice") {
+		t.Fatalf("Expected callback with state and code, got %s", callback)
+	}
+
+	ctx := context.Background()
+	token, err := authenticator.ExchangeCode(ctx, callback.Query().Get("code"))
+	if err != nil {
+		t.Fatalf("Code exchange failed: %v", err)
+	}
+	if token.AccessToken != Token("alice") {
+		t.Errorf("Expected token %q, got %q", Token("alice"), token.AccessToken)
+	}
+
+	client := authenticator.NewClient(ctx, token)
+	player, err := auth.FetchPlayerInfo(ctx, client)
+	if err != nil {
+		t.Fatalf("Fetching profile failed: %v", err)
+	}
+	if player.ID != "alice" || player.Name != "Alice" || player.AvatarURL != "https://i.scdn.co/image/alice" {
+		t.Errorf("Expected Alice's profile, got %+v", player)
+	}
+
+	tracks, err := auth.FetchPlayerTopTracks(ctx, client)
+	if err != nil {
+		t.Fatalf("Fetching top tracks failed: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Rank != 1 || tracks[1].ArtistIDs[0] != "artist-b" {
+		t.Fatalf("Unexpected top tracks: %+v", tracks)
+	}
+	if tracks[0].PreviewURL != "https://p.scdn.co/mp3-preview/one" || tracks[1].PreviewURL != "" {
+		t.Errorf("Expected previews scraped from the fake embed page, got %q and %q",
+			tracks[0].PreviewURL, tracks[1].PreviewURL)
+	}
+
+	artistTracks, err := auth.FetchArtistTopTracks(ctx, Token("alice"), "artist-a")
+	if err != nil || len(artistTracks) != 1 || artistTracks[0].Name != "One" {
+		t.Errorf("Expected artist A's track, got %+v (%v)", artistTracks, err)
+	}
+
+	if _, err := auth.FetchPlayerInfo(ctx, auth.ClientForToken(ctx, "bogus")); err == nil {
+		t.Error("Expected an unknown access token to be rejected")
+	}
+
+	if _, err := authenticator.ExchangeCode(ctx, Code("mallory")); err == nil {
+		t.Error("Expected a code for an unknown user to be rejected")
+	}
+
+	t.Logf("✓ Fake Spotify serves the OAuth, API and embed endpoints")
+}
+
+// TestTokenRefresh verifies a player's expired token is renewed with its
+// refresh token and the renewed token is kept
+func TestTokenRefresh(t *testing.T) {
+	NewServer(t, User{ID: "alice", DisplayName: "Alice"})
+	authenticator := auth.NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	ctx := context.Background()
+
+	authenticator.Tokens().Save("alice", &oauth2.Token{
+		AccessToken:  "expired-alice",
+		RefreshToken: RefreshToken("alice"),
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+	player, err := auth.FetchPlayerInfo(ctx, authenticator.Client(ctx, "alice"))
+	if err != nil || player.ID != "alice" {
+		t.Fatalf("Expected the expired token to be renewed, got %+v (%v)", player, err)
+	}
+
+	player.TokenSource = authenticator.TokenSource("alice")
+	if token, err := player.CurrentToken(); err != nil || token != Token("alice") {
+		t.Errorf("Expected the player's current token to be the renewed one, got %q (%v)", token, err)
+	}
+
+	if _, err := auth.FetchPlayerInfo(ctx, authenticator.Client(ctx, "bob")); err == nil {
+		t.Error("Expected a player who never signed in to have no Spotify client")
+	}
+	authenticator.Tokens().Forget("alice")
+	if _, err := player.CurrentToken(); !errors.Is(err, auth.ErrNoSpotifyToken) {
+		t.Errorf("Expected a forgotten player to have no token, got %v", err)
+	}
+
+	t.Logf("✓ Expired tokens are refreshed and kept for the player")
+}
+
+// TestLastFMSignIn signs in with Last.fm and finds the player's most
+// scrobbled tracks on Spotify
+func TestLastFMSignIn(t *testing.T) {
+	one := Track{ID: "authtestScrobbleOne001", Name: "One", ArtistID: "artist-a", ArtistName: "Artist A",
+		PreviewURL: "https://p.scdn.co/mp3-preview/one"}
+	demo := Track{Name: "Demo", ArtistName: "Nobody"} // not on Spotify
+	two := Track{ID: "authtestScrobbleTwo002", Name: "Two", ArtistID: "artist-b", ArtistName: "Artist B"}
+	NewServer(t, User{ID: "alice", Scrobbles: []Track{one, demo, two}})
+
+	spotifyAuth := auth.NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	lastFM := auth.NewLastFMAuthenticator("key", "secret", "http://localhost/auth/lastfm/callback", spotifyAuth)
+	noFollow := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noFollow.Get(lastFM.AuthURL("state-123") + "&user=alice")
+	if err != nil {
+		t.Fatalf("Auth request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	callback, _ := url.Parse(resp.Header.Get("Location"))
+	if callback.Query().Get("state") != "state-123" || callback.Query().Get("token") != LastFMToken("alice") {
+		t.Fatalf("Expected callback with state and token, got %s", callback)
+	}
+
+	ctx := context.Background()
+	player, err := lastFM.Exchange(ctx, callback.Query())
+	if err != nil || player.ID != auth.LastFMPlayerPrefix+"alice" || player.Name != "alice" {
+		t.Fatalf("Expected alice's Last.fm session, got %+v (%v)", player, err)
+	}
+	if profile, err := lastFM.FetchProfile(ctx, player.ID); err != nil || profile.Name != "alice" {
+		t.Errorf("Expected alice's profile, got %+v (%v)", profile, err)
+	}
+
+	tracks, err := lastFM.FetchTopTracks(ctx, player.ID, auth.PoolOptions{TimeRange: "long_term"})
+	if err != nil || len(tracks) != 2 {
+		t.Fatalf("Expected the 2 tracks on Spotify, got %+v (%v)", tracks, err)
+	}
+	if tracks[0].ID != one.ID || tracks[0].Rank != 1 || tracks[0].PreviewURL != one.PreviewURL {
+		t.Errorf("Expected One at rank 1 with its preview, got %+v", tracks[0])
+	}
+	if tracks[1].ID != two.ID || tracks[1].Rank != 3 {
+		t.Errorf("Expected Two to keep its Last.fm rank, got %+v", tracks[1])
+	}
+
+	if _, err := lastFM.Exchange(ctx, url.Values{"token": {"bogus"}}); err == nil {
+		t.Error("Expected an unknown token to be rejected")
+	}
+	lastFM.Forget(player.ID)
+	if _, err := lastFM.FetchTopTracks(ctx, player.ID, auth.PoolOptions{}); !errors.Is(err, auth.ErrNoLastFMSession) {
+		t.Errorf("Expected a forgotten player to need to sign in again, got %v", err)
+	}
+
+	t.Logf("✓ Last.fm players' top tracks are found on Spotify")
+}
+
+// TestDeezerSignIn signs in with Deezer, reads the player's charts and
+// resolves a preview
+func TestDeezerSignIn(t *testing.T) {
+	one := Track{ID: "3135556", Name: "One", ArtistID: "27", ArtistName: "Artist A",
+		PreviewURL: "https://cdns-preview-d.dzcdn.net/stream/one.mp3"}
+	two := Track{ID: "3135557", Name: "Two", ArtistID: "28", ArtistName: "Artist B"}
+	NewServer(t, User{ID: "alice", DisplayName: "Alice", DeezerID: 4242, DeezerCharts: []Track{one, two}})
+
+	deezer := auth.NewDeezerAuthenticator("app", "secret", "http://localhost/auth/deezer/callback")
+	noFollow := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noFollow.Get(deezer.AuthURL("state-123") + "&user=alice")
+	if err != nil {
+		t.Fatalf("Auth request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	callback, _ := url.Parse(resp.Header.Get("Location"))
+	if callback.Query().Get("state") != "state-123" || callback.Query().Get("code") != DeezerCode("alice") {
+		t.Fatalf("Expected callback with state and code, got %s", callback)
+	}
+
+	ctx := context.Background()
+	player, err := deezer.Exchange(ctx, callback.Query())
+	if err != nil || player.ID != auth.DeezerPrefix+"4242" || player.Name != "Alice" {
+		t.Fatalf("Expected alice's Deezer account, got %+v (%v)", player, err)
+	}
+	if profile, err := deezer.FetchProfile(ctx, player.ID); err != nil || profile.ID != player.ID {
+		t.Errorf("Expected alice's profile, got %+v (%v)", profile, err)
+	}
+
+	tracks, err := deezer.FetchTopTracks(ctx, player.ID, auth.PoolOptions{TimeRange: "short_term"})
+	if err != nil || len(tracks) != 2 {
+		t.Fatalf("Expected alice's 2 top tracks, got %+v (%v)", tracks, err)
+	}
+	if tracks[0].ID != "deezer:3135556" || tracks[0].Rank != 1 || !reflect.DeepEqual(tracks[0].ArtistIDs, []string{"deezer:27"}) {
+		t.Errorf("Expected One at rank 1 with prefixed IDs, got %+v", tracks[0])
+	}
+	if tracks[0].PreviewURL != one.PreviewURL || tracks[0].Duration != 200*time.Second {
+		t.Errorf("Expected One's preview and duration, got %+v", tracks[0])
+	}
+
+	if preview, err := deezer.ResolvePreview(ctx, tracks[0].ID); err != nil || preview != one.PreviewURL {
+		t.Errorf("Expected One's preview to resolve, got %q (%v)", preview, err)
+	}
+	if _, err := deezer.ResolvePreview(ctx, tracks[1].ID); !errors.Is(err, auth.ErrNoPreview) {
+		t.Errorf("Expected Two to have no preview, got %v", err)
+	}
+
+	if _, err := deezer.Exchange(ctx, url.Values{"code": {"bogus"}}); err == nil {
+		t.Error("Expected an unknown code to be rejected")
+	}
+	deezer.Forget(player.ID)
+	if _, err := deezer.FetchTopTracks(ctx, player.ID, auth.PoolOptions{}); !errors.Is(err, auth.ErrNoDeezerSession) {
+		t.Errorf("Expected a forgotten player to need to sign in again, got %v", err)
+	}
+
+	t.Logf("✓ Deezer players' top tracks and previews come from Deezer")
+}
+
+// TestProviderDispatch checks players and tracks are routed to the
+// provider their ID belongs to
+func TestProviderDispatch(t *testing.T) {
+	NewServer(t)
+
+	spotifyAuth := auth.NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	lastFM := auth.NewLastFMAuthenticator("key", "secret", "http://localhost/auth/lastfm/callback", spotifyAuth)
+	deezer := auth.NewDeezerAuthenticator("", "", "")
+	providers := auth.NewProviders(spotifyAuth, lastFM, deezer)
+
+	cases := map[string]string{
+		"alice":               "spotify",
+		"lastfm:alice":        "lastfm",
+		"deezer:4242":         "deezer",
+		"authtestTrackOne001": "spotify",
+		"unknown:alice":       "spotify",
+	}
+	for id, want := range cases {
+		if got := providers.ForPlayer(id).Name(); got != want {
+			t.Errorf("Expected %s to belong to %s, got %s", id, want, got)
+		}
+	}
+
+	var names []string
+	for _, provider := range providers.All() {
+		names = append(names, provider.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"spotify", "lastfm", "deezer"}) {
+		t.Errorf("Expected Spotify first, then the others in order, got %v", names)
+	}
+	if deezer.Enabled() || !lastFM.Enabled() {
+		t.Error("Expected only configured providers to be enabled")
+	}
+
+	t.Logf("✓ IDs are routed to their provider by prefix")
+}
+
+// TestFakeSpotifyTrackPool builds a pool from every source and checks each
+// track is tagged with the sources it came from
+func TestFakeSpotifyTrackPool(t *testing.T) {
+	one := Track{ID: "authtestPoolOne0000001", Name: "One", ArtistID: "artist-a", ArtistName: "Artist A"}
+	two := Track{ID: "authtestPoolTwo0000002", Name: "Two", ArtistID: "artist-b", ArtistName: "Artist B"}
+	three := Track{ID: "authtestPoolThree00003", Name: "Three", ArtistID: "artist-c", ArtistName: "Artist C"}
+	NewServer(t, User{
+		ID:          "alice",
+		TopTracks:   []Track{one},
+		SavedTracks: []Track{two, one},
+		Recent:      []Track{three, three},
+		Playlists:   map[string][]Track{"road-trip": {two}},
+	})
+
+	ctx := context.Background()
+	client := auth.ClientForToken(ctx, Token("alice"))
+	pool, err := auth.FetchPlayerTrackPool(ctx, client, auth.PoolOptions{
+		Sources:    auth.TrackSources,
+		PlaylistID: "road-trip",
+	})
+	if err != nil {
+		t.Fatalf("Fetching the pool failed: %v", err)
+	}
+
+	want := map[string][]auth.TrackSource{
+		one.ID:   {auth.SourceTop, auth.SourceSaved},
+		two.ID:   {auth.SourceSaved, auth.SourcePlaylist},
+		three.ID: {auth.SourceRecent},
+	}
+	if len(pool) != len(want) {
+		t.Fatalf("Expected %d pooled tracks, got %+v", len(want), pool)
+	}
+	for _, track := range pool {
+		if !reflect.DeepEqual(track.Sources, want[track.ID]) {
+			t.Errorf("Expected %s from %v, got %v", track.Name, want[track.ID], track.Sources)
+		}
+		if track.Rank != 1 {
+			t.Errorf("Expected %s to keep its best rank, got %d", track.Name, track.Rank)
+		}
+	}
+
+	// Missing optional sources are skipped rather than failing the pool
+	pool, err = auth.FetchPlayerTrackPool(ctx, client, auth.PoolOptions{
+		Sources:    []auth.TrackSource{auth.SourceTop, auth.SourcePlaylist},
+		PlaylistID: "gone",
+	})
+	if err != nil || len(pool) != 1 {
+		t.Errorf("Expected just the top track when the playlist is missing, got %+v (%v)", pool, err)
+	}
+
+	t.Logf("✓ Track pools merge sources and tag each track with its sources")
+}
+
+// TestFakeSpotifyGenres verifies pooled tracks pick up their artists' genres
+func TestFakeSpotifyGenres(t *testing.T) {
+	rap := Track{ID: "authtestGenreRap000001", Name: "Rap", ArtistID: "artist-rap", ArtistName: "Rapper", Genres: []string{"southern hip hop", "trap"}}
+	folk := Track{ID: "authtestGenreFolk00002", Name: "Folk", ArtistID: "artist-folk", ArtistName: "Folkie", Genres: []string{"indie folk"}}
+	plain := Track{ID: "authtestGenrePlain0003", Name: "Plain", ArtistID: "artist-plain", ArtistName: "Nobody"}
+	NewServer(t, User{ID: "alice", TopTracks: []Track{rap, folk, plain}})
+
+	ctx := context.Background()
+	pool, err := auth.FetchPlayerTrackPool(ctx, auth.ClientForToken(ctx, Token("alice")), auth.PoolOptions{})
+	if err != nil || len(pool) != 3 {
+		t.Fatalf("Fetching the pool failed: %+v (%v)", pool, err)
+	}
+
+	want := map[string][]string{rap.ID: rap.Genres, folk.ID: folk.Genres, plain.ID: nil}
+	for _, track := range pool {
+		if !reflect.DeepEqual(track.Genres, want[track.ID]) {
+			t.Errorf("Expected %s to have genres %v, got %v", track.Name, want[track.ID], track.Genres)
+		}
+	}
+
+	t.Logf("✓ Pooled tracks are tagged with their artists' genres")
+}
+
+// TestFakeSpotifyPlaylist parses the forms a playlist link comes in and
+// fetches its tracks in playlist order
+func TestFakeSpotifyPlaylist(t *testing.T) {
+	for input, want := range map[string]string{
+		"https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc": "37i9dQZF1DXcBWIGoYBM5M",
+		"https://open.spotify.com/intl-de/playlist/roadTrip":              "roadTrip",
+		"spotify:playlist:roadTrip":                                       "roadTrip",
+		" roadTrip ":                                                      "roadTrip",
+		"https://open.spotify.com/album/roadTrip":                         "",
+		"https://example.com/playlist/roadTrip":                           "",
+	} {
+		if got, err := auth.ParsePlaylistID(input); got != want || (want == "") != (err != nil) {
+			t.Errorf("ParsePlaylistID(%q) = %q, %v; expected %q", input, got, err, want)
+		}
+	}
+
+	one := Track{ID: "authtestListOne0000001", Name: "One", ArtistID: "artist-a", ArtistName: "Artist A"}
+	two := Track{ID: "authtestListTwo0000002", Name: "Two", ArtistID: "artist-b", ArtistName: "Artist B"}
+	NewServer(t, User{ID: "alice", Playlists: map[string][]Track{"roadTrip": {two, one}}})
+
+	ctx := context.Background()
+	tracks, err := auth.FetchPlaylist(ctx, auth.ClientForToken(ctx, Token("alice")), "roadTrip")
+	if err != nil || len(tracks) != 2 || tracks[0].ID != two.ID || tracks[1].Rank != 2 {
+		t.Fatalf("Expected both tracks in playlist order, got %+v (%v)", tracks, err)
+	}
+
+	t.Logf("✓ Playlist links parse and their tracks are fetched in order")
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeezerPrefix starts the ID of every Deezer player, track and artist, so
+// they never collide with Spotify IDs
+const DeezerPrefix = "deezer:"
+
+// deezerPerms are the permissions players are asked for. offline_access
+// makes the token last until the player revokes it, since Deezer has no
+// refresh tokens.
+const deezerPerms = "basic_access,offline_access,listening_history"
+
+// ErrNoDeezerSession is returned for a Deezer player who hasn't signed in
+// since the server started (without a token vault), or whose data was deleted
+var ErrNoDeezerSession = errors.New("no Deezer session, sign in again")
+
+// DeezerAuthenticator signs players in with Deezer and plays their top
+// tracks from Deezer's own previews
+type DeezerAuthenticator struct {
+	appID       string
+	secret      string
+	redirectURL string
+	client      *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string // access token by player ID
+	vault  *TokenVault
+}
+
+// NewDeezerAuthenticator creates an authenticator for a Deezer app. Without
+// an app ID Deezer sign-in is disabled.
+func NewDeezerAuthenticator(appID, secret, redirectURL string) *DeezerAuthenticator {
+	return &DeezerAuthenticator{
+		appID:       appID,
+		secret:      secret,
+		redirectURL: redirectURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		tokens:      make(map[string]string),
+	}
+}
+
+// Name identifies Deezer among the music providers
+func (da *DeezerAuthenticator) Name() string {
+	return "deezer"
+}
+
+// Enabled reports whether Deezer sign-in is configured
+func (da *DeezerAuthenticator) Enabled() bool {
+	return da.appID != "" && da.secret != ""
+}
+
+// AuthURL returns the Deezer page that asks the player to let us in. Deezer
+// sends them back to the redirect URL with a code, and the state.
+func (da *DeezerAuthenticator) AuthURL(state string) string {
+	query := url.Values{
+		"app_id":       {da.appID},
+		"redirect_uri": {withState(da.redirectURL, state)},
+		"perms":        {deezerPerms},
+	}
+	return currentEndpoints().DeezerAuthURL + "?" + query.Encode()
+}
+
+// Exchange swaps the code Deezer sent back to the callback for an access
+// token and returns the player it belongs to
+func (da *DeezerAuthenticator) Exchange(ctx context.Context, callback url.Values) (*Player, error) {
+	code := callback.Get("code")
+	if code == "" {
+		return nil, errors.New("failed to get Deezer token: no code")
+	}
+	query := url.Values{
+		"app_id": {da.appID},
+		"secret": {da.secret},
+		"code":   {code},
+		"output": {"json"},
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	// A bad code gets a plain text reply, which fails to decode
+	if err := da.get(ctx, currentEndpoints().DeezerTokenURL+"?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to get Deezer token: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("failed to get Deezer token: no token")
+	}
+
+	player, err := da.fetchUser(ctx, result.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	da.mu.Lock()
+	da.tokens[player.ID] = result.AccessToken
+	vault := da.vault
+	da.mu.Unlock()
+	if vault != nil {
+		if err := vault.Put(da.Name(), player.ID, []byte(result.AccessToken)); err != nil {
+			log.Printf("Failed to keep Deezer token of player %s: %v", player.ID, err)
+		}
+	}
+	return player, nil
+}
+
+// FetchProfile retrieves the signed-in player's profile
+func (da *DeezerAuthenticator) FetchProfile(ctx context.Context, playerID string) (*Player, error) {
+	token, err := da.token(playerID)
+	if err != nil {
+		return nil, err
+	}
+	return da.fetchUser(ctx, token)
+}
+
+// FetchTopTracks retrieves the signed-in player's 50 top tracks. Deezer
+// keeps a single chart per player, so the time range and other sources are
+// ignored.
+func (da *DeezerAuthenticator) FetchTopTracks(ctx context.Context, playerID string, opts PoolOptions) ([]Track, error) {
+	token, err := da.token(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data []deezerTrack `json:"data"`
+	}
+	query := url.Values{"access_token": {token}, "limit": {"50"}}
+	if err := da.get(ctx, currentEndpoints().DeezerAPIURL+"user/me/charts/tracks?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch Deezer top tracks: %w", err)
+	}
+
+	tracks := make([]Track, 0, len(result.Data))
+	for i, track := range result.Data {
+		tracks = append(tracks, track.toTrack(i+1))
+	}
+	LogPreviewURLStats(tracks)
+	return tracks, nil
+}
+
+// ResolvePreview looks the track up again for its preview URL. Deezer's
+// preview URLs are signed and expire, so the one fetched with the pool may
+// be stale by the time it's played.
+func (da *DeezerAuthenticator) ResolvePreview(ctx context.Context, trackID string) (string, error) {
+	id, ok := strings.CutPrefix(trackID, DeezerPrefix)
+	if !ok {
+		return "", fmt.Errorf("not a Deezer track: %s", trackID)
+	}
+	var track deezerTrack
+	if err := da.get(ctx, currentEndpoints().DeezerAPIURL+"track/"+url.PathEscape(id), &track); err != nil {
+		return "", fmt.Errorf("failed to fetch Deezer track: %w", err)
+	}
+	if track.Preview == "" {
+		return "", ErrNoPreview
+	}
+	return track.Preview, nil
+}
+
+// Forget drops the player's access token
+func (da *DeezerAuthenticator) Forget(playerID string) {
+	da.mu.Lock()
+	delete(da.tokens, playerID)
+	vault := da.vault
+	da.mu.Unlock()
+	if vault != nil {
+		if err := vault.Delete(da.Name(), playerID); err != nil {
+			log.Printf("Failed to delete Deezer token of player %s: %v", playerID, err)
+		}
+	}
+}
+
+// useVault keeps tokens in vault from now on, restoring the ones kept there
+func (da *DeezerAuthenticator) useVault(vault *TokenVault) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+
+	da.vault = vault
+	for playerID, token := range vault.Tokens(da.Name()) {
+		da.tokens[playerID] = string(token)
+	}
+}
+
+func (da *DeezerAuthenticator) token(playerID string) (string, error) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	token, ok := da.tokens[playerID]
+	if !ok {
+		return "", ErrNoDeezerSession
+	}
+	return token, nil
+}
+
+func (da *DeezerAuthenticator) fetchUser(ctx context.Context, token string) (*Player, error) {
+	var user struct {
+		ID      int64  `json:"id"`
+		Name    string `json:"name"`
+		Picture string `json:"picture_medium"`
+	}
+	query := url.Values{"access_token": {token}}
+	if err := da.get(ctx, currentEndpoints().DeezerAPIURL+"user/me?"+query.Encode(), &user); err != nil {
+		return nil, fmt.Errorf("failed to fetch Deezer profile: %w", err)
+	}
+	if user.ID == 0 {
+		return nil, errors.New("failed to fetch Deezer profile: no user")
+	}
+	return &Player{
+		ID:        DeezerPrefix + strconv.FormatInt(user.ID, 10),
+		Name:      user.Name,
+		AvatarURL: user.Picture,
+	}, nil
+}
+
+// get makes a Deezer request. Deezer reports API errors in a 200 response,
+// so the body is checked for one.
+func (da *DeezerAuthenticator) get(ctx context.Context, requestURL string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := da.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var apiErr struct {
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != nil {
+		return fmt.Errorf("%s: %s", apiErr.Error.Type, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, result)
+}
+
+// deezerTrack is a track as the Deezer API returns it
+type deezerTrack struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	Duration int    `json:"duration"` // seconds
+	Preview  string `json:"preview"`
+	Artist   struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"artist"`
+	Album struct {
+		Cover string `json:"cover_big"`
+	} `json:"album"`
+}
+
+func (t deezerTrack) toTrack(rank int) Track {
+	return Track{
+		ID:         DeezerPrefix + strconv.FormatInt(t.ID, 10),
+		Name:       t.Title,
+		Artists:    []string{t.Artist.Name},
+		ArtistIDs:  []string{DeezerPrefix + strconv.FormatInt(t.Artist.ID, 10)},
+		Rank:       rank,
+		URI:        t.Link,
+		ImageURL:   t.Album.Cover,
+		PreviewURL: t.Preview,
+		Duration:   time.Duration(t.Duration) * time.Second,
+		Sources:    []TrackSource{SourceTop},
+	}
+}
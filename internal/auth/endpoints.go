@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"sync"
+
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+)
+
+// Endpoints are the Spotify, Last.fm and Deezer URLs the auth package talks
+// to
+type Endpoints struct {
+	AuthURL  string // OAuth authorize page
+	TokenURL string // OAuth token exchange
+	APIURL   string // Web API base, with a trailing slash
+	EmbedURL string // embed page base scraped for preview URLs, with a trailing slash
+
+	LastFMAuthURL string // Last.fm web auth page
+	LastFMAPIURL  string // Last.fm API root
+
+	DeezerAuthURL  string // Deezer OAuth authorize page
+	DeezerTokenURL string // Deezer OAuth token exchange
+	DeezerAPIURL   string // Deezer API base, with a trailing slash
+}
+
+// DefaultEndpoints are the real services
+var DefaultEndpoints = Endpoints{
+	AuthURL:  spotifyauth.AuthURL,
+	TokenURL: spotifyauth.TokenURL,
+	APIURL:   "https://api.spotify.com/v1/",
+	EmbedURL: "https://open.spotify.com/embed/track/",
+
+	LastFMAuthURL: "https://www.last.fm/api/auth/",
+	LastFMAPIURL:  "https://ws.audioscrobbler.com/2.0/",
+
+	DeezerAuthURL:  "https://connect.deezer.com/oauth/auth.php",
+	DeezerTokenURL: "https://connect.deezer.com/oauth/access_token.php",
+	DeezerAPIURL:   "https://api.deezer.com/",
+}
+
+var (
+	endpointsMu     sync.RWMutex
+	activeEndpoints = DefaultEndpoints
+)
+
+// UseEndpoints points the package at other Spotify endpoints, e.g. a fake
+// server in tests, and returns a function restoring the previous ones.
+// Authenticators created before the call keep their OAuth endpoints.
+func UseEndpoints(e Endpoints) (restore func()) {
+	endpointsMu.Lock()
+	previous := activeEndpoints
+	activeEndpoints = e
+	endpointsMu.Unlock()
+
+	return func() {
+		endpointsMu.Lock()
+		activeEndpoints = previous
+		endpointsMu.Unlock()
+	}
+}
+
+func currentEndpoints() Endpoints {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	return activeEndpoints
+}
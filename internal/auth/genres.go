@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// artistBatchSize is the most artists Spotify returns per lookup
+const artistBatchSize = 50
+
+// enrichGenres tags each track with its artists' genres. Spotify only
+// records genres on artists, so they're looked up in batches.
+func enrichGenres(ctx context.Context, client *spotify.Client, tracks []Track) error {
+	var ids []spotify.ID
+	seen := make(map[string]bool)
+	for _, track := range tracks {
+		for _, id := range track.ArtistIDs {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, spotify.ID(id))
+			}
+		}
+	}
+
+	genres := make(map[string][]string, len(ids))
+	for batch := range slices.Chunk(ids, artistBatchSize) {
+		artists, err := client.GetArtists(ctx, batch...)
+		if err != nil {
+			return err
+		}
+		for _, artist := range artists {
+			if artist != nil {
+				genres[artist.ID.String()] = artist.Genres
+			}
+		}
+	}
+
+	for i := range tracks {
+		var trackGenres []string
+		for _, id := range tracks[i].ArtistIDs {
+			for _, genre := range genres[id] {
+				if !slices.Contains(trackGenres, genre) {
+					trackGenres = append(trackGenres, genre)
+				}
+			}
+		}
+		tracks[i].Genres = trackGenres
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInviteInvalid = errors.New("invalid invite")
+	ErrInviteExpired = errors.New("invite has expired")
+	ErrInviteUsed    = errors.New("invite has already been used")
+)
+
+// InviteSigner issues and redeems signed, single-use room invite tokens
+type InviteSigner struct {
+	secret []byte
+	used   map[string]time.Time // nonce -> expiry
+	mu     sync.Mutex
+}
+
+// NewInviteSigner creates a signer with the given secret. An empty secret
+// generates a random one, so invites only survive until the server restarts.
+func NewInviteSigner(secret string) *InviteSigner {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+
+	return &InviteSigner{
+		secret: key,
+		used:   make(map[string]time.Time),
+	}
+}
+
+// Issue creates an invite token for roomID that expires after ttl
+func (s *InviteSigner) Issue(roomID string, ttl time.Duration) (string, time.Time) {
+	nonce := make([]byte, 12)
+	rand.Read(nonce)
+
+	expiresAt := time.Now().Add(ttl)
+	payload := strings.Join([]string{
+		roomID,
+		strconv.FormatInt(expiresAt.Unix(), 10),
+		hex.EncodeToString(nonce),
+	}, "|")
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.sign(encoded), expiresAt
+}
+
+// Redeem validates a token for roomID and marks it as used
+func (s *InviteSigner) Redeem(roomID, token string) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return ErrInviteInvalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInviteInvalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || parts[0] != roomID {
+		return ErrInviteInvalid
+	}
+
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInviteInvalid
+	}
+	expiresAt := time.Unix(expiry, 0)
+	if time.Now().After(expiresAt) {
+		return ErrInviteExpired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneUsed()
+	nonce := parts[2]
+	if _, used := s.used[nonce]; used {
+		return ErrInviteUsed
+	}
+	s.used[nonce] = expiresAt
+
+	return nil
+}
+
+func (s *InviteSigner) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// pruneUsed forgets nonces whose invites have expired anyway
+func (s *InviteSigner) pruneUsed() {
+	now := time.Now()
+	for nonce, expiresAt := range s.used {
+		if now.After(expiresAt) {
+			delete(s.used, nonce)
+		}
+	}
+}
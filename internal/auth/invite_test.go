@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// TestInviteRedeem verifies invites are room-bound, single-use and expire
+func TestInviteRedeem(t *testing.T) {
+	signer := NewInviteSigner("test-secret")
+
+	token, _ := signer.Issue("Room 1", time.Minute)
+
+	if err := signer.Redeem("Room 2", token); err != ErrInviteInvalid {
+		t.Errorf("Expected invite for another room to be invalid, got %v", err)
+	}
+	if err := signer.Redeem("Room 1", token); err != nil {
+		t.Fatalf("Expected valid invite to redeem, got %v", err)
+	}
+	if err := signer.Redeem("Room 1", token); err != ErrInviteUsed {
+		t.Errorf("Expected reused invite to be rejected, got %v", err)
+	}
+
+	expired, _ := signer.Issue("Room 1", -time.Minute)
+	if err := signer.Redeem("Room 1", expired); err != ErrInviteExpired {
+		t.Errorf("Expected expired invite to be rejected, got %v", err)
+	}
+
+	other := NewInviteSigner("other-secret")
+	forged, _ := other.Issue("Room 1", time.Minute)
+	if err := signer.Redeem("Room 1", forged); err != ErrInviteInvalid {
+		t.Errorf("Expected invite signed with another secret to be invalid, got %v", err)
+	}
+
+	t.Logf("✓ Invites are signed, room-bound, single-use and expiring")
+}
@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// LastFMPlayerPrefix starts the player ID of everyone signed in with
+// Last.fm, so they never collide with Spotify IDs
+const LastFMPlayerPrefix = "lastfm:"
+
+// lastFMPeriods maps room time ranges to Last.fm's periods. Long term is
+// the player's whole scrobble history, which often goes back much further
+// than Spotify's.
+var lastFMPeriods = map[string]string{
+	"short_term":  "1month",
+	"medium_term": "6month",
+	"long_term":   "overall",
+}
+
+// ErrNoLastFMSession is returned for a Last.fm player who hasn't signed in
+// since the server started, or whose data was deleted
+var ErrNoLastFMSession = errors.New("no Last.fm session, sign in again")
+
+// LastFMAuthenticator signs players in with Last.fm and reads their top
+// tracks, which are played from Spotify
+type LastFMAuthenticator struct {
+	apiKey      string
+	secret      string
+	callbackURL string
+	client      *http.Client
+	spotify     *SpotifyAuthenticator // finds scrobbled tracks to play
+
+	mu       sync.Mutex
+	sessions map[string]string // session key by player ID
+	vault    *TokenVault
+}
+
+// NewLastFMAuthenticator creates an authenticator for a Last.fm API account,
+// resolving tracks with spotify. Without an API key Last.fm sign-in is
+// disabled.
+func NewLastFMAuthenticator(apiKey, secret, callbackURL string, spotify *SpotifyAuthenticator) *LastFMAuthenticator {
+	return &LastFMAuthenticator{
+		apiKey:      apiKey,
+		secret:      secret,
+		callbackURL: callbackURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		spotify:     spotify,
+		sessions:    make(map[string]string),
+	}
+}
+
+// Name identifies Last.fm among the music providers
+func (la *LastFMAuthenticator) Name() string {
+	return "lastfm"
+}
+
+// Enabled reports whether Last.fm sign-in is configured
+func (la *LastFMAuthenticator) Enabled() bool {
+	return la.apiKey != "" && la.secret != ""
+}
+
+// AuthURL returns the Last.fm page that asks the player to let us in.
+// Last.fm sends them back to the callback with a token, and the state.
+func (la *LastFMAuthenticator) AuthURL(state string) string {
+	query := url.Values{"api_key": {la.apiKey}, "cb": {withState(la.callbackURL, state)}}
+	return currentEndpoints().LastFMAuthURL + "?" + query.Encode()
+}
+
+// Exchange swaps the token Last.fm sent back to the callback for a session
+// and returns the player it belongs to
+func (la *LastFMAuthenticator) Exchange(ctx context.Context, callback url.Values) (*Player, error) {
+	token := callback.Get("token")
+	if token == "" {
+		return nil, errors.New("failed to get Last.fm session: no token")
+	}
+
+	var result struct {
+		Session struct {
+			Name string `json:"name"`
+			Key  string `json:"key"`
+		} `json:"session"`
+	}
+	err := la.call(ctx, url.Values{"method": {"auth.getSession"}, "token": {token}}, true, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Last.fm session: %w", err)
+	}
+	if result.Session.Name == "" {
+		return nil, errors.New("failed to get Last.fm session: no user")
+	}
+
+	player := &Player{
+		ID:   LastFMPlayerPrefix + result.Session.Name,
+		Name: result.Session.Name,
+	}
+	la.mu.Lock()
+	la.sessions[player.ID] = result.Session.Key
+	vault := la.vault
+	la.mu.Unlock()
+	if vault != nil {
+		if err := vault.Put(la.Name(), player.ID, []byte(result.Session.Key)); err != nil {
+			log.Printf("Failed to keep Last.fm session of player %s: %v", player.ID, err)
+		}
+	}
+	return player, nil
+}
+
+// SignedIn reports whether the Last.fm player's session is kept
+func (la *LastFMAuthenticator) SignedIn(playerID string) bool {
+	la.mu.Lock()
+	defer la.mu.Unlock()
+	_, ok := la.sessions[playerID]
+	return ok
+}
+
+// FetchProfile returns the signed-in player. Last.fm profiles have no
+// picture worth showing, so only the username is known.
+func (la *LastFMAuthenticator) FetchProfile(ctx context.Context, playerID string) (*Player, error) {
+	if !la.SignedIn(playerID) {
+		return nil, ErrNoLastFMSession
+	}
+	return &Player{ID: playerID, Name: strings.TrimPrefix(playerID, LastFMPlayerPrefix)}, nil
+}
+
+// Forget drops the player's Last.fm session
+func (la *LastFMAuthenticator) Forget(playerID string) {
+	la.mu.Lock()
+	delete(la.sessions, playerID)
+	vault := la.vault
+	la.mu.Unlock()
+	if vault != nil {
+		if err := vault.Delete(la.Name(), playerID); err != nil {
+			log.Printf("Failed to delete Last.fm session of player %s: %v", playerID, err)
+		}
+	}
+}
+
+// useVault keeps sessions in vault from now on, restoring the ones kept there
+func (la *LastFMAuthenticator) useVault(vault *TokenVault) {
+	la.mu.Lock()
+	defer la.mu.Unlock()
+
+	la.vault = vault
+	for playerID, key := range vault.Tokens(la.Name()) {
+		la.sessions[playerID] = string(key)
+	}
+}
+
+// FetchTopTracks retrieves the Last.fm player's most scrobbled tracks over
+// the time range and finds them on Spotify. Other sources are ignored;
+// Last.fm only has scrobbles.
+func (la *LastFMAuthenticator) FetchTopTracks(ctx context.Context, playerID string, opts PoolOptions) ([]Track, error) {
+	scrobbles, err := la.fetchScrobbles(ctx, playerID, opts.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+	return la.spotify.ResolveTracks(ctx, scrobbles)
+}
+
+// ResolvePreview returns a preview from Spotify, where Last.fm players'
+// tracks come from
+func (la *LastFMAuthenticator) ResolvePreview(ctx context.Context, trackID string) (string, error) {
+	return la.spotify.ResolvePreview(ctx, trackID)
+}
+
+// fetchScrobbles retrieves the Last.fm player's 50 most scrobbled tracks
+// over the time range. Only names are known; ResolveTracks finds them on
+// Spotify.
+func (la *LastFMAuthenticator) fetchScrobbles(ctx context.Context, playerID, timeRange string) ([]Track, error) {
+	if !la.SignedIn(playerID) {
+		return nil, ErrNoLastFMSession
+	}
+	period, ok := lastFMPeriods[timeRange]
+	if !ok {
+		period = lastFMPeriods["medium_term"]
+	}
+
+	var result struct {
+		TopTracks struct {
+			Track []struct {
+				Name   string `json:"name"`
+				Artist struct {
+					Name string `json:"name"`
+				} `json:"artist"`
+				Attr struct {
+					Rank string `json:"rank"`
+				} `json:"@attr"`
+			} `json:"track"`
+		} `json:"toptracks"`
+	}
+	err := la.call(ctx, url.Values{
+		"method": {"user.getTopTracks"},
+		"user":   {strings.TrimPrefix(playerID, LastFMPlayerPrefix)},
+		"period": {period},
+		"limit":  {"50"},
+	}, false, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Last.fm top tracks: %w", err)
+	}
+
+	tracks := make([]Track, 0, len(result.TopTracks.Track))
+	for i, scrobbled := range result.TopTracks.Track {
+		rank, err := strconv.Atoi(scrobbled.Attr.Rank)
+		if err != nil {
+			rank = i + 1
+		}
+		tracks = append(tracks, Track{
+			Name:    scrobbled.Name,
+			Artists: []string{scrobbled.Artist.Name},
+			Rank:    rank,
+			Sources: []TrackSource{SourceTop},
+		})
+	}
+	return tracks, nil
+}
+
+// call makes a Last.fm API request, signing it when the method needs it
+func (la *LastFMAuthenticator) call(ctx context.Context, params url.Values, signed bool, result interface{}) error {
+	params.Set("api_key", la.apiKey)
+	if signed {
+		params.Set("api_sig", la.sign(params))
+	}
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, currentEndpoints().LastFMAPIURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := la.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var apiErr struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != 0 {
+		return fmt.Errorf("error %d: %s", apiErr.Error, apiErr.Message)
+	}
+	return json.Unmarshal(body, result)
+}
+
+// sign computes a request's api_sig: the MD5 of every parameter's name and
+// value, in name order, followed by the shared secret
+func (la *LastFMAuthenticator) sign(params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(params.Get(name))
+	}
+	b.WriteString(la.secret)
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResolveTracks finds tracks known only by name and artist, e.g. from
+// Last.fm, on Spotify, so they can be played like any other. Searches use the
+// app's own credentials. Tracks that can't be found are dropped, as are
+// repeats; each keeps its rank and sources.
+func (sa *SpotifyAuthenticator) ResolveTracks(ctx context.Context, tracks []Track) ([]Track, error) {
+	credentials := clientcredentials.Config{
+		ClientID:     sa.config.ClientID,
+		ClientSecret: sa.config.ClientSecret,
+		TokenURL:     sa.config.Endpoint.TokenURL,
+	}
+	client := newAPIClient(credentials.Client(ctx))
+
+	resolved := make([]Track, 0, len(tracks))
+	seen := make(map[string]bool)
+	var searchErr error
+	for _, track := range tracks {
+		if len(track.Artists) == 0 {
+			continue
+		}
+		query := fmt.Sprintf("track:%q artist:%q", track.Name, track.Artists[0])
+		result, err := client.Search(ctx, query, spotify.SearchTypeTrack, spotify.Limit(1))
+		if err != nil {
+			log.Printf("Spotify search failed for %s - %s: %v", track.Artists[0], track.Name, err)
+			searchErr = err
+			continue
+		}
+		if result.Tracks == nil || len(result.Tracks.Tracks) == 0 || seen[string(result.Tracks.Tracks[0].ID)] {
+			continue
+		}
+
+		found := newTrack(ctx, result.Tracks.Tracks[0], track.Rank, SourceTop)
+		found.Sources = track.Sources
+		seen[found.ID] = true
+		resolved = append(resolved, found)
+	}
+	if len(resolved) == 0 && searchErr != nil {
+		return nil, fmt.Errorf("failed to search Spotify: %w", searchErr)
+	}
+
+	if err := enrichGenres(ctx, client, resolved); err != nil {
+		log.Printf("Skipping genres: %v", err)
+	}
+	LogPreviewURLStats(resolved)
+	return resolved, nil
+}
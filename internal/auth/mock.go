@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// MockTopTracks is how many top tracks a mock player has at most
+const MockTopTracks = 30
+
+// GenerateMockPlayer creates a stand-in player, such as a practice bot, with
+// a plausible top tracks list. Tracks are drawn from catalogue so they can
+// actually be played; with an empty catalogue they're made up and have no
+// previews. The seed makes the draw reproducible.
+func GenerateMockPlayer(id, name string, catalogue []Track, seed int64) *Player {
+	rng := rand.New(rand.NewSource(seed))
+
+	var tracks []Track
+	if len(catalogue) > 0 {
+		// A random share of the catalogue, so mock players overlap unevenly
+		count := min(MockTopTracks, len(catalogue)/2+rng.Intn(len(catalogue)/2+1))
+		for _, i := range rng.Perm(len(catalogue))[:max(count, 1)] {
+			tracks = append(tracks, catalogue[i])
+		}
+	} else {
+		for i := 0; i < MockTopTracks; i++ {
+			tracks = append(tracks, Track{
+				ID:      fmt.Sprintf("mock-%s-%02d", id, i),
+				Name:    fmt.Sprintf("Track %d", i+1),
+				Artists: []string{fmt.Sprintf("Artist %d", rng.Intn(10)+1)},
+			})
+		}
+	}
+	for i := range tracks {
+		tracks[i].Rank = i + 1
+	}
+
+	return &Player{
+		ID:        id,
+		Name:      name,
+		SpotifyID: "mock-" + id,
+		TopTracks: tracks,
+	}
+}
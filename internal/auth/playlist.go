@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// ErrInvalidPlaylist is returned for input that doesn't name a Spotify playlist
+var ErrInvalidPlaylist = errors.New("not a Spotify playlist link")
+
+var playlistIDPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// ParsePlaylistID extracts the playlist ID from a share link such as
+// https://open.spotify.com/playlist/<id>?si=..., a spotify:playlist:<id> URI
+// or a bare ID
+func ParsePlaylistID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+
+	id := input
+	switch {
+	case strings.HasPrefix(input, "spotify:playlist:"):
+		id = strings.TrimPrefix(input, "spotify:playlist:")
+	case strings.Contains(input, "/"):
+		link, err := url.Parse(input)
+		if err != nil || link.Host != "open.spotify.com" {
+			return "", ErrInvalidPlaylist
+		}
+		// Links may carry a locale first, e.g. /intl-de/playlist/<id>
+		parts := strings.Split(strings.Trim(link.Path, "/"), "/")
+		if len(parts) < 2 || parts[len(parts)-2] != "playlist" {
+			return "", ErrInvalidPlaylist
+		}
+		id = parts[len(parts)-1]
+	}
+
+	if !playlistIDPattern.MatchString(id) {
+		return "", ErrInvalidPlaylist
+	}
+	return id, nil
+}
+
+// FetchPlaylist retrieves a playlist's tracks, ranked by their position in it
+func FetchPlaylist(ctx context.Context, client *spotify.Client, playlistID string) ([]Track, error) {
+	return fetchPlaylistTracks(ctx, client, playlistID)
+}
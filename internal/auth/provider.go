@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrNoPreview is returned for a track with no preview to play
+var ErrNoPreview = errors.New("no preview available")
+
+// MusicProvider is a music service players can sign in with, whose
+// listening history makes up their track pool. Players and tracks from
+// providers other than Spotify have IDs starting with the provider's name and
+// a colon, e.g. "deezer:3135556", so Spotify IDs saved before there were
+// other providers keep working.
+type MusicProvider interface {
+	// Name identifies the provider in sign-in routes and ID prefixes
+	Name() string
+	// Enabled reports whether the provider is configured
+	Enabled() bool
+	// AuthURL is the page a player signs in on; state comes back to the
+	// callback
+	AuthURL(state string) string
+	// Exchange finishes signing in from the callback's query and keeps the
+	// player's credentials on the server
+	Exchange(ctx context.Context, callback url.Values) (*Player, error)
+	// FetchProfile returns a signed-in player's profile
+	FetchProfile(ctx context.Context, playerID string) (*Player, error)
+	// FetchTopTracks returns the tracks a signed-in player's pool is made
+	// of. Options a provider can't offer are ignored.
+	FetchTopTracks(ctx context.Context, playerID string, opts PoolOptions) ([]Track, error)
+	// ResolvePreview returns a playable preview URL for one of the
+	// provider's tracks
+	ResolvePreview(ctx context.Context, trackID string) (string, error)
+	// Forget drops the player's credentials, ending their sessions
+	Forget(playerID string)
+}
+
+// Providers are the music services players can sign in with. Spotify is
+// always one of them, and owns every unprefixed player and track ID.
+type Providers struct {
+	spotify *SpotifyAuthenticator
+	list    []MusicProvider
+	byName  map[string]MusicProvider
+}
+
+// NewProviders combines Spotify with the other providers
+func NewProviders(spotify *SpotifyAuthenticator, others ...MusicProvider) *Providers {
+	p := &Providers{
+		spotify: spotify,
+		list:    []MusicProvider{spotify},
+		byName:  map[string]MusicProvider{spotify.Name(): spotify},
+	}
+	for _, provider := range others {
+		p.list = append(p.list, provider)
+		p.byName[provider.Name()] = provider
+	}
+	return p
+}
+
+// All returns every provider, Spotify first
+func (p *Providers) All() []MusicProvider {
+	return p.list
+}
+
+// Spotify returns the Spotify provider
+func (p *Providers) Spotify() *SpotifyAuthenticator {
+	return p.spotify
+}
+
+// ForPlayer returns the provider a player signed in with
+func (p *Providers) ForPlayer(playerID string) MusicProvider {
+	return p.owner(playerID)
+}
+
+// ForTrack returns the provider a track comes from
+func (p *Providers) ForTrack(trackID string) MusicProvider {
+	return p.owner(trackID)
+}
+
+func (p *Providers) owner(id string) MusicProvider {
+	if name, _, ok := strings.Cut(id, ":"); ok {
+		if provider, ok := p.byName[name]; ok {
+			return provider
+		}
+	}
+	return p.spotify
+}
+
+// PreviewAudio returns preview audio for a track from any provider in the
+// requested quality. Low-quality requests fall back to the standard variant
+// when ffmpeg isn't available on the host.
+func (p *Providers) PreviewAudio(ctx context.Context, trackID string, quality AudioQuality) ([]byte, error) {
+	return audioCache.Get(ctx, trackID, quality, p.ForTrack(trackID).ResolvePreview)
+}
+
+// withState adds the sign-in state to a callback URL, for providers that
+// don't pass it back themselves
+func withState(callbackURL, state string) string {
+	separator := "?"
+	if strings.Contains(callbackURL, "?") {
+		separator = "&"
+	}
+	return callbackURL + separator + "state=" + url.QueryEscape(state)
+}
@@ -81,7 +81,7 @@ func FetchPreviewURLCached(trackID string) string {
 
 // scrapeSpotifyEmbed makes the HTTP request to scrape the embed page
 func scrapeSpotifyEmbed(trackID string) (string, error) {
-	embedURL := fmt.Sprintf("https://open.spotify.com/embed/track/%s", trackID)
+	embedURL := currentEndpoints().EmbedURL + trackID
 	
 	client := &http.Client{
 		Timeout: 15 * time.Second,
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// SessionTTL is how long a sign-in lasts before the player signs in again
+	SessionTTL = 12 * time.Hour
+	// SessionCookie is the HttpOnly cookie browsers keep their session in
+	SessionCookie = "session"
+)
+
+var (
+	ErrSessionInvalid = errors.New("invalid session")
+	ErrSessionExpired = errors.New("session has expired")
+)
+
+// sessionHeader is the JWT header of every session token; tokens with any
+// other header, e.g. another algorithm, are refused
+var sessionHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SessionClaims are what a session token says about who signed in
+type SessionClaims struct {
+	Subject   string `json:"sub"` // the player's Spotify ID
+	Name      string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SessionSigner issues and verifies session tokens: JWTs signed with
+// HMAC-SHA256. The Spotify token stays on the server; a session only names
+// the player it belongs to.
+type SessionSigner struct {
+	secret []byte
+}
+
+// NewSessionSigner creates a signer with the given secret. An empty secret
+// generates a random one, so sessions only survive until the server restarts.
+func NewSessionSigner(secret string) *SessionSigner {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &SessionSigner{secret: key}
+}
+
+// Issue creates a session token for the player that expires after ttl
+func (s *SessionSigner) Issue(playerID, name string, ttl time.Duration) (string, time.Time) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims, _ := json.Marshal(SessionClaims{
+		Subject:   playerID,
+		Name:      name,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+
+	signed := sessionHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return signed + "." + s.sign(signed), expiresAt
+}
+
+// Verify checks a session token's signature and expiry and returns its claims
+func (s *SessionSigner) Verify(token string) (SessionClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != sessionHeader {
+		return SessionClaims{}, ErrSessionInvalid
+	}
+	signed := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(signed))) {
+		return SessionClaims{}, ErrSessionInvalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return SessionClaims{}, ErrSessionInvalid
+	}
+	var claims SessionClaims
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.Subject == "" {
+		return SessionClaims{}, ErrSessionInvalid
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return SessionClaims{}, ErrSessionExpired
+	}
+	return claims, nil
+}
+
+func (s *SessionSigner) sign(signed string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
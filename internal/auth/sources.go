@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// TrackSource is where in a player's Spotify library a track came from
+type TrackSource string
+
+const (
+	SourceTop      TrackSource = "top"      // top tracks for the room's time range
+	SourceSaved    TrackSource = "saved"    // liked songs
+	SourcePlaylist TrackSource = "playlist" // a playlist the player picked
+	SourceRecent   TrackSource = "recent"   // recently played
+)
+
+// TrackSources lists every source, in the order pools are built
+var TrackSources = []TrackSource{SourceTop, SourceSaved, SourcePlaylist, SourceRecent}
+
+// sourceLimit is how many tracks are taken from each source
+const sourceLimit = 50
+
+// PoolOptions selects which of a player's sources make up their track pool
+type PoolOptions struct {
+	TimeRange  string        // for SourceTop
+	Sources    []TrackSource // defaults to SourceTop alone
+	PlaylistID string        // for SourcePlaylist; skipped when empty
+}
+
+// FetchPlayerTrackPool retrieves the player's tracks from each enabled
+// source and merges them. A track found in several sources appears once,
+// tagged with all of them, and keeps its best rank. Top tracks are required;
+// the other sources are skipped with a log line if they can't be read, and
+// so are genres.
+func FetchPlayerTrackPool(ctx context.Context, client *spotify.Client, opts PoolOptions) ([]Track, error) {
+	sources := opts.Sources
+	if len(sources) == 0 {
+		sources = []TrackSource{SourceTop}
+	}
+
+	var lists [][]Track
+	for _, source := range TrackSources {
+		if !slices.Contains(sources, source) {
+			continue
+		}
+
+		var tracks []Track
+		var err error
+		switch source {
+		case SourceTop:
+			if tracks, err = FetchPlayerTopTracksRange(ctx, client, opts.TimeRange); err != nil {
+				return nil, err
+			}
+		case SourceSaved:
+			tracks, err = fetchSavedTracks(ctx, client)
+		case SourcePlaylist:
+			if opts.PlaylistID == "" {
+				continue
+			}
+			tracks, err = fetchPlaylistTracks(ctx, client, opts.PlaylistID)
+		case SourceRecent:
+			tracks, err = fetchRecentTracks(ctx, client)
+		}
+		if err != nil {
+			log.Printf("Skipping %s tracks: %v", source, err)
+			continue
+		}
+		lists = append(lists, tracks)
+	}
+
+	pool := MergeTrackSources(lists...)
+	if err := enrichGenres(ctx, client, pool); err != nil {
+		log.Printf("Skipping genres: %v", err)
+	}
+	return pool, nil
+}
+
+// MergeTrackSources combines per-source track lists into one pool. Tracks
+// keep the order they were first seen in and gain every source they appear
+// in; a track's rank is the best it has in any source.
+func MergeTrackSources(lists ...[]Track) []Track {
+	var pool []Track
+	index := make(map[string]int)
+	for _, list := range lists {
+		for _, track := range list {
+			i, seen := index[track.ID]
+			if !seen {
+				track.Sources = slices.Clone(track.Sources)
+				index[track.ID] = len(pool)
+				pool = append(pool, track)
+				continue
+			}
+			merged := &pool[i]
+			for _, source := range track.Sources {
+				if !slices.Contains(merged.Sources, source) {
+					merged.Sources = append(merged.Sources, source)
+				}
+			}
+			merged.Rank = min(merged.Rank, track.Rank)
+		}
+	}
+	return pool
+}
+
+// fetchSavedTracks retrieves the player's most recently liked songs
+func fetchSavedTracks(ctx context.Context, client *spotify.Client) ([]Track, error) {
+	page, err := client.CurrentUsersTracks(ctx, spotify.Limit(sourceLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch saved tracks: %w", err)
+	}
+
+	tracks := make([]Track, 0, len(page.Tracks))
+	for _, saved := range page.Tracks {
+		tracks = append(tracks, newTrack(ctx, saved.FullTrack, len(tracks)+1, SourceSaved))
+	}
+	return tracks, nil
+}
+
+// fetchPlaylistTracks retrieves the first tracks of a playlist, skipping
+// podcast episodes and local files
+func fetchPlaylistTracks(ctx context.Context, client *spotify.Client, playlistID string) ([]Track, error) {
+	page, err := client.GetPlaylistItems(ctx, spotify.ID(playlistID), spotify.Limit(sourceLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch playlist %s: %w", playlistID, err)
+	}
+
+	tracks := make([]Track, 0, len(page.Items))
+	for _, item := range page.Items {
+		if item.Track.Track == nil || item.Track.Track.ID == "" {
+			continue
+		}
+		tracks = append(tracks, newTrack(ctx, *item.Track.Track, len(tracks)+1, SourcePlaylist))
+	}
+	return tracks, nil
+}
+
+// fetchRecentTracks retrieves the player's recently played tracks, most
+// recent first and without repeats
+func fetchRecentTracks(ctx context.Context, client *spotify.Client) ([]Track, error) {
+	items, err := client.PlayerRecentlyPlayedOpt(ctx, &spotify.RecentlyPlayedOptions{Limit: sourceLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recently played tracks: %w", err)
+	}
+
+	seen := make(map[spotify.ID]bool)
+	tracks := make([]Track, 0, len(items))
+	for _, item := range items {
+		if seen[item.Track.ID] {
+			continue
+		}
+		seen[item.Track.ID] = true
+		full := spotify.FullTrack{SimpleTrack: item.Track, Album: item.Track.Album}
+		tracks = append(tracks, newTrack(ctx, full, len(tracks)+1, SourceRecent))
+	}
+	return tracks, nil
+}
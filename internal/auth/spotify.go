@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
@@ -15,54 +19,181 @@ type Player struct {
 	ID          string   `json:"id"`
 	Name        string   `json:"name"`
 	SpotifyID   string   `json:"spotify_id"`
-	AccessToken string   `json:"-"`
+	AvatarURL   string   `json:"avatar_url,omitempty"` // their Spotify profile picture
 	TopTracks   []Track  `json:"-"`
+	// TokenSource hands out the player's Spotify token, renewing it when it
+	// expires; nil for players who didn't sign in, like bots
+	TokenSource oauth2.TokenSource `json:"-"`
+}
+
+// CurrentToken returns the player's access token, refreshed first if it has
+// expired
+func (p *Player) CurrentToken() (string, error) {
+	if p.TokenSource == nil {
+		return "", ErrNoSpotifyToken
+	}
+	token, err := p.TokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
 }
 
 // Track represents a Spotify track
 type Track struct {
-	ID         string   `json:"id"`
-	Name       string   `json:"name"`
-	Artists    []string `json:"artists"`
-	Rank       int      `json:"rank"`
-	URI        string   `json:"uri"`
-	ImageURL   string   `json:"image_url"`
-	PreviewURL string   `json:"preview_url"`
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Artists    []string      `json:"artists"`
+	ArtistIDs  []string      `json:"-"`
+	Markets    []string      `json:"-"`
+	Genres     []string      `json:"-"`              // the artists' genres, see enrichGenres
+	Year       int           `json:"year,omitempty"` // the album's release year, 0 when unknown
+	Rank       int           `json:"rank"`
+	Popularity int           `json:"popularity,omitempty"`
+	URI        string        `json:"uri"`
+	ImageURL   string        `json:"image_url"`
+	PreviewURL string        `json:"preview_url"`
+	Duration   time.Duration `json:"-"`
+	Sources    []TrackSource `json:"sources,omitempty"` // where the owner's copy came from
 }
 
+// spotifyProvider names Spotify among the music providers
+const spotifyProvider = "spotify"
+
 // SpotifyAuthenticator handles Spotify OAuth
 type SpotifyAuthenticator struct {
-	auth *spotifyauth.Authenticator
+	config *oauth2.Config
+	tokens *TokenStore
 }
 
-// NewSpotifyAuthenticator creates a new authenticator
+// NewSpotifyAuthenticator creates a new authenticator against the current
+// endpoints (see UseEndpoints)
 func NewSpotifyAuthenticator(clientID, clientSecret, redirectURI string) *SpotifyAuthenticator {
-	auth := spotifyauth.New(
-		spotifyauth.WithClientID(clientID),
-		spotifyauth.WithClientSecret(clientSecret),
-		spotifyauth.WithRedirectURL(redirectURI),
-		spotifyauth.WithScopes(spotifyauth.ScopeUserTopRead),
-	)
+	endpoints := currentEndpoints()
 
 	return &SpotifyAuthenticator{
-		auth: auth,
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURI,
+			Scopes: []string{
+				spotifyauth.ScopeUserTopRead,
+				spotifyauth.ScopeUserLibraryRead,
+				spotifyauth.ScopeUserReadRecentlyPlayed,
+				spotifyauth.ScopePlaylistReadPrivate,
+			},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  endpoints.AuthURL,
+				TokenURL: endpoints.TokenURL,
+			},
+		},
+		tokens: NewTokenStore(),
 	}
 }
 
 // GetAuthURL returns the Spotify authorization URL
 func (sa *SpotifyAuthenticator) GetAuthURL(state string) string {
-	return sa.auth.AuthURL(state)
+	return sa.config.AuthCodeURL(state)
 }
 
 // ExchangeCode exchanges authorization code for access token
 func (sa *SpotifyAuthenticator) ExchangeCode(ctx context.Context, code string) (*oauth2.Token, error) {
-	return sa.auth.Exchange(ctx, code)
+	return sa.config.Exchange(ctx, code)
 }
 
 // NewClient creates a new Spotify client with the given token
 func (sa *SpotifyAuthenticator) NewClient(ctx context.Context, token *oauth2.Token) *spotify.Client {
-	httpClient := sa.auth.Client(ctx, token)
-	return spotify.New(httpClient)
+	httpClient := sa.config.Client(ctx, token)
+	return newAPIClient(httpClient)
+}
+
+// Tokens is where signed-in players' tokens are kept
+func (sa *SpotifyAuthenticator) Tokens() *TokenStore {
+	return sa.tokens
+}
+
+// TokenSource returns a source for the signed-in player's kept token, which
+// renews it as it expires and keeps the renewed token
+func (sa *SpotifyAuthenticator) TokenSource(playerID string) oauth2.TokenSource {
+	return storedTokenSource{store: sa.tokens, config: sa.config, playerID: playerID}
+}
+
+// Client creates a Spotify client for the signed-in player (see TokenSource)
+func (sa *SpotifyAuthenticator) Client(ctx context.Context, playerID string) *spotify.Client {
+	return newAPIClient(oauth2.NewClient(ctx, sa.TokenSource(playerID)))
+}
+
+// Name identifies Spotify among the music providers
+func (sa *SpotifyAuthenticator) Name() string {
+	return spotifyProvider
+}
+
+// Enabled reports whether Spotify sign-in is configured
+func (sa *SpotifyAuthenticator) Enabled() bool {
+	return sa.config.ClientID != ""
+}
+
+// AuthURL returns the Spotify authorization URL
+func (sa *SpotifyAuthenticator) AuthURL(state string) string {
+	return sa.GetAuthURL(state)
+}
+
+// Exchange swaps the callback's code for a token, kept for the player it
+// belongs to
+func (sa *SpotifyAuthenticator) Exchange(ctx context.Context, callback url.Values) (*Player, error) {
+	token, err := sa.ExchangeCode(ctx, callback.Get("code"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	player, err := FetchPlayerInfo(ctx, sa.NewClient(ctx, token))
+	if err != nil {
+		return nil, err
+	}
+	sa.tokens.Save(player.ID, token)
+	return player, nil
+}
+
+// FetchProfile retrieves the signed-in player's profile, with a source for
+// their token
+func (sa *SpotifyAuthenticator) FetchProfile(ctx context.Context, playerID string) (*Player, error) {
+	player, err := FetchPlayerInfo(ctx, sa.Client(ctx, playerID))
+	if err != nil {
+		return nil, err
+	}
+	player.TokenSource = sa.TokenSource(playerID)
+	return player, nil
+}
+
+// FetchTopTracks retrieves the signed-in player's track pool (see
+// FetchPlayerTrackPool)
+func (sa *SpotifyAuthenticator) FetchTopTracks(ctx context.Context, playerID string, opts PoolOptions) ([]Track, error) {
+	return FetchPlayerTrackPool(ctx, sa.Client(ctx, playerID), opts)
+}
+
+// ResolvePreview returns the track's preview URL, scraped from its embed page
+func (sa *SpotifyAuthenticator) ResolvePreview(ctx context.Context, trackID string) (string, error) {
+	return resolveSpotifyPreview(ctx, trackID)
+}
+
+// Forget drops the player's kept token
+func (sa *SpotifyAuthenticator) Forget(playerID string) {
+	sa.tokens.Forget(playerID)
+}
+
+func (sa *SpotifyAuthenticator) useVault(vault *TokenVault) {
+	sa.tokens.useVault(vault)
+}
+
+func resolveSpotifyPreview(_ context.Context, trackID string) (string, error) {
+	if previewURL := FetchPreviewURLCached(trackID); previewURL != "" {
+		return previewURL, nil
+	}
+	return "", ErrNoPreview
+}
+
+// newAPIClient wraps an authorized HTTP client for the current API endpoint
+func newAPIClient(httpClient *http.Client) *spotify.Client {
+	return spotify.New(httpClient, spotify.WithBaseURL(currentEndpoints().APIURL))
 }
 
 // FetchPlayerInfo retrieves the current user's profile information
@@ -81,16 +212,29 @@ func FetchPlayerInfo(ctx context.Context, client *spotify.Client) (*Player, erro
 	if player.Name == "" {
 		player.Name = "Player " + user.ID[:4]
 	}
+	if len(user.Images) > 0 {
+		player.AvatarURL = user.Images[0].URL
+	}
 
 	return player, nil
 }
 
 // FetchPlayerTopTracks retrieves the user's top 50 tracks from the past 6 months
 func FetchPlayerTopTracks(ctx context.Context, client *spotify.Client) ([]Track, error) {
+	return FetchPlayerTopTracksRange(ctx, client, string(spotify.MediumTermRange))
+}
+
+// FetchPlayerTopTracksRange retrieves the user's top 50 tracks for the given
+// time range ("short_term", "medium_term" or "long_term")
+func FetchPlayerTopTracksRange(ctx context.Context, client *spotify.Client, timeRange string) ([]Track, error) {
+	if timeRange == "" {
+		timeRange = string(spotify.MediumTermRange)
+	}
+
 	topTracksPage, err := client.CurrentUsersTopTracks(
 		ctx,
 		spotify.Limit(50),
-		spotify.Timerange(spotify.MediumTermRange),
+		spotify.Timerange(spotify.Range(timeRange)),
 	)
 	if err != nil {
 		log.Printf("Error fetching top tracks: %v", err)
@@ -99,23 +243,7 @@ func FetchPlayerTopTracks(ctx context.Context, client *spotify.Client) ([]Track,
 
 	tracks := make([]Track, len(topTracksPage.Tracks))
 	for i, track := range topTracksPage.Tracks {
-		// Use the advanced cached fetcher with rate limiting
-		previewURL := FetchPreviewURLCached(string(track.ID))
-		
-		// Fallback to API preview URL if scraping fails
-		if previewURL == "" && track.PreviewURL != "" {
-			previewURL = track.PreviewURL
-		}
-
-		tracks[i] = Track{
-			ID:         string(track.ID),
-			Name:       track.Name,
-			Artists:    getArtistNames(track.Artists),
-			Rank:       i + 1,
-			URI:        string(track.URI),
-			ImageURL:   getAlbumImage(track.Album),
-			PreviewURL: previewURL,
-		}
+		tracks[i] = newTrack(ctx, track, i+1, SourceTop)
 	}
 
 	// Log statistics about preview URL availability
@@ -124,6 +252,40 @@ func FetchPlayerTopTracks(ctx context.Context, client *spotify.Client) ([]Track,
 	return tracks, nil
 }
 
+// newTrack converts a Spotify track at the given rank in one of the
+// player's sources, resolving and verifying its preview
+func newTrack(ctx context.Context, track spotify.FullTrack, rank int, source TrackSource) Track {
+	// Use the advanced cached fetcher with rate limiting
+	previewURL := FetchPreviewURLCached(string(track.ID))
+	
+	// Fallback to API preview URL if scraping fails
+	if previewURL == "" && track.PreviewURL != "" {
+		previewURL = track.PreviewURL
+	}
+
+	t := Track{
+		ID:         string(track.ID),
+		Name:       track.Name,
+		Artists:    getArtistNames(track.Artists),
+		ArtistIDs:  getArtistIDs(track.Artists),
+		Markets:    track.AvailableMarkets,
+		Rank:       rank,
+		Popularity: int(track.Popularity),
+		URI:        string(track.URI),
+		ImageURL:   getAlbumImage(track.Album),
+		Year:       releaseYear(track.Album),
+		PreviewURL: previewURL,
+		Duration:   track.TimeDuration(),
+		Sources:    []TrackSource{source},
+	}
+
+	// Drop previews that don't check out so they never reach a round
+	if previewVerifier != nil && previewURL != "" && !previewVerifier.Verify(ctx, t, previewURL) {
+		t.PreviewURL = ""
+	}
+	return t
+}
+
 func getArtistNames(artists []spotify.SimpleArtist) []string {
 	names := make([]string, len(artists))
 	for i, artist := range artists {
@@ -132,6 +294,62 @@ func getArtistNames(artists []spotify.SimpleArtist) []string {
 	return names
 }
 
+func getArtistIDs(artists []spotify.SimpleArtist) []string {
+	ids := make([]string, len(artists))
+	for i, artist := range artists {
+		ids[i] = string(artist.ID)
+	}
+	return ids
+}
+
+// releaseYear reads the year from an album's release date, which Spotify
+// gives as "1981", "1981-12" or "1981-12-15" depending on how well it's
+// known. Placeholder dates like "0000" count as unknown.
+func releaseYear(album spotify.SimpleAlbum) int {
+	if len(album.ReleaseDate) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(album.ReleaseDate[:4])
+	if err != nil || year < 1000 {
+		return 0
+	}
+	return year
+}
+
+// ClientForToken creates a Spotify client from a bare access token, e.g. one
+// a player presented when joining
+func ClientForToken(ctx context.Context, accessToken string) *spotify.Client {
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}))
+	return newAPIClient(httpClient)
+}
+
+// FetchArtistTopTracks retrieves an artist's most popular tracks in the
+// market of the given user's access token
+func FetchArtistTopTracks(ctx context.Context, accessToken, artistID string) ([]Track, error) {
+	client := ClientForToken(ctx, accessToken)
+
+	fullTracks, err := client.GetArtistsTopTracks(ctx, spotify.ID(artistID), "from_token")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artist top tracks: %w", err)
+	}
+
+	tracks := make([]Track, len(fullTracks))
+	for i, track := range fullTracks {
+		tracks[i] = Track{
+			ID:         string(track.ID),
+			Name:       track.Name,
+			Artists:    getArtistNames(track.Artists),
+			ArtistIDs:  getArtistIDs(track.Artists),
+			Rank:       i + 1,
+			Popularity: int(track.Popularity),
+			URI:        string(track.URI),
+			ImageURL:   getAlbumImage(track.Album),
+		}
+	}
+
+	return tracks, nil
+}
+
 func getAlbumImage(album spotify.SimpleAlbum) string {
 	if len(album.Images) > 0 {
 		return album.Images[0].URL
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshTimeout bounds a token refresh, which can happen outside any request
+const refreshTimeout = 10 * time.Second
+
+// ErrNoSpotifyToken is returned for a player whose Spotify token isn't kept,
+// because they haven't signed in since the server started (without a token
+// vault) or their data was deleted
+var ErrNoSpotifyToken = errors.New("no Spotify session, sign in again")
+
+// TokenStore keeps each signed-in player's full Spotify token, refresh
+// token included, so a session outlives the hour an access token lasts.
+// Tokens never leave the server; sessions refer to them by player ID. With a
+// vault (see Providers.UseVault) they're also kept encrypted on disk.
+type TokenStore struct {
+	mu      sync.Mutex
+	players map[string]*storedToken
+	vault   *TokenVault
+}
+
+type storedToken struct {
+	mu    sync.Mutex // held while refreshing, so only one refresh runs
+	token *oauth2.Token
+}
+
+// NewTokenStore creates an empty token store
+func NewTokenStore() *TokenStore {
+	return &TokenStore{
+		players: make(map[string]*storedToken),
+	}
+}
+
+// Save keeps the player's token, replacing any they had
+func (ts *TokenStore) Save(playerID string, token *oauth2.Token) {
+	ts.mu.Lock()
+	ts.players[playerID] = &storedToken{token: token}
+	ts.mu.Unlock()
+
+	ts.keep(playerID, token)
+}
+
+// Forget drops the player's token, ending their sessions
+func (ts *TokenStore) Forget(playerID string) {
+	ts.mu.Lock()
+	delete(ts.players, playerID)
+	vault := ts.vault
+	ts.mu.Unlock()
+
+	if vault != nil {
+		if err := vault.Delete(spotifyProvider, playerID); err != nil {
+			log.Printf("Failed to delete Spotify token of player %s: %v", playerID, err)
+		}
+	}
+}
+
+// useVault keeps tokens in vault from now on, restoring the ones kept there
+func (ts *TokenStore) useVault(vault *TokenVault) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.vault = vault
+	for playerID, data := range vault.Tokens(spotifyProvider) {
+		var token oauth2.Token
+		if err := json.Unmarshal(data, &token); err != nil {
+			log.Printf("Skipping unreadable Spotify token of player %s: %v", playerID, err)
+			continue
+		}
+		ts.players[playerID] = &storedToken{token: &token}
+	}
+}
+
+// keep writes the player's token to the vault, if there is one
+func (ts *TokenStore) keep(playerID string, token *oauth2.Token) {
+	ts.mu.Lock()
+	vault := ts.vault
+	ts.mu.Unlock()
+	if vault == nil {
+		return
+	}
+
+	data, err := json.Marshal(token)
+	if err == nil {
+		err = vault.Put(spotifyProvider, playerID, data)
+	}
+	if err != nil {
+		log.Printf("Failed to keep Spotify token of player %s: %v", playerID, err)
+	}
+}
+
+// token returns the player's current token, refreshing it with config
+// first if it has expired
+func (ts *TokenStore) token(config *oauth2.Config, playerID string) (*oauth2.Token, error) {
+	ts.mu.Lock()
+	stored, ok := ts.players[playerID]
+	ts.mu.Unlock()
+	if !ok {
+		return nil, ErrNoSpotifyToken
+	}
+
+	stored.mu.Lock()
+	defer stored.mu.Unlock()
+	if stored.token.Valid() {
+		return stored.token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	refreshed, err := config.TokenSource(ctx, stored.token).Token()
+	if err != nil {
+		return nil, err
+	}
+	// Spotify may hand out a new refresh token, so the vault's copy is updated
+	if refreshed.AccessToken != stored.token.AccessToken {
+		ts.keep(playerID, refreshed)
+	}
+	stored.token = refreshed
+	return refreshed, nil
+}
+
+// storedTokenSource hands out a player's latest token from the store
+type storedTokenSource struct {
+	store    *TokenStore
+	config   *oauth2.Config
+	playerID string
+}
+
+func (s storedTokenSource) Token() (*oauth2.Token, error) {
+	return s.store.token(s.config, s.playerID)
+}
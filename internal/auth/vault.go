@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// VaultKeySize is the length of a token vault key: AES-256
+const VaultKeySize = 32
+
+// ErrUnknownVaultKey is returned for a token sealed with a key that is no
+// longer configured
+var ErrUnknownVaultKey = errors.New("token is encrypted with a key that isn't configured")
+
+// KeyRing holds the keys provider tokens are encrypted with at rest. Every
+// token gets its own data key, which is wrapped with the primary key; the
+// other keys are only used to read tokens sealed before a rotation.
+type KeyRing struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeyRing reads a comma-separated list of id:key pairs, each key
+// base64-encoded 32 bytes. The first key is the primary one.
+func ParseKeyRing(spec string) (*KeyRing, error) {
+	ring := &KeyRing{keys: make(map[string]cipher.AEAD)}
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key %q must be written id:base64key", entry)
+		}
+		if _, exists := ring.keys[id]; exists {
+			return nil, fmt.Errorf("key %q is listed twice", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != VaultKeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, VaultKeySize, len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[id] = aead
+		if ring.primary == "" {
+			ring.primary = id
+		}
+	}
+	return ring, nil
+}
+
+// Primary returns the ID of the key new tokens are encrypted with
+func (k *KeyRing) Primary() string {
+	return k.primary
+}
+
+// sealedToken is a token encrypted with its own data key
+type sealedToken struct {
+	KeyID      string `json:"key_id"`
+	WrappedKey []byte `json:"wrapped_key"` // the data key, sealed with the key ring's key
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// seal encrypts secret with a fresh data key wrapped by the primary key.
+// The entry's name is bound in, so a sealed token can't be moved to another
+// player.
+func (k *KeyRing) seal(name string, secret []byte) (sealedToken, error) {
+	dataKey := make([]byte, VaultKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return sealedToken{}, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return sealedToken{}, err
+	}
+
+	wrapped, err := sealWith(k.keys[k.primary], dataKey, []byte(name))
+	if err != nil {
+		return sealedToken{}, err
+	}
+	ciphertext, err := sealWith(aead, secret, []byte(name))
+	if err != nil {
+		return sealedToken{}, err
+	}
+	return sealedToken{KeyID: k.primary, WrappedKey: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// open decrypts a token sealed under name
+func (k *KeyRing) open(name string, sealed sealedToken) ([]byte, error) {
+	keyAEAD, ok := k.keys[sealed.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVaultKey, sealed.KeyID)
+	}
+	dataKey, err := openWith(keyAEAD, sealed.WrappedKey, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := openWith(aead, sealed.Ciphertext, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return secret, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealWith encrypts data, prefixing the random nonce
+func sealWith(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, additional), nil
+}
+
+func openWith(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
+
+// TokenVault keeps signed-in players' provider tokens in a file, each one
+// encrypted, so sessions survive a restart
+type TokenVault struct {
+	path string
+	keys *KeyRing
+
+	mu      sync.Mutex
+	entries map[string]sealedToken // by vaultName
+}
+
+// OpenTokenVault opens the vault at path, creating it on the first write
+func OpenTokenVault(path string, keys *KeyRing) (*TokenVault, error) {
+	v := &TokenVault{
+		path:    path,
+		keys:    keys,
+		entries: make(map[string]sealedToken),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token vault: %w", err)
+	}
+	if err := json.Unmarshal(data, &v.entries); err != nil {
+		return nil, fmt.Errorf("failed to read token vault: %w", err)
+	}
+	return v, nil
+}
+
+// vaultName names a provider's token for a player
+func vaultName(provider, playerID string) string {
+	return provider + "|" + playerID
+}
+
+// Put encrypts and keeps a player's token for provider
+func (v *TokenVault) Put(provider, playerID string, secret []byte) error {
+	name := vaultName(provider, playerID)
+	sealed, err := v.keys.seal(name, secret)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.entries[name] = sealed
+	return v.save()
+}
+
+// Delete drops a player's token for provider
+func (v *TokenVault) Delete(provider, playerID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	name := vaultName(provider, playerID)
+	if _, ok := v.entries[name]; !ok {
+		return nil
+	}
+	delete(v.entries, name)
+	return v.save()
+}
+
+// Tokens decrypts every token kept for provider, by player ID. Tokens that
+// can't be decrypted, e.g. under a key that has been removed, are skipped.
+func (v *TokenVault) Tokens(provider string) map[string][]byte {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	tokens := make(map[string][]byte)
+	prefix := vaultName(provider, "")
+	for name, sealed := range v.entries {
+		playerID, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		secret, err := v.keys.open(name, sealed)
+		if err != nil {
+			log.Printf("Skipping %s token of player %s: %v", provider, playerID, err)
+			continue
+		}
+		tokens[playerID] = secret
+	}
+	return tokens
+}
+
+// Reencrypt seals every token that isn't under the primary key with it, e.g.
+// after a key rotation, and reports how many it rewrote. Once it's done,
+// keys that are no longer primary can be dropped.
+func (v *TokenVault) Reencrypt() (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	rewritten := 0
+	for name, sealed := range v.entries {
+		if sealed.KeyID == v.keys.primary {
+			continue
+		}
+		secret, err := v.keys.open(name, sealed)
+		if err != nil {
+			return 0, err
+		}
+		resealed, err := v.keys.seal(name, secret)
+		if err != nil {
+			return 0, err
+		}
+		v.entries[name] = resealed
+		rewritten++
+	}
+	if rewritten == 0 {
+		return 0, nil
+	}
+	if err := v.save(); err != nil {
+		return 0, err
+	}
+
+	log.Printf("Token vault re-encrypted %d tokens with key %s", rewritten, v.keys.primary)
+	return rewritten, nil
+}
+
+// save writes every entry. Callers must hold the lock.
+func (v *TokenVault) save() error {
+	data, err := json.Marshal(v.entries)
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves the file half-written
+	tmp := v.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.path)
+}
+
+// vaultUser is a provider that can keep its players' credentials in a vault
+type vaultUser interface {
+	useVault(vault *TokenVault)
+}
+
+// UseVault makes every provider keep its players' credentials in vault,
+// restoring the ones kept before a restart
+func (p *Providers) UseVault(vault *TokenVault) {
+	for _, provider := range p.list {
+		if user, ok := provider.(vaultUser); ok {
+			user.useVault(vault)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// testVaultKey returns an id:key pair for TOKEN_VAULT_KEYS
+func testVaultKey(t *testing.T, id string) string {
+	t.Helper()
+	key := make([]byte, VaultKeySize)
+	rand.Read(key)
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func openTestVault(t *testing.T, path, keys string) *TokenVault {
+	t.Helper()
+	ring, err := ParseKeyRing(keys)
+	if err != nil {
+		t.Fatalf("Failed to parse keys: %v", err)
+	}
+	vault, err := OpenTokenVault(path, ring)
+	if err != nil {
+		t.Fatalf("Failed to open vault: %v", err)
+	}
+	return vault
+}
+
+// TestParseKeyRing verifies malformed key lists are refused
+func TestParseKeyRing(t *testing.T) {
+	k1, k2 := testVaultKey(t, "k1"), testVaultKey(t, "k2")
+	ring, err := ParseKeyRing(k2 + ", " + k1)
+	if err != nil || ring.Primary() != "k2" {
+		t.Fatalf("Expected k2 to be primary, got %v (%v)", ring, err)
+	}
+
+	short := "k3:" + base64.StdEncoding.EncodeToString([]byte("too short"))
+	for _, spec := range []string{"", "k1", ":" + k1[3:], "k1:not-base64!", short, k1 + "," + k1} {
+		if _, err := ParseKeyRing(spec); err == nil {
+			t.Errorf("Expected %q to be refused", spec)
+		}
+	}
+
+	t.Logf("✓ Key lists are validated")
+}
+
+// TestTokenVaultEncrypted verifies tokens are encrypted on disk, bound to
+// their player, and read back after a restart
+func TestTokenVaultEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	keys := testVaultKey(t, "k1")
+	vault := openTestVault(t, path, keys)
+
+	vault.Put("deezer", "deezer:1", []byte("access-token-1"))
+	vault.Put("deezer", "deezer:2", []byte("access-token-2"))
+	vault.Put("lastfm", "lastfm:ann", []byte("session-key"))
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"access-token-1", "session-key"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("Expected %s to be encrypted on disk", secret)
+		}
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the vault to be private to the server, got %v", info.Mode().Perm())
+	}
+
+	reopened := openTestVault(t, path, keys)
+	tokens := reopened.Tokens("deezer")
+	if len(tokens) != 2 || string(tokens["deezer:1"]) != "access-token-1" {
+		t.Errorf("Expected both Deezer tokens back, got %v", tokens)
+	}
+
+	// A sealed token copied to another player doesn't decrypt
+	reopened.entries[vaultName("deezer", "deezer:2")] = reopened.entries[vaultName("deezer", "deezer:1")]
+	if tokens := reopened.Tokens("deezer"); len(tokens) != 1 {
+		t.Errorf("Expected the moved token to be skipped, got %v", tokens)
+	}
+
+	vault.Delete("lastfm", "lastfm:ann")
+	if tokens := openTestVault(t, path, keys).Tokens("lastfm"); len(tokens) != 0 {
+		t.Errorf("Expected the deleted session to be gone, got %v", tokens)
+	}
+
+	t.Logf("✓ Provider tokens are encrypted at rest")
+}
+
+// TestTokenVaultRotation verifies re-encrypting moves every token to the new
+// primary key so the old one can be retired
+func TestTokenVaultRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	k1, k2 := testVaultKey(t, "k1"), testVaultKey(t, "k2")
+
+	old := openTestVault(t, path, k1)
+	old.Put("lastfm", "lastfm:ann", []byte("session-a"))
+	old.Put("lastfm", "lastfm:bob", []byte("session-b"))
+
+	rotated := openTestVault(t, path, k2+","+k1)
+	if tokens := rotated.Tokens("lastfm"); len(tokens) != 2 {
+		t.Fatalf("Expected both tokens to be readable mid-rotation, got %d", len(tokens))
+	}
+	if rewritten, err := rotated.Reencrypt(); err != nil || rewritten != 2 {
+		t.Fatalf("Expected 2 tokens to be re-encrypted, got %d (%v)", rewritten, err)
+	}
+	if rewritten, _ := rotated.Reencrypt(); rewritten != 0 {
+		t.Errorf("Expected nothing left to re-encrypt, got %d", rewritten)
+	}
+
+	if tokens := openTestVault(t, path, k2).Tokens("lastfm"); string(tokens["lastfm:bob"]) != "session-b" {
+		t.Errorf("Expected the tokens to be readable with only the new key, got %v", tokens)
+	}
+	if tokens := openTestVault(t, path, k1).Tokens("lastfm"); len(tokens) != 0 {
+		t.Errorf("Expected the retired key to read nothing, got %v", tokens)
+	}
+
+	t.Logf("✓ Re-encrypting retires old vault keys")
+}
+
+// TestProvidersUseVault verifies signed-in players' Spotify and Deezer tokens
+// survive a restart and are removed when they delete their data
+func TestProvidersUseVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	keys := testVaultKey(t, "k1")
+
+	spotify := NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	deezer := NewDeezerAuthenticator("app", "secret", "http://localhost/deezer")
+	NewProviders(spotify, deezer).UseVault(openTestVault(t, path, keys))
+
+	spotify.Tokens().Save("alice", &oauth2.Token{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(time.Hour),
+	})
+	deezer.mu.Lock()
+	deezer.tokens["deezer:1"] = "deezer-token"
+	deezer.mu.Unlock()
+	deezer.vault.Put(deezer.Name(), "deezer:1", []byte("deezer-token"))
+
+	restartedSpotify := NewSpotifyAuthenticator("client", "secret", "http://localhost/callback")
+	restartedDeezer := NewDeezerAuthenticator("app", "secret", "http://localhost/deezer")
+	NewProviders(restartedSpotify, restartedDeezer).UseVault(openTestVault(t, path, keys))
+
+	token, err := restartedSpotify.Tokens().token(restartedSpotify.config, "alice")
+	if err != nil || token.RefreshToken != "refresh" {
+		t.Fatalf("Expected alice's token with its refresh token back, got %v (%v)", token, err)
+	}
+	if token, err := restartedDeezer.token("deezer:1"); err != nil || token != "deezer-token" {
+		t.Errorf("Expected the Deezer token back, got %q (%v)", token, err)
+	}
+
+	restartedSpotify.Forget("alice")
+	if tokens := openTestVault(t, path, keys).Tokens(spotifyProvider); len(tokens) != 0 {
+		t.Errorf("Expected alice's token to be deleted from the vault, got %d", len(tokens))
+	}
+
+	t.Logf("✓ Provider tokens survive restarts in the vault")
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/bits"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Preview verification thresholds
+const (
+	// MinPreviewDuration rejects clips too short to be a real preview
+	MinPreviewDuration = 5 * time.Second
+	// previewDurationSlack allows for encoder padding when checking that a
+	// preview isn't longer than the track it claims to be
+	previewDurationSlack = 2 * time.Second
+	// matchingSimilarity is how alike two fingerprints must be to be the
+	// same recording; unrelated audio scores around 0.5
+	matchingSimilarity = 0.85
+	// maxFingerprintOffset is how many frames either way recordings are
+	// aligned when compared
+	maxFingerprintOffset = 8
+	// maxPreviewBytes bounds how much audio is downloaded for a check
+	maxPreviewBytes = 5 << 20
+)
+
+// AudioAnalysis is what fingerprinting learned about a clip
+type AudioAnalysis struct {
+	Duration    time.Duration
+	Fingerprint []uint32 // raw chromaprint, one value per frame
+}
+
+// PreviewVerifier checks that a resolved preview is plausibly the track it
+// was resolved for before it reaches a round: long enough, no longer than
+// the track, not silent, and not the same recording as another track's
+// preview. Results are cached per track and URL.
+type PreviewVerifier struct {
+	client  *http.Client
+	analyze func(ctx context.Context, audio []byte) (AudioAnalysis, error)
+
+	mu      sync.Mutex
+	results map[string]bool            // track ID + URL → verdict
+	known   map[string]verifiedPreview // track ID → fingerprint that passed
+}
+
+type verifiedPreview struct {
+	name        string
+	fingerprint []uint32
+}
+
+var previewVerifier *PreviewVerifier
+
+// EnablePreviewVerification makes top track fetches verify every preview
+// with chromaprint's fpcalc, dropping previews that fail. It reports false,
+// leaving verification off, when fpcalc isn't installed.
+func EnablePreviewVerification() bool {
+	path, err := exec.LookPath("fpcalc")
+	if err != nil {
+		log.Printf("fpcalc not found, preview verification disabled")
+		return false
+	}
+	previewVerifier = NewPreviewVerifier(fpcalcAnalyzer(path))
+	return true
+}
+
+// NewPreviewVerifier creates a verifier using the given audio analyzer
+func NewPreviewVerifier(analyze func(ctx context.Context, audio []byte) (AudioAnalysis, error)) *PreviewVerifier {
+	return &PreviewVerifier{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		analyze: analyze,
+		results: make(map[string]bool),
+		known:   make(map[string]verifiedPreview),
+	}
+}
+
+// Verify reports whether the preview at url matches the track. Previews
+// that can't be downloaded or analyzed fail.
+func (v *PreviewVerifier) Verify(ctx context.Context, track Track, url string) bool {
+	key := track.ID + " " + url
+	v.mu.Lock()
+	verdict, cached := v.results[key]
+	v.mu.Unlock()
+	if cached {
+		return verdict
+	}
+
+	err := v.check(ctx, track, url)
+	if err != nil {
+		log.Printf("Rejected preview for track %s (%s): %v", track.ID, track.Name, err)
+	}
+
+	v.mu.Lock()
+	v.results[key] = err == nil
+	v.mu.Unlock()
+	return err == nil
+}
+
+func (v *PreviewVerifier) check(ctx context.Context, track Track, url string) error {
+	audio, err := v.download(ctx, url)
+	if err != nil {
+		return err
+	}
+	analysis, err := v.analyze(ctx, audio)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	if analysis.Duration < MinPreviewDuration {
+		return fmt.Errorf("preview lasts %v, too short", analysis.Duration)
+	}
+	if track.Duration > 0 && analysis.Duration > track.Duration+previewDurationSlack {
+		return fmt.Errorf("preview lasts %v, longer than the %v track", analysis.Duration, track.Duration)
+	}
+	if silent(analysis.Fingerprint) {
+		return fmt.Errorf("preview is silent or unreadable")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for otherID, other := range v.known {
+		if otherID == track.ID || strings.EqualFold(other.name, track.Name) {
+			continue // the same song released twice is fine
+		}
+		if similarity(analysis.Fingerprint, other.fingerprint) >= matchingSimilarity {
+			return fmt.Errorf("preview is the same recording as track %s (%s)", otherID, other.name)
+		}
+	}
+	v.known[track.ID] = verifiedPreview{name: track.Name, fingerprint: analysis.Fingerprint}
+	return nil
+}
+
+func (v *PreviewVerifier) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch preview: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxPreviewBytes))
+}
+
+// fpcalcAnalyzer fingerprints audio with chromaprint's fpcalc binary
+func fpcalcAnalyzer(path string) func(ctx context.Context, audio []byte) (AudioAnalysis, error) {
+	return func(ctx context.Context, audio []byte) (AudioAnalysis, error) {
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		defer cancel()
+
+		// fpcalc needs a seekable file
+		file, err := os.CreateTemp("", "preview-*.mp3")
+		if err != nil {
+			return AudioAnalysis{}, err
+		}
+		defer os.Remove(file.Name())
+		if _, err := file.Write(audio); err != nil {
+			file.Close()
+			return AudioAnalysis{}, err
+		}
+		file.Close()
+
+		var out, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, path, "-raw", "-json", file.Name())
+		cmd.Stdout = &out
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return AudioAnalysis{}, fmt.Errorf("fpcalc: %w: %s", err, stderr.String())
+		}
+
+		var result struct {
+			Duration    float64  `json:"duration"`
+			Fingerprint []uint32 `json:"fingerprint"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			return AudioAnalysis{}, fmt.Errorf("fpcalc output: %w", err)
+		}
+		return AudioAnalysis{
+			Duration:    time.Duration(result.Duration * float64(time.Second)),
+			Fingerprint: result.Fingerprint,
+		}, nil
+	}
+}
+
+// silent reports whether a fingerprint carries no signal: empty, or the
+// same value nearly throughout, as digital silence fingerprints
+func silent(fingerprint []uint32) bool {
+	if len(fingerprint) == 0 {
+		return true
+	}
+	counts := make(map[uint32]int)
+	for _, value := range fingerprint {
+		counts[value]++
+	}
+	for _, count := range counts {
+		if count*10 >= len(fingerprint)*9 {
+			return true
+		}
+	}
+	return false
+}
+
+// similarity compares two raw fingerprints as one minus the bit error rate
+// of their overlap, at the best alignment within maxFingerprintOffset frames
+func similarity(a, b []uint32) float64 {
+	best := 0.0
+	for offset := -maxFingerprintOffset; offset <= maxFingerprintOffset; offset++ {
+		differing, compared := 0, 0
+		for i := range a {
+			j := i + offset
+			if j < 0 || j >= len(b) {
+				continue
+			}
+			differing += bits.OnesCount32(a[i] ^ b[j])
+			compared += 32
+		}
+		if compared == 0 {
+			continue
+		}
+		best = max(best, 1-float64(differing)/float64(compared))
+	}
+	return best
+}
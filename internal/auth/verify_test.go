@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestPreviewVerification verifies short, overlong, silent and duplicated
+// previews are rejected and verdicts are cached
+func TestPreviewVerification(t *testing.T) {
+	song := make([]uint32, 120)
+	other := make([]uint32, 120)
+	for i := range song {
+		song[i] = uint32(i) * 2654435761
+		other[i] = ^song[i] ^ uint32(i*7)
+	}
+
+	// Each preview path stands for a different clip
+	clips := map[string]AudioAnalysis{
+		"/good":   {Duration: 30 * time.Second, Fingerprint: song},
+		"/other":  {Duration: 30 * time.Second, Fingerprint: other},
+		"/short":  {Duration: 2 * time.Second, Fingerprint: other},
+		"/long":   {Duration: 5 * time.Minute, Fingerprint: other},
+		"/silent": {Duration: 30 * time.Second, Fingerprint: make([]uint32, 120)},
+		"/copy":   {Duration: 30 * time.Second, Fingerprint: append([]uint32{1, 2}, song...)},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	analyzed := 0
+	verifier := NewPreviewVerifier(func(ctx context.Context, audio []byte) (AudioAnalysis, error) {
+		analyzed++
+		return clips[string(audio)], nil
+	})
+
+	track := func(id, name string) Track {
+		return Track{ID: id, Name: name, Duration: 3 * time.Minute}
+	}
+	ctx := context.Background()
+
+	if !verifier.Verify(ctx, track("t1", "Song"), server.URL+"/good") {
+		t.Fatalf("Expected a full-length preview to pass")
+	}
+	if !verifier.Verify(ctx, track("t2", "Other Song"), server.URL+"/other") {
+		t.Errorf("Expected a different recording to pass")
+	}
+	if verifier.Verify(ctx, track("t3", "Short"), server.URL+"/short") {
+		t.Errorf("Expected a 2 second preview to be rejected")
+	}
+	if verifier.Verify(ctx, track("t4", "Long"), server.URL+"/long") {
+		t.Errorf("Expected a preview longer than its track to be rejected")
+	}
+	if verifier.Verify(ctx, track("t5", "Silence"), server.URL+"/silent") {
+		t.Errorf("Expected a silent preview to be rejected")
+	}
+	if verifier.Verify(ctx, track("t6", "Missing"), server.URL+"/missing") {
+		t.Errorf("Expected an unreachable preview to be rejected")
+	}
+	if verifier.Verify(ctx, track("t7", "Not The Song"), server.URL+"/copy") {
+		t.Errorf("Expected another track's recording to be rejected")
+	}
+	if !verifier.Verify(ctx, track("t8", "song"), server.URL+"/copy") {
+		t.Errorf("Expected a re-release of the same song to pass")
+	}
+
+	before := analyzed
+	verifier.Verify(ctx, track("t1", "Song"), server.URL+"/good")
+	if analyzed != before {
+		t.Errorf("Expected a repeated check to use the cached verdict")
+	}
+
+	if similarity(song, other) > 0.7 || similarity(song, clips["/copy"].Fingerprint) < 0.99 {
+		t.Errorf("Unexpected similarities: unrelated %.2f, shifted %.2f",
+			similarity(song, other), similarity(song, clips["/copy"].Fingerprint))
+	}
+
+	t.Logf("✓ Previews are checked for length, silence and duplicate recordings")
+}
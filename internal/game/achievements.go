@@ -0,0 +1,97 @@
+package game
+
+import "time"
+
+// Achievement is a milestone a player reached in their saved games
+type Achievement struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	EarnedAt    time.Time `json:"earned_at"`
+	GameID      string    `json:"game_id,omitempty"` // the game that earned it
+}
+
+// achievementProgress is a player's running totals while their games are
+// replayed oldest first, with the game just added
+type achievementProgress struct {
+	games     int
+	wins      int
+	winStreak int
+	game      GameRecord
+	player    RecordPlayer
+}
+
+// achievementRule is earned by the first game after which earned is true
+type achievementRule struct {
+	id, name, description string
+	earned                func(p achievementProgress) bool
+}
+
+// achievementRules are checked in this order, which is also the order
+// they're listed in
+var achievementRules = []achievementRule{
+	{"first_game", "First Spin", "Finished a game", func(p achievementProgress) bool {
+		return p.games >= 1
+	}},
+	{"first_win", "Winner", "Won a game", func(p achievementProgress) bool {
+		return p.wins >= 1
+	}},
+	{"hat_trick", "Hat Trick", "Won 3 games in a row", func(p achievementProgress) bool {
+		return p.winStreak >= 3
+	}},
+	{"perfect_ear", "Perfect Ear", "Guessed every round right in a game of 5 or more rounds", func(p achievementProgress) bool {
+		return p.game.Rounds >= 5 && p.player.Correct >= p.game.Rounds
+	}},
+	{"regular", "Regular", "Played 10 games", func(p achievementProgress) bool {
+		return p.games >= 10
+	}},
+	{"champion", "Champion", "Won 10 games", func(p achievementProgress) bool {
+		return p.wins >= 10
+	}},
+	{"veteran", "Veteran", "Played 50 games", func(p achievementProgress) bool {
+		return p.games >= 50
+	}},
+}
+
+// achievements works out which achievements the player has earned from
+// their games, oldest first, and when
+func achievements(playerID string, games []GameRecord) []Achievement {
+	earned := make(map[string]Achievement)
+	var progress achievementProgress
+	for _, game := range games {
+		progress.games++
+		if game.WinnerID == playerID {
+			progress.wins++
+			progress.winStreak++
+		} else {
+			progress.winStreak = 0
+		}
+		progress.game = game
+		for _, p := range game.Players {
+			if p.ID == playerID {
+				progress.player = p
+			}
+		}
+
+		for _, rule := range achievementRules {
+			if _, done := earned[rule.id]; done || !rule.earned(progress) {
+				continue
+			}
+			earned[rule.id] = Achievement{
+				ID:          rule.id,
+				Name:        rule.name,
+				Description: rule.description,
+				EarnedAt:    game.FinishedAt,
+				GameID:      game.GameID,
+			}
+		}
+	}
+
+	list := make([]Achievement, 0, len(earned))
+	for _, rule := range achievementRules {
+		if achievement, ok := earned[rule.id]; ok {
+			list = append(list, achievement)
+		}
+	}
+	return list
+}
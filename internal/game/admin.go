@@ -0,0 +1,146 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// ErrNoRoundInProgress is returned when a repair needs a running round
+var ErrNoRoundInProgress = errors.New("no round in progress")
+
+// AdminAction identifies a support operation on a room
+type AdminAction string
+
+const (
+	AdminObserve       AdminAction = "observe"
+	AdminUnobserve     AdminAction = "unobserve"
+	AdminSystemMessage AdminAction = "system_message"
+	AdminForceEndRound AdminAction = "force_end_round"
+	AdminRebroadcast   AdminAction = "rebroadcast_state"
+	AdminVoidRound     AdminAction = "void_round"
+	AdminResetRoom     AdminAction = "reset_room"
+	AdminAddBot        AdminAction = "add_bot"
+)
+
+// AdminCommand asks the room to perform a support operation. The outcome is
+// sent on Result, which must be buffered.
+type AdminCommand struct {
+	Action     AdminAction
+	Actor      string
+	Message    string          // for AdminSystemMessage
+	Connection *websocket.Conn // for AdminObserve and AdminUnobserve
+	Round      int             // for AdminVoidRound
+	Profile    string          // for AdminAddBot
+	Result     chan error
+}
+
+func (r *GameRoom) handleAdminCommand(cmd AdminCommand) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	switch cmd.Action {
+	case AdminObserve:
+		// Observers receive every broadcast but never appear in the player list
+		r.observers[cmd.Connection] = cmd.Actor
+		if err = wsjson.Write(context.Background(), cmd.Connection, Message{
+			Type:    MsgTypeStateSnapshot,
+			Payload: r.stateSnapshot(""),
+		}); err != nil {
+			delete(r.observers, cmd.Connection)
+		}
+
+	case AdminUnobserve:
+		delete(r.observers, cmd.Connection)
+
+	case AdminSystemMessage:
+		r.Broadcast <- Message{
+			Type: MsgTypeSystemMessage,
+			Payload: map[string]interface{}{
+				"message": cmd.Message,
+			},
+		}
+
+	case AdminForceEndRound:
+		if r.State != StatePlaying || r.revealing || r.CurrentTrack == nil {
+			err = ErrNoRoundInProgress
+			break
+		}
+		if r.RoundTimer != nil {
+			r.RoundTimer.Stop()
+		}
+		go r.endRound()
+
+	case AdminRebroadcast:
+		for _, player := range r.Players {
+			r.sendDirect(player, Message{
+				Type:    MsgTypeStateSnapshot,
+				Payload: r.stateSnapshot(player.ID),
+			})
+		}
+
+	case AdminVoidRound:
+		err = r.voidRound(cmd.Round, "admin")
+
+	case AdminResetRoom:
+		if r.countdownActive {
+			r.cancelCountdown()
+		}
+		r.cancelRematch()
+		r.resetGame("admin")
+
+	case AdminAddBot:
+		_, err = r.addBot(cmd.Profile)
+
+	default:
+		err = errors.New("unknown admin action")
+	}
+
+	if err == nil && cmd.Action != AdminUnobserve {
+		log.Printf("Room %s: admin %s performed %s", r.ID, cmd.Actor, cmd.Action)
+	}
+	cmd.Result <- err
+}
+
+// broadcastToObservers relays a room message to invisible admin observers.
+// Callers must hold at least the room read lock.
+func (r *GameRoom) broadcastToObservers(msg Message) {
+	for conn, actor := range r.observers {
+		if err := wsjson.Write(context.Background(), conn, msg); err != nil {
+			log.Printf("Error broadcasting to observer %s: %v", actor, err)
+		}
+	}
+}
+
+// AdminRoomInfo is a room as operators see it, with everyone in it
+type AdminRoomInfo struct {
+	RoomInfo
+	Players []PlayerInfo `json:"players"`
+}
+
+// AdminRooms lists every room with its players, persistent rooms first
+func (rm *RoomManager) AdminRooms() []AdminRoomInfo {
+	rm.mu.RLock()
+	roomOrder := append([]string{"Room 1", "Room 2", "Room 3"}, rm.dynamicOrder...)
+	rooms := make([]*GameRoom, 0, len(roomOrder))
+	for _, roomID := range roomOrder {
+		if room, exists := rm.rooms[roomID]; exists {
+			rooms = append(rooms, room)
+		}
+	}
+	rm.mu.RUnlock()
+
+	infos := make([]AdminRoomInfo, 0, len(rooms))
+	for _, room := range rooms {
+		info := AdminRoomInfo{RoomInfo: room.Info()}
+		room.mu.RLock()
+		info.Players = room.getPlayerInfoList()
+		room.mu.RUnlock()
+		infos = append(infos, info)
+	}
+	return infos
+}
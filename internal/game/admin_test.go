@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestAdminRepairs verifies admin commands report their outcome and broadcast system messages
+func TestAdminRepairs(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+
+	result := make(chan error, 1)
+	room.handleAdminCommand(AdminCommand{Action: AdminForceEndRound, Actor: "support", Result: result})
+	if err := <-result; err != ErrNoRoundInProgress {
+		t.Errorf("Expected ErrNoRoundInProgress while waiting, got %v", err)
+	}
+
+	room.handleAdminCommand(AdminCommand{Action: AdminSystemMessage, Actor: "support", Message: "Restarting soon", Result: result})
+	if err := <-result; err != nil {
+		t.Fatalf("System message failed: %v", err)
+	}
+	msg := <-room.Broadcast
+	if msg.Type != MsgTypeSystemMessage {
+		t.Errorf("Expected system_message broadcast, got %s", msg.Type)
+	}
+
+	t.Logf("✓ Admin repairs report errors and system messages reach the room")
+}
+
+// TestAdminResetAndFlags verifies a forced reset returns the room to the lobby and drained servers start no games
+func TestAdminResetAndFlags(t *testing.T) {
+	rm := NewRoomManager()
+	room, _ := rm.GetRoom("Room 1")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.State = StatePlaying
+	room.CurrentRound = 3
+	room.Scores = map[string]int{"A": 30, "B": 10}
+
+	result := make(chan error, 1)
+	room.handleAdminCommand(AdminCommand{Action: AdminResetRoom, Actor: "support", Result: result})
+	if err := <-result; err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	msg := <-room.Broadcast
+	if msg.Type != MsgTypeGameReset || msg.Payload.(map[string]interface{})["reason"] != "admin" {
+		t.Errorf("Expected an admin game_reset, got %+v", msg)
+	}
+	if room.State != StateWaiting || room.CurrentRound != 0 || room.Scores["A"] != 0 {
+		t.Errorf("Expected a fresh lobby, got state %s round %d scores %v", room.State, room.CurrentRound, room.Scores)
+	}
+
+	rm.Drain()
+	if _, err := rm.CreateRoom(RoomOptions{}); err != ErrRoomCreationDisabled {
+		t.Errorf("Expected a draining server to refuse new rooms, got %v", err)
+	}
+	room.beginGame(StartGamePayload{RoomID: room.ID})
+	if msg := <-room.Broadcast; msg.Type != MsgTypeError || room.State != StateWaiting {
+		t.Errorf("Expected a draining server to refuse new games, got %s in %s", msg.Type, room.State)
+	}
+
+	if err := rm.Flags().Set("teleport", true); err != ErrUnknownFlag {
+		t.Errorf("Expected unknown flags to be rejected, got %v", err)
+	}
+	rm.Flags().Set(FeatureNewGames, true)
+	if !rm.Flags().All()[FeatureNewGames] || rm.Flags().All()[FeatureRoomCreation] {
+		t.Errorf("Expected only new games back on, got %v", rm.Flags().All())
+	}
+
+	t.Logf("✓ Admin resets clear the game and drained servers start nothing new")
+}
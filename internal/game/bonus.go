@@ -0,0 +1,208 @@
+package game
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// BonusRoundPoints are awarded for naming the player with the strongest
+// affinity for the bonus round's artist
+const BonusRoundPoints = 15
+
+// BonusRoundPrompt is shown to players during a bonus round
+const BonusRoundPrompt = "Who is most likely to know this?"
+
+// fetchArtistTopTracks is swapped out in tests
+var fetchArtistTopTracks = auth.FetchArtistTopTracks
+
+// bonusRound is a prepared artist round: a popular track by the game's most
+// common artist that isn't in anyone's top tracks
+type bonusRound struct {
+	ArtistID   string
+	ArtistName string
+	Track      auth.Track
+}
+
+// isBonusRound reports whether the current round number is due a bonus round.
+// Callers must hold the room lock.
+func (r *GameRoom) isBonusRound() bool {
+	interval := r.Settings.BonusRoundInterval
+	return interval > 0 && r.CurrentRound%interval == 0
+}
+
+// mostCommonArtist finds the artist appearing most often across the active
+// players' top tracks. Callers must hold the room lock.
+func (r *GameRoom) mostCommonArtist() (string, string) {
+	counts := make(map[string]int)
+	names := make(map[string]string)
+
+	for _, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			for i, artistID := range track.ArtistIDs {
+				counts[artistID]++
+				if i < len(track.Artists) {
+					names[artistID] = track.Artists[i]
+				}
+			}
+		}
+	}
+
+	bestID := ""
+	for artistID, count := range counts {
+		if count > counts[bestID] || (count == counts[bestID] && artistID < bestID) {
+			bestID = artistID
+		}
+	}
+	return bestID, names[bestID]
+}
+
+// prepareBonusRound looks up the next bonus track in the background so the
+// round can start without waiting on Spotify. Callers must hold the room lock.
+func (r *GameRoom) prepareBonusRound() {
+	r.bonus = nil
+	if r.Settings.BonusRoundInterval <= 0 {
+		return
+	}
+
+	artistID, artistName := r.mostCommonArtist()
+	if artistID == "" {
+		return
+	}
+
+	known := make(map[string]bool)
+	accounts := make([]*auth.Player, 0, len(r.Players))
+	for trackID := range r.PlayedTracks {
+		known[trackID] = true
+	}
+	for _, player := range r.Players {
+		for _, track := range player.TopTracks {
+			known[track.ID] = true
+		}
+		if player.TokenSource != nil {
+			accounts = append(accounts, player.Player)
+		}
+	}
+
+	r.bonusGen++
+	go r.fetchBonusRound(r.bonusGen, artistID, artistName, accounts, known)
+}
+
+func (r *GameRoom) fetchBonusRound(gen int, artistID, artistName string, accounts []*auth.Player, known map[string]bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Any player's token works; try the next one if a token can't be renewed
+	for _, account := range accounts {
+		token, err := account.CurrentToken()
+		if err != nil {
+			log.Printf("Room %s: bonus round lookup skipped a player: %v", r.ID, err)
+			continue
+		}
+		tracks, err := fetchArtistTopTracks(ctx, token, artistID)
+		if err != nil {
+			log.Printf("Room %s: bonus round lookup failed: %v", r.ID, err)
+			continue
+		}
+
+		for _, track := range tracks {
+			if known[track.ID] {
+				continue
+			}
+
+			r.mu.Lock()
+			if r.bonusGen == gen && r.State == StatePlaying {
+				r.bonus = &bonusRound{ArtistID: artistID, ArtistName: artistName, Track: track}
+				log.Printf("Room %s: bonus round ready for %s - %s", r.ID, artistName, track.Name)
+			}
+			r.mu.Unlock()
+			return
+		}
+		return
+	}
+}
+
+// artistAffinity scores each active player's affinity for an artist from how
+// many of their top tracks feature them, weighted by rank
+func (r *GameRoom) artistAffinity(artistID string) map[string]int {
+	affinity := make(map[string]int)
+	for playerID, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		affinity[playerID] = 0
+		for _, track := range player.TopTracks {
+			for _, id := range track.ArtistIDs {
+				if id == artistID {
+					affinity[playerID] += max(51-track.Rank, 1)
+					break
+				}
+			}
+		}
+	}
+	return affinity
+}
+
+// calculateBonusResults scores a bonus round: the answer is the player with
+// the highest affinity for the artist. Callers must hold the room lock.
+func (r *GameRoom) calculateBonusResults() *RoundResult {
+	affinity := r.artistAffinity(r.activeBonus.ArtistID)
+
+	winnerID := ""
+	for playerID, score := range affinity {
+		if score > affinity[winnerID] || (score == affinity[winnerID] && score > 0 && playerID < winnerID) {
+			winnerID = playerID
+		}
+	}
+
+	correctGuessers := make([]string, 0)
+	for playerID, guess := range r.Guesses {
+		if winnerID != "" && guess.GuessedPlayerID == winnerID {
+			correctGuessers = append(correctGuessers, playerID)
+		}
+	}
+	sort.Slice(correctGuessers, func(i, j int) bool {
+		return r.Guesses[correctGuessers[i]].Timestamp.Before(
+			r.Guesses[correctGuessers[j]].Timestamp,
+		)
+	})
+
+	pointsAwarded := make(map[string]int)
+	guessDurations := make(map[string]float64)
+	for _, playerID := range correctGuessers {
+		points := BonusRoundPoints
+		if r.ActivePowerups[playerID] == PowerupBooster {
+			points *= 2
+		}
+		points = r.handicapped(playerID, points)
+		pointsAwarded[playerID] = points
+		r.Scores[playerID] += points
+		guessDurations[playerID] = r.Guesses[playerID].Timestamp.Sub(r.RoundStartTime).Seconds()
+	}
+
+	var powerupsUsed map[string]PowerupType
+	var streaks map[string]int
+	if r.PowerupsEnabled {
+		powerupsUsed, streaks = r.applyStreaks(correctGuessers)
+	}
+
+	return &RoundResult{
+		Round:           r.CurrentRound,
+		Track:           *r.CurrentTrack,
+		WinnerID:        winnerID,
+		CorrectGuessers: correctGuessers,
+		PointsAwarded:   pointsAwarded,
+		UpdatedScores:   r.Scores,
+		GuessDurations:  guessDurations,
+		PowerupsUsed:    powerupsUsed,
+		Streaks:         streaks,
+		Bonus:           true,
+		Affinity:        affinity,
+	}
+}
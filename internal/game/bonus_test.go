@@ -0,0 +1,76 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"roulettify/internal/auth"
+)
+
+// TestBonusRoundArtistAffinity verifies bonus rounds pick an unknown track by the most common artist and score by affinity
+func TestBonusRoundArtistAffinity(t *testing.T) {
+	fetchArtistTopTracks = func(ctx context.Context, accessToken, artistID string) ([]auth.Track, error) {
+		return []auth.Track{
+			{ID: "known", Name: "Already Known", ArtistIDs: []string{artistID}},
+			{ID: "fresh", Name: "Deep Cut", ArtistIDs: []string{artistID}},
+		}, nil
+	}
+	defer func() { fetchArtistTopTracks = auth.FetchArtistTopTracks }()
+
+	room := NewGameRoom("test-room")
+	room.Settings.BonusRoundInterval = 2
+	room.State = StatePlaying
+
+	fan := newTestPlayer("A")
+	fan.TokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	fan.TopTracks = []auth.Track{
+		{ID: "known", Rank: 1, Artists: []string{"Band"}, ArtistIDs: []string{"band"}},
+		{ID: "t2", Rank: 2, Artists: []string{"Band"}, ArtistIDs: []string{"band"}},
+	}
+	casual := newTestPlayer("B")
+	casual.TopTracks = []auth.Track{
+		{ID: "t3", Rank: 40, Artists: []string{"Band"}, ArtistIDs: []string{"band"}},
+	}
+	room.Players["A"] = fan
+	room.Players["B"] = casual
+
+	room.mu.Lock()
+	room.prepareBonusRound()
+	room.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		room.mu.RLock()
+		bonus := room.bonus
+		room.mu.RUnlock()
+		if bonus != nil {
+			if bonus.Track.ID != "fresh" || bonus.ArtistName != "Band" {
+				t.Fatalf("Expected unknown track by Band, got %s by %s", bonus.Track.ID, bonus.ArtistName)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Bonus round was never prepared")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	room.activeBonus = room.bonus
+	room.CurrentTrack = &room.bonus.Track
+	room.CurrentRound = 2
+	room.Guesses["A"] = Guess{PlayerID: "A", GuessedPlayerID: "B", Timestamp: time.Now()}
+	room.Guesses["B"] = Guess{PlayerID: "B", GuessedPlayerID: "A", Timestamp: time.Now()}
+
+	result := room.calculateBonusResults()
+	if result.WinnerID != "A" {
+		t.Errorf("Expected the biggest fan to be the answer, got %s", result.WinnerID)
+	}
+	if result.PointsAwarded["B"] != BonusRoundPoints || result.PointsAwarded["A"] != 0 {
+		t.Errorf("Expected only B to score, got %v", result.PointsAwarded)
+	}
+
+	t.Logf("✓ Bonus rounds play an unfamiliar track and score by artist affinity")
+}
@@ -0,0 +1,320 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownBotProfile is returned when a bot asks for a profile that isn't loaded
+var ErrUnknownBotProfile = errors.New("unknown bot profile")
+
+// Latency distributions for bot guesses
+const (
+	LatencyUniform   = "uniform"
+	LatencyNormal    = "normal"
+	LatencyLogNormal = "lognormal"
+)
+
+// Special TasteBias keys that follow the room rather than a fixed player
+const (
+	BiasLeader     = "leader"
+	BiasLastWinner = "last_winner"
+)
+
+// AccuracyPoint is the chance a bot recognizes whose track it is when the
+// track sits at Rank in the owner's top 50
+type AccuracyPoint struct {
+	Rank     int     `json:"rank"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// LatencyProfile is how long a bot takes to guess, in milliseconds
+type LatencyProfile struct {
+	Distribution string  `json:"distribution"` // uniform, normal or lognormal
+	MinMs        int     `json:"min_ms"`
+	MaxMs        int     `json:"max_ms"`
+	MeanMs       float64 `json:"mean_ms"`   // normal and lognormal
+	StdDevMs     float64 `json:"stddev_ms"` // normal and lognormal
+}
+
+// BotProfile describes how a bot plays: how often it knows the answer, how
+// quickly it answers, and who it leans towards when it doesn't know
+type BotProfile struct {
+	Name          string          `json:"name"`
+	AccuracyCurve []AccuracyPoint `json:"accuracy_curve"` // interpolated by rank
+	Latency       LatencyProfile  `json:"latency"`
+	// TasteBias weights players the bot picks when guessing blind, by
+	// player ID or BiasLeader/BiasLastWinner; unlisted players weigh 1
+	TasteBias map[string]float64 `json:"taste_bias,omitempty"`
+}
+
+// DefaultBotProfiles are available without any configuration
+var DefaultBotProfiles = []BotProfile{
+	{
+		Name:          "casual",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 0.5}, {Rank: 50, Accuracy: 0.15}},
+		Latency:       LatencyProfile{Distribution: LatencyUniform, MinMs: 4000, MaxMs: 20000},
+	},
+	{
+		Name:          "regular",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 0.75}, {Rank: 20, Accuracy: 0.5}, {Rank: 50, Accuracy: 0.3}},
+		Latency:       LatencyProfile{Distribution: LatencyNormal, MinMs: 2000, MaxMs: 15000, MeanMs: 7000, StdDevMs: 2500},
+	},
+	{
+		Name:          "expert",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 0.95}, {Rank: 25, Accuracy: 0.8}, {Rank: 50, Accuracy: 0.6}},
+		Latency:       LatencyProfile{Distribution: LatencyLogNormal, MinMs: 1200, MaxMs: 10000, MeanMs: 3000, StdDevMs: 1500},
+	},
+	{
+		Name:          "fanboy",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 0.4}, {Rank: 50, Accuracy: 0.2}},
+		Latency:       LatencyProfile{Distribution: LatencyUniform, MinMs: 1000, MaxMs: 6000},
+		TasteBias:     map[string]float64{BiasLeader: 4},
+	},
+}
+
+// DefaultBotProfile is used when a bot doesn't ask for one
+const DefaultBotProfile = "regular"
+
+var (
+	botProfilesMu sync.RWMutex
+	botProfiles   = profileIndex(DefaultBotProfiles)
+)
+
+func profileIndex(profiles []BotProfile) map[string]BotProfile {
+	index := make(map[string]BotProfile, len(profiles))
+	for _, profile := range profiles {
+		index[profile.Name] = profile
+	}
+	return index
+}
+
+// Validate rejects profiles that can't produce sensible guesses
+func (p BotProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("bot profile needs a name")
+	}
+	if len(p.AccuracyCurve) == 0 {
+		return fmt.Errorf("bot profile %q needs an accuracy curve", p.Name)
+	}
+	for _, point := range p.AccuracyCurve {
+		if point.Rank < 1 || point.Rank > 50 || point.Accuracy < 0 || point.Accuracy > 1 {
+			return fmt.Errorf("bot profile %q: accuracy points need a rank of 1-50 and an accuracy of 0-1", p.Name)
+		}
+	}
+
+	l := p.Latency
+	if l.MinMs < 0 || l.MaxMs < l.MinMs {
+		return fmt.Errorf("bot profile %q: latency needs 0 <= min_ms <= max_ms", p.Name)
+	}
+	switch l.Distribution {
+	case LatencyUniform:
+	case LatencyNormal, LatencyLogNormal:
+		if l.MeanMs <= 0 || l.StdDevMs < 0 {
+			return fmt.Errorf("bot profile %q: %s latency needs a positive mean_ms", p.Name, l.Distribution)
+		}
+	default:
+		return fmt.Errorf("bot profile %q: unknown latency distribution %q", p.Name, l.Distribution)
+	}
+
+	for key, weight := range p.TasteBias {
+		if weight < 0 {
+			return fmt.Errorf("bot profile %q: taste bias for %q can't be negative", p.Name, key)
+		}
+	}
+	return nil
+}
+
+// LoadBotProfiles reads a JSON array of profiles from path. They are added
+// to the defaults, replacing any with the same name.
+func LoadBotProfiles(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bot profiles: %w", err)
+	}
+
+	var profiles []BotProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return fmt.Errorf("failed to parse bot profiles: %w", err)
+	}
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+	}
+
+	index := profileIndex(DefaultBotProfiles)
+	for _, profile := range profiles {
+		index[profile.Name] = profile
+	}
+
+	botProfilesMu.Lock()
+	botProfiles = index
+	botProfilesMu.Unlock()
+	return nil
+}
+
+// GetBotProfile returns a loaded profile by name, or the default for ""
+func GetBotProfile(name string) (BotProfile, error) {
+	if name == "" {
+		name = DefaultBotProfile
+	}
+
+	botProfilesMu.RLock()
+	defer botProfilesMu.RUnlock()
+
+	profile, ok := botProfiles[name]
+	if !ok {
+		return BotProfile{}, fmt.Errorf("%w %q", ErrUnknownBotProfile, name)
+	}
+	return profile, nil
+}
+
+// BotProfileNames lists the loaded profiles, sorted
+func BotProfileNames() []string {
+	botProfilesMu.RLock()
+	defer botProfilesMu.RUnlock()
+
+	names := make([]string, 0, len(botProfiles))
+	for name := range botProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BotRound is what a bot knows when deciding its guess. WinnerRank is how
+// high the track sits for its owner, which drives recognition.
+type BotRound struct {
+	BotID        string
+	Candidates   []string // players that can be guessed
+	WinnerID     string
+	WinnerRank   int
+	LeaderID     string
+	LastWinnerID string
+	Duration     time.Duration // round length; guesses land before it ends
+}
+
+// BotGuess is a bot's decision for a round
+type BotGuess struct {
+	GuessedPlayerID string
+	Delay           time.Duration
+}
+
+// BotBrain makes guesses for one bot according to its profile
+type BotBrain struct {
+	Profile BotProfile
+	rng     *rand.Rand
+}
+
+// NewBotBrain creates a brain for the profile. The seed makes its choices
+// reproducible.
+func NewBotBrain(profile BotProfile, seed int64) *BotBrain {
+	return &BotBrain{
+		Profile: profile,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Decide picks a player and a delay for the round
+func (b *BotBrain) Decide(round BotRound) BotGuess {
+	guess := BotGuess{Delay: b.latency(round.Duration)}
+
+	if round.WinnerID != "" && b.rng.Float64() < b.accuracy(round.WinnerRank) {
+		guess.GuessedPlayerID = round.WinnerID
+		return guess
+	}
+
+	guess.GuessedPlayerID = b.blindPick(round)
+	return guess
+}
+
+// accuracy interpolates the profile's curve at the given rank
+func (b *BotBrain) accuracy(rank int) float64 {
+	curve := append([]AccuracyPoint(nil), b.Profile.AccuracyCurve...)
+	sort.Slice(curve, func(i, j int) bool { return curve[i].Rank < curve[j].Rank })
+
+	if rank <= curve[0].Rank {
+		return curve[0].Accuracy
+	}
+	for i := 1; i < len(curve); i++ {
+		if rank <= curve[i].Rank {
+			from, to := curve[i-1], curve[i]
+			t := float64(rank-from.Rank) / float64(to.Rank-from.Rank)
+			return from.Accuracy + t*(to.Accuracy-from.Accuracy)
+		}
+	}
+	// Beyond the curve, e.g. a track nobody has (rank 999)
+	return curve[len(curve)-1].Accuracy
+}
+
+// latency draws a guess delay, kept inside the round
+func (b *BotBrain) latency(roundDuration time.Duration) time.Duration {
+	l := b.Profile.Latency
+
+	var ms float64
+	switch l.Distribution {
+	case LatencyNormal:
+		ms = l.MeanMs + b.rng.NormFloat64()*l.StdDevMs
+	case LatencyLogNormal:
+		// Parameterised by the mean and deviation of the delay itself
+		variance := math.Log(1 + (l.StdDevMs*l.StdDevMs)/(l.MeanMs*l.MeanMs))
+		mu := math.Log(l.MeanMs) - variance/2
+		ms = math.Exp(mu + b.rng.NormFloat64()*math.Sqrt(variance))
+	default:
+		ms = float64(l.MinMs) + b.rng.Float64()*float64(l.MaxMs-l.MinMs)
+	}
+
+	ms = math.Max(float64(l.MinMs), math.Min(ms, float64(l.MaxMs)))
+	delay := time.Duration(ms) * time.Millisecond
+	if roundDuration > 0 && delay >= roundDuration {
+		delay = roundDuration - roundDuration/10
+	}
+	return delay
+}
+
+// blindPick chooses a player weighted by the profile's taste bias
+func (b *BotBrain) blindPick(round BotRound) string {
+	weights := make([]float64, len(round.Candidates))
+	total := 0.0
+	for i, playerID := range round.Candidates {
+		weight := 1.0
+		if bias, ok := b.Profile.TasteBias[playerID]; ok {
+			weight = bias
+		}
+		if playerID == round.LeaderID {
+			if bias, ok := b.Profile.TasteBias[BiasLeader]; ok {
+				weight = bias
+			}
+		}
+		if playerID == round.LastWinnerID {
+			if bias, ok := b.Profile.TasteBias[BiasLastWinner]; ok {
+				weight = bias
+			}
+		}
+		weights[i] = weight
+		total += weight
+	}
+	if total <= 0 {
+		if len(round.Candidates) == 0 {
+			return ""
+		}
+		return round.Candidates[b.rng.Intn(len(round.Candidates))]
+	}
+
+	pick := b.rng.Float64() * total
+	for i, weight := range weights {
+		pick -= weight
+		if pick < 0 {
+			return round.Candidates[i]
+		}
+	}
+	return round.Candidates[len(round.Candidates)-1]
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestBotBrainFollowsProfile verifies accuracy, latency and taste bias come from the bot's profile
+func TestBotBrainFollowsProfile(t *testing.T) {
+	profile := BotProfile{
+		Name:          "test",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 1}, {Rank: 50, Accuracy: 0}},
+		Latency:       LatencyProfile{Distribution: LatencyNormal, MinMs: 1000, MaxMs: 3000, MeanMs: 2000, StdDevMs: 1000},
+		TasteBias:     map[string]float64{BiasLeader: 1000},
+	}
+	if err := profile.Validate(); err != nil {
+		t.Fatalf("Expected a valid profile, got %v", err)
+	}
+	brain := NewBotBrain(profile, 1)
+
+	if got := brain.accuracy(25); got < 0.5 || got > 0.52 {
+		t.Errorf("Expected accuracy near 0.51 at rank 25, got %v", got)
+	}
+
+	round := BotRound{
+		Candidates: []string{"A", "B", "C"},
+		WinnerID:   "B",
+		LeaderID:   "C",
+		Duration:   30 * time.Second,
+	}
+
+	round.WinnerRank = 1
+	for i := 0; i < 20; i++ {
+		guess := brain.Decide(round)
+		if guess.GuessedPlayerID != "B" {
+			t.Fatalf("A rank 1 track should always be recognized, got %s", guess.GuessedPlayerID)
+		}
+		if guess.Delay < time.Second || guess.Delay > 3*time.Second {
+			t.Fatalf("Delay %v outside the profile's 1-3s range", guess.Delay)
+		}
+	}
+
+	// Never recognized, so blind picks lean heavily to the leader
+	round.WinnerRank = 50
+	leaderPicks := 0
+	for i := 0; i < 100; i++ {
+		if brain.Decide(round).GuessedPlayerID == "C" {
+			leaderPicks++
+		}
+	}
+	if leaderPicks < 95 {
+		t.Errorf("Expected blind guesses to favor the leader, got %d/100", leaderPicks)
+	}
+
+	t.Logf("✓ Bot guesses follow the profile's accuracy, latency and bias")
+}
+
+// TestLoadBotProfiles verifies profiles load from config alongside the defaults
+func TestLoadBotProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bots.json")
+	os.WriteFile(path, []byte(`[{"name": "speedy", "accuracy_curve": [{"rank": 1, "accuracy": 0.9}],
+		"latency": {"distribution": "uniform", "min_ms": 500, "max_ms": 900}}]`), 0o644)
+
+	for _, profile := range DefaultBotProfiles {
+		if err := profile.Validate(); err != nil {
+			t.Errorf("Default profile invalid: %v", err)
+		}
+	}
+
+	if err := LoadBotProfiles(path); err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+	t.Cleanup(func() { botProfiles = profileIndex(DefaultBotProfiles) })
+
+	if _, err := GetBotProfile("speedy"); err != nil {
+		t.Errorf("Expected the configured profile to load, got %v", err)
+	}
+	if profile, err := GetBotProfile(""); err != nil || profile.Name != DefaultBotProfile {
+		t.Errorf("Expected the default profile for an empty name, got %q (%v)", profile.Name, err)
+	}
+
+	os.WriteFile(path, []byte(`[{"name": "broken", "accuracy_curve": [{"rank": 1, "accuracy": 2}]}]`), 0o644)
+	if err := LoadBotProfiles(path); err == nil {
+		t.Error("Expected an invalid profile to be rejected")
+	}
+
+	t.Logf("✓ Bot profiles load from config")
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+
+	"roulettify/internal/auth"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// ClientCapabilities are declared by the client when joining so the server
+// can tailor payloads and evolve the protocol without breaking old clients
+type ClientCapabilities struct {
+	// BinaryFrames clients receive JSON in binary WebSocket frames
+	BinaryFrames bool `json:"binary_frames"`
+	// AudioProxy clients stream previews from /audio and don't need preview URLs
+	AudioProxy bool `json:"audio_proxy"`
+	// Lite clients get player list deltas, no album art and no score maps
+	// the player list already carries, for constrained connections
+	Lite bool `json:"lite"`
+	// Debug clients get correlation IDs and server timestamps on every
+	// message and may send debug_report timings back
+	Debug bool `json:"debug"`
+}
+
+// writeMessage sends msg to a player in the form their client declared it supports
+func (r *GameRoom) writeMessage(player *Player, msg Message) error {
+	msg = tailorMessage(player.Capabilities, msg)
+	if player.Capabilities.Lite {
+		msg = liteMessage(player, msg)
+	}
+	if player.debug != nil {
+		msg = player.debug.stamp(msg)
+	}
+	ctx := context.Background()
+
+	if player.Capabilities.BinaryFrames {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return player.Connection.Write(ctx, websocket.MessageBinary, data)
+	}
+
+	return wsjson.Write(ctx, player.Connection, msg)
+}
+
+// tailorMessage strips fields a client has declared it doesn't need.
+// The original message is never modified since it is shared across players.
+func tailorMessage(caps ClientCapabilities, msg Message) Message {
+	if !caps.AudioProxy {
+		return msg
+	}
+
+	switch payload := msg.Payload.(type) {
+	case map[string]interface{}:
+		track, ok := payload["track"].(auth.Track)
+		if !ok {
+			return msg
+		}
+		tailored := make(map[string]interface{}, len(payload))
+		for key, value := range payload {
+			tailored[key] = value
+		}
+		track.PreviewURL = ""
+		tailored["track"] = track
+		msg.Payload = tailored
+
+	case *RoundResult:
+		tailored := *payload
+		tailored.Track.PreviewURL = ""
+		msg.Payload = &tailored
+	}
+
+	return msg
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// CardWidth and CardHeight are a result card's size, the usual social
+	// preview size
+	CardWidth  = 1200
+	CardHeight = 630
+	// cardPlayers is how many standings fit on a card
+	cardPlayers = 8
+	// cardNameLength is how many characters of a name fit on a card
+	cardNameLength = 22
+)
+
+// RecordTrack is a track as kept in a saved game
+type RecordTrack struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Artists  []string `json:"artists"`
+	ImageURL string   `json:"image_url"`
+}
+
+// winningTrack is the track of the round the winner scored most in, the
+// earliest on a tie. Callers must hold the room lock.
+func (r *GameRoom) winningTrack(winnerID string) *RecordTrack {
+	var best *RoundResult
+	for round, result := range r.roundResults {
+		if r.voidedRounds[round] || result.PointsAwarded[winnerID] <= 0 {
+			continue
+		}
+		if best == nil || result.PointsAwarded[winnerID] > best.PointsAwarded[winnerID] ||
+			(result.PointsAwarded[winnerID] == best.PointsAwarded[winnerID] && round < best.Round) {
+			best = result
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &RecordTrack{
+		ID:       best.Track.ID,
+		Name:     best.Track.Name,
+		Artists:  best.Track.Artists,
+		ImageURL: best.Track.ImageURL,
+	}
+}
+
+// RenderResultCard draws a finished game's final standings as an SVG card
+// for sharing. art is the winning track's cover image, embedded so the card
+// stands alone; without it the card shows a placeholder.
+func RenderResultCard(record GameRecord, art []byte, artType string) []byte {
+	players := append([]RecordPlayer{}, record.Players...)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Score > players[j].Score
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		CardWidth, CardHeight, CardWidth, CardHeight)
+	b.WriteString(`<rect width="100%" height="100%" fill="#121212"/>`)
+	b.WriteString(`<g font-family="Helvetica, Arial, sans-serif">`)
+
+	// Winning track on the left
+	if len(art) > 0 {
+		fmt.Fprintf(&b, `<image x="60" y="90" width="420" height="420" href="data:%s;base64,%s"/>`,
+			html.EscapeString(artType), base64.StdEncoding.EncodeToString(art))
+	} else {
+		b.WriteString(`<rect x="60" y="90" width="420" height="420" rx="8" fill="#282828"/>`)
+		b.WriteString(`<text x="270" y="320" font-size="120" fill="#535353" text-anchor="middle">♪</text>`)
+	}
+	if track := record.WinningTrack; track != nil {
+		fmt.Fprintf(&b, `<text x="60" y="555" font-size="26" font-weight="bold" fill="#ffffff">%s</text>`,
+			cardText(track.Name, 30))
+		fmt.Fprintf(&b, `<text x="60" y="590" font-size="22" fill="#b3b3b3">%s</text>`,
+			cardText(strings.Join(track.Artists, ", "), 36))
+	}
+
+	// Standings on the right
+	b.WriteString(`<text x="540" y="70" font-size="24" font-weight="bold" fill="#1db954">ROULETTIFY</text>`)
+	winner := ""
+	for _, p := range players {
+		if p.ID == record.WinnerID {
+			winner = p.Name
+		}
+	}
+	if winner != "" {
+		fmt.Fprintf(&b, `<text x="540" y="125" font-size="40" font-weight="bold" fill="#ffffff">%s wins!</text>`,
+			cardText(winner, cardNameLength))
+	}
+
+	rank := 0
+	for i, p := range players {
+		if i == cardPlayers {
+			fmt.Fprintf(&b, `<text x="540" y="%d" font-size="22" fill="#b3b3b3">+%d more</text>`,
+				180+i*48, len(players)-cardPlayers)
+			break
+		}
+		// Players level on points share a rank
+		if i == 0 || p.Score != players[i-1].Score {
+			rank = i + 1
+		}
+		fill := "#ffffff"
+		if p.ID == record.WinnerID {
+			fill = "#1db954"
+		}
+		y := 180 + i*48
+		fmt.Fprintf(&b, `<text x="540" y="%d" font-size="28" fill="#b3b3b3">%d</text>`, y, rank)
+		fmt.Fprintf(&b, `<text x="590" y="%d" font-size="28" fill="%s">%s</text>`, y, fill, cardText(p.Name, cardNameLength))
+		fmt.Fprintf(&b, `<text x="1140" y="%d" font-size="28" font-weight="bold" fill="%s" text-anchor="end">%d</text>`, y, fill, p.Score)
+	}
+
+	fmt.Fprintf(&b, `<text x="1140" y="600" font-size="20" fill="#535353" text-anchor="end">%d rounds · %s</text>`,
+		record.Rounds, record.FinishedAt.Format("2 Jan 2006"))
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
+// cardText escapes text for the card, cutting it to max characters
+func cardText(text string, max int) string {
+	if utf8.RuneCountInString(text) > max {
+		text = string([]rune(text)[:max-1]) + "…"
+	}
+	return html.EscapeString(text)
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestResultCard verifies a saved game keeps its winning track and renders
+// as a well-formed SVG card with escaped names and shared ranks
+func TestResultCard(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	h := newGameHarness(t, 7)
+	h.room.history = history
+	h.room.Settings.TotalRounds = 1
+
+	a := harnessPlayer("A", "a1")
+	a.Name = `<Ann & "Co">`
+	a.TopTracks[0].Artists = []string{"Artist A"}
+	a.TopTracks[0].ImageURL = "https://i.scdn.co/image/a1"
+	h.join(a)
+	h.join(harnessPlayer("B", "b1"))
+	h.join(harnessPlayer("C", "c1"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	// Play A's track so A wins with it
+	h.room.mu.Lock()
+	h.room.CurrentTrack = &a.TopTracks[0]
+	h.room.mu.Unlock()
+	h.guess("A", "A", time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.guess("C", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	record, ok := history.Game(h.room.GameID)
+	if !ok || record.WinningTrack == nil || record.WinningTrack.ID != "a1" || record.WinningTrack.ImageURL == "" {
+		t.Fatalf("Expected the saved game to keep A's winning track, got %+v", record.WinningTrack)
+	}
+
+	card := string(RenderResultCard(record, []byte("jpeg"), "image/jpeg"))
+	if err := xml.Unmarshal([]byte(card), new(struct{})); err != nil {
+		t.Fatalf("Expected well-formed SVG, got %v", err)
+	}
+	if !strings.Contains(card, "&lt;Ann &amp; &#34;Co&#34;&gt; wins!") || strings.Contains(card, "<Ann") {
+		t.Errorf("Expected the winner's name escaped on the card")
+	}
+	if !strings.Contains(card, "data:image/jpeg;base64,anBlZw==") || !strings.Contains(card, "Artist A") {
+		t.Errorf("Expected the cover art and artist on the card")
+	}
+	// B and C are level on 0 and share 2nd
+	if strings.Count(card, `fill="#b3b3b3">2</text>`) != 2 {
+		t.Errorf("Expected B and C to share 2nd place")
+	}
+
+	t.Logf("✓ Result cards show the standings and winning track")
+}
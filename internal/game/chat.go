@@ -0,0 +1,68 @@
+package game
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Lobby chat limits
+const (
+	MaxChatMessageLength = 300
+	ChatRateLimit        = 5                // messages per window
+	ChatRateWindow       = 10 * time.Second // sliding window for ChatRateLimit
+)
+
+func (r *GameRoom) handleChat(payload ChatPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists {
+		return
+	}
+
+	if !r.Flags.Enabled(FeatureChat) {
+		r.sendError(player.ID, "Chat is switched off on this server")
+		return
+	}
+
+	if r.State != StateWaiting {
+		r.sendError(player.ID, "Chat is only available in the lobby")
+		return
+	}
+
+	message := strings.TrimSpace(payload.Message)
+	if message == "" {
+		return
+	}
+	if utf8.RuneCountInString(message) > MaxChatMessageLength {
+		r.sendError(player.ID, "Chat messages can be at most 300 characters")
+		return
+	}
+
+	now := time.Now()
+	recent := player.chatTimes[:0]
+	for _, sentAt := range player.chatTimes {
+		if now.Sub(sentAt) < ChatRateWindow {
+			recent = append(recent, sentAt)
+		}
+	}
+	player.chatTimes = recent
+
+	if len(player.chatTimes) >= ChatRateLimit {
+		r.sendError(player.ID, "You're sending messages too quickly")
+		return
+	}
+	player.chatTimes = append(player.chatTimes, now)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeChat,
+		Payload: map[string]interface{}{
+			"player_id":   player.ID,
+			"player_name": player.Name,
+			"message":     message,
+			"sent_at":     now,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestLobbyChatRateLimit verifies chat is relayed in the lobby with sender details and rate limited per player
+func TestLobbyChatRateLimit(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+
+	room.handleChat(ChatPayload{PlayerID: "A", Message: "  hello  "})
+	msg := <-room.Broadcast
+	payload := msg.Payload.(map[string]interface{})
+	if msg.Type != MsgTypeChat || payload["message"] != "hello" || payload["player_name"] != "Player A" {
+		t.Fatalf("Unexpected chat broadcast: %+v", msg)
+	}
+
+	for i := 0; i < ChatRateLimit+2; i++ {
+		room.handleChat(ChatPayload{PlayerID: "A", Message: "spam"})
+	}
+	if len(room.Broadcast) != ChatRateLimit-1 {
+		t.Errorf("Expected %d more messages before the rate limit, got %d", ChatRateLimit-1, len(room.Broadcast))
+	}
+	for len(room.Broadcast) > 0 {
+		<-room.Broadcast
+	}
+
+	room.State = StatePlaying
+	room.Players["A"].chatTimes = nil
+	room.handleChat(ChatPayload{PlayerID: "A", Message: "mid-game"})
+	if len(room.Broadcast) != 0 {
+		t.Error("Chat should only be relayed while the room is waiting")
+	}
+
+	t.Logf("✓ Lobby chat is relayed and rate limited")
+}
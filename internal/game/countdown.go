@@ -0,0 +1,125 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// MinPlayersToStart is the minimum number of players needed for a game
+const MinPlayersToStart = 2
+
+// DefaultAutoStartCountdown is the number of seconds between everyone
+// readying up and the game starting on its own
+const DefaultAutoStartCountdown = 10
+
+// autoStartReady reports whether the room should count down to a game.
+// Hosted rooms need every player ready; host-less rooms need at least half.
+func (r *GameRoom) autoStartReady() bool {
+	if r.State != StateWaiting || r.rematch != nil || len(r.Players) < MinPlayersToStart {
+		return false
+	}
+
+	readyCount, readyPeople := 0, 0
+	for _, p := range r.Players {
+		if p.IsReady {
+			readyCount++
+			if !p.IsBot() {
+				readyPeople++
+			}
+		}
+	}
+	// Bots are always ready, so someone real has to be too
+	if readyPeople == 0 {
+		return false
+	}
+
+	if r.Hostless {
+		return readyCount >= MinPlayersToStart && readyCount*2 >= len(r.Players)
+	}
+	return readyCount == len(r.Players)
+}
+
+// evaluateAutoStart starts or cancels the auto-start countdown after the
+// room's ready state changed. Callers must hold the room lock.
+func (r *GameRoom) evaluateAutoStart() {
+	ready := r.autoStartReady()
+
+	switch {
+	case ready && !r.countdownActive:
+		r.startCountdown()
+	case !ready && r.countdownActive:
+		r.cancelCountdown()
+	}
+}
+
+// autoStartGame is what a game started by the countdown starts with: the
+// options the leader last sent with start_game, if any.
+// Callers must hold the room lock.
+func (r *GameRoom) autoStartGame() StartGamePayload {
+	if r.pendingGame != nil {
+		return *r.pendingGame
+	}
+	return StartGamePayload{RoomID: r.ID}
+}
+
+func (r *GameRoom) startCountdown() {
+	if r.AutoStartCountdown <= 0 {
+		log.Printf("Room %s: all players ready, auto-starting", r.ID)
+		r.beginGame(r.autoStartGame())
+		return
+	}
+
+	r.countdownActive = true
+	r.countdownGen++
+	r.countdownEndsAt = time.Now().Add(time.Duration(r.AutoStartCountdown) * time.Second)
+	log.Printf("Room %s: all players ready, starting in %ds", r.ID, r.AutoStartCountdown)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeCountdown,
+		Payload: map[string]interface{}{
+			"seconds_left": r.AutoStartCountdown,
+		},
+	}
+
+	go r.runCountdown(r.countdownGen, r.AutoStartCountdown)
+}
+
+func (r *GameRoom) cancelCountdown() {
+	r.countdownActive = false
+	r.countdownGen++
+	log.Printf("Room %s: auto-start countdown cancelled", r.ID)
+
+	r.Broadcast <- Message{
+		Type:    MsgTypeCountdownCancelled,
+		Payload: map[string]interface{}{},
+	}
+}
+
+// runCountdown ticks once per second until the game starts or the countdown
+// is superseded by a cancellation
+func (r *GameRoom) runCountdown(gen, seconds int) {
+	for left := seconds - 1; left >= 0; left-- {
+		time.Sleep(time.Second)
+
+		r.mu.Lock()
+		if gen != r.countdownGen || !r.countdownActive || r.State != StateWaiting {
+			r.mu.Unlock()
+			return
+		}
+
+		if left == 0 {
+			r.countdownActive = false
+			r.beginGame(r.autoStartGame())
+			r.mu.Unlock()
+			return
+		}
+
+		r.Broadcast <- Message{
+			Type: MsgTypeCountdown,
+			Payload: map[string]interface{}{
+				"seconds_left": left,
+			},
+		}
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestAutoStartKeepsLeaderOptions verifies a game the countdown starts uses
+// the options the leader sent with start_game before everyone was ready
+func TestAutoStartKeepsLeaderOptions(t *testing.T) {
+	h := newGameHarness(t, 1)
+	h.join(harnessPlayer("A", "A1"))
+	h.join(harnessPlayer("B", "B1"))
+	h.ready("A")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady)
+
+	// B isn't ready yet, and someone other than the leader can't pick options
+	h.room.handleGameStart(StartGamePayload{PlayerID: "A", TotalRounds: 3, Powerups: true, RoundDuration: 45})
+	h.room.handleGameStart(StartGamePayload{PlayerID: "B", TotalRounds: 7})
+	h.expect(MsgTypeError, MsgTypeError)
+
+	h.ready("B")
+	h.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	rounds, powerups, seconds, pending := h.room.TotalRounds, h.room.PowerupsEnabled, h.room.roundSeconds, h.room.pendingGame
+	h.room.mu.RUnlock()
+	if rounds != 3 || !powerups || seconds != 45 {
+		t.Errorf("Expected the leader's 3 rounds of 45s with power-ups, got %d rounds of %ds, power-ups %v", rounds, seconds, powerups)
+	}
+	if pending != nil {
+		t.Errorf("Expected the pending options to be used up, got %+v", pending)
+	}
+
+	t.Logf("✓ Auto-started games keep the leader's start_game options")
+}
@@ -0,0 +1,361 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// The daily challenge is DailyRounds solo rounds, each playing a snippet of
+// one of the player's own top tracks and asking which of DailyChoices of
+// them it is
+const (
+	DailyRounds        = 10
+	DailyChoices       = 4
+	DailyRoundDuration = 20 * time.Second
+	DailyDateLayout    = "2006-01-02"
+)
+
+var (
+	// ErrDailyPlayed is returned when starting a challenge already finished today
+	ErrDailyPlayed = errors.New("you've already played today's challenge, come back tomorrow")
+	// ErrNoDailyChallenge is returned when guessing without a challenge in progress
+	ErrNoDailyChallenge = errors.New("no daily challenge in progress, start one first")
+	// ErrDailyNotEnoughTracks is returned when a player's library is too small
+	ErrDailyNotEnoughTracks = fmt.Errorf("the daily challenge needs at least %d top tracks with previews", DailyRounds)
+)
+
+// DailyRound is a daily challenge round as shown to the player
+type DailyRound struct {
+	Date          string             `json:"date"`
+	Round         int                `json:"round"`
+	TotalRounds   int                `json:"total_rounds"`
+	AudioPath     string             `json:"audio_path"`
+	PreviewURL    string             `json:"preview_url"`
+	Choices       []ReverseCandidate `json:"choices"`
+	RoundDuration int                `json:"round_duration"` // seconds
+	Score         int                `json:"score"`
+}
+
+// DailyGuessResult is the outcome of a daily challenge guess, with the next
+// round or, after the last one, the player's final standing
+type DailyGuessResult struct {
+	Round    int         `json:"round"`
+	Correct  bool        `json:"correct"`
+	Answer   auth.Track  `json:"answer"`
+	Points   int         `json:"points"`
+	Score    int         `json:"score"`
+	Next     *DailyRound `json:"next,omitempty"`
+	Finished bool        `json:"finished"`
+	Rank     int         `json:"rank,omitempty"` // on the day's leaderboard, once finished
+}
+
+// DailyEntry is a finished daily challenge on the leaderboard
+type DailyEntry struct {
+	Date       string    `json:"date"`
+	PlayerID   string    `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	Score      int       `json:"score"`
+	Correct    int       `json:"correct"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// dailySession is a player's challenge in progress
+type dailySession struct {
+	date       string
+	playerID   string
+	playerName string
+	rounds     []dailyRound
+	current    int // index into rounds
+	startedAt  time.Time
+	score      int
+	correct    int
+}
+
+type dailyRound struct {
+	answer  auth.Track
+	choices []auth.Track
+}
+
+// DailyChallenges runs the solo daily challenge and keeps each day's
+// leaderboard, saved as JSON lines when persisted
+type DailyChallenges struct {
+	mu       sync.Mutex
+	path     string
+	sessions map[string]*dailySession // player ID → challenge in progress
+	entries  map[string][]DailyEntry  // date → finished challenges
+	now      func() time.Time
+}
+
+// NewDailyChallenges creates an in-memory daily challenge
+func NewDailyChallenges() *DailyChallenges {
+	return &DailyChallenges{
+		sessions: make(map[string]*dailySession),
+		entries:  make(map[string][]DailyEntry),
+		now:      time.Now,
+	}
+}
+
+// Persist saves finished challenges to daily.jsonl in dir, loading earlier ones
+func (d *DailyChallenges) Persist(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create daily challenge dir: %w", err)
+	}
+	path := filepath.Join(dir, "daily.jsonl")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.path = path
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open daily results: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry DailyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("Skipping unreadable daily result: %v", err)
+			continue
+		}
+		d.entries[entry.Date] = append(d.entries[entry.Date], entry)
+	}
+	return scanner.Err()
+}
+
+// Today is the current challenge date, in UTC
+func (d *DailyChallenges) Today() string {
+	return d.now().UTC().Format(DailyDateLayout)
+}
+
+// DailySeed is the day's seed; every player's challenge that day derives from it
+func DailySeed(date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("roulettify-daily-" + date))
+	return int64(h.Sum64())
+}
+
+// Start begins (or resumes) today's challenge for the player, drawn from
+// their top tracks with the day's seed so it can't be rerolled
+func (d *DailyChallenges) Start(player *auth.Player) (*DailyRound, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	date := d.Today()
+	if d.finished(date, player.ID) {
+		return nil, ErrDailyPlayed
+	}
+	if session, ok := d.sessions[player.ID]; ok && session.date == date {
+		return session.round(), nil
+	}
+
+	rounds, err := planDailyRounds(date, player.ID, player.TopTracks)
+	if err != nil {
+		return nil, err
+	}
+	session := &dailySession{
+		date:       date,
+		playerID:   player.ID,
+		playerName: player.Name,
+		rounds:     rounds,
+		startedAt:  d.now(),
+	}
+	d.sessions[player.ID] = session
+	log.Printf("Player %s started the %s daily challenge", player.ID, date)
+	return session.round(), nil
+}
+
+// Guess answers the current round. A guess after the round's time is up
+// counts as wrong.
+func (d *DailyChallenges) Guess(playerID, trackID string) (*DailyGuessResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	session, ok := d.sessions[playerID]
+	if !ok || session.date != d.Today() {
+		return nil, ErrNoDailyChallenge
+	}
+
+	round := session.rounds[session.current]
+	elapsed := d.now().Sub(session.startedAt)
+	result := &DailyGuessResult{
+		Round:   session.current + 1,
+		Correct: trackID == round.answer.ID && elapsed <= DailyRoundDuration,
+		Answer:  round.answer,
+	}
+	if result.Correct {
+		result.Points = timeDecayScoring(CorrectGuess{Elapsed: elapsed, RoundLength: DailyRoundDuration})
+		session.score += result.Points
+		session.correct++
+	}
+	result.Score = session.score
+
+	session.current++
+	session.startedAt = d.now()
+	if session.current < len(session.rounds) {
+		result.Next = session.round()
+		return result, nil
+	}
+
+	result.Finished = true
+	result.Rank = d.finish(session)
+	return result, nil
+}
+
+// Leaderboard returns the date's finished challenges, best first
+func (d *DailyChallenges) Leaderboard(date string) []DailyEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entries := append([]DailyEntry{}, d.entries[date]...)
+	sortDailyEntries(entries)
+	return entries
+}
+
+// finish records a completed challenge and returns the player's rank.
+// Callers must hold d.mu.
+func (d *DailyChallenges) finish(session *dailySession) int {
+	delete(d.sessions, session.playerID)
+
+	entry := DailyEntry{
+		Date:       session.date,
+		PlayerID:   session.playerID,
+		PlayerName: session.playerName,
+		Score:      session.score,
+		Correct:    session.correct,
+		FinishedAt: d.now().UTC(),
+	}
+	d.entries[entry.Date] = append(d.entries[entry.Date], entry)
+	log.Printf("Player %s finished the %s daily challenge with %d points", entry.PlayerID, entry.Date, entry.Score)
+
+	if d.path != "" {
+		if err := appendDailyEntry(d.path, entry); err != nil {
+			log.Printf("Failed to save daily result: %v", err)
+		}
+	}
+
+	entries := d.entries[entry.Date]
+	sortDailyEntries(entries)
+	for i, e := range entries {
+		if e.PlayerID == entry.PlayerID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// finished reports whether the player completed the date's challenge.
+// Callers must hold d.mu.
+func (d *DailyChallenges) finished(date, playerID string) bool {
+	for _, entry := range d.entries[date] {
+		if entry.PlayerID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *dailySession) round() *DailyRound {
+	round := s.rounds[s.current]
+	choices := make([]ReverseCandidate, len(round.choices))
+	for i, track := range round.choices {
+		choices[i] = ReverseCandidate{
+			TrackID:  track.ID,
+			Name:     track.Name,
+			Artists:  track.Artists,
+			ImageURL: track.ImageURL,
+		}
+	}
+	return &DailyRound{
+		Date:          s.date,
+		Round:         s.current + 1,
+		TotalRounds:   len(s.rounds),
+		AudioPath:     "/audio/" + round.answer.ID,
+		PreviewURL:    round.answer.PreviewURL,
+		Choices:       choices,
+		RoundDuration: int(DailyRoundDuration.Seconds()),
+		Score:         s.score,
+	}
+}
+
+// planDailyRounds draws the player's rounds for the date: DailyRounds
+// different tracks with previews, each shown among DailyChoices of the
+// player's tracks. The same date and player always get the same rounds.
+func planDailyRounds(date, playerID string, tracks []auth.Track) ([]dailyRound, error) {
+	playable := make([]auth.Track, 0, len(tracks))
+	for _, track := range tracks {
+		if track.PreviewURL != "" {
+			playable = append(playable, track)
+		}
+	}
+	if len(playable) < DailyRounds {
+		return nil, ErrDailyNotEnoughTracks
+	}
+	// Top track order shifts during the day; the draw mustn't
+	sort.Slice(playable, func(i, j int) bool { return playable[i].ID < playable[j].ID })
+
+	h := fnv.New64a()
+	h.Write([]byte(playerID))
+	rng := rand.New(rand.NewSource(DailySeed(date) ^ int64(h.Sum64())))
+
+	order := rng.Perm(len(playable))
+	rounds := make([]dailyRound, DailyRounds)
+	for i := range rounds {
+		answer := order[i]
+		picks := []int{answer}
+		for _, j := range rng.Perm(len(playable)) {
+			if len(picks) == DailyChoices {
+				break
+			}
+			if j != answer {
+				picks = append(picks, j)
+			}
+		}
+		rng.Shuffle(len(picks), func(a, b int) { picks[a], picks[b] = picks[b], picks[a] })
+
+		rounds[i].answer = playable[answer]
+		for _, j := range picks {
+			rounds[i].choices = append(rounds[i].choices, playable[j])
+		}
+	}
+	return rounds, nil
+}
+
+func sortDailyEntries(entries []DailyEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].FinishedAt.Before(entries[j].FinishedAt)
+	})
+}
+
+func appendDailyEntry(path string, entry DailyEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+func dailyPlayer(id string, tracks int) *auth.Player {
+	player := &auth.Player{ID: id, Name: "Player " + id}
+	for i := 0; i < tracks; i++ {
+		player.TopTracks = append(player.TopTracks, auth.Track{
+			ID:         fmt.Sprintf("%s-t%02d", id, i),
+			Name:       fmt.Sprintf("Track %d", i),
+			Rank:       i + 1,
+			PreviewURL: "https://p.scdn.co/" + id,
+		})
+	}
+	return player
+}
+
+// TestDailyChallenge verifies the daily challenge is seeded per day, scored
+// and played once, with results on the day's leaderboard
+func TestDailyChallenge(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	daily := NewDailyChallenges()
+	daily.now = func() time.Time { return now }
+
+	if _, err := daily.Start(dailyPlayer("small", DailyRounds-1)); err != ErrDailyNotEnoughTracks {
+		t.Errorf("Expected a small library to be refused, got %v", err)
+	}
+
+	first, _ := planDailyRounds("2026-03-14", "A", dailyPlayer("A", 20).TopTracks)
+	again, _ := planDailyRounds("2026-03-14", "A", dailyPlayer("A", 20).TopTracks)
+	tomorrow, _ := planDailyRounds("2026-03-15", "A", dailyPlayer("A", 20).TopTracks)
+	if first[0].answer.ID != again[0].answer.ID || len(first[0].choices) != DailyChoices {
+		t.Errorf("Expected the same day to deal the same rounds")
+	}
+	same := 0
+	for i := range first {
+		if first[i].answer.ID == tomorrow[i].answer.ID {
+			same++
+		}
+	}
+	if same == DailyRounds {
+		t.Errorf("Expected a new day to deal different rounds")
+	}
+
+	dailyDir := t.TempDir()
+	if err := daily.Persist(dailyDir); err != nil {
+		t.Fatalf("Failed to persist daily results: %v", err)
+	}
+
+	play := func(player *auth.Player, correct int) *DailyGuessResult {
+		round, err := daily.Start(player)
+		if err != nil {
+			t.Fatalf("Failed to start: %v", err)
+		}
+		if resumed, _ := daily.Start(player); resumed.Round != round.Round {
+			t.Errorf("Expected starting again to resume round %d, got %d", round.Round, resumed.Round)
+		}
+
+		var result *DailyGuessResult
+		for i := 0; i < DailyRounds; i++ {
+			session := daily.sessions[player.ID]
+			guess := session.rounds[session.current].answer.ID
+			if i >= correct {
+				guess = "wrong"
+			}
+			now = now.Add(2 * time.Second)
+			if result, err = daily.Guess(player.ID, guess); err != nil {
+				t.Fatalf("Failed to guess: %v", err)
+			}
+		}
+		return result
+	}
+
+	a := play(dailyPlayer("A", 20), DailyRounds)
+	if !a.Finished || a.Rank != 1 || a.Score != DailyRounds*14 {
+		t.Errorf("Expected a perfect run to finish first with %d, got %+v", DailyRounds*14, a)
+	}
+	if b := play(dailyPlayer("B", 15), 3); b.Rank != 2 || b.Score != 3*14 {
+		t.Errorf("Expected B second with 42, got %+v", b)
+	}
+
+	if _, err := daily.Start(dailyPlayer("A", 20)); err != ErrDailyPlayed {
+		t.Errorf("Expected a second attempt to be refused, got %v", err)
+	}
+	if _, err := daily.Guess("A", "anything"); err != ErrNoDailyChallenge {
+		t.Errorf("Expected guessing without a challenge to fail, got %v", err)
+	}
+
+	reloaded := NewDailyChallenges()
+	if err := reloaded.Persist(dailyDir); err != nil {
+		t.Fatalf("Failed to reload daily results: %v", err)
+	}
+	board := reloaded.Leaderboard("2026-03-14")
+	if len(board) != 2 || board[0].PlayerID != "A" || board[1].Correct != 3 {
+		t.Errorf("Expected the saved leaderboard A then B, got %+v", board)
+	}
+
+	t.Logf("✓ Daily challenges are seeded per day, played once and ranked")
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"slices"
+	"sort"
+)
+
+const (
+	// MaxDeceivingEntries is how many tracks and artists the deceiving
+	// stats list
+	MaxDeceivingEntries = 10
+	// MinDeceptionGuesses is how many guesses a track or artist needs
+	// across saved games before it's ranked, so one unlucky round doesn't
+	// top the list
+	MinDeceptionGuesses = 3
+)
+
+// TrackOutcome is how the rounds a track was played in went in one game
+type TrackOutcome struct {
+	TrackID string   `json:"track_id"`
+	Name    string   `json:"name"`
+	Artists []string `json:"artists"`
+	Rounds  int      `json:"rounds"`
+	Guesses int      `json:"guesses"`
+	Wrong   int      `json:"wrong"`
+}
+
+// DeceivingTrack is a track's wrong guesses across saved games
+type DeceivingTrack struct {
+	TrackID   string   `json:"track_id"`
+	Name      string   `json:"name"`
+	Artists   []string `json:"artists"`
+	Rounds    int      `json:"rounds"`
+	Guesses   int      `json:"guesses"`
+	Wrong     int      `json:"wrong"`
+	WrongRate float64  `json:"wrong_rate"` // wrong guesses per guess, 0-1
+}
+
+// DeceivingArtist is the wrong guesses on an artist's tracks across saved
+// games
+type DeceivingArtist struct {
+	Artist    string  `json:"artist"`
+	Tracks    int     `json:"tracks"`
+	Guesses   int     `json:"guesses"`
+	Wrong     int     `json:"wrong"`
+	WrongRate float64 `json:"wrong_rate"`
+}
+
+// DeceptionStats are the tracks and artists that fool players most, across
+// every saved game
+type DeceptionStats struct {
+	Games   int               `json:"games"` // saved games with track outcomes
+	Tracks  []DeceivingTrack  `json:"tracks"`
+	Artists []DeceivingArtist `json:"artists"`
+}
+
+// tallyOutcome adds a round's guesses to its track's outcome for the game,
+// or takes them back out when sign is -1. Bonus rounds ask about an artist
+// and reverse rounds about a player, so neither says anything about the
+// track. Callers must hold the room lock.
+func (r *GameRoom) tallyOutcome(result *RoundResult, sign int) {
+	if result.Bonus || result.Reverse || result.Track.ID == "" {
+		return
+	}
+
+	outcome, ok := r.outcomes[result.Track.ID]
+	if !ok {
+		outcome = &TrackOutcome{
+			TrackID: result.Track.ID,
+			Name:    result.Track.Name,
+			Artists: slices.Clone(result.Track.Artists),
+		}
+		r.outcomes[result.Track.ID] = outcome
+	}
+	outcome.Rounds += sign
+	for _, guesserID := range result.guessers {
+		outcome.Guesses += sign
+		if !slices.Contains(result.CorrectGuessers, guesserID) {
+			outcome.Wrong += sign
+		}
+	}
+	if outcome.Rounds <= 0 {
+		delete(r.outcomes, result.Track.ID)
+	}
+}
+
+// trackOutcomes returns the game's track outcomes by track ID. Callers must
+// hold the room lock.
+func (r *GameRoom) trackOutcomes() []TrackOutcome {
+	outcomes := make([]TrackOutcome, 0, len(r.outcomes))
+	for _, outcome := range r.outcomes {
+		outcomes = append(outcomes, *outcome)
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].TrackID < outcomes[j].TrackID
+	})
+	return outcomes
+}
+
+// Deceiving ranks the tracks and artists with the most wrong guesses across
+// saved games, then the highest share of wrong guesses
+func (h *HistoryStore) Deceiving() DeceptionStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := DeceptionStats{
+		Tracks:  make([]DeceivingTrack, 0),
+		Artists: make([]DeceivingArtist, 0),
+	}
+	tracks := make(map[string]*DeceivingTrack)
+	artists := make(map[string]*DeceivingArtist)
+	artistTracks := make(map[string]map[string]bool)
+
+	for _, record := range h.records {
+		if len(record.Outcomes) == 0 {
+			continue
+		}
+		stats.Games++
+		for _, outcome := range record.Outcomes {
+			track, ok := tracks[outcome.TrackID]
+			if !ok {
+				track = &DeceivingTrack{TrackID: outcome.TrackID}
+				tracks[outcome.TrackID] = track
+			}
+			// The latest name wins, in case the track was renamed
+			track.Name = outcome.Name
+			track.Artists = outcome.Artists
+			track.Rounds += outcome.Rounds
+			track.Guesses += outcome.Guesses
+			track.Wrong += outcome.Wrong
+
+			for _, name := range outcome.Artists {
+				artist, ok := artists[name]
+				if !ok {
+					artist = &DeceivingArtist{Artist: name}
+					artists[name] = artist
+					artistTracks[name] = make(map[string]bool)
+				}
+				artistTracks[name][outcome.TrackID] = true
+				artist.Guesses += outcome.Guesses
+				artist.Wrong += outcome.Wrong
+			}
+		}
+	}
+
+	for _, track := range tracks {
+		if track.Guesses < MinDeceptionGuesses || track.Wrong == 0 {
+			continue
+		}
+		track.WrongRate = float64(track.Wrong) / float64(track.Guesses)
+		stats.Tracks = append(stats.Tracks, *track)
+	}
+	sort.Slice(stats.Tracks, func(i, j int) bool {
+		a, b := stats.Tracks[i], stats.Tracks[j]
+		if a.Wrong != b.Wrong {
+			return a.Wrong > b.Wrong
+		}
+		if a.WrongRate != b.WrongRate {
+			return a.WrongRate > b.WrongRate
+		}
+		return a.TrackID < b.TrackID
+	})
+	if len(stats.Tracks) > MaxDeceivingEntries {
+		stats.Tracks = stats.Tracks[:MaxDeceivingEntries]
+	}
+
+	for name, artist := range artists {
+		if artist.Guesses < MinDeceptionGuesses || artist.Wrong == 0 {
+			continue
+		}
+		artist.Tracks = len(artistTracks[name])
+		artist.WrongRate = float64(artist.Wrong) / float64(artist.Guesses)
+		stats.Artists = append(stats.Artists, *artist)
+	}
+	sort.Slice(stats.Artists, func(i, j int) bool {
+		a, b := stats.Artists[i], stats.Artists[j]
+		if a.Wrong != b.Wrong {
+			return a.Wrong > b.Wrong
+		}
+		if a.WrongRate != b.WrongRate {
+			return a.WrongRate > b.WrongRate
+		}
+		return a.Artist < b.Artist
+	})
+	if len(stats.Artists) > MaxDeceivingEntries {
+		stats.Artists = stats.Artists[:MaxDeceivingEntries]
+	}
+	return stats
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestDeceivingTracks verifies each game saves its per-track guess outcomes
+// and the history ranks the tracks and artists that fool players most
+func TestDeceivingTracks(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	h := newGameHarness(t, 5)
+	h.room.history = history
+	playOneRoundGame(t, h)
+
+	// Everyone guessed A, so only A's own track would have fooled nobody
+	h.room.mu.RLock()
+	played := h.room.roundResults[1].Track
+	h.room.mu.RUnlock()
+	wrong := 3
+	if played.ID == "t1" {
+		wrong = 0
+	}
+	saved := history.Games("A")
+	if len(saved) != 1 || len(saved[0].Outcomes) != 1 {
+		t.Fatalf("Expected the game's track outcome to be saved, got %+v", saved)
+	}
+	if outcome := saved[0].Outcomes[0]; outcome.TrackID != played.ID || outcome.Rounds != 1 ||
+		outcome.Guesses != 3 || outcome.Wrong != wrong {
+		t.Errorf("Expected 3 guesses, %d wrong, on %s, got %+v", wrong, played.ID, outcome)
+	}
+
+	// Voiding the round takes it back out
+	h.room.mu.Lock()
+	h.room.voidRound(1, "A")
+	outcomes := h.room.trackOutcomes()
+	h.room.mu.Unlock()
+	if len(outcomes) != 0 {
+		t.Errorf("Expected a voided round's outcome to be dropped, got %+v", outcomes)
+	}
+
+	history, _ = NewHistoryStore(t.TempDir())
+	games := []GameRecord{
+		{GameID: "g1", Outcomes: []TrackOutcome{
+			{TrackID: "sneaky", Name: "Sneaky", Artists: []string{"Trickster"}, Rounds: 1, Guesses: 4, Wrong: 3},
+			{TrackID: "obvious", Name: "Obvious", Artists: []string{"Trickster", "Plain"}, Rounds: 1, Guesses: 4, Wrong: 0},
+			{TrackID: "fluke", Name: "Fluke", Artists: []string{"Rare"}, Rounds: 1, Guesses: 2, Wrong: 2},
+		}},
+		{GameID: "g2", Outcomes: []TrackOutcome{
+			{TrackID: "sneaky", Name: "Sneaky", Artists: []string{"Trickster"}, Rounds: 1, Guesses: 3, Wrong: 3},
+			{TrackID: "tricky", Name: "Tricky", Artists: []string{"Plain"}, Rounds: 1, Guesses: 6, Wrong: 6},
+		}},
+		{GameID: "g3"}, // saved before outcomes were
+	}
+	for _, game := range games {
+		history.Append(game)
+	}
+
+	stats := history.Deceiving()
+	if stats.Games != 2 {
+		t.Errorf("Expected 2 games with outcomes, got %d", stats.Games)
+	}
+	if len(stats.Tracks) != 2 {
+		t.Fatalf("Expected obvious and under-guessed tracks left out, got %+v", stats.Tracks)
+	}
+	// Tied on 6 wrong: every guess at tricky was wrong
+	if stats.Tracks[0].TrackID != "tricky" || stats.Tracks[1].TrackID != "sneaky" ||
+		stats.Tracks[1].Rounds != 2 || stats.Tracks[1].Guesses != 7 {
+		t.Errorf("Expected tricky then sneaky, got %+v", stats.Tracks)
+	}
+	// Also tied on 6 wrong, out of fewer guesses for Plain
+	if len(stats.Artists) != 2 || stats.Artists[0].Artist != "Plain" || stats.Artists[1].Artist != "Trickster" ||
+		stats.Artists[1].Wrong != 6 || stats.Artists[1].Tracks != 2 || stats.Artists[1].Guesses != 11 {
+		t.Errorf("Expected Plain then Trickster, got %+v", stats.Artists)
+	}
+
+	t.Logf("✓ Track outcomes are saved per game and ranked by wrong guesses")
+}
@@ -0,0 +1,262 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownConnection is returned for a diagnostics report on a connection
+// that never had debugging on, or one old enough to have been dropped
+var ErrUnknownConnection = errors.New("no diagnostics for that connection")
+
+const (
+	// MaxDiagnosticConnections is how many debug connections are kept; the
+	// oldest are dropped first, so reports stay readable after a player leaves
+	MaxDiagnosticConnections = 500
+	// MaxDebugTimings is how many client timings one report may carry
+	MaxDebugTimings = 100
+
+	// sentWindow is how many sent messages are remembered per connection to
+	// match client timings against
+	sentWindow = 200
+	// recentSamples is how many matched timings a report lists
+	recentSamples = 20
+)
+
+// DebugTiming is a client's report on one message it received
+type DebugTiming struct {
+	CorrelationID string  `json:"correlation_id"`
+	ReceivedAt    int64   `json:"received_at"` // client clock, unix milliseconds
+	HandledMs     float64 `json:"handled_ms"`  // how long the client took to apply it
+}
+
+// DebugReportPayload carries a debug client's timings for recent messages
+type DebugReportPayload struct {
+	Timings []DebugTiming `json:"timings"`
+}
+
+// DiagnosticsReport summarises one debug connection: what the server sent,
+// how long it took to arrive and how long the client took to handle it
+type DiagnosticsReport struct {
+	ConnectionID string                        `json:"connection_id"`
+	PlayerID     string                        `json:"player_id"`
+	RoomID       string                        `json:"room_id"`
+	OpenedAt     time.Time                     `json:"opened_at"`
+	RTTMs        float64                       `json:"rtt_ms"` // smoothed ping round trip
+	Quality      ConnectionQuality             `json:"quality,omitempty"`
+	MessagesSent int                           `json:"messages_sent"`
+	Reports      int                           `json:"reports"`   // client timings received
+	Unmatched    int                           `json:"unmatched"` // timings for messages no longer remembered
+	ByType       map[MessageType]TimingSummary `json:"by_type"`
+	Recent       []TimingSample                `json:"recent"` // latest matched timings, oldest first
+	GeneratedAt  time.Time                     `json:"generated_at"`
+}
+
+// TimingSummary aggregates the timings of one message type. Delivery is the
+// client's receive time minus the server's send time, so it includes any
+// offset between their clocks: compare it across messages rather than
+// reading it as a latency.
+type TimingSummary struct {
+	Count         int     `json:"count"`
+	AvgDeliveryMs float64 `json:"avg_delivery_ms"`
+	MaxDeliveryMs float64 `json:"max_delivery_ms"`
+	AvgHandledMs  float64 `json:"avg_handled_ms"`
+	MaxHandledMs  float64 `json:"max_handled_ms"`
+}
+
+// TimingSample is one client timing matched to the message it reports on
+type TimingSample struct {
+	CorrelationID string      `json:"correlation_id"`
+	Type          MessageType `json:"type"`
+	ServerTime    int64       `json:"server_time"`
+	DeliveryMs    float64     `json:"delivery_ms"`
+	HandledMs     float64     `json:"handled_ms"`
+}
+
+// Diagnostics holds the instance's debug connections by ID. A nil
+// *Diagnostics turns debugging off.
+type Diagnostics struct {
+	mu          sync.Mutex
+	connections map[string]*connectionDiagnostics
+	order       []string // oldest first
+}
+
+// NewDiagnostics creates an empty set of debug connections
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{connections: make(map[string]*connectionDiagnostics)}
+}
+
+// open starts diagnostics for a debug client's connection
+func (d *Diagnostics) open(player *Player, roomID string) *connectionDiagnostics {
+	if d == nil {
+		return nil
+	}
+
+	conn := &connectionDiagnostics{
+		id:       uuid.NewString(),
+		player:   player,
+		roomID:   roomID,
+		openedAt: time.Now(),
+		sent:     make(map[string]sentMessage),
+		byType:   make(map[MessageType]*timingTotals),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connections[conn.id] = conn
+	d.order = append(d.order, conn.id)
+	if len(d.order) > MaxDiagnosticConnections {
+		delete(d.connections, d.order[0])
+		d.order = d.order[1:]
+	}
+	return conn
+}
+
+// Report returns the diagnostics report for a debug connection
+func (d *Diagnostics) Report(connectionID string) (*DiagnosticsReport, error) {
+	if d == nil {
+		return nil, ErrUnknownConnection
+	}
+	d.mu.Lock()
+	conn, ok := d.connections[connectionID]
+	d.mu.Unlock()
+	if !ok {
+		return nil, ErrUnknownConnection
+	}
+	return conn.report(), nil
+}
+
+// connectionDiagnostics records one debug connection's traffic. Messages are
+// written while the room holds only its read lock, so it has its own lock.
+type connectionDiagnostics struct {
+	mu        sync.Mutex
+	id        string
+	player    *Player
+	roomID    string
+	openedAt  time.Time
+	sentCount int
+	sent      map[string]sentMessage // by correlation ID
+	sentOrder []string
+	byType    map[MessageType]*timingTotals
+	reports   int
+	unmatched int
+	recent    []TimingSample
+}
+
+type sentMessage struct {
+	msgType    MessageType
+	serverTime int64
+}
+
+type timingTotals struct {
+	count                   int
+	delivery, handled       float64
+	maxDelivery, maxHandled float64
+}
+
+// stamp gives a message going to the debug client a correlation ID and the
+// server's send time, and remembers it to match the client's timings against
+func (c *connectionDiagnostics) stamp(msg Message) Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sentCount++
+	msg.CorrelationID = fmt.Sprintf("%s-%d", c.id[:8], c.sentCount)
+	msg.ServerTime = time.Now().UnixMilli()
+
+	c.sent[msg.CorrelationID] = sentMessage{msgType: msg.Type, serverTime: msg.ServerTime}
+	c.sentOrder = append(c.sentOrder, msg.CorrelationID)
+	if len(c.sentOrder) > sentWindow {
+		delete(c.sent, c.sentOrder[0])
+		c.sentOrder = c.sentOrder[1:]
+	}
+	return msg
+}
+
+// record matches a client's timings to the messages they report on
+func (c *connectionDiagnostics) record(timings []DebugTiming) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, timing := range timings {
+		c.reports++
+		sent, ok := c.sent[timing.CorrelationID]
+		if !ok {
+			c.unmatched++
+			continue
+		}
+
+		sample := TimingSample{
+			CorrelationID: timing.CorrelationID,
+			Type:          sent.msgType,
+			ServerTime:    sent.serverTime,
+			DeliveryMs:    float64(timing.ReceivedAt - sent.serverTime),
+			HandledMs:     max(timing.HandledMs, 0),
+		}
+		totals := c.byType[sent.msgType]
+		if totals == nil {
+			totals = &timingTotals{}
+			c.byType[sent.msgType] = totals
+		}
+		totals.count++
+		totals.delivery += sample.DeliveryMs
+		totals.handled += sample.HandledMs
+		totals.maxDelivery = max(totals.maxDelivery, sample.DeliveryMs)
+		totals.maxHandled = max(totals.maxHandled, sample.HandledMs)
+
+		c.recent = append(c.recent, sample)
+		if len(c.recent) > recentSamples {
+			c.recent = c.recent[1:]
+		}
+	}
+}
+
+func (c *connectionDiagnostics) report() *DiagnosticsReport {
+	c.player.connStats.mu.Lock()
+	rtt, quality := c.player.connStats.rtt, c.player.connStats.quality
+	c.player.connStats.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &DiagnosticsReport{
+		ConnectionID: c.id,
+		PlayerID:     c.player.ID,
+		RoomID:       c.roomID,
+		OpenedAt:     c.openedAt,
+		RTTMs:        float64(rtt.Microseconds()) / 1000,
+		Quality:      quality,
+		MessagesSent: c.sentCount,
+		Reports:      c.reports,
+		Unmatched:    c.unmatched,
+		ByType:       make(map[MessageType]TimingSummary, len(c.byType)),
+		Recent:       append([]TimingSample{}, c.recent...),
+		GeneratedAt:  time.Now(),
+	}
+	for msgType, totals := range c.byType {
+		report.ByType[msgType] = TimingSummary{
+			Count:         totals.count,
+			AvgDeliveryMs: totals.delivery / float64(totals.count),
+			MaxDeliveryMs: totals.maxDelivery,
+			AvgHandledMs:  totals.handled / float64(totals.count),
+			MaxHandledMs:  totals.maxHandled,
+		}
+	}
+	return report
+}
+
+// RecordDebugReport adds a debug client's timings to its connection's
+// diagnostics. Clients without the debug capability are ignored.
+func (p *Player) RecordDebugReport(timings []DebugTiming) {
+	if p.debug == nil {
+		return
+	}
+	if len(timings) > MaxDebugTimings {
+		timings = timings[:MaxDebugTimings]
+	}
+	p.debug.record(timings)
+}
@@ -0,0 +1,48 @@
+package game
+
+import "testing"
+
+// TestDebugDiagnostics verifies debug clients get a diagnostics connection,
+// stamped messages and a report aggregating their timings
+func TestDebugDiagnostics(t *testing.T) {
+	h := newGameHarness(t, 1)
+	h.room.diagnostics = NewDiagnostics()
+
+	debugger := harnessPlayer("A", "a1")
+	debugger.Capabilities.Debug = true
+	h.join(debugger)
+	h.join(harnessPlayer("B", "b1"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+	if debugger.debug == nil || h.room.Players["B"].debug != nil {
+		t.Fatal("Expected only the debug client to get diagnostics")
+	}
+
+	first := debugger.debug.stamp(Message{Type: MsgTypeRoundStarted})
+	second := debugger.debug.stamp(Message{Type: MsgTypeRoundStarted})
+	if first.CorrelationID == "" || first.CorrelationID == second.CorrelationID || first.ServerTime == 0 {
+		t.Fatalf("Expected distinct correlation IDs and a server time, got %+v and %+v", first, second)
+	}
+
+	debugger.RecordDebugReport([]DebugTiming{
+		{CorrelationID: first.CorrelationID, ReceivedAt: first.ServerTime + 40, HandledMs: 10},
+		{CorrelationID: second.CorrelationID, ReceivedAt: second.ServerTime + 80, HandledMs: 30},
+		{CorrelationID: "made-up", ReceivedAt: 1},
+	})
+
+	report, err := h.room.diagnostics.Report(debugger.debug.id)
+	if err != nil {
+		t.Fatalf("Expected a report, got %v", err)
+	}
+	summary := report.ByType[MsgTypeRoundStarted]
+	if report.MessagesSent != 2 || report.Reports != 3 || report.Unmatched != 1 || len(report.Recent) != 2 {
+		t.Errorf("Unexpected report totals: %+v", report)
+	}
+	if summary.Count != 2 || summary.AvgDeliveryMs != 60 || summary.MaxDeliveryMs != 80 || summary.AvgHandledMs != 20 {
+		t.Errorf("Unexpected round_started timings: %+v", summary)
+	}
+	if _, err := h.room.diagnostics.Report("unknown"); err != ErrUnknownConnection {
+		t.Errorf("Expected an unknown connection to have no report, got %v", err)
+	}
+
+	t.Logf("✓ Debug clients' timings are aggregated per connection")
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"sort"
+
+	"roulettify/internal/auth"
+)
+
+// RoundOrder selects how a game's tracks are ordered across its rounds
+type RoundOrder string
+
+const (
+	// RoundOrderRandom picks each round's track as the round starts
+	RoundOrderRandom RoundOrder = "random"
+	// RoundOrderRamp picks every round's track when the game starts and plays
+	// them easiest first, so the deep cuts come at the end
+	RoundOrderRamp RoundOrder = "ramp"
+)
+
+// trackDifficulty scores how hard a track is to place, from 0 (a hit that's
+// someone's favourite) to 1 (an obscure track far down everyone's list):
+// half from its Spotify popularity, half from the best rank any player gives it
+func trackDifficulty(track auth.Track, bestRank int) float64 {
+	obscurity := 1 - float64(track.Popularity)/100
+	depth := float64(bestRank-1) / 49
+	return 0.5*min(max(obscurity, 0), 1) + 0.5*min(max(depth, 0), 1)
+}
+
+// planRounds picks the tracks for every round up front and orders them by
+// difficulty when the room ramps up. Endless games have no fixed rounds to
+// plan. Callers must hold the room lock.
+func (r *GameRoom) planRounds() {
+	if r.tutorial != nil {
+		r.planTutorial()
+		return
+	}
+
+	r.roundPlan = nil
+	// Ramps rank difficulty by the players' tracks, so playlists play
+	// shuffled; era rounds pick each round's track from its decade
+	if r.Settings.RoundOrder != RoundOrderRamp || r.Settings.Endless || r.playlist != nil || r.Settings.EraRounds {
+		return
+	}
+
+	picked := make(map[string]bool)
+	for len(r.roundPlan) < r.TotalRounds {
+		track := r.selectTrack()
+		if track == nil {
+			break
+		}
+		r.roundPlan = append(r.roundPlan, *track)
+		r.PlayedTracks[track.ID] = true
+		picked[track.ID] = true
+	}
+	// Planned tracks are only played once their round comes up
+	for trackID := range picked {
+		delete(r.PlayedTracks, trackID)
+	}
+
+	difficulty := make(map[string]float64, len(r.roundPlan))
+	for _, track := range r.roundPlan {
+		difficulty[track.ID] = trackDifficulty(track, r.bestRank(track.ID))
+	}
+	sort.SliceStable(r.roundPlan, func(i, j int) bool {
+		return difficulty[r.roundPlan[i].ID] < difficulty[r.roundPlan[j].ID]
+	})
+}
+
+// nextTrack is the next planned track still owned by a player in the room,
+// or a freshly selected one when there's no plan left. Callers must hold
+// the room lock.
+func (r *GameRoom) nextTrack() *auth.Track {
+	for len(r.roundPlan) > 0 {
+		track := r.roundPlan[0]
+		r.roundPlan = r.roundPlan[1:]
+		if !r.PlayedTracks[track.ID] && (r.playlist != nil || r.bestRank(track.ID) > 0) {
+			return &track
+		}
+	}
+	return r.selectTrack()
+}
+
+// bestRank is the highest position a playing player has the track at in
+// their top tracks, or 0 when nobody in the room has it
+func (r *GameRoom) bestRank(trackID string) int {
+	best := 0
+	for _, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if track.ID == trackID && (best == 0 || track.Rank < best) {
+				best = track.Rank
+			}
+		}
+	}
+	return best
+}
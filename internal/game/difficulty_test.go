@@ -0,0 +1,70 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRampOrdersRoundsByDifficulty verifies ramped games play their planned
+// tracks from easiest to hardest
+func TestRampOrdersRoundsByDifficulty(t *testing.T) {
+	h := newGameHarness(t, 4)
+	h.room.Settings.TotalRounds = 3
+	h.room.Settings.RoundOrder = RoundOrderRamp
+
+	popularity := map[string]int{"t1": 10, "t2": 95, "t3": 60, "t4": 30}
+	a := harnessPlayer("A", "t1", "t2")
+	b := harnessPlayer("B", "t3", "t4")
+	for _, p := range []*Player{a, b} {
+		for i := range p.TopTracks {
+			p.TopTracks[i].Popularity = popularity[p.TopTracks[i].ID]
+		}
+	}
+	h.join(a)
+	h.join(b)
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	played := []string{h.room.CurrentTrack.ID}
+	planned := len(h.room.roundPlan)
+	h.room.mu.RUnlock()
+	if planned != 2 {
+		t.Fatalf("Expected two planned rounds left after the first, got %d", planned)
+	}
+
+	for round := 1; round <= 3; round++ {
+		h.guess("A", "A", time.Second)
+		h.guess("B", "A", time.Second)
+		if round < 3 {
+			h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeRoundStarted)
+			h.room.mu.RLock()
+			played = append(played, h.room.CurrentTrack.ID)
+			h.room.mu.RUnlock()
+		}
+	}
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	difficulty := make(map[string]float64)
+	for _, p := range []*Player{a, b} {
+		for _, track := range p.TopTracks {
+			difficulty[track.ID] = trackDifficulty(track, track.Rank)
+		}
+	}
+	for i := 1; i < len(played); i++ {
+		if difficulty[played[i]] < difficulty[played[i-1]] {
+			t.Errorf("Expected rounds to get harder, got %v", played)
+		}
+	}
+	if played[0] != "t2" {
+		t.Errorf("Expected the hit t2 first, got %v", played)
+	}
+
+	if trackDifficulty(a.TopTracks[1], 1) >= trackDifficulty(a.TopTracks[1], 40) {
+		t.Errorf("Expected deeper cuts to be harder")
+	}
+
+	t.Logf("✓ Ramped games play their tracks from easiest to hardest")
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"slices"
+	"sort"
+)
+
+// GuessShare is how many players picked one candidate in a round, for a bar
+// chart of where the guesses went
+type GuessShare struct {
+	CandidateID string `json:"candidate_id"` // a player, or a track in reverse rounds
+	Name        string `json:"name"`
+	Count       int    `json:"count"`
+	Correct     bool   `json:"correct"`
+}
+
+// guessDistribution counts the round's guesses per candidate: every seated
+// player, or every track offered in a reverse round, including ones nobody
+// picked. Typed title guesses have no candidates to count, so title rounds
+// get none. Callers must hold the room lock.
+func (r *GameRoom) guessDistribution(result *RoundResult) {
+	if r.guessMode() == GuessTitle && r.activeBonus == nil && r.activeReverse == nil {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, guess := range r.Guesses {
+		if r.activeReverse != nil {
+			counts[guess.GuessedTrackID]++
+		} else if guess.GuessedPlayerID != "" {
+			counts[guess.GuessedPlayerID]++
+		}
+	}
+
+	distribution := make([]GuessShare, 0)
+	if r.activeReverse != nil {
+		for _, track := range r.activeReverse.Candidates {
+			distribution = append(distribution, GuessShare{
+				CandidateID: track.ID,
+				Name:        track.Name,
+				Count:       counts[track.ID],
+				Correct:     track.ID == r.activeReverse.Answer.ID,
+			})
+			delete(counts, track.ID)
+		}
+	} else {
+		correct := func(playerID string) bool {
+			if len(result.ValidAnswers) > 0 {
+				return slices.Contains(result.ValidAnswers, playerID)
+			}
+			return playerID == result.WinnerID
+		}
+		for _, playerID := range r.PlayerOrder {
+			player, ok := r.Players[playerID]
+			if !ok || player.IsSpectator {
+				continue
+			}
+			distribution = append(distribution, GuessShare{
+				CandidateID: playerID,
+				Name:        player.Name,
+				Count:       counts[playerID],
+				Correct:     correct(playerID),
+			})
+			delete(counts, playerID)
+		}
+
+		// Candidates who have since left the room or moved to spectating
+		left := make([]string, 0, len(counts))
+		for playerID := range counts {
+			left = append(left, playerID)
+		}
+		sort.Strings(left)
+		for _, playerID := range left {
+			share := GuessShare{CandidateID: playerID, Count: counts[playerID], Correct: correct(playerID)}
+			if player, ok := r.Players[playerID]; ok {
+				share.Name = player.Name
+			}
+			distribution = append(distribution, share)
+		}
+	}
+	result.GuessDistribution = distribution
+}
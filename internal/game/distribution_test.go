@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGuessDistribution verifies round results count the guesses for every
+// seated player, including the ones nobody picked
+func TestGuessDistribution(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "a1"))
+	h.join(harnessPlayer("B", "b1"))
+	h.join(harnessPlayer("C", "c1"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	owner := harnessOwner(h.room.CurrentTrack.ID)
+	h.room.mu.RUnlock()
+	wrong := map[string]string{"A": "B", "B": "C", "C": "A"}[owner]
+
+	h.guess("A", owner, time.Second)
+	h.guess("B", wrong, time.Second)
+	h.guess("C", wrong, time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+
+	distribution := msgs[3].Payload.(*RoundResult).GuessDistribution
+	if len(distribution) != 3 {
+		t.Fatalf("Expected a share for each of the 3 players, got %+v", distribution)
+	}
+	counts := make(map[string]int)
+	for i, share := range distribution {
+		if share.CandidateID != []string{"A", "B", "C"}[i] || share.Name == "" {
+			t.Errorf("Expected shares in seat order with names, got %+v", share)
+		}
+		if share.Correct != (share.CandidateID == owner) {
+			t.Errorf("Expected only %s to be marked correct, got %+v", owner, share)
+		}
+		counts[share.CandidateID] = share.Count
+	}
+	if counts[owner] != 1 || counts[wrong] != 2 || len(counts) != 3 {
+		t.Errorf("Expected 1 guess for %s, 2 for %s and none for the third, got %v", owner, wrong, counts)
+	}
+
+	t.Logf("✓ Round results carry the guess distribution across every candidate")
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"time"
+)
+
+// EmoteType identifies one of the predefined quick messages
+type EmoteType string
+
+const (
+	EmoteGG       EmoteType = "gg"
+	EmoteNice     EmoteType = "nice"
+	EmoteCalledIt EmoteType = "called_it"
+	EmoteNoWay    EmoteType = "no_way"
+	EmoteLaugh    EmoteType = "laugh"
+	EmoteFacepalm EmoteType = "facepalm"
+	EmoteFire     EmoteType = "fire"
+)
+
+// Emotes is the full set of quick messages players can send
+var Emotes = map[EmoteType]bool{
+	EmoteGG:       true,
+	EmoteNice:     true,
+	EmoteCalledIt: true,
+	EmoteNoWay:    true,
+	EmoteLaugh:    true,
+	EmoteFacepalm: true,
+	EmoteFire:     true,
+}
+
+// EmoteCooldown is the minimum time between two emotes from the same player
+const EmoteCooldown = 3 * time.Second
+
+// emoteWindowOpen reports whether the room is at a moment where emotes are
+// allowed: between a round's reveal and the next round, or at game over.
+// Callers must hold the room lock.
+func (r *GameRoom) emoteWindowOpen() bool {
+	switch r.State {
+	case StateGameOver:
+		return true
+	case StatePlaying:
+		return r.revealing
+	}
+	return false
+}
+
+func (r *GameRoom) handleEmote(payload EmotePayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists {
+		return
+	}
+
+	if r.Settings.DisableEmotes || !r.Flags.Enabled(FeatureEmotes) {
+		r.sendError(player.ID, "Emotes are disabled in this room")
+		return
+	}
+
+	if !Emotes[payload.Emote] {
+		r.sendError(player.ID, "Unknown emote")
+		return
+	}
+
+	if !r.emoteWindowOpen() {
+		r.sendError(player.ID, "Emotes can only be sent after a reveal or at game over")
+		return
+	}
+
+	// Throttled emotes are dropped quietly so spamming the button is harmless
+	if time.Since(player.lastEmoteAt) < EmoteCooldown {
+		return
+	}
+	player.lastEmoteAt = time.Now()
+
+	r.Broadcast <- Message{
+		Type: MsgTypeEmote,
+		Payload: map[string]interface{}{
+			"player_id": player.ID,
+			"emote":     payload.Emote,
+		},
+	}
+}
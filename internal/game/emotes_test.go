@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestEmoteWindowAndThrottle verifies emotes are only relayed after reveals and are throttled per player
+func TestEmoteWindowAndThrottle(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.State = StatePlaying
+
+	// Mid-round emotes are rejected
+	room.handleEmote(EmotePayload{PlayerID: "A", Emote: EmoteGG})
+	if len(room.Broadcast) != 0 {
+		t.Fatalf("Expected no emote broadcast mid-round, got %d messages", len(room.Broadcast))
+	}
+
+	room.revealing = true
+	room.handleEmote(EmotePayload{PlayerID: "A", Emote: EmoteGG})
+	room.handleEmote(EmotePayload{PlayerID: "A", Emote: EmoteFire})
+	if len(room.Broadcast) != 1 {
+		t.Fatalf("Expected exactly one emote within the cooldown, got %d", len(room.Broadcast))
+	}
+	if msg := <-room.Broadcast; msg.Type != MsgTypeEmote {
+		t.Errorf("Expected emote broadcast, got %s", msg.Type)
+	}
+
+	room.Settings.DisableEmotes = true
+	room.Players["A"].lastEmoteAt = room.Players["A"].lastEmoteAt.Add(-EmoteCooldown)
+	room.handleEmote(EmotePayload{PlayerID: "A", Emote: EmoteNice})
+	if len(room.Broadcast) != 0 {
+		t.Errorf("Expected no emote broadcast when emotes are disabled")
+	}
+
+	t.Logf("✓ Emotes respect the reveal window, cooldown and room setting")
+}
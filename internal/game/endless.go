@@ -0,0 +1,168 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EndlessCheckpointInterval is how many rounds pass between score checkpoints
+const EndlessCheckpointInterval = 5
+
+// endlessCheckpointMaxAge bounds how old a checkpoint can be and still be resumed
+const endlessCheckpointMaxAge = 24 * time.Hour
+
+// EndlessCheckpoint is the on-disk snapshot of a running endless game
+type EndlessCheckpoint struct {
+	GameID       string           `json:"game_id"`
+	RoomID       string           `json:"room_id"`
+	Round        int              `json:"round"`
+	Scores       map[string]int   `json:"scores"`
+	ScoreHistory map[string][]int `json:"score_history"`
+	SavedAt      time.Time        `json:"saved_at"`
+}
+
+func (r *GameRoom) handleVoteEnd(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if player, exists := r.Players[playerID]; !exists || player.IsSpectator {
+		return
+	}
+
+	if r.State != StatePlaying || !r.Settings.Endless {
+		r.sendError(playerID, "Voting to end is only available during endless games")
+		return
+	}
+
+	r.EndVotes[playerID] = true
+	// Bots never vote, so only people count towards the majority
+	needed := r.activeHumanCount()/2 + 1
+
+	log.Printf("Room %s: %d/%d votes to end the endless game", r.ID, len(r.EndVotes), needed)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeVoteEndProgress,
+		Payload: map[string]interface{}{
+			"votes":  len(r.EndVotes),
+			"needed": needed,
+		},
+	}
+
+	if len(r.EndVotes) >= needed {
+		r.finishGame()
+	}
+}
+
+// rollingScores sums each player's points over the last RollingWindow rounds
+func (r *GameRoom) rollingScores() map[string]int {
+	window := r.Settings.RollingWindow
+	rolling := make(map[string]int, len(r.ScoreHistory))
+
+	for playerID, history := range r.ScoreHistory {
+		if len(history) == 0 {
+			continue
+		}
+		latest := history[len(history)-1]
+		if len(history) <= window {
+			rolling[playerID] = latest
+			continue
+		}
+		rolling[playerID] = latest - history[len(history)-1-window]
+	}
+
+	return rolling
+}
+
+func (r *GameRoom) checkpointPath() string {
+	name := strings.ReplaceAll(r.ID, " ", "_")
+	return filepath.Join(r.CheckpointDir, name+".endless.json")
+}
+
+// checkpointEndless writes the running scores to disk every few rounds so an
+// endless session can be resumed after a restart
+func (r *GameRoom) checkpointEndless() {
+	if r.CurrentRound%EndlessCheckpointInterval != 0 {
+		return
+	}
+	r.saveEndlessCheckpoint()
+}
+
+// saveEndlessCheckpoint writes the running scores to disk now
+func (r *GameRoom) saveEndlessCheckpoint() {
+	if r.CheckpointDir == "" {
+		return
+	}
+
+	checkpoint := EndlessCheckpoint{
+		GameID:       r.GameID,
+		RoomID:       r.ID,
+		Round:        r.CurrentRound,
+		Scores:       r.Scores,
+		ScoreHistory: r.ScoreHistory,
+		SavedAt:      time.Now(),
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		log.Printf("Room %s: failed to encode checkpoint: %v", r.ID, err)
+		return
+	}
+
+	if err := os.WriteFile(r.checkpointPath(), data, 0o644); err != nil {
+		log.Printf("Room %s: failed to write checkpoint: %v", r.ID, err)
+		return
+	}
+
+	log.Printf("Room %s: endless checkpoint saved at round %d", r.ID, r.CurrentRound)
+}
+
+// resumeEndlessCheckpoint restores scores from a recent checkpoint when an
+// endless game starts. Players not present yet get their score back on join.
+func (r *GameRoom) resumeEndlessCheckpoint() {
+	if r.CheckpointDir == "" {
+		return
+	}
+
+	data, err := os.ReadFile(r.checkpointPath())
+	if err != nil {
+		return
+	}
+
+	var checkpoint EndlessCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		log.Printf("Room %s: ignoring unreadable checkpoint: %v", r.ID, err)
+		return
+	}
+
+	if time.Since(checkpoint.SavedAt) > endlessCheckpointMaxAge {
+		r.clearEndlessCheckpoint()
+		return
+	}
+
+	// A resumed endless game is the same game
+	if checkpoint.GameID != "" {
+		r.GameID = checkpoint.GameID
+	}
+	r.CurrentRound = checkpoint.Round
+	r.ScoreHistory = checkpoint.ScoreHistory
+	r.checkpointScores = checkpoint.Scores
+	for playerID := range r.Players {
+		r.Scores[playerID] = checkpoint.Scores[playerID]
+	}
+
+	log.Printf("Room %s: resumed endless game from round %d", r.ID, checkpoint.Round)
+}
+
+func (r *GameRoom) clearEndlessCheckpoint() {
+	r.checkpointScores = nil
+	if r.CheckpointDir == "" {
+		return
+	}
+	if err := os.Remove(r.checkpointPath()); err != nil && !os.IsNotExist(err) {
+		log.Printf("Room %s: failed to remove checkpoint: %v", r.ID, err)
+	}
+}
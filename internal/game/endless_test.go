@@ -0,0 +1,193 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startEndlessGame seats A, B and C in an endless game and plays up to the
+// first round
+func startEndlessGame(h *gameHarness) {
+	h.room.Settings.Endless = true
+	for _, id := range []string{"A", "B", "C"} {
+		h.join(harnessPlayer(id, id+"1", id+"2", id+"3"))
+		h.expect(MsgTypePlayerJoined)
+	}
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerReady, MsgTypePlayerReady)
+	h.ready("C")
+	h.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+}
+
+// TestEndlessVoteToEnd verifies an endless game ends once a majority of the
+// players vote for it
+func TestEndlessVoteToEnd(t *testing.T) {
+	h := newGameHarness(t, 1)
+
+	h.join(harnessPlayer("A", "A1"))
+	h.expect(MsgTypePlayerJoined)
+	h.room.handleVoteEnd("A")
+	if lastError(h.room.Players["A"]) != "Voting to end is only available during endless games" {
+		t.Errorf("Expected voting outside an endless game to be refused, got %q", lastError(h.room.Players["A"]))
+	}
+	h.room.handlePlayerLeave("A")
+	h.expect(MsgTypePlayerLeft)
+
+	startEndlessGame(h)
+
+	h.room.handleVoteEnd("A")
+	progress := h.expect(MsgTypeVoteEndProgress)[0].Payload.(map[string]interface{})
+	if progress["votes"] != 1 || progress["needed"] != 2 {
+		t.Fatalf("Expected 1 of 2 votes, got %v of %v", progress["votes"], progress["needed"])
+	}
+	h.room.handleVoteEnd("A") // voting twice counts once
+	progress = h.expect(MsgTypeVoteEndProgress)[0].Payload.(map[string]interface{})
+	if progress["votes"] != 1 {
+		t.Errorf("Expected a repeated vote to count once, got %v votes", progress["votes"])
+	}
+	if h.room.State != StatePlaying {
+		t.Fatalf("Expected the game to go on without a majority, got %s", h.room.State)
+	}
+
+	h.room.handleVoteEnd("B")
+	h.expect(MsgTypeVoteEndProgress, MsgTypeGameOver)
+	if h.room.State != StateGameOver {
+		t.Errorf("Expected the game to end, got %s", h.room.State)
+	}
+
+	t.Logf("✓ Endless games end on a majority vote")
+}
+
+// TestEndlessRollingScores verifies the rolling leaderboard only counts the
+// last RollingWindow rounds
+func TestEndlessRollingScores(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Settings.RollingWindow = 3
+	room.ScoreHistory = map[string][]int{
+		"A": {10, 20, 35, 50, 60}, // 40 points over rounds 3-5
+		"B": {5, 15},              // fewer rounds than the window
+		"C": {},
+	}
+
+	expected := map[string]int{"A": 40, "B": 15}
+	if got := room.rollingScores(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected rolling scores %v, got %v", expected, got)
+	}
+
+	// Round results carry the rolling leaderboard
+	h := newGameHarness(t, 1)
+	h.room.Settings.RollingWindow = 1
+	startEndlessGame(h)
+	owner := h.room.CurrentTrack.ID[:1] // each player owns the tracks named after them
+	for _, id := range []string{"A", "B", "C"} {
+		h.guess(id, owner, time.Second)
+	}
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+	result := msgs[3].Payload.(*RoundResult)
+	if !reflect.DeepEqual(result.RollingScores, h.room.Scores) {
+		t.Errorf("Expected round 1's rolling scores to match the scores %v, got %v", h.room.Scores, result.RollingScores)
+	}
+
+	t.Logf("✓ The endless leaderboard rolls over the last %d rounds", room.Settings.RollingWindow)
+}
+
+// TestEndlessCheckpointRestore verifies an endless game's scores are saved
+// every few rounds and picked up again when the room's next endless game starts
+func TestEndlessCheckpointRestore(t *testing.T) {
+	dir := t.TempDir()
+	room := NewGameRoom("harness-room")
+	room.CheckpointDir = dir
+	room.GameID = "game-1"
+	room.Scores = map[string]int{"A": 40, "B": 25, "C": 10}
+	room.ScoreHistory = map[string][]int{"A": {40}, "B": {25}, "C": {10}}
+
+	room.CurrentRound = EndlessCheckpointInterval - 1
+	room.checkpointEndless()
+	if _, err := os.Stat(room.checkpointPath()); !os.IsNotExist(err) {
+		t.Fatalf("Expected no checkpoint between intervals, got %v", err)
+	}
+	room.CurrentRound = EndlessCheckpointInterval
+	room.checkpointEndless()
+
+	// A new room with the same ID resumes the game; C comes back mid-game
+	h := newGameHarness(t, 1)
+	h.room.CheckpointDir = dir
+	h.room.Settings.Endless = true
+	h.join(harnessPlayer("A", "A1", "A2"))
+	h.join(harnessPlayer("B", "B1", "B2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	gameID, round := h.room.GameID, h.room.CurrentRound
+	scores := map[string]int{"A": h.room.Scores["A"], "B": h.room.Scores["B"]}
+	h.room.mu.RUnlock()
+	if gameID != "game-1" || round != EndlessCheckpointInterval+1 {
+		t.Errorf("Expected game-1 to resume at round %d, got %s at round %d", EndlessCheckpointInterval+1, gameID, round)
+	}
+	if scores["A"] != 40 || scores["B"] != 25 {
+		t.Errorf("Expected the checkpointed scores back, got %v", scores)
+	}
+
+	h.join(harnessPlayer("C", "C1"))
+	h.expect(MsgTypePlayerJoined)
+	h.room.mu.RLock()
+	late := h.room.Scores["C"]
+	h.room.mu.RUnlock()
+	if late != 10 {
+		t.Errorf("Expected C to get their checkpointed score back on joining, got %d", late)
+	}
+
+	// C keeps it when they're promoted at the next round
+	h.guess("A", "nobody", time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeRoundStarted)
+	h.room.mu.RLock()
+	promoted, spectating := h.room.Scores["C"], h.room.Players["C"].IsSpectator
+	h.room.mu.RUnlock()
+	if spectating || promoted != 10 {
+		t.Errorf("Expected C promoted with their checkpointed score, got %d (spectating=%v)", promoted, spectating)
+	}
+
+	// Finishing the game clears the checkpoint
+	h.room.mu.Lock()
+	h.room.finishGame()
+	h.room.mu.Unlock()
+	if _, err := os.Stat(room.checkpointPath()); !os.IsNotExist(err) {
+		t.Errorf("Expected the checkpoint to be removed when the game ends, got %v", err)
+	}
+
+	t.Logf("✓ Endless checkpoints save every %d rounds and resume", EndlessCheckpointInterval)
+}
+
+// TestEndlessCheckpointExpires verifies a checkpoint older than a day is discarded
+func TestEndlessCheckpointExpires(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.CheckpointDir = t.TempDir()
+
+	data, _ := json.Marshal(EndlessCheckpoint{
+		GameID:  "stale",
+		Round:   20,
+		Scores:  map[string]int{"A": 100},
+		SavedAt: time.Now().Add(-endlessCheckpointMaxAge - time.Hour),
+	})
+	if err := os.WriteFile(room.checkpointPath(), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	room.resumeEndlessCheckpoint()
+	if room.CurrentRound != 0 || room.GameID == "stale" {
+		t.Errorf("Expected the stale checkpoint to be ignored, resumed %s at round %d", room.GameID, room.CurrentRound)
+	}
+	if _, err := os.Stat(room.checkpointPath()); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale checkpoint to be removed, got %v", err)
+	}
+
+	t.Logf("✓ Day-old endless checkpoints are discarded")
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"roulettify/internal/auth"
+)
+
+// Bounds for a room's decade filter
+const (
+	MinDecade = 1900
+	MaxDecade = 2090
+)
+
+// decadeOf is the decade a year falls in, e.g. 2010 for 2014
+func decadeOf(year int) int {
+	return year / 10 * 10
+}
+
+// eraLabel names a decade the way players say it, e.g. "2010s"
+func eraLabel(decade int) string {
+	return fmt.Sprintf("%ds", decade)
+}
+
+// validateDecade checks a decade filter: off, or the first year of a decade
+func validateDecade(decade int) error {
+	if decade == 0 {
+		return nil
+	}
+	if decade < MinDecade || decade > MaxDecade || decade%10 != 0 {
+		return fmt.Errorf("decade must be the first year of a decade between %d and %d, e.g. 2010", MinDecade, MaxDecade)
+	}
+	return nil
+}
+
+// matchesDecade reports whether a track was released in the given decade.
+// Tracks without a release year never match a decade.
+func matchesDecade(track auth.Track, decade int) bool {
+	return decade == 0 || (track.Year > 0 && decadeOf(track.Year) == decade)
+}
+
+// poolDecades lists the decades the active players' tracks come from, oldest
+// first. Callers must hold the room lock.
+func (r *GameRoom) poolDecades() []int {
+	seen := make(map[int]bool)
+	var decades []int
+	for _, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if track.Year == 0 || seen[decadeOf(track.Year)] {
+				continue
+			}
+			seen[decadeOf(track.Year)] = true
+			decades = append(decades, decadeOf(track.Year))
+		}
+	}
+	sort.Ints(decades)
+	return decades
+}
+
+// selectEraTrack themes the round on the next decade in the pool after the
+// last round's, skipping decades with no tracks left, and picks a track from
+// it. When no decade has tracks left the round plays without a theme.
+// Callers must hold the room lock.
+func (r *GameRoom) selectEraTrack() *auth.Track {
+	defer func() { r.eraFilter = 0 }()
+
+	decades := r.poolDecades()
+	start := sort.SearchInts(decades, r.lastEra+1)
+	for i := range decades {
+		decade := decades[(start+i)%len(decades)]
+		r.eraFilter = decade
+		if track := r.selectTrack(); track != nil {
+			r.roundEra, r.lastEra = decade, decade
+			return track
+		}
+	}
+
+	return r.selectTrack()
+}
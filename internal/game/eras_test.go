@@ -0,0 +1,76 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// eraPlayer is a player whose tracks, "<id>1", "<id>2"..., were released in
+// the given years
+func eraPlayer(id string, years ...int) *Player {
+	player := newTestPlayer(id)
+	for i, year := range years {
+		trackID := fmt.Sprintf("%s%d", id, i+1)
+		player.TopTracks = append(player.TopTracks, auth.Track{ID: trackID, Name: "Track " + trackID, Rank: i + 1, Year: year})
+	}
+	return player
+}
+
+// TestEraRoundsRotateDecades verifies era rounds announce a decade, play a
+// track from it and move on to the next decade each round
+func TestEraRoundsRotateDecades(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 4
+	h.room.Settings.EraRounds = true
+
+	h.join(eraPlayer("A", 1994, 2012))
+	h.join(eraPlayer("B", 2003, 2019))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted)
+
+	for _, want := range []string{"1990s", "2000s", "2010s", "2010s"} {
+		msgs := h.expect(MsgTypeRoundStarted)
+		h.room.mu.RLock()
+		year := h.room.CurrentTrack.Year
+		h.room.mu.RUnlock()
+		if era := msgs[0].Payload.(map[string]interface{})["era"]; era != want || eraLabel(decadeOf(year)) != want {
+			t.Errorf("Expected a %s round, got era %v with a track from %d", want, era, year)
+		}
+
+		h.guess("A", "nobody", time.Second)
+		h.guess("B", "nobody", time.Second)
+		h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+	}
+	h.expect(MsgTypeGameOver)
+
+	t.Logf("✓ Era rounds rotate through the pool's decades")
+}
+
+// TestDecadeFilter verifies a decade filter keeps only that decade's tracks
+// and that decades are validated
+func TestDecadeFilter(t *testing.T) {
+	settings := DefaultRoomSettings()
+	settings.Decade = 2015
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected a decade that isn't a decade's first year to be rejected")
+	}
+
+	room := NewGameRoom("test-room")
+	room.Settings.Decade = 2000
+	room.TotalRounds = 1
+	room.Players["A"] = eraPlayer("A", 1999, 2004, 0)
+
+	if matching := room.filteredPoolSize(); matching != 1 {
+		t.Errorf("Expected 1 track from the 2000s, got %d", matching)
+	}
+	room.applyTrackFilter()
+	if track := room.selectTrack(); track == nil || track.ID != "A2" {
+		t.Errorf("Expected only the 2004 track to be selectable, got %v", track)
+	}
+
+	t.Logf("✓ Decade filters keep one decade's tracks")
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"time"
+)
+
+// MaxRoomEvents is how many recent events each room keeps
+const MaxRoomEvents = 100
+
+// Room event types kept in the history
+const (
+	EventPlayerJoined  = "player_joined"
+	EventPlayerLeft    = "player_left"
+	EventGameStarted   = "game_started"
+	EventRoundComplete = "round_complete"
+	EventGameOver      = "game_over"
+	EventRoundVoided   = "round_voided"
+)
+
+// RoomEvent is an entry in a room's recent history
+type RoomEvent struct {
+	Seq      int64                  `json:"seq"`
+	Type     string                 `json:"type"`
+	At       time.Time              `json:"at"`
+	PlayerID string                 `json:"player_id,omitempty"`
+	GameID   string                 `json:"game_id,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// eventLog is a fixed-size ring buffer of room events
+type eventLog struct {
+	events []RoomEvent
+	next   int   // index the next event is written to once full
+	seq    int64 // sequence number of the last event
+}
+
+func (l *eventLog) add(event RoomEvent) {
+	l.seq++
+	event.Seq = l.seq
+	event.At = time.Now()
+
+	if len(l.events) < MaxRoomEvents {
+		l.events = append(l.events, event)
+		return
+	}
+	l.events[l.next] = event
+	l.next = (l.next + 1) % MaxRoomEvents
+}
+
+// since returns events newer than seq, oldest first
+func (l *eventLog) since(seq int64) []RoomEvent {
+	ordered := make([]RoomEvent, 0, len(l.events))
+	ordered = append(ordered, l.events[l.next:]...)
+	ordered = append(ordered, l.events[:l.next]...)
+
+	for i, event := range ordered {
+		if event.Seq > seq {
+			return ordered[i:]
+		}
+	}
+	return []RoomEvent{}
+}
+
+// recordEvent adds an event to the room's history. Callers must hold the room lock.
+func (r *GameRoom) recordEvent(eventType, playerID string, data map[string]interface{}) {
+	r.events.add(RoomEvent{
+		Type:     eventType,
+		PlayerID: playerID,
+		GameID:   r.currentGameID(),
+		Data:     data,
+	})
+}
+
+// Events returns the room's recent events with a sequence number above since
+func (r *GameRoom) Events(since int64) []RoomEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.events.since(since)
+}
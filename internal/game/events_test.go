@@ -0,0 +1,29 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestRoomEventRingBuffer verifies the event history keeps the newest events in order and supports since
+func TestRoomEventRingBuffer(t *testing.T) {
+	room := NewGameRoom("test-room")
+
+	for i := 0; i < MaxRoomEvents+5; i++ {
+		room.recordEvent(EventPlayerJoined, "A", nil)
+	}
+
+	events := room.Events(0)
+	if len(events) != MaxRoomEvents {
+		t.Fatalf("Expected %d events, got %d", MaxRoomEvents, len(events))
+	}
+	if events[0].Seq != 6 || events[len(events)-1].Seq != MaxRoomEvents+5 {
+		t.Errorf("Expected events 6..%d oldest first, got %d..%d", MaxRoomEvents+5, events[0].Seq, events[len(events)-1].Seq)
+	}
+
+	newer := room.Events(MaxRoomEvents + 3)
+	if len(newer) != 2 {
+		t.Errorf("Expected 2 events after seq %d, got %d", MaxRoomEvents+3, len(newer))
+	}
+
+	t.Logf("✓ Room event history keeps the latest events")
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"log"
+)
+
+// MaxExcludedTracks is how many of their own top tracks a player can hide
+const MaxExcludedTracks = 5
+
+func (r *GameRoom) handleExcludeTracks(payload ExcludeTracksPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists {
+		return
+	}
+
+	if r.State == StatePlaying {
+		r.sendError(player.ID, "Hidden tracks can only be changed between games")
+		return
+	}
+
+	if len(payload.TrackIDs) > MaxExcludedTracks {
+		r.sendError(player.ID, "You can hide at most 5 tracks")
+		return
+	}
+
+	owned := make(map[string]bool, len(player.TopTracks))
+	for _, track := range player.TopTracks {
+		owned[track.ID] = true
+	}
+
+	excluded := make(map[string]bool, len(payload.TrackIDs))
+	for _, trackID := range payload.TrackIDs {
+		if !owned[trackID] {
+			r.sendError(player.ID, "You can only hide tracks from your own top tracks")
+			return
+		}
+		excluded[trackID] = true
+	}
+
+	// Replaces any previous selection; only the count is ever logged or sent
+	player.ExcludedTracks = excluded
+	if len(excluded) > 0 {
+		r.hiddenTracks[player.ID] = excluded
+	} else {
+		delete(r.hiddenTracks, player.ID)
+	}
+	log.Printf("Player %s hid %d tracks in room %s", player.Name, len(excluded), r.ID)
+
+	r.sendToPlayer(player.ID, Message{
+		Type: MsgTypeExclusionsUpdated,
+		Payload: map[string]interface{}{
+			"track_ids": payload.TrackIDs,
+			"max":       MaxExcludedTracks,
+		},
+	})
+}
+
+// restoreHiddenTracks hides the tracks a rejoining player hid earlier in this
+// room, as long as they're still among their top tracks. Hidden tracks stay
+// with the room, so they aren't carried into other rooms.
+// Callers must hold the room lock.
+func (r *GameRoom) restoreHiddenTracks(player *Player) {
+	hidden := r.hiddenTracks[player.ID]
+	if len(hidden) == 0 {
+		return
+	}
+
+	player.ExcludedTracks = make(map[string]bool, len(hidden))
+	for _, track := range player.TopTracks {
+		if hidden[track.ID] {
+			player.ExcludedTracks[track.ID] = true
+		}
+	}
+}
+
+// excludedTracks collects every track hidden by a player in the room.
+// A hidden track is never played, even if someone else also has it.
+// Callers must hold the room lock.
+func (r *GameRoom) excludedTracks() map[string]bool {
+	excluded := make(map[string]bool)
+	for _, player := range r.Players {
+		for trackID := range player.ExcludedTracks {
+			excluded[trackID] = true
+		}
+	}
+	return excluded
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func (h *gameHarness) exclude(playerID string, trackIDs ...string) {
+	h.room.handleExcludeTracks(ExcludeTracksPayload{PlayerID: playerID, TrackIDs: trackIDs})
+}
+
+// TestHiddenTrackNeverPicked verifies a hidden track is never played, even
+// when another player has it too
+func TestHiddenTrackNeverPicked(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = harnessPlayer("A", "t1", "t2", "t3")
+	room.Players["B"] = harnessPlayer("B", "t1", "t4")
+	room.Players["A"].ExcludedTracks = map[string]bool{"t1": true}
+
+	for seed := int64(0); seed < 50; seed++ {
+		room.pickIndex = rand.New(rand.NewSource(seed)).Intn
+		if track := room.selectTrack(); track == nil || track.ID == "t1" {
+			t.Fatalf("Seed %d: expected a track other than the hidden one, got %v", seed, track)
+		}
+	}
+
+	t.Logf("✓ Hidden tracks are never picked")
+}
+
+// TestExcludeTracksValidation verifies players can only hide up to 5 of their
+// own tracks, and only between games
+func TestExcludeTracksValidation(t *testing.T) {
+	h := newGameHarness(t, 1)
+	h.join(harnessPlayer("A", "t1", "t2", "t3", "t4", "t5", "t6"))
+	h.join(harnessPlayer("B", "t7", "t8"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+	a := h.room.Players["A"]
+
+	h.exclude("A", "t1", "t2", "t3", "t4", "t5", "t6")
+	if lastError(a) != "You can hide at most 5 tracks" {
+		t.Errorf("Expected more than 5 tracks to be refused, got %q", lastError(a))
+	}
+	h.exclude("A", "t1", "t7")
+	if lastError(a) != "You can only hide tracks from your own top tracks" {
+		t.Errorf("Expected someone else's track to be refused, got %q", lastError(a))
+	}
+	if len(a.ExcludedTracks) != 0 {
+		t.Fatalf("Expected refused lists to hide nothing, got %v", a.ExcludedTracks)
+	}
+
+	h.exclude("A", "t1", "t2")
+	last := a.replay.entries[len(a.replay.entries)-1]
+	if last.Type != MsgTypeExclusionsUpdated {
+		t.Fatalf("Expected the list to be acknowledged, got %s", last.Type)
+	}
+	if !reflect.DeepEqual(a.ExcludedTracks, map[string]bool{"t1": true, "t2": true}) {
+		t.Errorf("Expected t1 and t2 to be hidden, got %v", a.ExcludedTracks)
+	}
+	h.expectQuiet(50 * time.Millisecond)
+
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	h.exclude("A", "t3")
+	if lastError(a) != "Hidden tracks can only be changed between games" {
+		t.Errorf("Expected changes mid-game to be refused, got %q", lastError(a))
+	}
+	h.room.mu.RLock()
+	hidden := a.ExcludedTracks
+	h.room.mu.RUnlock()
+	if !reflect.DeepEqual(hidden, map[string]bool{"t1": true, "t2": true}) {
+		t.Errorf("Expected the list to stay the same mid-game, got %v", hidden)
+	}
+
+	t.Logf("✓ Hidden track lists are validated")
+}
+
+// TestHiddenTracksKeptOnRejoin verifies a player who leaves and rejoins the
+// room keeps their hidden tracks, but not in other rooms
+func TestHiddenTracksKeptOnRejoin(t *testing.T) {
+	h := newGameHarness(t, 1)
+	h.join(harnessPlayer("A", "t1", "t2", "t3"))
+	h.expect(MsgTypePlayerJoined)
+	h.exclude("A", "t1", "t2")
+
+	h.room.handlePlayerLeave("A")
+	h.expect(MsgTypePlayerLeft)
+
+	// Their top tracks changed in the meantime: t2 is no longer among them
+	h.join(harnessPlayer("A", "t1", "t3", "t4"))
+	h.expect(MsgTypePlayerJoined)
+	if hidden := h.room.Players["A"].ExcludedTracks; !reflect.DeepEqual(hidden, map[string]bool{"t1": true}) {
+		t.Errorf("Expected t1 to stay hidden after rejoining, got %v", hidden)
+	}
+
+	other := newGameHarness(t, 1)
+	other.join(harnessPlayer("A", "t1", "t3", "t4"))
+	other.expect(MsgTypePlayerJoined)
+	if hidden := other.room.Players["A"].ExcludedTracks; len(hidden) != 0 {
+		t.Errorf("Expected nothing hidden in another room, got %v", hidden)
+	}
+
+	t.Logf("✓ Hidden tracks last as long as the room")
+}
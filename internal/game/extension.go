@@ -0,0 +1,63 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// RoundExtension is the grace period a slow round gets when its timer runs
+// out with fewer than half the players having guessed
+const RoundExtension = 10 * time.Second
+
+// roundTimeUp ends a round whose timer ran out, unless it's a slow round that
+// gets a grace period first
+func (r *GameRoom) roundTimeUp() {
+	r.mu.RLock()
+	paused := r.paused != nil
+	r.mu.RUnlock()
+	// Resuming restarts the timer with the time that was left
+	if paused || r.extendRound() {
+		return
+	}
+	r.endRound()
+}
+
+// extendRound gives the round RoundExtension more when slow-round extensions
+// are on, the round hasn't been extended yet and fewer than half the players
+// have guessed, e.g. because previews were slow to load
+func (r *GameRoom) extendRound() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.Settings.ExtendSlowRounds || r.State != StatePlaying || r.revealing || r.roundExtension > 0 {
+		return false
+	}
+	needed := r.guessersNeeded()
+	if needed == 0 || 2*len(r.Guesses) >= needed {
+		return false
+	}
+
+	r.roundExtension = RoundExtension
+	r.RoundTimer = time.AfterFunc(RoundExtension, func() {
+		r.endRound()
+	})
+	log.Printf("Round %d in room %s extended: %d/%d guesses at the buzzer", r.CurrentRound, r.ID, len(r.Guesses), needed)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoundExtended,
+		Payload: map[string]interface{}{
+			"round":         r.CurrentRound,
+			"extra_seconds": int(RoundExtension.Seconds()),
+			"round_ends_at": r.roundEndsAt(),
+			"guesses":       len(r.Guesses),
+			"needed":        needed,
+		},
+	}
+	return true
+}
+
+// roundEndsAt is when the current round's timer runs out, counting any
+// extension. Callers must hold the room lock.
+func (r *GameRoom) roundEndsAt() time.Time {
+	return r.RoundStartTime.Add(r.currentRoundDuration() + r.roundExtension)
+}
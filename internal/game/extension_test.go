@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSlowRoundsGetOneExtension verifies a round with under half the guesses
+// in is extended once when its timer runs out, and that busier rounds end on
+// time
+func TestSlowRoundsGetOneExtension(t *testing.T) {
+	h := newGameHarness(t, 4)
+	h.room.Settings.TotalRounds = 2
+	h.room.Settings.ExtendSlowRounds = true
+
+	h.join(harnessPlayer("A", "a1", "a2"))
+	h.join(harnessPlayer("B", "b1", "b2"))
+	h.join(harnessPlayer("C", "c1", "c2"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	// One guess of three: the buzzer extends the round
+	h.guess("A", "nobody", time.Second)
+	h.room.roundTimeUp()
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeRoundExtended)
+	extended := msgs[1].Payload.(map[string]interface{})
+	if extended["extra_seconds"] != int(RoundExtension.Seconds()) || extended["guesses"] != 1 || extended["needed"] != 3 {
+		t.Errorf("Unexpected extension: %v", extended)
+	}
+	h.room.mu.RLock()
+	endsAt := h.room.roundEndsAt()
+	wantEndsAt := h.room.RoundStartTime.Add(h.room.currentRoundDuration() + RoundExtension)
+	h.room.mu.RUnlock()
+	if !endsAt.Equal(wantEndsAt) {
+		t.Errorf("Expected the round to end %v later, ends at %v", RoundExtension, endsAt)
+	}
+
+	// Only once per round
+	h.room.roundTimeUp()
+	h.expect(MsgTypeRoundComplete, MsgTypeRoundStarted)
+
+	// Two guesses of three is enough to end on time
+	h.guess("A", "nobody", time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.room.roundTimeUp()
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	t.Logf("✓ Slow rounds get one grace period")
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrNewGamesDisabled is returned when the server isn't starting games,
+	// e.g. while it drains for a deploy
+	ErrNewGamesDisabled = errors.New("server isn't starting new games right now, try again shortly")
+	// ErrRoomCreationDisabled is returned when room creation is switched off
+	ErrRoomCreationDisabled = errors.New("server isn't creating rooms right now, try an existing room")
+	// ErrUnknownFlag is returned when setting a flag the server doesn't have
+	ErrUnknownFlag = errors.New("unknown feature flag")
+)
+
+// Feature names an instance-wide switch operators can flip at runtime
+type Feature string
+
+const (
+	FeatureRoomCreation Feature = "room_creation"
+	FeatureNewGames     Feature = "new_games"
+	FeatureChat         Feature = "chat"
+	FeatureEmotes       Feature = "emotes"
+)
+
+// FeatureFlags holds the instance's runtime switches; every feature starts
+// enabled. A nil *FeatureFlags has everything enabled.
+type FeatureFlags struct {
+	mu       sync.RWMutex
+	disabled map[Feature]bool
+}
+
+// NewFeatureFlags creates a set of flags with every feature enabled
+func NewFeatureFlags() *FeatureFlags {
+	return &FeatureFlags{disabled: make(map[Feature]bool)}
+}
+
+// Enabled reports whether a feature is switched on
+func (f *FeatureFlags) Enabled(feature Feature) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return !f.disabled[feature]
+}
+
+// Set switches a feature on or off
+func (f *FeatureFlags) Set(feature Feature, enabled bool) error {
+	switch feature {
+	case FeatureRoomCreation, FeatureNewGames, FeatureChat, FeatureEmotes:
+	default:
+		return ErrUnknownFlag
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disabled[feature] = !enabled
+	return nil
+}
+
+// All reports every feature and whether it's enabled
+func (f *FeatureFlags) All() map[Feature]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return map[Feature]bool{
+		FeatureRoomCreation: !f.disabled[FeatureRoomCreation],
+		FeatureNewGames:     !f.disabled[FeatureNewGames],
+		FeatureChat:         !f.disabled[FeatureChat],
+		FeatureEmotes:       !f.disabled[FeatureEmotes],
+	}
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"log"
+	"math"
+)
+
+// Bounds for a player's handicap, the multiplier on the points they score
+const (
+	MinHandicap = 0.5
+	MaxHandicap = 2.0
+)
+
+// SetHandicapPayload asks for a player's points to be scaled, e.g. 1.25 to
+// help a newcomer keep up
+type SetHandicapPayload struct {
+	PlayerID   string  `json:"player_id"`
+	TargetID   string  `json:"target_id"`
+	Multiplier float64 `json:"multiplier"` // 1 removes the handicap
+}
+
+func (r *GameRoom) handleSetHandicap(payload SetHandicapPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can set handicaps")
+		return
+	}
+	target, ok := r.Players[payload.TargetID]
+	if !ok {
+		r.sendError(payload.PlayerID, "Player not found")
+		return
+	}
+	if payload.Multiplier < MinHandicap || payload.Multiplier > MaxHandicap {
+		r.sendError(payload.PlayerID, "Handicaps must be between 0.5x and 2x")
+		return
+	}
+
+	// Kept to two decimals so what players see is what's applied
+	target.Handicap = math.Round(payload.Multiplier*100) / 100
+	if target.Handicap == 1 {
+		target.Handicap = 0
+	}
+	log.Printf("Room %s: %s's handicap set to %.2fx", r.ID, target.Name, payload.Multiplier)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"players": r.getPlayerInfoList(),
+		},
+	}
+}
+
+// handicapped scales the points a player earned by their handicap, rounded
+// to the nearest point. Callers must hold the room lock.
+func (r *GameRoom) handicapped(playerID string, points int) int {
+	player, ok := r.Players[playerID]
+	if !ok || player.Handicap == 0 {
+		return points
+	}
+	return int(math.Round(float64(points) * player.Handicap))
+}
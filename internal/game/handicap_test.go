@@ -0,0 +1,45 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHandicapScalesPoints verifies only the leader sets handicaps, that
+// they're shown in the player list and that they scale the points scored
+func TestHandicapScalesPoints(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t1"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	h.room.handleSetHandicap(SetHandicapPayload{PlayerID: "B", TargetID: "B", Multiplier: 2})
+	h.room.handleSetHandicap(SetHandicapPayload{PlayerID: "A", TargetID: "B", Multiplier: 3})
+	if h.room.Players["B"].Handicap != 0 {
+		t.Fatalf("Expected B's own request and an out-of-range one to be refused")
+	}
+
+	h.room.handleSetHandicap(SetHandicapPayload{PlayerID: "A", TargetID: "B", Multiplier: 1.25})
+	msgs := h.expect(MsgTypeRoomUpdated)
+	players := msgs[0].Payload.(map[string]interface{})["players"].([]PlayerInfo)
+	if players[0].Handicap != 0 || players[1].Handicap != 1.25 {
+		t.Errorf("Expected B's 1.25x handicap in the player list, got %+v", players)
+	}
+
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", 2*time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	// A is first for 15; B's 10 for second place becomes 12.5, rounded up
+	result := msgs[2].Payload.(*RoundResult)
+	if result.PointsAwarded["A"] != 15 || result.PointsAwarded["B"] != 13 {
+		t.Errorf("Expected A=15 and B=13, got %v", result.PointsAwarded)
+	}
+
+	t.Logf("✓ Leaders set visible handicaps that scale points")
+}
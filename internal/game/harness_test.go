@@ -0,0 +1,231 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// harnessTimeout bounds how long the harness waits for an expected broadcast
+const harnessTimeout = 2 * time.Second
+
+// gameHarness drives a real GameRoom through a scripted game. It calls the
+// room's handlers the way Run would and records every broadcast in order,
+// standing in for Run's fan-out to connections. Track selection is seeded so
+// a script replays identically.
+type gameHarness struct {
+	t    *testing.T
+	room *GameRoom
+	seen []Message
+}
+
+func newGameHarness(t *testing.T, seed int64) *gameHarness {
+	t.Helper()
+
+	room := NewGameRoom("harness-room")
+	room.AutoStartCountdown = 0
+	room.Settings.IntermissionLength = 0
+	room.pickIndex = rand.New(rand.NewSource(seed)).Intn
+
+	h := &gameHarness{t: t, room: room}
+	t.Cleanup(h.stop)
+	return h
+}
+
+// stop shuts the room down so its pending rounds and timers do nothing
+// once the test is over. Broadcasts are drained meanwhile, since a timer may
+// be blocked on the full channel while holding the room lock.
+func (h *gameHarness) stop() {
+	stopped := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-h.room.Broadcast:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+	h.room.shutdown("test finished")
+	close(stopped)
+}
+
+// harnessPlayer creates a player whose top tracks are the given IDs in rank order
+func harnessPlayer(id string, trackIDs ...string) *Player {
+	player := newTestPlayer(id)
+	for i, trackID := range trackIDs {
+		player.TopTracks = append(player.TopTracks, auth.Track{
+			ID:   trackID,
+			Name: "Track " + trackID,
+			Rank: i + 1,
+		})
+	}
+	return player
+}
+
+func (h *gameHarness) join(player *Player) {
+	h.room.handlePlayerJoin(player)
+}
+
+func (h *gameHarness) ready(playerID string) {
+	h.room.handlePlayerReady(ReadyPayload{PlayerID: playerID, IsReady: true})
+}
+
+// guess submits a guess stamped at the given offset from the round's start
+func (h *gameHarness) guess(playerID, guessedID string, after time.Duration) {
+	h.room.mu.RLock()
+	startedAt := h.room.RoundStartTime
+	h.room.mu.RUnlock()
+
+	h.room.handleGuess(Guess{
+		PlayerID:        playerID,
+		GuessedPlayerID: guessedID,
+		Timestamp:       startedAt.Add(after),
+	})
+}
+
+// disconnect drops a player's connection as the server does when a socket closes
+func (h *gameHarness) disconnect(playerID string) {
+	h.room.handleDisconnect(DisconnectPayload{PlayerID: playerID})
+}
+
+// expect asserts the next broadcasts are exactly the given types, in order,
+// and returns them
+func (h *gameHarness) expect(types ...MessageType) []Message {
+	h.t.Helper()
+
+	got := make([]Message, 0, len(types))
+	for i, want := range types {
+		select {
+		case msg := <-h.room.Broadcast:
+			h.seen = append(h.seen, msg)
+			got = append(got, msg)
+			if msg.Type != want {
+				h.t.Fatalf("Broadcast %d: expected %s, got %s (sequence so far: %v)", i, want, msg.Type, h.sequence())
+			}
+		case <-time.After(harnessTimeout):
+			h.t.Fatalf("Broadcast %d: timed out waiting for %s (sequence so far: %v)", i, want, h.sequence())
+		}
+	}
+	return got
+}
+
+// expectQuiet asserts nothing else is broadcast within the given window
+func (h *gameHarness) expectQuiet(window time.Duration) {
+	h.t.Helper()
+
+	select {
+	case msg := <-h.room.Broadcast:
+		h.t.Fatalf("Expected no more broadcasts, got %s", msg.Type)
+	case <-time.After(window):
+	}
+}
+
+// sequence returns the types of every broadcast seen so far
+func (h *gameHarness) sequence() []MessageType {
+	types := make([]MessageType, len(h.seen))
+	for i, msg := range h.seen {
+		types[i] = msg.Type
+	}
+	return types
+}
+
+// playScriptedGame plays a two-round game in which A always owns the track:
+// A guesses right first in round 1, B guesses right first in round 2.
+// It returns the tracks played and the final scores.
+func playScriptedGame(t *testing.T, seed int64) ([]string, map[string]int) {
+	h := newGameHarness(t, seed)
+	h.room.Settings.TotalRounds = 2
+
+	h.join(harnessPlayer("A", "t1", "t2", "t3"))
+	h.expect(MsgTypePlayerJoined)
+	h.join(harnessPlayer("B", "t4", "t5", "t6", "t1", "t2", "t3"))
+	h.expect(MsgTypePlayerJoined)
+
+	h.ready("A")
+	h.expect(MsgTypePlayerReady)
+	h.ready("B")
+	played := make([]string, 0, 2)
+	msgs := h.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	played = append(played, msgs[2].Payload.(map[string]interface{})["track"].(auth.Track).ID)
+
+	h.guess("A", "A", 1*time.Second)
+	h.guess("B", "A", 2*time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+	first := msgs[2].Payload.(*RoundResult)
+	if first.WinnerID != "A" || first.PointsAwarded["A"] != 15 || first.PointsAwarded["B"] != 10 {
+		t.Fatalf("Unexpected round 1 result: winner %s, points %v", first.WinnerID, first.PointsAwarded)
+	}
+	if first.GuessDurations["B"] != 2 {
+		t.Errorf("Expected B's guess duration to come from the scripted timestamp, got %v", first.GuessDurations["B"])
+	}
+
+	msgs = h.expect(MsgTypeRoundStarted)
+	played = append(played, msgs[0].Payload.(map[string]interface{})["track"].(auth.Track).ID)
+
+	h.guess("A", "B", 1*time.Second)
+	h.guess("B", "A", 3*time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	over := msgs[3].Payload.(map[string]interface{})
+	if over["winner_id"] != "B" {
+		t.Errorf("Expected B to win, got %v", over["winner_id"])
+	}
+	h.expectQuiet(100 * time.Millisecond)
+
+	scores := over["final_scores"].(map[string]int)
+	return played, map[string]int{"A": scores["A"], "B": scores["B"]}
+}
+
+// TestScriptedGameBroadcastSequence plays a full game and checks every broadcast and the final scores
+func TestScriptedGameBroadcastSequence(t *testing.T) {
+	played, scores := playScriptedGame(t, 42)
+
+	if played[0] == played[1] {
+		t.Errorf("A track was played twice: %v", played)
+	}
+	if scores["A"] != 15 || scores["B"] != 25 {
+		t.Errorf("Expected final scores A=15 B=25, got %v", scores)
+	}
+
+	replayed, replayScores := playScriptedGame(t, 42)
+	if !reflect.DeepEqual(played, replayed) || !reflect.DeepEqual(scores, replayScores) {
+		t.Errorf("Replay diverged: tracks %v vs %v, scores %v vs %v", played, replayed, scores, replayScores)
+	}
+
+	t.Logf("✓ Scripted game produces the exact broadcast sequence and replays identically")
+}
+
+// TestScriptedGameWithDisconnect verifies a disconnect mid-round holds the seat and the round ends on its timer
+func TestScriptedGameWithDisconnect(t *testing.T) {
+	h := newGameHarness(t, 7)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.RoundDuration = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.disconnect("B")
+	h.expect(MsgTypePlayerDisconnected)
+
+	// B can't guess, so the round runs to its timer
+	h.guess("A", "B", 500*time.Millisecond)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	result := msgs[1].Payload.(*RoundResult)
+	if _, ranked := result.AllRankings["B"]; !ranked {
+		t.Error("Expected the disconnected player to keep their seat in the results")
+	}
+	if h.room.Players["B"] == nil || !h.room.Players["B"].Disconnected {
+		t.Error("Expected B's seat to be held while disconnected")
+	}
+	h.room.Players["B"].resumeTimer.Stop()
+
+	t.Logf("✓ Disconnects mid-round hold the seat and the round completes on its timer")
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"roulettify/internal/auth"
+)
+
+// HintKind names a clue about the track revealed partway through a round
+type HintKind string
+
+const (
+	HintAlbumArt     HintKind = "album_art"
+	HintTitleInitial HintKind = "title_initial"
+)
+
+// hintOrder is the order hints are revealed in, one per configured mark
+var hintOrder = []HintKind{HintAlbumArt, HintTitleInitial}
+
+// Hint is a clue revealed during the current round
+type Hint struct {
+	Round int      `json:"round"`
+	Kind  HintKind `json:"kind"`
+	Value string   `json:"value"` // album art URL, or the title's first letter
+}
+
+// validateHintMarks checks the seconds into a round at which hints are
+// revealed: one mark per hint, in increasing order
+func validateHintMarks(marks []int) error {
+	if len(marks) > len(hintOrder) {
+		return fmt.Errorf("at most %d hint marks are allowed", len(hintOrder))
+	}
+	for i, mark := range marks {
+		if mark < 1 || mark >= MaxRoundDuration {
+			return fmt.Errorf("hint marks must be between 1 and %d seconds", MaxRoundDuration-1)
+		}
+		if i > 0 && mark <= marks[i-1] {
+			return fmt.Errorf("hint marks must be in increasing order")
+		}
+	}
+	return nil
+}
+
+// scheduleHints starts the current round's hint timers. Marks at or past the
+// end of the round (e.g. shrunk speed rounds) are skipped, and reverse rounds
+// get no hints since their track is one of the choices on screen. Callers
+// must hold the room lock.
+func (r *GameRoom) scheduleHints() {
+	r.stopHints()
+	r.hints = nil
+	r.startHintTimers()
+}
+
+// startHintTimers schedules the round's hints that are still to come, e.g.
+// again when a paused game resumes. Callers must hold the room lock.
+func (r *GameRoom) startHintTimers() {
+	if r.activeReverse != nil {
+		return
+	}
+
+	round := r.CurrentRound
+	duration := r.currentRoundDuration()
+	elapsed := time.Since(r.RoundStartTime)
+	for i, mark := range r.Settings.HintMarks {
+		at := time.Duration(mark) * time.Second
+		if at >= duration {
+			break
+		}
+		if at <= elapsed {
+			continue
+		}
+		kind := hintOrder[i]
+		r.hintTimers = append(r.hintTimers, time.AfterFunc(at-elapsed, func() {
+			r.revealHint(round, kind)
+		}))
+	}
+}
+
+// stopHints cancels hints still waiting to be revealed. Callers must hold
+// the room lock.
+func (r *GameRoom) stopHints() {
+	for _, timer := range r.hintTimers {
+		timer.Stop()
+	}
+	r.hintTimers = nil
+}
+
+// revealHint broadcasts a hint about the round's track
+func (r *GameRoom) revealHint(round int, kind HintKind) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The round may have ended early, or the game been reset
+	if r.State != StatePlaying || r.CurrentRound != round || r.revealing || r.CurrentTrack == nil {
+		return
+	}
+
+	value := hintValue(kind, *r.CurrentTrack)
+	if value == "" {
+		return
+	}
+
+	hint := Hint{Round: round, Kind: kind, Value: value}
+	r.hints = append(r.hints, hint)
+	log.Printf("Room %s: revealing %s hint for round %d", r.ID, kind, round)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeHint,
+		Payload: map[string]interface{}{
+			"round": hint.Round,
+			"kind":  hint.Kind,
+			"value": hint.Value,
+		},
+	}
+}
+
+// hintValue is what a hint shows about a track, or "" when the track has
+// nothing to show
+func hintValue(kind HintKind, track auth.Track) string {
+	switch kind {
+	case HintAlbumArt:
+		return track.ImageURL
+	case HintTitleInitial:
+		for _, c := range strings.TrimSpace(track.Name) {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				return string(unicode.ToUpper(c))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHintsRevealedDuringRound verifies hints arrive at their marks and stop
+// once the round ends
+func TestHintsRevealedDuringRound(t *testing.T) {
+	h := newGameHarness(t, 5)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.HintMarks = []int{1, 2}
+
+	// Only A owns t1, so the round always plays A's copy with its hint data
+	a := harnessPlayer("A", "t1")
+	a.TopTracks[0].ImageURL = "https://img/t1"
+	a.TopTracks[0].Name = "  (blue) Monday"
+	h.join(a)
+	h.join(harnessPlayer("B"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	msgs := h.expect(MsgTypeHint)
+	hint := msgs[0].Payload.(map[string]interface{})
+	if hint["kind"] != HintAlbumArt || hint["value"] != "https://img/t1" || hint["round"] != 1 {
+		t.Errorf("Expected the album art first, got %v", hint)
+	}
+
+	h.room.mu.RLock()
+	snapshot := h.room.stateSnapshot("A")
+	h.room.mu.RUnlock()
+	if hints := snapshot["hints"].([]Hint); len(hints) != 1 {
+		t.Errorf("Expected the snapshot to list the revealed hint, got %v", hints)
+	}
+
+	msgs = h.expect(MsgTypeHint)
+	if hint := msgs[0].Payload.(map[string]interface{}); hint["kind"] != HintTitleInitial || hint["value"] != "B" {
+		t.Errorf("Expected the title's first letter second, got %v", hint)
+	}
+
+	// A round that ends before its marks reveals nothing more
+	h.room.mu.Lock()
+	h.room.Settings.HintMarks = []int{5}
+	h.room.scheduleHints()
+	h.room.mu.Unlock()
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	h.room.mu.RLock()
+	pending := len(h.room.hintTimers)
+	h.room.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("Expected hint timers to be stopped when the round ended, %d left", pending)
+	}
+
+	settings := DefaultRoomSettings()
+	for _, marks := range [][]int{{0}, {20, 10}, {5, 10, 15}, {MaxRoundDuration}} {
+		settings.HintMarks = marks
+		if settings.Validate() == nil {
+			t.Errorf("Expected hint marks %v to be rejected", marks)
+		}
+	}
+
+	t.Logf("✓ Hints are revealed at their marks and cancelled when the round ends")
+}
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNoHistory is returned by stats endpoints when game history is disabled
+var ErrNoHistory = errors.New("game history is not enabled on this server")
+
+// GameRecord is a finished game as kept in the history
+type GameRecord struct {
+	GameID       string         `json:"game_id"`
+	RoomID       string         `json:"room_id"`
+	FinishedAt   time.Time      `json:"finished_at"`
+	Rounds       int            `json:"rounds"`
+	WinnerID     string         `json:"winner_id"`
+	Players      []RecordPlayer `json:"players"`
+	Taste        []TasteLink    `json:"taste,omitempty"`          // who knew whose taste this game
+	WinningTrack *RecordTrack   `json:"winning_track,omitempty"`  // the round the winner scored most in, for result cards
+	Outcomes     []TrackOutcome `json:"track_outcomes,omitempty"` // guesses and wrong guesses per track
+}
+
+// RecordPlayer is one player's result in a GameRecord
+type RecordPlayer struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Score        int            `json:"score"`
+	Guesses      int            `json:"guesses,omitempty"`       // rounds they guessed in
+	Correct      int            `json:"correct,omitempty"`       // correct guesses
+	GuessSeconds float64        `json:"guess_seconds,omitempty"` // total time taken by correct guesses
+	Giveaways    map[string]int `json:"giveaways,omitempty"`     // artist -> rounds their tracks by them were guessed
+}
+
+// score returns a player's score in the game and whether they played
+func (g GameRecord) score(playerID string) (int, bool) {
+	for _, p := range g.Players {
+		if p.ID == playerID {
+			return p.Score, true
+		}
+	}
+	return 0, false
+}
+
+// name returns the name a player played the game under
+func (g GameRecord) name(playerID string) string {
+	for _, p := range g.Players {
+		if p.ID == playerID {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// HistoryStore keeps finished games as JSON lines in a file, with every
+// record held in memory for stats queries
+type HistoryStore struct {
+	path    string
+	mu      sync.RWMutex
+	records []GameRecord
+}
+
+// NewHistoryStore opens the history in dir, loading any earlier games
+func NewHistoryStore(dir string) (*HistoryStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history dir: %w", err)
+	}
+
+	store := &HistoryStore{path: filepath.Join(dir, "games.jsonl")}
+
+	file, err := os.Open(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record GameRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Printf("Skipping unreadable game record: %v", err)
+			continue
+		}
+		store.records = append(store.records, record)
+	}
+	return store, scanner.Err()
+}
+
+// Append adds a finished game to the history
+func (h *HistoryStore) Append(record GameRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	h.records = append(h.records, record)
+	return nil
+}
+
+// Games returns every game the player took part in, oldest first
+func (h *HistoryStore) Games(playerID string) []GameRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	games := make([]GameRecord, 0)
+	for _, record := range h.records {
+		if _, played := record.score(playerID); played {
+			games = append(games, record)
+		}
+	}
+	return games
+}
+
+// Game returns the saved game with the given ID
+func (h *HistoryStore) Game(gameID string) (GameRecord, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, record := range h.records {
+		if record.GameID != "" && record.GameID == gameID {
+			return record, true
+		}
+	}
+	return GameRecord{}, false
+}
+
+// recordGame adds the finished game to the history, the room's leaderboard
+// and the result sink. Callers must hold the room lock.
+func (r *GameRoom) recordGame(winnerID string) {
+	r.storeGame(winnerID)
+
+	// Practice games against bots don't count towards anyone's stats
+	if r.practice != nil {
+		return
+	}
+	record, ok := r.gameRecord(winnerID)
+	if !ok {
+		return
+	}
+
+	r.updateLeaderboard(record)
+	if r.history == nil {
+		return
+	}
+	if err := r.history.Append(record); err != nil {
+		log.Printf("Room %s: failed to record game: %v", r.ID, err)
+	}
+}
+
+// gameRecord describes the finished game, or reports false when too few
+// players took part for it to count. Callers must hold the room lock.
+func (r *GameRoom) gameRecord(winnerID string) (GameRecord, bool) {
+	record := GameRecord{
+		GameID:       r.GameID,
+		RoomID:       r.ID,
+		FinishedAt:   time.Now().UTC(),
+		Rounds:       r.CurrentRound,
+		WinnerID:     winnerID,
+		Taste:        r.tasteMatrix(),
+		WinningTrack: r.winningTrack(winnerID),
+		Outcomes:     r.trackOutcomes(),
+	}
+	for _, playerID := range r.PlayerOrder {
+		player, ok := r.Players[playerID]
+		// Bots have no account to keep stats for
+		if !ok || player.IsSpectator || player.IsBot() {
+			continue
+		}
+		entry := RecordPlayer{
+			ID:    player.ID,
+			Name:  player.Name,
+			Score: r.Scores[player.ID],
+		}
+		if tally, ok := r.tallies[player.ID]; ok {
+			entry.Guesses = tally.Guesses
+			entry.Correct = tally.Correct
+			entry.GuessSeconds = tally.GuessSeconds
+			entry.Giveaways = maps.Clone(tally.Giveaways)
+		}
+		record.Players = append(record.Players, entry)
+	}
+	return record, len(record.Players) >= MinPlayersToStart
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGameIDsTagEachGame verifies every game in a reused room gets its own ID
+// on messages, events and its saved record
+func TestGameIDsTagEachGame(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	h := newGameHarness(t, 3)
+	h.room.history = history
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.room.broadcastToAll(Message{Type: MsgTypeRoomUpdated})
+	if last := h.room.Players["A"].replay.entries; last[len(last)-1].GameID != "" {
+		t.Errorf("Expected lobby messages to carry no game ID")
+	}
+
+	play := func(start func()) string {
+		start()
+		h.expect(MsgTypeGameStarted, MsgTypeRoundStarted)
+
+		h.room.mu.RLock()
+		gameID := h.room.GameID
+		h.room.mu.RUnlock()
+
+		h.room.broadcastToAll(Message{Type: MsgTypeRoundStarted})
+		entries := h.room.Players["B"].replay.entries
+		if got := entries[len(entries)-1].GameID; got != gameID {
+			t.Errorf("Expected broadcasts to carry game ID %q, got %q", gameID, got)
+		}
+
+		h.guess("A", "A", time.Second)
+		h.guess("B", "A", time.Second)
+		h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+		return gameID
+	}
+
+	first := play(func() {
+		h.ready("A")
+		h.ready("B")
+		h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady)
+	})
+	events := h.room.Events(0)
+	if last := events[len(events)-1]; last.Type != EventGameOver || last.GameID != first {
+		t.Errorf("Expected the game over event to carry game ID %q, got %+v", first, last)
+	}
+
+	// The same room plays again
+	second := play(func() {
+		h.room.mu.Lock()
+		h.room.beginGame(StartGamePayload{RoomID: h.room.ID})
+		h.room.mu.Unlock()
+	})
+	if first == "" || first == second {
+		t.Fatalf("Expected distinct game IDs, got %q and %q", first, second)
+	}
+
+	record, ok := history.Game(second)
+	if !ok || record.GameID != second || record.RoomID != h.room.ID {
+		t.Errorf("Expected the second game to be saved under its ID, got %+v", record)
+	}
+	if _, ok := history.Game(""); ok {
+		t.Errorf("Expected an empty ID to match no game")
+	}
+
+	t.Logf("✓ Each game in a room gets its own ID on messages, events and saved records")
+}
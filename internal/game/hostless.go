@@ -0,0 +1,41 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// HostlessResultsDuration is how long results stay on screen in a host-less
+// room before the next game starts
+const HostlessResultsDuration = 15 * time.Second
+
+// startNextHostlessGame resets a host-less room after the results screen and
+// keeps the rotation going with everyone still present
+func (r *GameRoom) startNextHostlessGame() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != StateGameOver {
+		return
+	}
+
+	r.State = StateWaiting
+	r.CurrentRound = 0
+	r.Scores = make(map[string]int)
+	r.promoteSpectators()
+	for pid, p := range r.Players {
+		r.Scores[pid] = 0
+		p.IsReady = true
+	}
+
+	log.Printf("Room %s: starting next host-less game with %d players", r.ID, len(r.Players))
+
+	r.Broadcast <- Message{
+		Type: MsgTypeGameReset,
+		Payload: map[string]interface{}{
+			"players": r.getPlayerInfoList(),
+		},
+	}
+
+	r.evaluateAutoStart()
+}
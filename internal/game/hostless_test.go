@@ -0,0 +1,77 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestHostlessAutoStart verifies host-less rooms have no leader and start once enough players are ready
+func TestHostlessAutoStart(t *testing.T) {
+	manager := NewRoomManager()
+	room, err := manager.CreateRoom(RoomOptions{Hostless: true})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	room.mu.Lock()
+	room.AutoStartCountdown = 0
+	room.mu.Unlock()
+
+	for _, id := range []string{"A", "B", "C"} {
+		room.handlePlayerJoin(newTestPlayer(id))
+	}
+
+	room.mu.RLock()
+	leaderID := room.LeaderID
+	room.mu.RUnlock()
+	if leaderID != "" {
+		t.Errorf("Host-less room should not have a leader, got %s", leaderID)
+	}
+
+	room.handlePlayerReady(ReadyPayload{PlayerID: "A", IsReady: true})
+	room.mu.RLock()
+	state := room.State
+	room.mu.RUnlock()
+	if state != StateWaiting {
+		t.Errorf("Expected room to keep waiting with 1 ready player, got %s", state)
+	}
+
+	room.handlePlayerReady(ReadyPayload{PlayerID: "B", IsReady: true})
+	room.mu.RLock()
+	state = room.State
+	totalRounds := room.TotalRounds
+	room.mu.RUnlock()
+	if state != StatePlaying {
+		t.Errorf("Expected room to auto-start with 2/3 ready players, got %s", state)
+	}
+	if totalRounds != 10 {
+		t.Errorf("Expected default 10 rounds, got %d", totalRounds)
+	}
+
+	t.Logf("✓ Host-less rooms auto-start with default settings")
+}
+
+// TestAutoStartCountdownCancel verifies the countdown starts when everyone is ready and stops when someone un-readies
+func TestAutoStartCountdownCancel(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.handlePlayerJoin(newTestPlayer("A"))
+	room.handlePlayerJoin(newTestPlayer("B"))
+
+	room.handlePlayerReady(ReadyPayload{PlayerID: "A", IsReady: true})
+	if room.countdownActive {
+		t.Error("Countdown should not start until every player is ready")
+	}
+
+	room.handlePlayerReady(ReadyPayload{PlayerID: "B", IsReady: true})
+	if !room.countdownActive {
+		t.Fatal("Countdown should start once every player is ready")
+	}
+
+	room.handlePlayerReady(ReadyPayload{PlayerID: "B", IsReady: false})
+	if room.countdownActive {
+		t.Error("Countdown should be cancelled when a player un-readies")
+	}
+	if room.State != StateWaiting {
+		t.Errorf("Expected room to still be waiting, got %s", room.State)
+	}
+
+	t.Logf("✓ Auto-start countdown starts and cancels with ready state")
+}
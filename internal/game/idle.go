@@ -0,0 +1,69 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// IdleGameTimeout is how long a game may go without guesses or round
+// progress before it is considered wedged and reset
+const IdleGameTimeout = 5 * time.Minute
+
+// idleCheckInterval is how often the room's watchdog looks for a wedged game
+const idleCheckInterval = 30 * time.Second
+
+// touchActivity records guess or round progress for the idle watchdog.
+// Callers must hold the room lock.
+func (r *GameRoom) touchActivity() {
+	r.lastActivity = time.Now()
+}
+
+// checkIdle ends a game that has made no progress for IdleGameTimeout, e.g.
+// when every connection died or a round failed to start, so the room never
+// stays stuck in StatePlaying
+func (r *GameRoom) checkIdle() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Paused games resume themselves after MaxPauseLength instead
+	if r.State != StatePlaying || r.paused != nil || time.Since(r.lastActivity) < IdleGameTimeout {
+		return
+	}
+
+	log.Printf("Room %s: no activity for %v, resetting game", r.ID, IdleGameTimeout)
+	r.resetGame("idle")
+}
+
+// resetGame abandons the room's game and returns everyone to the lobby,
+// unreadied. Callers must hold the room lock.
+func (r *GameRoom) resetGame(reason string) {
+	if r.RoundTimer != nil {
+		r.RoundTimer.Stop()
+	}
+	r.releaseGameSlot()
+	r.forgetState()
+	r.recorder = nil
+	r.cancelMiniGame()
+	r.clearPause()
+
+	r.State = StateWaiting
+	r.revealing = false
+	r.CurrentRound = 0
+	r.CurrentTrack = nil
+	r.Guesses = make(map[string]Guess)
+	r.EndVotes = make(map[string]bool)
+	r.Scores = make(map[string]int)
+	r.promoteSpectators()
+	for pid, p := range r.Players {
+		r.Scores[pid] = 0
+		p.IsReady = p.IsBot()
+	}
+
+	r.Broadcast <- Message{
+		Type: MsgTypeGameReset,
+		Payload: map[string]interface{}{
+			"reason":  reason,
+			"players": r.getPlayerInfoList(),
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIdleGameReset verifies a game with no progress is ended and the room returns to waiting
+func TestIdleGameReset(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.State = StatePlaying
+	room.CurrentRound = 3
+	room.Scores["A"] = 200
+
+	room.touchActivity()
+	room.checkIdle()
+	if room.State != StatePlaying {
+		t.Fatalf("Active game should not be reset, got %s", room.State)
+	}
+
+	room.lastActivity = time.Now().Add(-IdleGameTimeout - time.Second)
+	room.checkIdle()
+
+	if room.State != StateWaiting {
+		t.Errorf("Expected idle game to reset to waiting, got %s", room.State)
+	}
+	if room.CurrentRound != 0 || room.Scores["A"] != 0 {
+		t.Errorf("Expected round and scores to be cleared, got round %d score %d", room.CurrentRound, room.Scores["A"])
+	}
+
+	msg := <-room.Broadcast
+	if msg.Type != MsgTypeGameReset {
+		t.Errorf("Expected game_reset broadcast, got %s", msg.Type)
+	}
+
+	t.Logf("✓ Idle games reset the room to waiting")
+}
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -195,3 +197,111 @@ func TestConcurrentRoomAccess(t *testing.T) {
 
 	t.Logf("✓ Concurrent room access is thread-safe")
 }
+
+// TestRoomFilter verifies state/open-slot filtering and pagination of the room list
+func TestRoomFilter(t *testing.T) {
+	rooms := []RoomInfo{
+		{ID: "Room 1", PlayerCount: 2, MaxPlayers: 10, State: StateWaiting},
+		{ID: "Room 2", PlayerCount: 10, MaxPlayers: 10, State: StateWaiting},
+		{ID: "Room 3", PlayerCount: 4, MaxPlayers: 10, State: StatePlaying},
+	}
+
+	waiting, total := RoomFilter{State: StateWaiting}.Apply(rooms)
+	if total != 2 || len(waiting) != 2 {
+		t.Errorf("Expected 2 waiting rooms, got %d (total %d)", len(waiting), total)
+	}
+
+	open, total := RoomFilter{OpenOnly: true}.Apply(rooms)
+	if total != 2 || open[0].ID != "Room 1" || open[1].ID != "Room 3" {
+		t.Errorf("Expected Room 1 and Room 3 to have open slots, got %+v", open)
+	}
+
+	page, total := RoomFilter{Page: 2, PageSize: 2}.Apply(rooms)
+	if total != 3 || len(page) != 1 || page[0].ID != "Room 3" {
+		t.Errorf("Expected page 2 to contain only Room 3, got %+v (total %d)", page, total)
+	}
+
+	empty, _ := RoomFilter{Page: 5, PageSize: 2}.Apply(rooms)
+	if len(empty) != 0 {
+		t.Errorf("Expected out-of-range page to be empty, got %d rooms", len(empty))
+	}
+
+	t.Logf("✓ Room filtering and pagination work correctly")
+}
+
+// TestCreateRoomNameValidation verifies room names are trimmed, bounded and screened
+func TestCreateRoomNameValidation(t *testing.T) {
+	manager := NewRoomManager()
+
+	room, err := manager.CreateRoom(RoomOptions{Name: "  Indie heads only ", Description: "Deep cuts welcome"})
+	if err != nil {
+		t.Fatalf("Failed to create named room: %v", err)
+	}
+	if info := room.Info(); info.Name != "Indie heads only" || info.Description != "Deep cuts welcome" {
+		t.Errorf("Expected trimmed name and description in RoomInfo, got %q / %q", info.Name, info.Description)
+	}
+
+	invalid := []RoomOptions{
+		{Name: strings.Repeat("a", MaxRoomNameLength+1)},
+		{Name: "Sh1t music"},
+		{Description: "no\x07bells"},
+	}
+	for _, opts := range invalid {
+		if _, err := manager.CreateRoom(opts); !errors.Is(err, ErrInvalidRoomOptions) {
+			t.Errorf("Expected ErrInvalidRoomOptions for %+v, got %v", opts, err)
+		}
+	}
+
+	if containsProfanity("Scunthorpe classics") {
+		t.Error("Words merely containing a blocked word should be allowed")
+	}
+
+	t.Logf("✓ Room names and descriptions are validated")
+}
+
+// TestRoomRegions verifies rooms carry a region label and the list can be filtered by it
+func TestRoomRegions(t *testing.T) {
+	manager := NewRoomManager()
+	manager.SetRegion("us-east")
+
+	room, err := manager.CreateRoom(RoomOptions{Region: " EU-West "})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	if info := room.Info(); info.Region != "eu-west" || info.CreatedIn != "us-east" {
+		t.Errorf("Expected region eu-west created in us-east, got %q / %q", info.Region, info.CreatedIn)
+	}
+
+	rooms, total := RoomFilter{Region: "us-east"}.Apply(manager.ListRooms())
+	if total != 3 || len(rooms) != 3 {
+		t.Errorf("Expected the 3 persistent rooms in us-east, got %d", total)
+	}
+
+	if _, err := manager.CreateRoom(RoomOptions{Region: "earth/moon"}); !errors.Is(err, ErrInvalidRoomOptions) {
+		t.Errorf("Expected invalid region to be rejected, got %v", err)
+	}
+
+	t.Logf("✓ Rooms are tagged and filtered by region")
+}
+
+// TestRoomRoutingHints verifies rooms report the instance and endpoint that own them
+func TestRoomRoutingHints(t *testing.T) {
+	manager := NewRoomManager()
+	if info := manager.ListRooms()[0]; info.Instance != "" || info.Endpoint != "" {
+		t.Errorf("Single-instance rooms should have no routing hints, got %q / %q", info.Instance, info.Endpoint)
+	}
+
+	manager.SetInstance("node-2", "wss://node-2.example.com/ws")
+	room, err := manager.CreateRoom(RoomOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	for _, info := range []RoomInfo{manager.ListRooms()[0], room.Info()} {
+		if info.Instance != "node-2" || info.Endpoint != "wss://node-2.example.com/ws" {
+			t.Errorf("Expected room %s to be routed to node-2, got %q / %q", info.ID, info.Instance, info.Endpoint)
+		}
+	}
+
+	t.Logf("✓ Rooms carry sticky routing hints")
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// IntermissionSlot is a piece of content the server shows between rounds
+type IntermissionSlot string
+
+const (
+	// IntermissionPodium: the top three of the running standings
+	IntermissionPodium IntermissionSlot = "podium"
+	// IntermissionTrackDetails: the full metadata of the track just revealed
+	IntermissionTrackDetails IntermissionSlot = "track_details"
+	// IntermissionTrivia: a fact about the track just revealed
+	IntermissionTrivia IntermissionSlot = "trivia"
+)
+
+// PodiumSize is how many of the running standings the podium slot shows
+const PodiumSize = 3
+
+var intermissionSlots = map[IntermissionSlot]bool{
+	IntermissionPodium:       true,
+	IntermissionTrackDetails: true,
+	IntermissionTrivia:       true,
+}
+
+// validateIntermissionContent rejects unknown and repeated slots
+func validateIntermissionContent(slots []IntermissionSlot) error {
+	seen := make(map[IntermissionSlot]bool, len(slots))
+	for _, slot := range slots {
+		if !intermissionSlots[slot] {
+			return fmt.Errorf("unknown intermission content %q", slot)
+		}
+		if seen[slot] {
+			return fmt.Errorf("intermission content %q is listed twice", slot)
+		}
+		seen[slot] = true
+	}
+	return nil
+}
+
+// broadcastIntermissionContent sends one intermission_content message per
+// configured slot, in the order the leader listed them, about the round that
+// just ended. Callers must hold the room lock.
+func (r *GameRoom) broadcastIntermissionContent() {
+	result, exists := r.roundResults[r.CurrentRound]
+	if !exists {
+		return
+	}
+
+	for _, slot := range r.Settings.IntermissionContent {
+		var content interface{}
+		switch slot {
+		case IntermissionPodium:
+			standings := r.standings()
+			content = standings[:min(PodiumSize, len(standings))]
+		case IntermissionTrackDetails:
+			content = map[string]interface{}{
+				"track":     result.Track,
+				"winner_id": result.WinnerID,
+				"rankings":  result.AllRankings,
+			}
+		case IntermissionTrivia:
+			fact := r.trackTrivia(result)
+			if fact == "" {
+				continue
+			}
+			content = map[string]interface{}{
+				"track_id": result.Track.ID,
+				"fact":     fact,
+			}
+		}
+
+		r.Broadcast <- Message{
+			Type: MsgTypeIntermissionContent,
+			Payload: map[string]interface{}{
+				"round":   result.Round,
+				"kind":    slot,
+				"content": content,
+			},
+		}
+	}
+}
+
+// trackTrivia picks a fact about a revealed track from what the game knows
+// about it, or "" when there's nothing to say
+func (r *GameRoom) trackTrivia(result *RoundResult) string {
+	track := result.Track
+	var facts []string
+
+	if len(result.AllRankings) > 1 {
+		facts = append(facts, fmt.Sprintf("%d players have %s in their top tracks", len(result.AllRankings), track.Name))
+	}
+	if track.Popularity > 0 {
+		facts = append(facts, fmt.Sprintf("%s has a Spotify popularity of %d out of 100", track.Name, track.Popularity))
+	}
+	if len(track.Artists) > 0 {
+		artist := track.Artists[0]
+		plays := 0
+		for _, earlier := range r.roundResults {
+			if len(earlier.Track.Artists) > 0 && earlier.Track.Artists[0] == artist {
+				plays++
+			}
+		}
+		if plays > 1 {
+			facts = append(facts, fmt.Sprintf("%s has come up %d times this game", artist, plays))
+		}
+	}
+
+	if len(facts) == 0 {
+		return ""
+	}
+	return facts[rand.Intn(len(facts))]
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestIntermissionContent verifies the configured slots are broadcast in order between rounds
+func TestIntermissionContent(t *testing.T) {
+	room := NewGameRoom("test-room")
+	for _, id := range []string{"A", "B", "C", "D"} {
+		room.Players[id] = newTestPlayer(id)
+	}
+	room.Scores = map[string]int{"A": 10, "B": 25, "C": 15, "D": 0}
+	room.State = StatePlaying
+	room.Settings.IntermissionContent = []IntermissionSlot{IntermissionTrivia, IntermissionPodium, IntermissionTrackDetails}
+
+	room.CurrentRound = 1
+	track := auth.Track{ID: "t1", Name: "Song", Artists: []string{"Artist"}, Popularity: 72}
+	room.recordRoundResult(&RoundResult{Round: 1, Track: track, WinnerID: "B", AllRankings: map[string]int{"B": 1}})
+
+	room.broadcastIntermissionContent()
+	var kinds []IntermissionSlot
+	contents := make(map[IntermissionSlot]interface{})
+	for len(room.Broadcast) > 0 {
+		msg := <-room.Broadcast
+		if msg.Type != MsgTypeIntermissionContent {
+			t.Fatalf("Expected intermission_content, got %s", msg.Type)
+		}
+		payload := msg.Payload.(map[string]interface{})
+		kind := payload["kind"].(IntermissionSlot)
+		kinds = append(kinds, kind)
+		contents[kind] = payload["content"]
+	}
+	if len(kinds) != 3 || kinds[0] != IntermissionTrivia || kinds[1] != IntermissionPodium || kinds[2] != IntermissionTrackDetails {
+		t.Fatalf("Expected trivia, podium and track details in order, got %v", kinds)
+	}
+
+	podium := contents[IntermissionPodium].([]Standing)
+	if len(podium) != PodiumSize || podium[0].PlayerID != "B" || podium[1].PlayerID != "C" || podium[2].PlayerID != "A" {
+		t.Errorf("Expected a podium of B, C, A, got %+v", podium)
+	}
+	// Popularity is the only thing worth saying about a one-owner track on its first play
+	fact := contents[IntermissionTrivia].(map[string]interface{})["fact"].(string)
+	if !strings.Contains(fact, "72") {
+		t.Errorf("Expected a popularity fact, got %q", fact)
+	}
+
+	settings := DefaultRoomSettings()
+	settings.IntermissionContent = []IntermissionSlot{IntermissionPodium, IntermissionPodium}
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected repeated intermission content to be rejected")
+	}
+	settings.IntermissionContent = []IntermissionSlot{"weather"}
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected unknown intermission content to be rejected")
+	}
+
+	t.Logf("✓ Intermission content slots are broadcast in the configured order")
+}
@@ -0,0 +1,23 @@
+package game
+
+import "errors"
+
+// ErrCannotInvite is returned when someone who isn't in the room, and doesn't
+// own it, asks for an invite link. Invites skip the room password, so they
+// can only come from someone already let in.
+var ErrCannotInvite = errors.New("only players in the room or its owner can invite people")
+
+// CanInvite reports whether playerID may issue invites to the room: anyone
+// seated in it, its leader included, or its owner
+func (r *GameRoom) CanInvite(playerID string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, seated := r.Players[playerID]; seated {
+		return nil
+	}
+	if r.OwnerID != "" && r.OwnerID == playerID {
+		return nil
+	}
+	return ErrCannotInvite
+}
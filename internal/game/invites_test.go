@@ -0,0 +1,27 @@
+package game
+
+import "testing"
+
+// TestCanInvite verifies only players in the room or its owner can invite
+// people into a password-protected room
+func TestCanInvite(t *testing.T) {
+	h := newGameHarness(t, 1)
+	h.join(harnessPlayer("A"))
+	h.expect(MsgTypePlayerJoined)
+	h.room.Password = "secret"
+	h.room.OwnerID = "owner"
+
+	if err := h.room.CanInvite("stranger"); err != ErrCannotInvite {
+		t.Errorf("Expected a stranger to be refused, got %v", err)
+	}
+	if err := h.room.CanInvite(""); err != ErrCannotInvite {
+		t.Errorf("Expected an empty player ID to be refused, got %v", err)
+	}
+	for _, id := range []string{"A", "owner"} {
+		if err := h.room.CanInvite(id); err != nil {
+			t.Errorf("Expected %s to be allowed to invite, got %v", id, err)
+		}
+	}
+
+	t.Logf("✓ Only players in the room and its owner can mint invites")
+}
@@ -0,0 +1,185 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ErrNoLeaderboard is returned for a room that doesn't keep a leaderboard:
+// only the persistent rooms and claimed rooms do
+var ErrNoLeaderboard = errors.New("only persistent and claimed rooms keep a leaderboard")
+
+// RoomStanding is a player's all-time record in one room
+type RoomStanding struct {
+	Rank         int       `json:"rank"`
+	PlayerID     string    `json:"player_id"`
+	Name         string    `json:"name"`
+	GamesPlayed  int       `json:"games_played"`
+	Wins         int       `json:"wins"`
+	Points       int       `json:"points"`
+	BestScore    int       `json:"best_score"`
+	LastPlayedAt time.Time `json:"last_played_at"`
+}
+
+func (s *RoomStore) leaderboardPath(roomID string) string {
+	return filepath.Join(s.dir, roomID+".leaderboard.json")
+}
+
+// SaveLeaderboard writes a room's all-time leaderboard, replacing any
+// previous version
+func (s *RoomStore) SaveLeaderboard(roomID string, standings []RoomStanding) error {
+	data, err := json.MarshalIndent(standings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write then rename so a crash never leaves a half-written leaderboard
+	tmp := s.leaderboardPath(roomID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.leaderboardPath(roomID))
+}
+
+// LoadLeaderboard returns a room's all-time leaderboard, empty if it has none
+func (s *RoomStore) LoadLeaderboard(roomID string) ([]RoomStanding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.leaderboardPath(roomID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var standings []RoomStanding
+	if err := json.Unmarshal(data, &standings); err != nil {
+		return nil, err
+	}
+	return standings, nil
+}
+
+// keepsLeaderboard reports whether the room tallies an all-time leaderboard.
+// Callers must hold the room lock.
+func (r *GameRoom) keepsLeaderboard() bool {
+	return r.store != nil && (isPersistentRoom(r.ID) || r.OwnerID != "")
+}
+
+// loadLeaderboard reads the room's leaderboard from the store the first time
+// it's needed. Callers must hold the room lock.
+func (r *GameRoom) loadLeaderboard() error {
+	if r.leaderboard != nil {
+		return nil
+	}
+	standings, err := r.store.LoadLeaderboard(r.ID)
+	if err != nil {
+		return err
+	}
+	r.leaderboard = make(map[string]*RoomStanding, len(standings))
+	for _, standing := range standings {
+		r.leaderboard[standing.PlayerID] = &standing
+	}
+	return nil
+}
+
+// updateLeaderboard adds a finished game to the room's all-time leaderboard.
+// Callers must hold the room lock.
+func (r *GameRoom) updateLeaderboard(record GameRecord) {
+	if !r.keepsLeaderboard() {
+		return
+	}
+	if err := r.loadLeaderboard(); err != nil {
+		log.Printf("Room %s: failed to load leaderboard: %v", r.ID, err)
+		return
+	}
+
+	for _, p := range record.Players {
+		standing, ok := r.leaderboard[p.ID]
+		if !ok {
+			standing = &RoomStanding{PlayerID: p.ID}
+			r.leaderboard[p.ID] = standing
+		}
+		standing.Name = p.Name
+		standing.GamesPlayed++
+		standing.Points += p.Score
+		standing.BestScore = max(standing.BestScore, p.Score)
+		standing.LastPlayedAt = record.FinishedAt
+		if record.WinnerID == p.ID {
+			standing.Wins++
+		}
+	}
+	r.saveLeaderboard()
+}
+
+// saveLeaderboard writes the room's leaderboard to the store. Callers must
+// hold the room lock.
+func (r *GameRoom) saveLeaderboard() {
+	if err := r.store.SaveLeaderboard(r.ID, r.rankedLeaderboard()); err != nil {
+		log.Printf("Room %s: failed to save leaderboard: %v", r.ID, err)
+	}
+}
+
+// rankedLeaderboard ranks everyone who has played in the room by wins, then
+// points. Players level on both share a rank. Callers must hold the room lock.
+func (r *GameRoom) rankedLeaderboard() []RoomStanding {
+	standings := make([]RoomStanding, 0, len(r.leaderboard))
+	for _, standing := range r.leaderboard {
+		standings = append(standings, *standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.PlayerID < b.PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if prev := i - 1; prev >= 0 && standings[i].Wins == standings[prev].Wins && standings[i].Points == standings[prev].Points {
+			standings[i].Rank = standings[prev].Rank
+		}
+	}
+	return standings
+}
+
+// Leaderboard returns the room's all-time leaderboard, best first
+func (r *GameRoom) Leaderboard() ([]RoomStanding, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.store == nil {
+		return nil, ErrNoRoomStore
+	}
+	if !r.keepsLeaderboard() {
+		return nil, ErrNoLeaderboard
+	}
+	if err := r.loadLeaderboard(); err != nil {
+		return nil, err
+	}
+	return r.rankedLeaderboard(), nil
+}
+
+// forgetStanding drops a player from the room's leaderboard. Callers must
+// hold the room lock.
+func (r *GameRoom) forgetStanding(playerID string) {
+	if !r.keepsLeaderboard() || r.loadLeaderboard() != nil {
+		return
+	}
+	if _, ok := r.leaderboard[playerID]; !ok {
+		return
+	}
+	delete(r.leaderboard, playerID)
+	r.saveLeaderboard()
+}
@@ -0,0 +1,53 @@
+package game
+
+import "testing"
+
+// TestRoomLeaderboard verifies persistent rooms tally everyone's games across
+// restarts, while rooms nobody owns keep no leaderboard
+func TestRoomLeaderboard(t *testing.T) {
+	store, err := NewRoomStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open room store: %v", err)
+	}
+
+	h := newGameHarness(t, 5)
+	if _, err := h.room.Leaderboard(); err != ErrNoRoomStore {
+		t.Errorf("Expected no leaderboard without a room store, got %v", err)
+	}
+	h.room.store = store
+	if _, err := h.room.Leaderboard(); err != ErrNoLeaderboard {
+		t.Errorf("Expected an unclaimed room to keep no leaderboard, got %v", err)
+	}
+
+	h.room.ID = "Room 1"
+	playOneRoundGame(t, h)
+	// Game over counted the game; count it again as if they'd played twice
+	h.room.mu.Lock()
+	h.room.recordGame(h.room.getWinnerID())
+	h.room.mu.Unlock()
+
+	// A fresh room reads the tally back from the store
+	restarted := NewGameRoom("Room 1")
+	restarted.store = store
+	standings, err := restarted.Leaderboard()
+	if err != nil || len(standings) != 3 {
+		t.Fatalf("Expected 3 players on the leaderboard, got %+v (%v)", standings, err)
+	}
+	top := standings[0]
+	if top.Rank != 1 || top.Wins != 2 || top.GamesPlayed != 2 || top.Points != 2*h.room.Scores[top.PlayerID] || top.LastPlayedAt.IsZero() {
+		t.Errorf("Expected the winner to lead with 2 wins from 2 games, got %+v", top)
+	}
+	if standings[1].Wins != 0 || standings[1].GamesPlayed != 2 {
+		t.Errorf("Expected everyone else to have played 2 games without a win, got %+v", standings[1])
+	}
+
+	restarted.forget("B")
+	standings, _ = restarted.Leaderboard()
+	for _, standing := range standings {
+		if standing.PlayerID == "B" {
+			t.Errorf("Expected B to be dropped from the leaderboard")
+		}
+	}
+
+	t.Logf("✓ Persistent rooms keep an all-time leaderboard across restarts")
+}
@@ -0,0 +1,200 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// RoomStopTimeout bounds how long RoomManager.Shutdown waits for rooms
+const RoomStopTimeout = 5 * time.Second
+
+// runRestartDelay spaces out restarts of a run loop that keeps failing
+const runRestartDelay = time.Second
+
+// EmptyRoomTimeout is how long a dynamic room may sit with no people in it,
+// unclaimed, before it's closed
+const EmptyRoomTimeout = 10 * time.Minute
+
+// reapInterval is how often the manager looks for empty dynamic rooms
+const reapInterval = time.Minute
+
+// RequestStop asks the room's run loop to shut the room down, closing every
+// connection with the given reason. It never blocks; Done is closed once
+// the room has stopped.
+func (r *GameRoom) RequestStop(reason string) {
+	select {
+	case r.Stop <- reason:
+	default:
+		// A stop is already pending
+	}
+}
+
+// Done is closed once the room has been shut down
+func (r *GameRoom) Done() <-chan struct{} {
+	return r.done
+}
+
+// isStopped reports whether the room was shut down on purpose
+func (r *GameRoom) isStopped() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stopped
+}
+
+// shutdown stops the room's timers and closes every connection. Runs on the
+// run loop, which exits straight after.
+func (r *GameRoom) shutdown(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return
+	}
+	r.stopped = true
+
+	// Pending rounds, countdowns and bonus fetches see a waiting room or a
+	// stale generation and do nothing
+	if r.RoundTimer != nil {
+		r.RoundTimer.Stop()
+	}
+	if r.revealTimer != nil {
+		r.revealTimer.Stop()
+	}
+	r.countdownActive = false
+	r.countdownGen++
+	r.bonusGen++
+	r.cancelMiniGame()
+	r.cancelRematch()
+	r.clearPause()
+	r.releaseGameSlot()
+	r.State = StateWaiting
+
+	for _, player := range r.Players {
+		r.presence.release(player.ID, r.ID)
+		if player.resumeTimer != nil {
+			player.resumeTimer.Stop()
+		}
+		if player.Connection != nil {
+			player.Connection.Close(websocket.StatusGoingAway, reason)
+		}
+	}
+	for _, player := range r.queue {
+		if player.Connection != nil {
+			player.Connection.Close(websocket.StatusGoingAway, reason)
+		}
+	}
+	for conn := range r.observers {
+		conn.Close(websocket.StatusGoingAway, reason)
+	}
+
+	r.Players = make(map[string]*Player)
+	r.PlayerOrder = make([]string, 0)
+	r.Scores = make(map[string]int)
+	r.queue = nil
+	r.observers = make(map[*websocket.Conn]string)
+
+	log.Printf("Room %s shut down: %s", r.ID, reason)
+	close(r.done)
+}
+
+// supervise runs a room's loop until the room is stopped. A loop that exits
+// any other way, e.g. after a recovered panic, is restarted so the room
+// keeps serving players.
+func (rm *RoomManager) supervise(room *GameRoom) {
+	for {
+		room.Run()
+
+		if room.isStopped() {
+			return
+		}
+		rm.mu.RLock()
+		registered := rm.rooms[room.ID] == room
+		rm.mu.RUnlock()
+		if !registered {
+			return
+		}
+
+		log.Printf("Room %s: run loop exited unexpectedly, restarting", room.ID)
+		time.Sleep(runRestartDelay)
+	}
+}
+
+// emptyFor reports whether the room has had no people in it, seated,
+// queued or joining, and no owner, for at least timeout. Bots don't count.
+// The first call that finds the room empty starts the clock.
+func (r *GameRoom) emptyFor(now time.Time, timeout time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.humanCount() > 0 || len(r.queue) > 0 || len(r.Join) > 0 || r.OwnerID != "" {
+		r.emptySince = time.Time{}
+		return false
+	}
+	if r.emptySince.IsZero() {
+		r.emptySince = now
+	}
+	return now.Sub(r.emptySince) >= timeout
+}
+
+// runReaper closes empty dynamic rooms for as long as the process runs
+func (rm *RoomManager) runReaper() {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		rm.reapEmptyRooms(now)
+	}
+}
+
+// reapEmptyRooms stops and unregisters every dynamic room that has been
+// empty and unclaimed for EmptyRoomTimeout, so it stops holding a run loop
+// and counting towards MaxRooms. It returns how many rooms were closed.
+func (rm *RoomManager) reapEmptyRooms(now time.Time) int {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	kept := rm.dynamicOrder[:0]
+	reaped := 0
+	for _, roomID := range rm.dynamicOrder {
+		room, ok := rm.rooms[roomID]
+		if ok && !room.emptyFor(now, EmptyRoomTimeout) {
+			kept = append(kept, roomID)
+			continue
+		}
+		if ok {
+			room.RequestStop("Room closed after being empty")
+			delete(rm.rooms, roomID)
+			reaped++
+			log.Printf("Room %s closed after being empty for %v", roomID, EmptyRoomTimeout)
+		}
+	}
+	rm.dynamicOrder = kept
+	return reaped
+}
+
+// Shutdown stops every room, closing players' connections with the given
+// reason, and waits up to RoomStopTimeout for them to finish
+func (rm *RoomManager) Shutdown(reason string) {
+	rm.mu.RLock()
+	rooms := make([]*GameRoom, 0, len(rm.rooms))
+	for _, room := range rm.rooms {
+		rooms = append(rooms, room)
+	}
+	rm.mu.RUnlock()
+
+	for _, room := range rooms {
+		room.RequestStop(reason)
+	}
+
+	deadline := time.After(RoomStopTimeout)
+	for _, room := range rooms {
+		select {
+		case <-room.Done():
+		case <-deadline:
+			log.Printf("Room shutdown timed out after %v", RoomStopTimeout)
+			return
+		}
+	}
+	log.Printf("All %d rooms shut down", len(rooms))
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRoomStop verifies a stopped room cancels its round and empties out
+func TestRoomStop(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.PlayerOrder = []string{"A"}
+	room.State = StatePlaying
+	fired := make(chan struct{}, 1)
+	room.RoundTimer = time.AfterFunc(50*time.Millisecond, func() { fired <- struct{}{} })
+
+	exited := make(chan struct{})
+	go func() {
+		room.Run()
+		close(exited)
+	}()
+
+	room.RequestStop("maintenance")
+	room.RequestStop("maintenance") // never blocks
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not exit after a stop request")
+	}
+	<-room.Done()
+
+	if len(room.Players) != 0 || room.State != StateWaiting {
+		t.Errorf("Expected an empty waiting room, got %d players in %s", len(room.Players), room.State)
+	}
+	select {
+	case <-fired:
+		t.Error("Round timer fired after the room stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	t.Logf("✓ Stopped rooms cancel timers and exit their run loop")
+}
+
+// TestPersistentRoomRestartsAfterPanic verifies the manager restarts a crashed run loop and shuts rooms down
+func TestPersistentRoomRestartsAfterPanic(t *testing.T) {
+	manager := NewRoomManager()
+	room, _ := manager.GetRoom("Room 1")
+
+	// A nil player makes the join handler panic
+	room.Join <- nil
+
+	result := make(chan error, 1)
+	room.Admin <- AdminCommand{Action: AdminRebroadcast, Actor: "test", Result: result}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Expected the restarted room to handle commands, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Room did not restart after its run loop panicked")
+	}
+
+	manager.Shutdown("test over")
+	for _, info := range manager.ListRooms() {
+		r, _ := manager.GetRoom(info.ID)
+		select {
+		case <-r.Done():
+		default:
+			t.Errorf("Expected %s to be shut down", info.ID)
+		}
+	}
+
+	t.Logf("✓ Crashed room loops restart and Shutdown stops every room")
+}
+
+// TestEmptyRoomsReaped verifies dynamic rooms nobody is in are closed and
+// unregistered once they've been empty long enough, unless they're claimed
+func TestEmptyRoomsReaped(t *testing.T) {
+	manager := NewRoomManager()
+	empty, _ := manager.CreateRoom(RoomOptions{})
+	busy, _ := manager.CreateRoom(RoomOptions{})
+	claimed, _ := manager.CreateRoom(RoomOptions{})
+	botsOnly, _ := manager.CreateRoom(RoomOptions{})
+
+	busy.mu.Lock()
+	busy.Players["A"] = newTestPlayer("A")
+	busy.mu.Unlock()
+	claimed.mu.Lock()
+	claimed.OwnerID = "B"
+	claimed.mu.Unlock()
+	botsOnly.mu.Lock()
+	bot := newTestPlayer("bot-1")
+	bot.bot = &BotBrain{}
+	botsOnly.Players[bot.ID] = bot
+	botsOnly.mu.Unlock()
+
+	now := time.Now()
+	if reaped := manager.reapEmptyRooms(now); reaped != 0 {
+		t.Fatalf("Expected rooms only just found empty to be kept, %d closed", reaped)
+	}
+	if reaped := manager.reapEmptyRooms(now.Add(EmptyRoomTimeout - time.Second)); reaped != 0 {
+		t.Fatalf("Expected rooms to be kept until the timeout, %d closed", reaped)
+	}
+	if reaped := manager.reapEmptyRooms(now.Add(EmptyRoomTimeout)); reaped != 2 {
+		t.Fatalf("Expected the empty and bots-only rooms to be closed, %d closed", reaped)
+	}
+
+	for _, room := range []*GameRoom{empty, botsOnly} {
+		select {
+		case <-room.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("Room %s was not stopped", room.ID)
+		}
+		if _, err := manager.GetRoom(room.ID); err == nil {
+			t.Errorf("Expected room %s to be unregistered", room.ID)
+		}
+	}
+	for _, id := range []string{busy.ID, claimed.ID, "Room 1"} {
+		if _, err := manager.GetRoom(id); err != nil {
+			t.Errorf("Expected room %s to be kept, got %v", id, err)
+		}
+	}
+	if rooms := manager.ListRooms(); len(rooms) != 5 {
+		t.Errorf("Expected 3 persistent and 2 dynamic rooms listed, got %d", len(rooms))
+	}
+
+	t.Logf("✓ Empty, unclaimed dynamic rooms are closed")
+}
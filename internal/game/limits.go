@@ -0,0 +1,139 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrRoomLimit       = errors.New("server is at its room limit, try an existing room")
+	ErrGameLimit       = errors.New("server is at its concurrent game limit, try again shortly")
+	ErrConnectionLimit = errors.New("server is at its connection limit, try again shortly")
+	ErrTrackPoolLimit  = errors.New("this room's track pool is full")
+)
+
+// Limit names used as keys in rejection metrics
+const (
+	LimitRooms       = "rooms"
+	LimitGames       = "games"
+	LimitConnections = "connections"
+	LimitTrackPool   = "track_pool"
+)
+
+// Limits holds per-instance resource ceilings. A zero ceiling means unlimited.
+type Limits struct {
+	MaxRooms           int
+	MaxConcurrentGames int
+	MaxConnections     int
+	MaxTrackPool       int // tracks held per room across all players
+
+	activeGames int64
+	connections int64
+
+	rejections map[string]int64
+	mu         sync.Mutex
+}
+
+// NewLimits creates a set of ceilings with rejection counters
+func NewLimits(maxRooms, maxGames, maxConnections, maxTrackPool int) *Limits {
+	return &Limits{
+		MaxRooms:           maxRooms,
+		MaxConcurrentGames: maxGames,
+		MaxConnections:     maxConnections,
+		MaxTrackPool:       maxTrackPool,
+		rejections:         make(map[string]int64),
+	}
+}
+
+func (l *Limits) reject(limit string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rejections[limit]++
+}
+
+// AcquireGame reserves a concurrent game slot
+func (l *Limits) AcquireGame() bool {
+	if n := atomic.AddInt64(&l.activeGames, 1); l.MaxConcurrentGames > 0 && n > int64(l.MaxConcurrentGames) {
+		atomic.AddInt64(&l.activeGames, -1)
+		l.reject(LimitGames)
+		return false
+	}
+	return true
+}
+
+// ReleaseGame frees a concurrent game slot
+func (l *Limits) ReleaseGame() {
+	atomic.AddInt64(&l.activeGames, -1)
+}
+
+// AcquireConnection reserves a WebSocket connection slot
+func (l *Limits) AcquireConnection() bool {
+	if n := atomic.AddInt64(&l.connections, 1); l.MaxConnections > 0 && n > int64(l.MaxConnections) {
+		atomic.AddInt64(&l.connections, -1)
+		l.reject(LimitConnections)
+		return false
+	}
+	return true
+}
+
+// ReleaseConnection frees a WebSocket connection slot
+func (l *Limits) ReleaseConnection() {
+	atomic.AddInt64(&l.connections, -1)
+}
+
+// Metrics reports current usage and how often each limit was hit
+func (l *Limits) Metrics() map[string]interface{} {
+	l.mu.Lock()
+	rejections := make(map[string]int64, len(l.rejections))
+	for limit, count := range l.rejections {
+		rejections[limit] = count
+	}
+	l.mu.Unlock()
+
+	return map[string]interface{}{
+		"active_games":     atomic.LoadInt64(&l.activeGames),
+		"connections":      atomic.LoadInt64(&l.connections),
+		"limit_rejections": rejections,
+	}
+}
+
+// trimToTrackPool caps a joining player's tracks so the room's total pool
+// stays within MaxTrackPool, keeping their highest-ranked tracks, and tells
+// the player how many made it in. Callers must hold the room lock.
+func (r *GameRoom) trimToTrackPool(player *Player) {
+	if r.Limits == nil || r.Limits.MaxTrackPool <= 0 {
+		return
+	}
+
+	held := 0
+	for _, p := range r.Players {
+		held += len(p.TopTracks)
+	}
+
+	budget := max(r.Limits.MaxTrackPool-held, 0)
+	if len(player.TopTracks) > budget {
+		fetched := len(player.TopTracks)
+		player.TopTracks = player.TopTracks[:budget]
+		r.Limits.reject(LimitTrackPool)
+		log.Printf("Room %s: track pool limit kept %d of %s's %d tracks", r.ID, budget, player.Name, fetched)
+		r.sendDirect(player, Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": fmt.Sprintf("%s: only your top %d of %d tracks are in play", ErrTrackPoolLimit, budget, fetched),
+				"limit":   LimitTrackPool,
+			},
+		})
+	}
+}
+
+// releaseGameSlot returns the room's concurrent game slot once its game is
+// no longer running. Callers must hold the room lock.
+func (r *GameRoom) releaseGameSlot() {
+	if r.holdsGameSlot && r.Limits != nil {
+		r.Limits.ReleaseGame()
+	}
+	r.holdsGameSlot = false
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRoomLimit verifies only live dynamic rooms count towards MaxRooms
+func TestRoomLimit(t *testing.T) {
+	manager := NewRoomManager()
+	limits := NewLimits(2, 0, 0, 0)
+	manager.SetLimits(limits)
+
+	if _, err := manager.CreateRoom(RoomOptions{}); err != nil {
+		t.Fatalf("Expected the first room to be created, got %v", err)
+	}
+	if _, err := manager.CreateRoom(RoomOptions{}); err != nil {
+		t.Fatalf("Expected the second room to be created, got %v", err)
+	}
+	if _, err := manager.CreateRoom(RoomOptions{}); !errors.Is(err, ErrRoomLimit) {
+		t.Fatalf("Expected ErrRoomLimit past the limit, got %v", err)
+	}
+	if rejections := limits.Metrics()["limit_rejections"].(map[string]int64); rejections[LimitRooms] != 1 {
+		t.Errorf("Expected 1 room rejection, got %v", rejections)
+	}
+
+	// Both rooms are empty, so closing them frees their slots
+	now := time.Now()
+	manager.reapEmptyRooms(now)
+	if reaped := manager.reapEmptyRooms(now.Add(EmptyRoomTimeout)); reaped != 2 {
+		t.Fatalf("Expected both empty rooms to be closed, %d closed", reaped)
+	}
+	if _, err := manager.CreateRoom(RoomOptions{}); err != nil {
+		t.Errorf("Expected a room to be created once the empty ones closed, got %v", err)
+	}
+
+	t.Logf("✓ MaxRooms counts live dynamic rooms only")
+}
+
+// TestConcurrentGameLimit verifies a room can't start a game while every slot
+// is taken, and can once another room's game ends
+func TestConcurrentGameLimit(t *testing.T) {
+	limits := NewLimits(0, 1, 0, 0)
+	first := newGameHarness(t, 1)
+	second := newGameHarness(t, 2)
+	for _, h := range []*gameHarness{first, second} {
+		h.room.Limits = limits
+		h.room.Settings.TotalRounds = 1
+		h.join(harnessPlayer("A", "t1"))
+		h.join(harnessPlayer("B", "t2"))
+		h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+		h.ready("A")
+		h.expect(MsgTypePlayerReady)
+	}
+
+	first.ready("B")
+	first.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	second.ready("B")
+	msgs := second.expect(MsgTypePlayerReady, MsgTypeError)
+	if msg := msgs[1].Payload.(map[string]interface{})["message"]; msg != ErrGameLimit.Error() {
+		t.Errorf("Expected the game limit error, got %v", msg)
+	}
+	if second.room.State != StateWaiting {
+		t.Errorf("Expected the second room to stay waiting, got %s", second.room.State)
+	}
+	if rejections := limits.Metrics()["limit_rejections"].(map[string]int64); rejections[LimitGames] != 1 {
+		t.Errorf("Expected 1 game rejection, got %v", rejections)
+	}
+
+	first.guess("A", "A", time.Second)
+	first.guess("B", "A", time.Second)
+	first.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	if active := limits.Metrics()["active_games"]; active != int64(0) {
+		t.Fatalf("Expected the finished game to release its slot, %v active", active)
+	}
+
+	second.room.handlePlayerReady(ReadyPayload{PlayerID: "B", IsReady: false})
+	second.expect(MsgTypePlayerReady)
+	second.ready("B")
+	second.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	t.Logf("✓ MaxConcurrentGames holds back new games until a slot frees")
+}
+
+// TestTrackPoolLimit verifies a joining player's tracks are trimmed to the
+// room's remaining pool and that they're told which tracks made it in
+func TestTrackPoolLimit(t *testing.T) {
+	limits := NewLimits(0, 0, 0, 3)
+	h := newGameHarness(t, 1)
+	h.room.Limits = limits
+
+	h.join(harnessPlayer("A", "t1", "t2"))
+	h.join(harnessPlayer("B", "t3", "t4", "t5"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	a, b := h.room.Players["A"], h.room.Players["B"]
+	if len(a.TopTracks) != 2 {
+		t.Errorf("Expected A to keep both tracks, got %d", len(a.TopTracks))
+	}
+	if len(b.TopTracks) != 1 || b.TopTracks[0].ID != "t3" {
+		t.Fatalf("Expected B to keep only their top track, got %v", b.TopTracks)
+	}
+	if rejections := limits.Metrics()["limit_rejections"].(map[string]int64); rejections[LimitTrackPool] != 1 {
+		t.Errorf("Expected 1 track pool rejection, got %v", rejections)
+	}
+
+	told := false
+	for _, msg := range b.replay.entries {
+		if msg.Type != MsgTypeError {
+			continue
+		}
+		text, _ := msg.Payload.(map[string]interface{})["message"].(string)
+		told = told || strings.Contains(text, "top 1 of 3 tracks")
+	}
+	if !told {
+		t.Error("Expected B to be told their tracks were trimmed")
+	}
+	for _, msg := range a.replay.entries {
+		if msg.Type == MsgTypeError {
+			t.Errorf("Expected A to get no error, got %v", msg.Payload)
+		}
+	}
+
+	t.Logf("✓ The track pool cap trims and tells the joining player")
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"maps"
+	"slices"
+
+	"roulettify/internal/auth"
+)
+
+// PlayersDelta replaces the full player list for lite clients: only the
+// players that changed since the last list the client was sent
+type PlayersDelta struct {
+	Changed []PlayerInfo `json:"changed,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+	Order   []string     `json:"order"` // every player ID, in seat order
+}
+
+// redundantScoreKeys are score maps that repeat what the player list already
+// carries, so lite clients don't get them alongside one
+var redundantScoreKeys = []string{"scores", "updated_scores", "final_scores"}
+
+// liteMessage rewrites msg for a lite client: player lists become deltas
+// against what the client was last sent, album art is dropped and score maps
+// duplicated by the player list are removed. State snapshots always carry
+// the full list, as does the first message after joining or resuming.
+// The original message is never modified since it is shared across players.
+func liteMessage(player *Player, msg Message) Message {
+	switch payload := msg.Payload.(type) {
+	case map[string]interface{}:
+		tailored := make(map[string]interface{}, len(payload))
+		for key, value := range payload {
+			tailored[key] = value
+		}
+
+		if track, ok := tailored["track"].(auth.Track); ok {
+			track.ImageURL = ""
+			tailored["track"] = track
+		}
+
+		if players, ok := tailored["players"].([]PlayerInfo); ok {
+			for _, key := range redundantScoreKeys {
+				delete(tailored, key)
+			}
+			if player.liteBaseline != nil && msg.Type != MsgTypeStateSnapshot {
+				delete(tailored, "players")
+				tailored["players_delta"] = player.liteBaseline.delta(players)
+			}
+			player.liteBaseline = newPlayerBaseline(players)
+		}
+		msg.Payload = tailored
+
+	case *RoundResult:
+		tailored := *payload
+		tailored.Track.ImageURL = ""
+		msg.Payload = &tailored
+	}
+
+	return msg
+}
+
+// playerBaseline is the player list a lite client last received
+type playerBaseline map[string]PlayerInfo
+
+func newPlayerBaseline(players []PlayerInfo) playerBaseline {
+	baseline := make(playerBaseline, len(players))
+	for _, info := range players {
+		// Powerups is the player's live map; keep a copy to compare against
+		info.Powerups = maps.Clone(info.Powerups)
+		baseline[info.ID] = info
+	}
+	return baseline
+}
+
+// delta reports how players differs from the baseline
+func (b playerBaseline) delta(players []PlayerInfo) PlayersDelta {
+	delta := PlayersDelta{Order: make([]string, 0, len(players))}
+	seen := make(map[string]bool, len(players))
+
+	for _, info := range players {
+		delta.Order = append(delta.Order, info.ID)
+		seen[info.ID] = true
+		if previous, ok := b[info.ID]; !ok || !samePlayerInfo(previous, info) {
+			delta.Changed = append(delta.Changed, info)
+		}
+	}
+	for id := range b {
+		if !seen[id] {
+			delta.Removed = append(delta.Removed, id)
+		}
+	}
+	slices.Sort(delta.Removed)
+	return delta
+}
+
+func samePlayerInfo(a, b PlayerInfo) bool {
+	return a.Name == b.Name && a.Score == b.Score && a.IsReady == b.IsReady &&
+		a.IsLeader == b.IsLeader && a.Streak == b.Streak && a.Disconnected == b.Disconnected &&
+		a.IsSpectator == b.IsSpectator && a.Quality == b.Quality && a.IsBot == b.IsBot &&
+		maps.Equal(a.Powerups, b.Powerups)
+}
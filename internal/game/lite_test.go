@@ -0,0 +1,82 @@
+package game
+
+import (
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestLiteMessagesSendPlayerDeltas verifies lite clients get a full player
+// list once, then only what changed, without album art or duplicate scores
+func TestLiteMessagesSendPlayerDeltas(t *testing.T) {
+	room := NewGameRoom("test-room")
+	for _, id := range []string{"p1", "p2", "p3"} {
+		room.Players[id] = newTestPlayer(id)
+		room.PlayerOrder = append(room.PlayerOrder, id)
+	}
+	lite := room.Players["p1"]
+	lite.Capabilities.Lite = true
+
+	players := func(msgType MessageType) Message {
+		return Message{
+			Type: msgType,
+			Payload: map[string]interface{}{
+				"players": room.getPlayerInfoList(),
+				"scores":  room.Scores,
+				"track":   auth.Track{ID: "t1", ImageURL: "https://img"},
+			},
+		}
+	}
+
+	first := liteMessage(lite, players(MsgTypePlayerJoined)).Payload.(map[string]interface{})
+	if list, ok := first["players"].([]PlayerInfo); !ok || len(list) != 3 {
+		t.Fatalf("Expected the first message to carry the full player list, got %v", first)
+	}
+	if _, ok := first["scores"]; ok {
+		t.Errorf("Expected the score map to be dropped alongside the player list")
+	}
+	if track := first["track"].(auth.Track); track.ImageURL != "" {
+		t.Errorf("Expected album art to be stripped, got %q", track.ImageURL)
+	}
+
+	room.Scores["p2"] = 10
+	room.Players["p2"].Powerups = map[PowerupType]int{PowerupShield: 1}
+	delete(room.Players, "p3")
+	room.PlayerOrder = room.PlayerOrder[:2]
+
+	original := players(MsgTypePlayerLeft)
+	next := liteMessage(lite, original).Payload.(map[string]interface{})
+	if _, ok := next["players"]; ok {
+		t.Fatalf("Expected a delta instead of the full list")
+	}
+	delta := next["players_delta"].(PlayersDelta)
+	if len(delta.Changed) != 1 || delta.Changed[0].ID != "p2" || delta.Changed[0].Score != 10 {
+		t.Errorf("Expected only p2 to have changed, got %+v", delta.Changed)
+	}
+	if len(delta.Removed) != 1 || delta.Removed[0] != "p3" || len(delta.Order) != 2 {
+		t.Errorf("Expected p3 removed and two players in order, got %+v", delta)
+	}
+	if _, ok := original.Payload.(map[string]interface{})["players"]; !ok {
+		t.Errorf("Expected the shared message to be left untouched")
+	}
+
+	// Changing a live powerup map must still show up as a change
+	room.Players["p2"].Powerups[PowerupShield] = 0
+	again := liteMessage(lite, players(MsgTypePlayerJoined)).Payload.(map[string]interface{})
+	if delta := again["players_delta"].(PlayersDelta); len(delta.Changed) != 1 {
+		t.Errorf("Expected the used shield to be reported, got %+v", delta.Changed)
+	}
+
+	snapshot := liteMessage(lite, players(MsgTypeStateSnapshot)).Payload.(map[string]interface{})
+	if _, ok := snapshot["players"]; !ok {
+		t.Errorf("Expected state snapshots to always carry the full list")
+	}
+
+	regular := room.Players["p2"]
+	full := tailorMessage(regular.Capabilities, players(MsgTypePlayerJoined)).Payload.(map[string]interface{})
+	if _, ok := full["scores"]; !ok || regular.liteBaseline != nil {
+		t.Errorf("Expected regular clients to be unaffected")
+	}
+
+	t.Logf("✓ Lite clients receive player deltas without album art or duplicate scores")
+}
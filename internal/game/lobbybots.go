@@ -0,0 +1,100 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// Errors from adding a bot to a room
+var (
+	ErrBotsNeedWaitingRoom = errors.New("bots can only join a room that's waiting for a game")
+	ErrRoomFull            = errors.New("room is full")
+)
+
+// AddBotPayload asks for a bot to fill a seat in the room
+type AddBotPayload struct {
+	PlayerID string `json:"player_id"`
+	Profile  string `json:"profile"` // skill level; defaults to DefaultBotProfile
+}
+
+func (r *GameRoom) handleAddBot(payload AddBotPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can add bots")
+		return
+	}
+	if _, err := r.addBot(payload.Profile); err != nil {
+		r.sendError(payload.PlayerID, err.Error())
+	}
+}
+
+// addBot seats a ready bot playing with the given profile. Its top tracks
+// are drawn from the people already seated so rounds stay playable. Callers
+// must hold the room lock.
+func (r *GameRoom) addBot(profileName string) (*Player, error) {
+	if r.State != StateWaiting {
+		return nil, ErrBotsNeedWaitingRoom
+	}
+	if r.practice != nil {
+		return nil, errors.New("practice rooms seat their own bots")
+	}
+	if len(r.Players) >= MaxPlayersPerRoom || len(r.queue) > 0 {
+		return nil, ErrRoomFull
+	}
+	profile, err := GetBotProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalogue []auth.Track
+	seen := make(map[string]bool)
+	for _, id := range r.PlayerOrder {
+		player := r.Players[id]
+		if player.IsBot() {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if !seen[track.ID] {
+				seen[track.ID] = true
+				catalogue = append(catalogue, track)
+			}
+		}
+	}
+
+	bot := r.newBot(profile, catalogue)
+	r.seatPlayer(bot)
+	log.Printf("Room %s: added %s bot %s", r.ID, profile.Name, bot.Name)
+	return bot, nil
+}
+
+// yieldBotSeat removes the most recently seated bot so a person can take its
+// place, reporting whether there was one. Callers must hold the room lock.
+func (r *GameRoom) yieldBotSeat() bool {
+	for i := len(r.PlayerOrder) - 1; i >= 0; i-- {
+		if player := r.Players[r.PlayerOrder[i]]; player.IsBot() {
+			r.removePlayer(player.ID, "Seat given to a player")
+			return true
+		}
+	}
+	return false
+}
+
+// newBot creates a bot with a mock top tracks list drawn from catalogue
+func (r *GameRoom) newBot(profile BotProfile, catalogue []auth.Track) *Player {
+	r.botSeq++
+	seed := botSeed() + int64(r.botSeq)
+	id := fmt.Sprintf("bot-%s-%d", r.ID, r.botSeq)
+	name := botNames[(r.botSeq-1)%len(botNames)] + " (bot)"
+
+	return &Player{
+		Player:   auth.GenerateMockPlayer(id, name, catalogue, seed),
+		JoinedAt: time.Now(),
+		bot:      NewBotBrain(profile, seed),
+	}
+}
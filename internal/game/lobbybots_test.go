@@ -0,0 +1,61 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLobbyBotsFillAndYieldSeats verifies the leader can add ready bots to a
+// waiting room, and that bots give up seats to people and leave with them
+func TestLobbyBotsFillAndYieldSeats(t *testing.T) {
+	h := newGameHarness(t, 6)
+	h.join(harnessPlayer("A", "t1", "t2", "t3"))
+	h.expect(MsgTypePlayerJoined)
+
+	h.room.handleAddBot(AddBotPayload{PlayerID: "someone", Profile: "expert"})
+	h.room.handleAddBot(AddBotPayload{PlayerID: "A", Profile: "nobody"})
+	if len(h.room.Players) != 1 {
+		t.Fatalf("Expected only the leader to add bots with a known profile, got %d players", len(h.room.Players))
+	}
+
+	for i := 1; i < MaxPlayersPerRoom; i++ {
+		h.room.handleAddBot(AddBotPayload{PlayerID: "A", Profile: "expert"})
+		h.expect(MsgTypePlayerJoined)
+	}
+	h.room.mu.Lock()
+	bot := h.room.Players["bot-harness-room-1"]
+	if bot == nil || !bot.IsReady || bot.bot.Profile.Name != "expert" {
+		t.Errorf("Expected a ready expert bot, got %+v", bot)
+	}
+	if _, err := h.room.addBot(""); !errors.Is(err, ErrRoomFull) {
+		t.Errorf("Expected a full room to refuse more bots, got %v", err)
+	}
+	h.room.mu.Unlock()
+
+	// A person joining the full room takes the newest bot's seat
+	h.join(harnessPlayer("B", "t1"))
+	h.expect(MsgTypePlayerLeft, MsgTypePlayerJoined)
+	if _, ok := h.room.Players["B"]; !ok || h.room.Players["bot-harness-room-9"] != nil {
+		t.Errorf("Expected B to replace the last bot")
+	}
+
+	h.room.mu.Lock()
+	h.room.State = StatePlaying
+	if _, err := h.room.addBot(""); !errors.Is(err, ErrBotsNeedWaitingRoom) {
+		t.Errorf("Expected bots to be refused mid-game, got %v", err)
+	}
+	h.room.State = StateWaiting
+	h.room.mu.Unlock()
+
+	h.room.handlePlayerLeave("A")
+	h.expect(MsgTypePlayerLeft)
+	h.room.handlePlayerLeave("B")
+	for i := 0; i < MaxPlayersPerRoom-1; i++ {
+		h.expect(MsgTypePlayerLeft)
+	}
+	if len(h.room.Players) != 0 {
+		t.Errorf("Expected the bots to leave once no people were left, %d remain", len(h.room.Players))
+	}
+
+	t.Logf("✓ Bots fill seats on request, yield them to people and leave with them")
+}
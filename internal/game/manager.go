@@ -2,21 +2,64 @@ package game
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type RoomManager struct {
-	rooms map[string]*GameRoom
-	mu    sync.RWMutex
+	rooms        map[string]*GameRoom
+	dynamicOrder  []string
+	checkpointDir string
+	limits        *Limits
+	flags         *FeatureFlags
+	region        string // region this instance runs in
+	instanceID    string // this instance in a multi-instance deployment
+	endpoint      string // public WebSocket URL that reaches this instance directly
+	store         *RoomStore
+	history       *HistoryStore
+	seasons       *Seasons
+	replays       *ReplayStore
+	profiles      *Profiles
+	daily         *DailyChallenges
+	presence      *Presence
+	diagnostics   *Diagnostics
+	results       ResultSink
+	stateSink     RoomStateSink
+	webhooks      *Webhooks
+	mu            sync.RWMutex
+}
+
+// RoomOptions configures a dynamically created room
+type RoomOptions struct {
+	Hostless    bool   `json:"hostless"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Region      string `json:"region"` // defaults to the instance's region
+	Practice    bool   `json:"practice"`    // one player against bots
+	Bots        int    `json:"bots"`        // practice only, defaults to DefaultPracticeBots
+	BotProfile  string `json:"bot_profile"` // practice only, defaults to DefaultBotProfile
+	Tutorial    bool   `json:"tutorial"`    // a scripted game that teaches one new player
 }
 
 func NewRoomManager() *RoomManager {
 	rm := &RoomManager{
-		rooms: make(map[string]*GameRoom),
+		rooms:       make(map[string]*GameRoom),
+		limits:      NewLimits(0, 0, 0, 0),
+		flags:       NewFeatureFlags(),
+		daily:       NewDailyChallenges(),
+		presence:    NewPresence(),
+		diagnostics: NewDiagnostics(),
+		webhooks:    NewWebhooks(),
 	}
 	
 	// Initialize 3 persistent rooms
 	rm.initializePersistentRooms()
+	go rm.runReaper()
 	
 	return rm
 }
@@ -27,11 +70,113 @@ func (rm *RoomManager) initializePersistentRooms() {
 	
 	for _, roomName := range roomNames {
 		room := NewGameRoom(roomName)
+		room.Limits = rm.limits
+		room.Flags = rm.flags
+		room.presence = rm.presence
+		room.diagnostics = rm.diagnostics
+		room.results = rm.results
+		room.stateSink = rm.stateSink
+		room.webhooks = rm.webhooks
 		rm.rooms[roomName] = room
-		go room.Run()
+		go rm.supervise(room)
+	}
+}
+
+// EnableCheckpoints makes endless games in every room checkpoint their scores
+// to dir so they can be resumed after a restart
+func (rm *RoomManager) EnableCheckpoints(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir: %w", err)
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.checkpointDir = dir
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.CheckpointDir = dir
+		room.mu.Unlock()
+	}
+	return nil
+}
+
+// SetRegion tags this instance's rooms with a region label so clients can
+// pick rooms close to them
+func (rm *RoomManager) SetRegion(region string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.region = region
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.Region = region
+		room.CreatedRegion = region
+		room.mu.Unlock()
+	}
+}
+
+// SetInstance identifies this instance when several run behind a load
+// balancer. Room listings then say which instance owns each room and the
+// WebSocket endpoint that reaches it without a relay hop.
+func (rm *RoomManager) SetInstance(instanceID, endpoint string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.instanceID = instanceID
+	rm.endpoint = endpoint
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.Instance = instanceID
+		room.Endpoint = endpoint
+		room.mu.Unlock()
 	}
 }
 
+// SetLimits replaces the instance-wide resource ceilings
+func (rm *RoomManager) SetLimits(limits *Limits) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.limits = limits
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.Limits = limits
+		room.mu.Unlock()
+	}
+}
+
+// Limits returns the instance-wide resource ceilings
+func (rm *RoomManager) Limits() *Limits {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.limits
+}
+
+// Flags returns the instance's runtime feature switches
+func (rm *RoomManager) Flags() *FeatureFlags {
+	return rm.flags
+}
+
+// Presence returns the instance's record of which room each player is in
+func (rm *RoomManager) Presence() *Presence {
+	return rm.presence
+}
+
+// Diagnostics returns the instance's debug connections
+func (rm *RoomManager) Diagnostics() *Diagnostics {
+	return rm.diagnostics
+}
+
+// Drain stops the instance creating rooms and starting games ahead of a
+// deploy. Games already running play out; watch active_games in the metrics
+// to know when it's safe to stop.
+func (rm *RoomManager) Drain() {
+	rm.flags.Set(FeatureRoomCreation, false)
+	rm.flags.Set(FeatureNewGames, false)
+	log.Printf("Draining: no new rooms or games")
+}
+
 // GetRoom returns a room by ID
 func (rm *RoomManager) GetRoom(roomID string) (*GameRoom, error) {
 	rm.mu.RLock()
@@ -44,36 +189,356 @@ func (rm *RoomManager) GetRoom(roomID string) (*GameRoom, error) {
 	return nil, fmt.Errorf("room not found - valid rooms are: Room 1, Room 2, Room 3")
 }
 
-// ListRooms returns all persistent rooms with their player counts
-// Rooms are always returned in order: Room 1, Room 2, Room 3
+// CreateRoom creates a dynamic room alongside the persistent ones
+func (rm *RoomManager) CreateRoom(opts RoomOptions) (*GameRoom, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if !rm.flags.Enabled(FeatureRoomCreation) {
+		return nil, ErrRoomCreationDisabled
+	}
+	// Only dynamic rooms count; empty ones are closed (see reapEmptyRooms)
+	if rm.limits.MaxRooms > 0 && len(rm.dynamicOrder) >= rm.limits.MaxRooms {
+		rm.limits.reject(LimitRooms)
+		return nil, ErrRoomLimit
+	}
+
+	room := rm.newDynamicRoom(uuid.New().String()[:8])
+	room.Hostless = opts.Hostless
+	room.Name = opts.Name
+	room.Description = opts.Description
+	room.Region = opts.Region
+	if room.Region == "" {
+		room.Region = rm.region
+	}
+	if opts.Practice {
+		profile, _ := GetBotProfile(opts.BotProfile)
+		room.practice = &practiceSetup{bots: opts.Bots, profile: profile}
+	}
+	if opts.Tutorial {
+		room.enableTutorial()
+	}
+
+	go rm.supervise(room)
+	return room, nil
+}
+
+// newDynamicRoom registers a dynamic room with the manager's shared
+// configuration. Callers must hold the manager lock and start supervise.
+func (rm *RoomManager) newDynamicRoom(roomID string) *GameRoom {
+	room := NewGameRoom(roomID)
+	room.CreatedRegion = rm.region
+	room.Instance = rm.instanceID
+	room.Endpoint = rm.endpoint
+	room.CheckpointDir = rm.checkpointDir
+	room.Limits = rm.limits
+	room.Flags = rm.flags
+	room.store = rm.store
+	room.history = rm.history
+	room.replays = rm.replays
+	room.presence = rm.presence
+	room.diagnostics = rm.diagnostics
+	room.results = rm.results
+	room.stateSink = rm.stateSink
+	room.webhooks = rm.webhooks
+
+	rm.rooms[roomID] = room
+	rm.dynamicOrder = append(rm.dynamicOrder, roomID)
+	return room
+}
+
+// EnableRoomStore persists claimed rooms in dir and restores any rooms
+// claimed before the last restart
+func (rm *RoomManager) EnableRoomStore(dir string) error {
+	store, err := NewRoomStore(dir)
+	if err != nil {
+		return err
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load rooms: %w", err)
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.store = store
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.store = store
+		room.mu.Unlock()
+	}
+
+	for _, record := range records {
+		if _, exists := rm.rooms[record.ID]; exists {
+			continue
+		}
+		room := rm.newDynamicRoom(record.ID)
+		room.restore(record)
+		go rm.supervise(room)
+		log.Printf("Restored room %s owned by %s", record.ID, record.OwnerName)
+	}
+	return nil
+}
+
+// EnableHistory records finished games in dir for stats such as rivalries
+// and seasons, along with their replays, player profiles and daily
+// challenge results
+func (rm *RoomManager) EnableHistory(dir string) error {
+	history, err := NewHistoryStore(dir)
+	if err != nil {
+		return err
+	}
+	seasons, err := NewSeasons(history, dir)
+	if err != nil {
+		return err
+	}
+	replays, err := NewReplayStore(filepath.Join(dir, "replays"))
+	if err != nil {
+		return err
+	}
+	profiles, err := NewProfiles(history, dir)
+	if err != nil {
+		return err
+	}
+	if err := rm.daily.Persist(dir); err != nil {
+		return err
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.history = history
+	rm.seasons = seasons
+	rm.replays = replays
+	rm.profiles = profiles
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.history = history
+		room.replays = replays
+		room.mu.Unlock()
+	}
+	return nil
+}
+
+// SetResultSink hands every room's completed rounds and finished games to
+// sink, e.g. to store them in a database
+func (rm *RoomManager) SetResultSink(sink ResultSink) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.results = sink
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.results = sink
+		room.mu.Unlock()
+	}
+}
+
+// SetRoomStateSink mirrors every room's running game to sink, so it can be
+// restored with RestoreRooms after a restart
+func (rm *RoomManager) SetRoomStateSink(sink RoomStateSink) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.stateSink = sink
+	for _, room := range rm.rooms {
+		room.mu.Lock()
+		room.stateSink = sink
+		room.mu.Unlock()
+	}
+}
+
+// RestoreRooms brings back games mirrored before a restart, recreating
+// dynamic rooms that no longer exist. Players get RestoreDelay plus the
+// usual grace period to resume their seats. It returns how many games were
+// restored.
+func (rm *RoomManager) RestoreRooms(states []RoomState) int {
+	restored := 0
+	for _, state := range states {
+		if time.Since(state.SavedAt) > RoomStateTTL {
+			continue
+		}
+
+		rm.mu.Lock()
+		room, exists := rm.rooms[state.RoomID]
+		if !exists {
+			room = rm.newDynamicRoom(state.RoomID)
+			room.Region = rm.region
+			go rm.supervise(room)
+		}
+		rm.mu.Unlock()
+
+		if room.restoreState(state, RestoreDelay) {
+			restored++
+		}
+	}
+	return restored
+}
+
+// History returns the game history, or ErrNoHistory when it's disabled
+func (rm *RoomManager) History() (*HistoryStore, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if rm.history == nil {
+		return nil, ErrNoHistory
+	}
+	return rm.history, nil
+}
+
+// Seasons returns the season leaderboards, or ErrNoHistory when history is
+// disabled
+func (rm *RoomManager) Seasons() (*Seasons, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if rm.seasons == nil {
+		return nil, ErrNoHistory
+	}
+	return rm.seasons, nil
+}
+
+// Replays returns the recorded games, or ErrNoHistory when history is
+// disabled
+func (rm *RoomManager) Replays() (*ReplayStore, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if rm.replays == nil {
+		return nil, ErrNoHistory
+	}
+	return rm.replays, nil
+}
+
+// Profiles returns the player profiles, or ErrNoHistory when history is
+// disabled
+func (rm *RoomManager) Profiles() (*Profiles, error) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if rm.profiles == nil {
+		return nil, ErrNoHistory
+	}
+	return rm.profiles, nil
+}
+
+// Daily returns the solo daily challenge
+func (rm *RoomManager) Daily() *DailyChallenges {
+	return rm.daily
+}
+
+// ClaimRoom makes a player in a dynamic room its owner so the room's
+// settings, ban list and name persist across restarts
+func (rm *RoomManager) ClaimRoom(roomID, playerID string) (*GameRoom, error) {
+	room, err := rm.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if isPersistentRoom(roomID) {
+		return nil, ErrRoomNotClaimable
+	}
+	return room, room.claim(playerID)
+}
+
+func isPersistentRoom(roomID string) bool {
+	switch roomID {
+	case "Room 1", "Room 2", "Room 3":
+		return true
+	}
+	return false
+}
+
+// ListRooms returns all rooms with their player counts
+// Persistent rooms always come first in order: Room 1, Room 2, Room 3,
+// followed by dynamic rooms in creation order
 func (rm *RoomManager) ListRooms() []RoomInfo {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 
 	// Return rooms in consistent order
-	roomOrder := []string{"Room 1", "Room 2", "Room 3"}
-	roomInfos := make([]RoomInfo, 0, 3)
+	roomOrder := append([]string{"Room 1", "Room 2", "Room 3"}, rm.dynamicOrder...)
+	roomInfos := make([]RoomInfo, 0, len(roomOrder))
 	
 	for _, roomID := range roomOrder {
 		if room, exists := rm.rooms[roomID]; exists {
-			room.mu.RLock()
-			roomInfos = append(roomInfos, RoomInfo{
-				ID:          roomID,
-				PlayerCount: len(room.Players),
-				MaxPlayers:  MaxPlayersPerRoom,
-				State:       room.State,
-			})
-			room.mu.RUnlock()
+			roomInfos = append(roomInfos, room.Info())
 		}
 	}
 	return roomInfos
 }
 
 type RoomInfo struct {
-	ID          string    `json:"id"`
-	PlayerCount int       `json:"player_count"`
-	MaxPlayers  int       `json:"max_players"`
-	State       GameState `json:"state"`
+	ID           string    `json:"id"`
+	Name         string    `json:"name,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	PlayerCount  int       `json:"player_count"`
+	MaxPlayers   int       `json:"max_players"`
+	State        GameState `json:"state"`
+	CurrentRound int       `json:"current_round"`
+	TotalRounds  int       `json:"total_rounds"`
+	LeaderName   string    `json:"leader_name"`
+	HasPassword  bool      `json:"has_password"`
+	Region       string    `json:"region,omitempty"`
+	CreatedIn    string    `json:"created_in,omitempty"` // region of the instance that created the room
+	OwnerName    string    `json:"owner_name,omitempty"`
+	Queued       int       `json:"queued"`
+	Hostless     bool      `json:"hostless"`
+	Instance     string    `json:"instance,omitempty"`    // instance that owns the room
+	Endpoint     string    `json:"ws_endpoint,omitempty"` // connect here to reach that instance directly
+	GameID       string    `json:"game_id,omitempty"`     // the game being played or just finished
+	Practice     bool      `json:"practice,omitempty"`    // one player against bots
+	Tutorial     bool      `json:"tutorial,omitempty"`    // a scripted practice game for new players
+}
+
+// RoomFilter narrows and paginates the room list for the lobby browser
+type RoomFilter struct {
+	State    GameState
+	Region   string
+	OpenOnly bool
+	Page     int
+	PageSize int
+}
+
+// Apply filters the given rooms and returns the requested page along with
+// the total number of rooms that matched before pagination
+func (f RoomFilter) Apply(rooms []RoomInfo) ([]RoomInfo, int) {
+	matched := make([]RoomInfo, 0, len(rooms))
+	for _, info := range rooms {
+		if f.State != "" && info.State != f.State {
+			continue
+		}
+		if f.Region != "" && info.Region != f.Region {
+			continue
+		}
+		if f.OpenOnly && info.PlayerCount >= info.MaxPlayers {
+			continue
+		}
+		matched = append(matched, info)
+	}
+
+	total := len(matched)
+	if f.PageSize <= 0 {
+		return matched, total
+	}
+
+	page := f.Page
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * f.PageSize
+	if start >= total {
+		return []RoomInfo{}, total
+	}
+	end := min(start+f.PageSize, total)
+
+	return matched[start:end], total
 }
 
 func (rm *RoomManager) GetMetrics() map[string]interface{} {
@@ -92,10 +557,15 @@ func (rm *RoomManager) GetMetrics() map[string]interface{} {
 		room.mu.RUnlock()
 	}
 
-	return map[string]interface{}{
+	metrics := map[string]interface{}{
 		"total_rooms":    len(rm.rooms),
 		"total_players":  totalPlayers,
 		"active_players": activePlayers,
 	}
+	for key, value := range rm.limits.Metrics() {
+		metrics[key] = value
+	}
+
+	return metrics
 }
 
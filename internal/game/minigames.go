@@ -0,0 +1,167 @@
+package game
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// MiniGameDuration is how long players have to answer an intermission mini-game
+const MiniGameDuration = 4 * time.Second
+
+// MiniGamePoints are awarded for a correct mini-game answer
+const MiniGamePoints = 3
+
+// MinIntermissionForMiniGames leaves time to reveal the answer before the
+// next round starts
+const MinIntermissionForMiniGames = 5
+
+// MiniGameKind identifies an intermission mini-game
+type MiniGameKind string
+
+const (
+	// MiniGameHigherLower: is the track just played more or less popular
+	// than an earlier one?
+	MiniGameHigherLower MiniGameKind = "higher_lower"
+)
+
+// Answers to a higher-or-lower mini-game
+const (
+	AnswerHigher = "higher"
+	AnswerLower  = "lower"
+)
+
+// miniGame is an open intermission mini-game
+type miniGame struct {
+	Gen     int
+	Kind    MiniGameKind
+	Earlier auth.Track // revealed earlier in the game
+	Latest  auth.Track // the track just revealed
+	Answers map[string]string
+	timer   *time.Timer
+}
+
+// startMiniGame opens a higher-or-lower question comparing the track just
+// revealed with an earlier one. Callers must hold the room lock.
+func (r *GameRoom) startMiniGame() {
+	if !r.Settings.MiniGames || r.CurrentTrack == nil {
+		return
+	}
+
+	latest := *r.CurrentTrack
+	candidates := make([]auth.Track, 0, len(r.roundResults))
+	for round, result := range r.roundResults {
+		if round != r.CurrentRound && result.Track.Popularity != latest.Popularity {
+			candidates = append(candidates, result.Track)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	r.miniGameGen++
+	game := &miniGame{
+		Gen:     r.miniGameGen,
+		Kind:    MiniGameHigherLower,
+		Earlier: candidates[rand.Intn(len(candidates))],
+		Latest:  latest,
+		Answers: make(map[string]string),
+	}
+	gen := game.Gen
+	game.timer = time.AfterFunc(MiniGameDuration, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.miniGame != nil && r.miniGame.Gen == gen {
+			r.finishMiniGame()
+		}
+	})
+	r.miniGame = game
+
+	r.Broadcast <- Message{
+		Type: MsgTypeMiniGameStarted,
+		Payload: map[string]interface{}{
+			"kind":           game.Kind,
+			"prompt":         "Is " + latest.Name + " more or less popular than " + game.Earlier.Name + "?",
+			"earlier_track":  game.Earlier,
+			"latest_track":   game.Latest,
+			"answers":        []string{AnswerHigher, AnswerLower},
+			"points":         MiniGamePoints,
+			"answer_seconds": int(MiniGameDuration.Seconds()),
+		},
+	}
+}
+
+func (r *GameRoom) handleMiniGameAnswer(payload MiniGameAnswerPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists || player.IsSpectator {
+		return
+	}
+
+	if r.miniGame == nil {
+		r.sendError(player.ID, "There's no mini-game to answer right now")
+		return
+	}
+	if payload.Answer != AnswerHigher && payload.Answer != AnswerLower {
+		r.sendError(player.ID, "Answer higher or lower")
+		return
+	}
+	if _, answered := r.miniGame.Answers[player.ID]; answered {
+		return
+	}
+
+	r.miniGame.Answers[player.ID] = payload.Answer
+}
+
+// finishMiniGame awards points for the open mini-game and reveals the
+// answer. Callers must hold the room lock.
+func (r *GameRoom) finishMiniGame() {
+	game := r.miniGame
+	if game == nil {
+		return
+	}
+	r.miniGame = nil
+	game.timer.Stop()
+
+	correct := AnswerLower
+	if game.Latest.Popularity > game.Earlier.Popularity {
+		correct = AnswerHigher
+	}
+
+	pointsAwarded := make(map[string]int)
+	for playerID, answer := range game.Answers {
+		if _, present := r.Players[playerID]; !present || answer != correct {
+			continue
+		}
+		r.Scores[playerID] += MiniGamePoints
+		pointsAwarded[playerID] = MiniGamePoints
+	}
+
+	log.Printf("Mini-game in room %s: %d of %d answered %s", r.ID, len(pointsAwarded), len(game.Answers), correct)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeMiniGameResult,
+		Payload: map[string]interface{}{
+			"kind":               game.Kind,
+			"correct_answer":     correct,
+			"earlier_popularity": game.Earlier.Popularity,
+			"latest_popularity":  game.Latest.Popularity,
+			"answers":            game.Answers,
+			"points_awarded":     pointsAwarded,
+			"updated_scores":     r.Scores,
+		},
+	}
+}
+
+// cancelMiniGame drops an open mini-game without scoring it.
+// Callers must hold the room lock.
+func (r *GameRoom) cancelMiniGame() {
+	if r.miniGame != nil {
+		r.miniGame.timer.Stop()
+		r.miniGame = nil
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestHigherLowerMiniGame verifies the intermission mini-game compares popularity and awards bonus points
+func TestHigherLowerMiniGame(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.Players["C"] = newTestPlayer("C")
+	room.Players["C"].IsSpectator = true
+	room.State = StatePlaying
+	room.Settings.MiniGames = true
+
+	room.startMiniGame()
+	if room.miniGame != nil || len(room.Broadcast) != 0 {
+		t.Fatal("A mini-game needs an earlier track to compare against")
+	}
+
+	room.CurrentRound = 2
+	room.recordRoundResult(&RoundResult{Round: 1, Track: auth.Track{ID: "t1", Name: "Old Hit", Popularity: 40}})
+	room.CurrentTrack = &auth.Track{ID: "t2", Name: "New Hit", Popularity: 85}
+	room.recordRoundResult(&RoundResult{Round: 2, Track: *room.CurrentTrack})
+
+	room.startMiniGame()
+	if msg := <-room.Broadcast; msg.Type != MsgTypeMiniGameStarted {
+		t.Fatalf("Expected mini_game_started, got %s", msg.Type)
+	}
+
+	room.handleMiniGameAnswer(MiniGameAnswerPayload{PlayerID: "A", Answer: AnswerHigher})
+	room.handleMiniGameAnswer(MiniGameAnswerPayload{PlayerID: "A", Answer: AnswerLower}) // first answer sticks
+	room.handleMiniGameAnswer(MiniGameAnswerPayload{PlayerID: "B", Answer: AnswerLower})
+	room.handleMiniGameAnswer(MiniGameAnswerPayload{PlayerID: "C", Answer: AnswerHigher})
+
+	room.finishMiniGame()
+	msg := <-room.Broadcast
+	payload := msg.Payload.(map[string]interface{})
+	if msg.Type != MsgTypeMiniGameResult || payload["correct_answer"] != AnswerHigher {
+		t.Fatalf("Expected higher to be correct, got %+v", msg)
+	}
+	if room.Scores["A"] != MiniGamePoints || room.Scores["B"] != 0 || room.Scores["C"] != 0 {
+		t.Errorf("Expected only A to earn mini-game points, got %v", room.Scores)
+	}
+
+	settings := DefaultRoomSettings()
+	settings.MiniGames = true
+	settings.IntermissionLength = 3
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected mini-games to need a longer intermission")
+	}
+
+	t.Logf("✓ Higher-or-lower mini-games score popularity guesses between rounds")
+}
@@ -15,6 +15,26 @@ type Player struct {
 	JoinedAt   time.Time
 	IsReady    bool
 	IsLeader   bool
+	Streak     int
+	Powerups     map[PowerupType]int
+	RoomPassword string // password presented when joining
+	QueueIfFull  bool   // wait in the room's queue instead of being rejected when full
+	Invited      bool   // joined with a valid invite, bypassing the password
+	ResumeToken  string
+	Disconnected bool
+	IsSpectator  bool // joined mid-game, promoted when the room is back to waiting
+	Capabilities ClientCapabilities
+	// Own tracks the player never wants played; never broadcast
+	ExcludedTracks map[string]bool
+	connStats    connectionStats
+	resumeTimer  *time.Timer
+	lastEmoteAt  time.Time
+	chatTimes    []time.Time // recent lobby chat messages, for rate limiting
+	replay       replayBuffer
+	liteBaseline playerBaseline // player list a lite client last received
+	bot          *BotBrain      // guesses for practice bots; nil for people
+	Handicap     float64        // multiplier on the points they score, set by the leader; 0 is none
+	debug        *connectionDiagnostics // set for clients with the debug capability
 }
 
 // GameState represents the current state of the game
@@ -37,6 +57,26 @@ const (
 	MsgTypeReady        MessageType = "ready"
 	MsgTypeStartGame    MessageType = "start_game"
 	MsgTypeSubmitGuess  MessageType = "submit_guess"
+	MsgTypeUsePowerup   MessageType = "use_powerup"
+	MsgTypeSetPassword  MessageType = "set_password"
+	MsgTypeUpdateSettings MessageType = "update_settings"
+	MsgTypeVoteEnd      MessageType = "vote_end"
+	MsgTypeResetSession MessageType = "reset_session"
+	MsgTypeVoteKick     MessageType = "vote_kick"
+	MsgTypeSendEmote    MessageType = "send_emote"
+	MsgTypePredict      MessageType = "predict"
+	MsgTypeExcludeTracks MessageType = "exclude_tracks"
+	MsgTypeChat         MessageType = "chat" // sent by clients and relayed to the room
+	MsgTypeVoidRound    MessageType = "void_round"
+	MsgTypeMiniGameAnswer MessageType = "mini_game_answer"
+	MsgTypeQuickRematch MessageType = "quick_rematch"
+	MsgTypeRematch      MessageType = "rematch"
+	MsgTypeReplayTrack  MessageType = "replay_track"
+	MsgTypeAddBot       MessageType = "add_bot"
+	MsgTypeSetHandicap  MessageType = "set_handicap"
+	MsgTypeSetPlaylist  MessageType = "set_playlist"
+	MsgTypePauseGame    MessageType = "pause_game"
+	MsgTypeResumeGame   MessageType = "resume_game"
 
 	// Server to Client
 	MsgTypePlayerJoined   MessageType = "player_joined"
@@ -46,8 +86,50 @@ const (
 	MsgTypeRoundStarted   MessageType = "round_started"
 	MsgTypeGuessReceived  MessageType = "guess_received"
 	MsgTypeRoundComplete  MessageType = "round_complete"
+	MsgTypeRoundWinner    MessageType = "round_winner"
+	MsgTypeResumed        MessageType = "resumed"
 	MsgTypeGameOver       MessageType = "game_over"
 	MsgTypeGameReset      MessageType = "game_reset"
+	MsgTypePowerupEarned  MessageType = "powerup_earned"
+	MsgTypePowerupActive  MessageType = "powerup_activated"
+	MsgTypeRoomUpdated    MessageType = "room_updated"
+	MsgTypeSettingsUpdated MessageType = "settings_updated"
+	MsgTypeVoteEndProgress MessageType = "vote_end_progress"
+	MsgTypeSession        MessageType = "session"
+	MsgTypeStateSnapshot  MessageType = "state_snapshot"
+	MsgTypePlayerDisconnected MessageType = "player_disconnected"
+	MsgTypePlayerReconnected  MessageType = "player_reconnected"
+	MsgTypeSessionReset   MessageType = "session_reset"
+	MsgTypeVoteKickProgress MessageType = "vote_kick_progress"
+	MsgTypePlayerKicked   MessageType = "player_kicked"
+	MsgTypeCountdown      MessageType = "countdown"
+	MsgTypeCountdownCancelled MessageType = "countdown_cancelled"
+	MsgTypeEmote          MessageType = "emote"
+	MsgTypeSystemMessage  MessageType = "system_message"
+	MsgTypeQueuePosition  MessageType = "queue_position"
+	MsgTypeFilterWarning  MessageType = "filter_warning"
+	MsgTypePredictionPlaced MessageType = "prediction_placed"
+	MsgTypeExclusionsUpdated MessageType = "exclusions_updated"
+	MsgTypeConnectionQuality MessageType = "connection_quality"
+	MsgTypeRoundVoided    MessageType = "round_voided"
+	MsgTypeMiniGameStarted MessageType = "mini_game_started"
+	MsgTypeMiniGameResult MessageType = "mini_game_result"
+	MsgTypeRivalry        MessageType = "rivalry"
+	MsgTypeRematchPending MessageType = "rematch_pending"
+	MsgTypeRematchCancelled MessageType = "rematch_cancelled"
+	MsgTypeRematchVote    MessageType = "rematch_vote"
+	MsgTypeTrackReplay    MessageType = "track_replay"
+	MsgTypeIntermissionContent MessageType = "intermission_content"
+	MsgTypeHint           MessageType = "hint"
+	MsgTypeTitleReveal    MessageType = "title_reveal"
+	MsgTypeTutorialStep   MessageType = "tutorial_step"
+	MsgTypeOvertimeStarted MessageType = "overtime_started"
+	MsgTypeSuggestions    MessageType = "suggestions"
+	MsgTypeRoundExtended  MessageType = "round_extended"
+	MsgTypeDebugReport    MessageType = "debug_report"
+	MsgTypeGamePaused     MessageType = "game_paused"
+	MsgTypeGameResumed    MessageType = "game_resumed"
+	MsgTypeGuessMade      MessageType = "guess_made" // only in replays
 	MsgTypeError          MessageType = "error"
 )
 
@@ -55,14 +137,26 @@ const (
 type Message struct {
 	Type    MessageType `json:"type"`
 	Payload interface{} `json:"payload"`
+	Seq     int64       `json:"seq,omitempty"`     // per-player, for resuming without a resync
+	GameID  string      `json:"game_id,omitempty"` // the game the message belongs to
+	// Debug clients only, for matching their timing reports
+	CorrelationID string `json:"correlation_id,omitempty"`
+	ServerTime    int64  `json:"server_time,omitempty"` // unix milliseconds
 }
 
 // JoinRoomPayload for joining a room
 type JoinRoomPayload struct {
-	RoomID      string `json:"room_id"`
-	PlayerID    string `json:"player_id"`
-	PlayerName  string `json:"player_name"`
-	AccessToken string `json:"access_token"`
+	RoomID       string             `json:"room_id"`
+	PlayerID     string             `json:"player_id"`
+	PlayerName   string             `json:"player_name"`
+	SessionToken string             `json:"session_token"` // for clients without the session cookie
+	Password     string             `json:"password"`
+	ResumeToken  string             `json:"resume_token"`
+	LastSeq      int64              `json:"last_seq"` // last message seen before the connection dropped
+	InviteToken  string             `json:"invite_token"`
+	Capabilities ClientCapabilities `json:"capabilities"`
+	Queue        bool               `json:"queue"`
+	PlaylistID   string             `json:"playlist_id"` // for rooms that draw on a playlist
 }
 
 // ReadyPayload for readying up
@@ -73,8 +167,11 @@ type ReadyPayload struct {
 
 // StartGamePayload for starting a game
 type StartGamePayload struct {
-	RoomID      string `json:"room_id"`
-	TotalRounds int    `json:"total_rounds"`
+	PlayerID      string `json:"player_id"`
+	RoomID        string `json:"room_id"`
+	TotalRounds   int    `json:"total_rounds"`
+	Powerups      bool   `json:"powerups"`
+	RoundDuration int    `json:"round_duration"` // seconds, overriding the room's setting for this game
 }
 
 // SubmitGuessPayload for submitting a guess
@@ -82,33 +179,118 @@ type SubmitGuessPayload struct {
 	RoomID          string `json:"room_id"`
 	PlayerID        string `json:"player_id"`
 	GuessedPlayerID string `json:"guessed_player_id"`
+	GuessedTitle    string `json:"guessed_title"` // title guess mode
+	GuessedTrackID  string `json:"guessed_track_id"` // reverse rounds
+}
+
+// SetPasswordPayload for the leader protecting the room with a password
+type SetPasswordPayload struct {
+	PlayerID string `json:"player_id"`
+	Password string `json:"password"`
+}
+
+// UpdateSettingsPayload for the leader changing room settings
+type UpdateSettingsPayload struct {
+	PlayerID string       `json:"player_id"`
+	Settings RoomSettings `json:"settings"`
+}
+
+// VoteKickPayload for voting to remove a player
+type VoteKickPayload struct {
+	PlayerID string `json:"player_id"`
+	TargetID string `json:"target_id"`
+}
+
+// EmotePayload for sending a quick message to the room
+type EmotePayload struct {
+	PlayerID string    `json:"player_id"`
+	Emote    EmoteType `json:"emote"`
+}
+
+// PredictionPayload for a spectator predicting a round's winner
+type PredictionPayload struct {
+	PlayerID          string `json:"player_id"`
+	PredictedPlayerID string `json:"predicted_player_id"`
+}
+
+// ExcludeTracksPayload for privately hiding some of a player's own top tracks
+type ExcludeTracksPayload struct {
+	PlayerID string   `json:"player_id"`
+	TrackIDs []string `json:"track_ids"`
+}
+
+// ChatPayload for a lobby chat message
+type ChatPayload struct {
+	PlayerID string `json:"player_id"`
+	Message  string `json:"message"`
+}
+
+// VoidRoundPayload for the leader voiding a completed round
+type VoidRoundPayload struct {
+	PlayerID string `json:"player_id"`
+	Round    int    `json:"round"`
+}
+
+// MiniGameAnswerPayload for answering an intermission mini-game
+type MiniGameAnswerPayload struct {
+	PlayerID string `json:"player_id"`
+	Answer   string `json:"answer"`
+}
+
+// UsePowerupPayload for activating a power-up before a round
+type UsePowerupPayload struct {
+	PlayerID string      `json:"player_id"`
+	Powerup  PowerupType `json:"powerup"`
 }
 
 // Guess represents a player's guess
 type Guess struct {
 	PlayerID        string    `json:"player_id"`
 	GuessedPlayerID string    `json:"guessed_player_id"`
+	GuessedTitle    string    `json:"guessed_title,omitempty"`
+	GuessedTrackID  string    `json:"guessed_track_id,omitempty"`
 	Timestamp       time.Time `json:"timestamp"`
 }
 
 // RoundResult contains the results of a round
 type RoundResult struct {
-	Round           int                    `json:"round"`
-	Track           auth.Track             `json:"track"`
-	WinnerID        string                 `json:"winner_id"`
-	WinnerRank      int                    `json:"winner_rank"`
-	CorrectGuessers []string               `json:"correct_guessers"`
-	PointsAwarded   map[string]int         `json:"points_awarded"`
-	AllRankings     map[string]int         `json:"all_rankings"`
-	UpdatedScores   map[string]int         `json:"updated_scores"`
-	GuessDurations  map[string]float64     `json:"guess_durations"`
+	Round                int                    `json:"round"`
+	Track                auth.Track             `json:"track"`
+	WinnerID             string                 `json:"winner_id"`
+	WinnerRank           int                    `json:"winner_rank"`
+	CorrectGuessers      []string               `json:"correct_guessers"`
+	PointsAwarded        map[string]int         `json:"points_awarded"`
+	AllRankings          map[string]int         `json:"all_rankings"`
+	UpdatedScores        map[string]int         `json:"updated_scores"`
+	GuessDurations       map[string]float64     `json:"guess_durations"`
+	PowerupsUsed         map[string]PowerupType `json:"powerups_used,omitempty"`
+	Streaks              map[string]int         `json:"streaks,omitempty"`
+	RollingScores        map[string]int         `json:"rolling_scores,omitempty"`
+	Bonus                bool                   `json:"bonus,omitempty"`
+	Affinity             map[string]int         `json:"affinity,omitempty"`         // bonus rounds: artist affinity per player
+	Reverse              bool                   `json:"reverse,omitempty"`          // the winner is the subject, the track their answer
+	ValidAnswers         []string               `json:"valid_answers,omitempty"`    // shared ties: every player a guess could name
+	SpectatorPredictions map[string]string      `json:"spectator_predictions,omitempty"`
+	SpectatorScores      map[string]int         `json:"spectator_scores,omitempty"` // spectator leaderboard, separate from the game
+	SourceAttribution    map[string][]auth.TrackSource `json:"source_attribution,omitempty"` // mixed pools: where each player's copy came from
+	Rerolled             bool                   `json:"rerolled,omitempty"`         // nobody got it right, so the track went back into the pool
+	GuessDistribution    []GuessShare           `json:"guess_distribution,omitempty"` // how many picked each candidate
+
+	guessers []string // everyone who guessed, for who-knows-whom
 }
 
 // PlayerInfo for client-side display
 type PlayerInfo struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Score    int    `json:"score"`
-	IsReady  bool   `json:"is_ready"`
-	IsLeader bool   `json:"is_leader"`
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Score        int                 `json:"score"`
+	IsReady      bool                `json:"is_ready"`
+	IsLeader     bool                `json:"is_leader"`
+	Streak       int                 `json:"streak"`
+	Powerups     map[PowerupType]int `json:"powerups,omitempty"`
+	Disconnected bool                `json:"disconnected,omitempty"`
+	IsSpectator  bool                `json:"is_spectator"`
+	Quality      ConnectionQuality   `json:"connection_quality,omitempty"`
+	IsBot        bool                `json:"is_bot,omitempty"`
+	Handicap     float64             `json:"handicap,omitempty"` // score multiplier, when not 1x
 }
\ No newline at end of file
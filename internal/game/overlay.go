@@ -0,0 +1,173 @@
+package game
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOverlayNotInRoom is returned when someone outside the room asks for
+	// its overlay token
+	ErrOverlayNotInRoom = errors.New("you must be in the room to stream it")
+	// ErrOverlayToken is returned for a missing or wrong overlay token
+	ErrOverlayToken = errors.New("invalid overlay token")
+)
+
+// OverlayState is a compact view of a room for stream overlays, small enough
+// to poll every second
+type OverlayState struct {
+	RoomID          string         `json:"room_id"`
+	Name            string         `json:"name"`
+	State           GameState      `json:"state"`
+	Round           int            `json:"round"`
+	TotalRounds     int            `json:"total_rounds"`
+	RoundEndsAt     *time.Time     `json:"round_ends_at,omitempty"`     // while a round is open
+	CountdownEndsAt *time.Time     `json:"countdown_ends_at,omitempty"` // while the lobby counts down to a game
+	Paused          bool           `json:"paused,omitempty"`
+	Scoreboard      []OverlayScore `json:"scoreboard"`
+	LastReveal      *OverlayReveal `json:"last_reveal,omitempty"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// OverlayScore is one line of the overlay's scoreboard
+type OverlayScore struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	Streak   int    `json:"streak,omitempty"`
+	IsBot    bool   `json:"is_bot,omitempty"`
+}
+
+// OverlayReveal is the most recently revealed round
+type OverlayReveal struct {
+	Round           int      `json:"round"`
+	TrackName       string   `json:"track_name"`
+	Artists         []string `json:"artists"`
+	ImageURL        string   `json:"image_url,omitempty"`
+	WinnerID        string   `json:"winner_id"`
+	WinnerName      string   `json:"winner_name"`
+	CorrectGuessers []string `json:"correct_guessers"` // names, fastest first
+}
+
+// OverlayToken returns the token that reads the room's overlay, creating it
+// the first time. Only someone in the room can get it.
+func (r *GameRoom) OverlayToken(playerID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, present := r.Players[playerID]; !present {
+		return "", ErrOverlayNotInRoom
+	}
+	if r.overlayToken == "" {
+		r.overlayToken = uuid.NewString()
+	}
+	return r.overlayToken, nil
+}
+
+// Overlay returns the room's overlay state if token is its overlay token
+func (r *GameRoom) Overlay(token string) (*OverlayState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.overlayToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(r.overlayToken)) != 1 {
+		return nil, ErrOverlayToken
+	}
+
+	overlay := &OverlayState{
+		RoomID:      r.ID,
+		Name:        r.Name,
+		State:       r.State,
+		Round:       r.CurrentRound,
+		TotalRounds: r.TotalRounds,
+		Scoreboard:  r.overlayScoreboard(),
+		LastReveal:  r.overlayReveal(),
+		Paused:      r.paused != nil,
+		UpdatedAt:   time.Now(),
+	}
+	if r.roundOpen() && r.paused == nil {
+		endsAt := r.roundEndsAt()
+		overlay.RoundEndsAt = &endsAt
+	}
+	if r.countdownActive {
+		endsAt := r.countdownEndsAt
+		overlay.CountdownEndsAt = &endsAt
+	}
+	return overlay, nil
+}
+
+// overlayScoreboard lists the players still in the game, highest score
+// first. Players on the same score share a rank. Callers must hold the room
+// lock.
+func (r *GameRoom) overlayScoreboard() []OverlayScore {
+	scoreboard := make([]OverlayScore, 0, len(r.PlayerOrder))
+	for _, id := range r.PlayerOrder {
+		player, ok := r.Players[id]
+		if !ok || player.IsSpectator {
+			continue
+		}
+		scoreboard = append(scoreboard, OverlayScore{
+			PlayerID: player.ID,
+			Name:     player.Name,
+			Score:    r.Scores[player.ID],
+			Streak:   player.Streak,
+			IsBot:    player.IsBot(),
+		})
+	}
+
+	sort.SliceStable(scoreboard, func(i, j int) bool {
+		return scoreboard[i].Score > scoreboard[j].Score
+	})
+	for i := range scoreboard {
+		scoreboard[i].Rank = i + 1
+		if i > 0 && scoreboard[i].Score == scoreboard[i-1].Score {
+			scoreboard[i].Rank = scoreboard[i-1].Rank
+		}
+	}
+	return scoreboard
+}
+
+// overlayReveal describes the latest round whose answer has been revealed,
+// or nil before the first. Callers must hold the room lock.
+func (r *GameRoom) overlayReveal() *OverlayReveal {
+	if r.State == StateWaiting {
+		return nil
+	}
+
+	var result *RoundResult
+	for round := r.CurrentRound; round > 0 && result == nil; round-- {
+		if candidate, ok := r.roundResults[round]; ok && !r.voidedRounds[round] {
+			result = candidate
+		}
+	}
+	if result == nil {
+		return nil
+	}
+
+	reveal := &OverlayReveal{
+		Round:           result.Round,
+		TrackName:       result.Track.Name,
+		Artists:         result.Track.Artists,
+		ImageURL:        result.Track.ImageURL,
+		WinnerID:        result.WinnerID,
+		WinnerName:      r.playerName(result.WinnerID),
+		CorrectGuessers: make([]string, 0, len(result.CorrectGuessers)),
+	}
+	for _, id := range result.CorrectGuessers {
+		reveal.CorrectGuessers = append(reveal.CorrectGuessers, r.playerName(id))
+	}
+	return reveal
+}
+
+// playerName is a player's display name, or their ID once they've left.
+// Callers must hold the room lock.
+func (r *GameRoom) playerName(playerID string) string {
+	if player, ok := r.Players[playerID]; ok {
+		return player.Name
+	}
+	return playerID
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOverlayFeed verifies the overlay token is only handed to players in the
+// room, and that the feed tracks the round, scoreboard and last reveal
+func TestOverlayFeed(t *testing.T) {
+	h := newGameHarness(t, 11)
+	h.room.Settings.TotalRounds = 2
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	if _, err := h.room.Overlay(""); err != ErrOverlayToken {
+		t.Errorf("Expected no overlay before a token was issued, got %v", err)
+	}
+	if _, err := h.room.OverlayToken("stranger"); err != ErrOverlayNotInRoom {
+		t.Errorf("Expected a stranger to be refused a token, got %v", err)
+	}
+	token, err := h.room.OverlayToken("A")
+	if err != nil || token == "" {
+		t.Fatalf("Expected a token for A, got %q (%v)", token, err)
+	}
+	if again, _ := h.room.OverlayToken("B"); again != token {
+		t.Errorf("Expected everyone in the room to share one token")
+	}
+	if _, err := h.room.Overlay("wrong"); err != ErrOverlayToken {
+		t.Errorf("Expected a wrong token to be refused, got %v", err)
+	}
+
+	overlay, _ := h.room.Overlay(token)
+	if overlay.State != StateWaiting || len(overlay.Scoreboard) != 2 || overlay.LastReveal != nil || overlay.RoundEndsAt != nil {
+		t.Errorf("Unexpected lobby overlay: %+v", overlay)
+	}
+
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	overlay, _ = h.room.Overlay(token)
+	if overlay.Round != 1 || overlay.RoundEndsAt == nil || !overlay.RoundEndsAt.After(time.Now()) {
+		t.Errorf("Expected round 1 to be open with an end time, got %+v", overlay)
+	}
+
+	h.room.mu.RLock()
+	owner := map[string]string{"t1": "A", "t2": "B"}[h.room.CurrentTrack.ID]
+	h.room.mu.RUnlock()
+	h.guess("A", owner, time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+
+	overlay, _ = h.room.Overlay(token)
+	reveal := overlay.LastReveal
+	if reveal == nil || reveal.Round != 1 || reveal.WinnerID != owner || len(reveal.CorrectGuessers) != 1 || reveal.CorrectGuessers[0] != h.room.Players["A"].Name {
+		t.Fatalf("Expected round 1's reveal with A right, got %+v", reveal)
+	}
+	if top := overlay.Scoreboard[0]; top.PlayerID != "A" || top.Rank != 1 || top.Score == 0 || overlay.Scoreboard[1].Rank != 2 {
+		t.Errorf("Expected A to lead the scoreboard, got %+v", overlay.Scoreboard)
+	}
+
+	t.Logf("✓ Overlay feed is token-protected and tracks rounds, scores and reveals")
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"log"
+
+	"roulettify/internal/auth"
+)
+
+// MaxOvertimeRounds caps how many sudden-death rounds a game can add. A game
+// still level after them is settled by the usual tie-breaks.
+const MaxOvertimeRounds = 5
+
+// overtime is a game's sudden-death phase: extra rounds that only the
+// players tied for the lead take part in
+type overtime struct {
+	contenders map[string]bool
+	order      []string // contenders in seat order, for broadcasts
+	rounds     int      // overtime rounds started so far
+}
+
+// tiedLeaders returns the players sharing the top score in seat order, or
+// nil when someone leads outright. Callers must hold the room lock.
+func (r *GameRoom) tiedLeaders() []string {
+	var leaders []string
+	best := 0
+	for _, id := range r.PlayerOrder {
+		player, ok := r.Players[id]
+		if !ok || player.IsSpectator {
+			continue
+		}
+		switch score := r.Scores[id]; {
+		case leaders == nil || score > best:
+			best, leaders = score, []string{id}
+		case score == best:
+			leaders = append(leaders, id)
+		}
+	}
+	if len(leaders) < 2 {
+		return nil
+	}
+	return leaders
+}
+
+// extendOvertime adds a sudden-death round when the last round left the lead
+// tied, announcing overtime the first time. It reports false when the game
+// should end instead: overtime is off, someone pulled ahead, the cap was
+// reached or the tied players have no tracks left. Callers must hold the
+// room lock.
+func (r *GameRoom) extendOvertime() bool {
+	if !r.Settings.Overtime {
+		return false
+	}
+	leaders := r.tiedLeaders()
+	if leaders == nil || (r.overtime != nil && r.overtime.rounds >= MaxOvertimeRounds) {
+		return false
+	}
+
+	starting := r.overtime == nil
+	previous := r.overtime
+	r.overtime = &overtime{contenders: make(map[string]bool), order: leaders}
+	for _, id := range leaders {
+		r.overtime.contenders[id] = true
+	}
+	if previous != nil {
+		r.overtime.rounds = previous.rounds
+	}
+
+	// Queue the round's track now so overtime is never announced without one
+	track := r.selectTrack()
+	if track == nil {
+		log.Printf("Room %s: no tracks left for overtime, ending the game tied", r.ID)
+		r.overtime = previous
+		return false
+	}
+	r.roundPlan = []auth.Track{*track}
+	r.overtime.rounds++
+	r.TotalRounds++
+
+	if starting {
+		log.Printf("Room %s: %d players tied on %d, going to overtime", r.ID, len(leaders), r.Scores[leaders[0]])
+		r.Broadcast <- Message{
+			Type: MsgTypeOvertimeStarted,
+			Payload: map[string]interface{}{
+				"contenders":   leaders,
+				"score":        r.Scores[leaders[0]],
+				"max_rounds":   MaxOvertimeRounds,
+				"total_rounds": r.TotalRounds,
+				"players":      r.getPlayerInfoList(),
+			},
+		}
+	}
+	return true
+}
+
+// benched reports whether the player sits out the current round because
+// it's an overtime round and they aren't tied for the lead. Callers must
+// hold the room lock.
+func (r *GameRoom) benched(playerID string) bool {
+	return r.overtime != nil && !r.overtime.contenders[playerID]
+}
+
+// overtimeGuessers is how many contenders are still playing overtime.
+// Callers must hold the room lock.
+func (r *GameRoom) overtimeGuessers() int {
+	count := 0
+	for id := range r.overtime.contenders {
+		if player, ok := r.Players[id]; ok && !player.IsSpectator {
+			count++
+		}
+	}
+	return count
+}
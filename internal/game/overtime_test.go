@@ -0,0 +1,100 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// harnessOwner is the player whose harness tracks are named after them, e.g. "a2" is A's
+func harnessOwner(trackID string) string {
+	return strings.ToUpper(trackID[:1])
+}
+
+// TestOvertimeSettlesTiedLead verifies a tied lead at the end goes to
+// sudden death between the tied players only, until one pulls ahead
+func TestOvertimeSettlesTiedLead(t *testing.T) {
+	h := newGameHarness(t, 5)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.Overtime = true
+
+	h.join(harnessPlayer("A", "a1", "a2", "a3"))
+	h.join(harnessPlayer("B", "b1", "b2", "b3"))
+	h.join(harnessPlayer("C", "c1", "c2", "c3"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	// A and B go into the last round level; nobody scores in it
+	h.room.mu.Lock()
+	h.room.Scores["A"], h.room.Scores["B"], h.room.Scores["C"] = 20, 20, 5
+	h.room.mu.Unlock()
+	for _, id := range []string{"A", "B", "C"} {
+		h.guess(id, "nobody", time.Second)
+	}
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+		MsgTypeRoundComplete, MsgTypeOvertimeStarted, MsgTypeRoundStarted)
+
+	started := msgs[4].Payload.(map[string]interface{})
+	if !reflect.DeepEqual(started["contenders"], []string{"A", "B"}) || started["total_rounds"] != 2 {
+		t.Errorf("Expected A and B to play overtime as round 2, got %v", started)
+	}
+	// The broadcast track is masked, so check the room's copy
+	round := msgs[5].Payload.(map[string]interface{})
+	h.room.mu.RLock()
+	trackOwner := harnessOwner(h.room.CurrentTrack.ID)
+	h.room.mu.RUnlock()
+	if round["overtime"] != true || trackOwner == "C" {
+		t.Errorf("Expected an overtime round on A's or B's track, got %v from %s", round["overtime"], trackOwner)
+	}
+
+	// C sits overtime out; A and B guessing ends the round
+	h.guess("C", trackOwner, time.Second)
+	h.guess("A", trackOwner, time.Second)
+	h.guess("B", "nobody", 2*time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	result := msgs[2].Payload.(*RoundResult)
+	if _, ranked := result.AllRankings["C"]; ranked || result.PointsAwarded["C"] != 0 {
+		t.Errorf("Expected C to sit the overtime round out, got %+v", result)
+	}
+	if over := msgs[3].Payload.(map[string]interface{}); over["winner_id"] != "A" {
+		t.Errorf("Expected A to win in overtime, got %v", over["winner_id"])
+	}
+
+	t.Logf("✓ Tied leaders play sudden death until one pulls ahead")
+}
+
+// TestOvertimeEndsAtCap verifies overtime gives up after MaxOvertimeRounds
+func TestOvertimeEndsAtCap(t *testing.T) {
+	h := newGameHarness(t, 6)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.Overtime = true
+
+	h.join(harnessPlayer("A", "a1", "a2", "a3", "a4", "a5", "a6"))
+	h.join(harnessPlayer("B", "b1", "b2", "b3", "b4", "b5", "b6"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted)
+
+	for round := 0; round <= MaxOvertimeRounds; round++ {
+		h.expect(MsgTypeRoundStarted)
+		h.guess("A", "nobody", time.Second)
+		h.guess("B", "nobody", time.Second)
+		h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+		if round == 0 {
+			h.expect(MsgTypeOvertimeStarted)
+		}
+	}
+	h.expect(MsgTypeGameOver)
+	h.expectQuiet(50 * time.Millisecond)
+
+	if h.room.TotalRounds != 1+MaxOvertimeRounds {
+		t.Errorf("Expected %d rounds, got %d", 1+MaxOvertimeRounds, h.room.TotalRounds)
+	}
+
+	t.Logf("✓ Overtime ends after %d rounds", MaxOvertimeRounds)
+}
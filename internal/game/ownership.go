@@ -0,0 +1,239 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRoomNotClaimable = errors.New("only dynamic rooms can be claimed")
+	ErrRoomAlreadyOwned = errors.New("room already has an owner")
+	ErrNotInRoom        = errors.New("you must be in the room to claim it")
+	ErrNoRoomStore      = errors.New("room ownership is not enabled on this server")
+)
+
+// OwnedRoomRecord is the persisted state of a claimed room
+type OwnedRoomRecord struct {
+	ID          string       `json:"id"`
+	OwnerID     string       `json:"owner_id"`
+	OwnerName   string       `json:"owner_name"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Region      string       `json:"region"`
+	Hostless    bool         `json:"hostless"`
+	Settings    RoomSettings `json:"settings"`
+	Banned      []string     `json:"banned"`
+	Webhook     *Webhook     `json:"webhook,omitempty"`
+	SavedAt     time.Time    `json:"saved_at"`
+}
+
+// RoomStore keeps claimed rooms as JSON files so they survive restarts
+type RoomStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewRoomStore creates a store in dir, creating the directory if needed
+func NewRoomStore(dir string) (*RoomStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create room store dir: %w", err)
+	}
+	return &RoomStore{dir: dir}, nil
+}
+
+func (s *RoomStore) path(roomID string) string {
+	return filepath.Join(s.dir, roomID+".room.json")
+}
+
+// Save writes a room record, replacing any previous version
+func (s *RoomStore) Save(record OwnedRoomRecord) error {
+	record.SavedAt = time.Now()
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write then rename so a crash never leaves a half-written record
+	tmp := s.path(record.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(record.ID))
+}
+
+// Load returns every stored room record
+func (s *RoomStore) Load() ([]OwnedRoomRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]OwnedRoomRecord, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".room.json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			log.Printf("Skipping room record %s: %v", entry.Name(), err)
+			continue
+		}
+		var record OwnedRoomRecord
+		if err := json.Unmarshal(data, &record); err != nil || record.ID == "" {
+			log.Printf("Skipping unreadable room record %s: %v", entry.Name(), err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].SavedAt.Before(records[j].SavedAt)
+	})
+	return records, nil
+}
+
+// claim makes an authenticated player the room's owner
+func (r *GameRoom) claim(ownerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.store == nil {
+		return ErrNoRoomStore
+	}
+	if r.OwnerID != "" {
+		return ErrRoomAlreadyOwned
+	}
+
+	owner, present := r.Players[ownerID]
+	if !present {
+		return ErrNotInRoom
+	}
+
+	r.OwnerID = owner.ID
+	r.OwnerName = owner.Name
+	log.Printf("Player %s claimed room %s", owner.Name, r.ID)
+
+	if !r.Hostless {
+		r.transferLeadership(owner)
+	}
+	r.persist()
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"owner_name": r.OwnerName,
+			"players":    r.getPlayerInfoList(),
+		},
+	}
+	return nil
+}
+
+// transferLeadership makes player the room's leader.
+// Callers must hold the room lock.
+func (r *GameRoom) transferLeadership(player *Player) {
+	if current, ok := r.Players[r.LeaderID]; ok && current != player {
+		current.IsLeader = false
+	}
+	player.IsLeader = true
+	r.LeaderID = player.ID
+}
+
+// rotateLeader passes leadership to the next person in seat order after a
+// game when the room rotates its leader, so a group shares control over a
+// night. Bots and disconnected players are skipped. Callers must hold the
+// room lock.
+func (r *GameRoom) rotateLeader() {
+	if !r.Settings.RotateLeader || r.Hostless || r.practice != nil {
+		return
+	}
+
+	next := r.nextLeader()
+	if next == nil {
+		return
+	}
+	r.transferLeadership(next)
+	log.Printf("Leadership of room %s rotated to %s", r.ID, next.Name)
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"leader_id": r.LeaderID,
+			"players":   r.getPlayerInfoList(),
+		},
+	}
+}
+
+// nextLeader returns the first person after the leader in seat order,
+// skipping bots and disconnected players, or nil if there's nobody else.
+// Callers must hold the room lock.
+func (r *GameRoom) nextLeader() *Player {
+	start := slices.Index(r.PlayerOrder, r.LeaderID)
+	for i := 1; i <= len(r.PlayerOrder); i++ {
+		next := r.Players[r.PlayerOrder[(start+i)%len(r.PlayerOrder)]]
+		if next == nil || next.IsBot() || next.Disconnected || next.ID == r.LeaderID {
+			continue
+		}
+		return next
+	}
+	return nil
+}
+
+// persist saves an owned room's durable state. Callers must hold the room lock.
+func (r *GameRoom) persist() {
+	if r.store == nil || r.OwnerID == "" {
+		return
+	}
+
+	banned := make([]string, 0, len(r.Banned))
+	for playerID := range r.Banned {
+		banned = append(banned, playerID)
+	}
+	sort.Strings(banned)
+
+	err := r.store.Save(OwnedRoomRecord{
+		ID:          r.ID,
+		OwnerID:     r.OwnerID,
+		OwnerName:   r.OwnerName,
+		Name:        r.Name,
+		Description: r.Description,
+		Region:      r.Region,
+		Hostless:    r.Hostless,
+		Settings:    r.Settings,
+		Banned:      banned,
+		Webhook:     r.webhook,
+	})
+	if err != nil {
+		log.Printf("Room %s: failed to persist: %v", r.ID, err)
+	}
+}
+
+// restore applies a stored record to a freshly created room
+func (r *GameRoom) restore(record OwnedRoomRecord) {
+	r.OwnerID = record.OwnerID
+	r.OwnerName = record.OwnerName
+	r.Name = record.Name
+	r.Description = record.Description
+	r.Region = record.Region
+	r.Hostless = record.Hostless
+	if err := record.Settings.Validate(); err == nil {
+		r.Settings = record.Settings
+	}
+	for _, playerID := range record.Banned {
+		r.Banned[playerID] = true
+	}
+	r.webhook = record.Webhook
+}
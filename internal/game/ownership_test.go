@@ -0,0 +1,113 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestClaimedRoomPersists verifies a claimed room's name, settings and bans survive a restart and its owner leads
+func TestClaimedRoomPersists(t *testing.T) {
+	dir := t.TempDir()
+
+	manager := NewRoomManager()
+	if err := manager.EnableRoomStore(dir); err != nil {
+		t.Fatalf("Failed to enable room store: %v", err)
+	}
+
+	room, err := manager.CreateRoom(RoomOptions{Name: "Indie heads only"})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	room.handlePlayerJoin(newTestPlayer("A"))
+	room.handlePlayerJoin(newTestPlayer("owner"))
+
+	if _, err := manager.ClaimRoom("Room 1", "owner"); err != ErrRoomNotClaimable {
+		t.Errorf("Expected persistent rooms to be unclaimable, got %v", err)
+	}
+	if _, err := manager.ClaimRoom(room.ID, "stranger"); err != ErrNotInRoom {
+		t.Errorf("Expected ErrNotInRoom for a player outside the room, got %v", err)
+	}
+	if _, err := manager.ClaimRoom(room.ID, "owner"); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	room.mu.RLock()
+	leaderID := room.LeaderID
+	room.mu.RUnlock()
+	if leaderID != "owner" {
+		t.Errorf("Expected the owner to lead the room, got %s", leaderID)
+	}
+
+	settings := DefaultRoomSettings()
+	settings.TotalRounds = 20
+	room.handleUpdateSettings(UpdateSettingsPayload{PlayerID: "owner", Settings: settings})
+	room.mu.Lock()
+	room.Banned["troll"] = true
+	room.persist()
+	room.mu.Unlock()
+
+	// Simulate a restart
+	restarted := NewRoomManager()
+	if err := restarted.EnableRoomStore(dir); err != nil {
+		t.Fatalf("Failed to reload room store: %v", err)
+	}
+	restored, err := restarted.GetRoom(room.ID)
+	if err != nil {
+		t.Fatalf("Claimed room was not restored: %v", err)
+	}
+
+	restored.mu.RLock()
+	name, rounds, banned := restored.Name, restored.Settings.TotalRounds, restored.Banned["troll"]
+	restored.mu.RUnlock()
+	if name != "Indie heads only" || rounds != 20 || !banned {
+		t.Errorf("Expected name, settings and bans to persist, got %q, %d rounds, banned=%v", name, rounds, banned)
+	}
+
+	restored.handlePlayerJoin(newTestPlayer("A"))
+	restored.handlePlayerJoin(newTestPlayer("owner"))
+	restored.mu.RLock()
+	leaderID = restored.LeaderID
+	restored.mu.RUnlock()
+	if leaderID != "owner" {
+		t.Errorf("Expected the owner to take the lead on rejoining, got %s", leaderID)
+	}
+
+	t.Logf("✓ Claimed rooms persist and their owner always leads")
+}
+
+// TestRotateLeaderAfterGame verifies leadership passes down the seat order
+// after each game, skipping players who can't take it
+func TestRotateLeaderAfterGame(t *testing.T) {
+	h := newGameHarness(t, 4)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.RotateLeader = true
+
+	for _, id := range []string{"A", "B", "C"} {
+		h.join(harnessPlayer(id, "t1"))
+	}
+	for _, id := range []string{"A", "B", "C"} {
+		h.ready(id)
+	}
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	for _, id := range []string{"A", "B", "C"} {
+		h.guess(id, "A", time.Second)
+	}
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+		MsgTypeRoundComplete, MsgTypeGameOver, MsgTypeRoomUpdated)
+	if leader := msgs[5].Payload.(map[string]interface{})["leader_id"]; leader != "B" || !h.room.Players["B"].IsLeader || h.room.Players["A"].IsLeader {
+		t.Fatalf("Expected leadership to pass from A to B, got %v", leader)
+	}
+
+	// C is away, so the next game's leadership wraps round to A
+	h.room.mu.Lock()
+	h.room.Players["C"].Disconnected = true
+	h.room.rotateLeader()
+	h.room.mu.Unlock()
+	h.expect(MsgTypeRoomUpdated)
+	if h.room.LeaderID != "A" {
+		t.Errorf("Expected leadership to skip C and return to A, got %s", h.room.LeaderID)
+	}
+
+	t.Logf("✓ Leadership rotates through the room after each game")
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// MaxPauseLength is how long a game stays paused before resuming itself, so
+// a forgotten pause doesn't hold the room forever
+const MaxPauseLength = 15 * time.Minute
+
+// PausePayload asks to pause the game, or to resume it when Paused is false
+type PausePayload struct {
+	PlayerID string `json:"-"`
+	Paused   bool   `json:"-"` // set from the message type
+}
+
+// gamePause is a paused game's frozen state
+type gamePause struct {
+	at         time.Time
+	remaining  time.Duration // left on the round timer; 0 when paused between rounds
+	nextRound  bool          // the intermission ran out while paused
+	botGuesses []Guess       // bots' guesses that came due while paused
+	timer      *time.Timer   // resumes the game after MaxPauseLength
+}
+
+func (r *GameRoom) handlePause(payload PausePayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can pause or resume the game")
+		return
+	}
+
+	if !payload.Paused {
+		if r.paused == nil {
+			r.sendError(payload.PlayerID, "The game isn't paused")
+			return
+		}
+		r.resumeGame(payload.PlayerID)
+		return
+	}
+
+	if r.State != StatePlaying {
+		r.sendError(payload.PlayerID, "There's no game to pause")
+		return
+	}
+	if r.paused != nil {
+		r.sendError(payload.PlayerID, "The game is already paused")
+		return
+	}
+	r.pauseGame(payload.PlayerID)
+}
+
+// pauseGame freezes the game: an open round's timer, hints and title reveal
+// stop and guesses are refused; between rounds the next round waits. Callers
+// must hold the room lock.
+func (r *GameRoom) pauseGame(playerID string) {
+	p := &gamePause{at: time.Now()}
+	payload := map[string]interface{}{
+		"paused_by":         playerID,
+		"round":             r.CurrentRound,
+		"max_pause_seconds": int(MaxPauseLength.Seconds()),
+	}
+
+	if r.roundOpen() {
+		if r.RoundTimer != nil {
+			r.RoundTimer.Stop()
+		}
+		r.stopHints()
+		if r.titleReveal != nil {
+			r.titleReveal.ticker.Stop()
+		}
+		p.remaining = max(time.Until(r.roundEndsAt()), 0)
+		payload["remaining_seconds"] = p.remaining.Seconds()
+	}
+
+	p.timer = time.AfterFunc(MaxPauseLength, func() {
+		r.autoResume(p)
+	})
+	r.paused = p
+	log.Printf("Room %s: game paused by %s in round %d", r.ID, playerID, r.CurrentRound)
+
+	r.Broadcast <- Message{
+		Type:    MsgTypeGamePaused,
+		Payload: payload,
+	}
+}
+
+// resumeGame picks the game up where it was paused: the round gets the time
+// it had left, with its start moved on by the pause so hints and time-based
+// scoring don't count it. playerID is empty when the pause ran out. Callers
+// must hold the room lock.
+func (r *GameRoom) resumeGame(playerID string) {
+	p := r.paused
+	r.clearPause()
+	r.touchActivity()
+
+	payload := map[string]interface{}{
+		"resumed_by": playerID,
+		"round":      r.CurrentRound,
+	}
+	if playerID == "" {
+		payload["auto"] = true
+	}
+
+	if r.roundOpen() {
+		r.RoundStartTime = r.RoundStartTime.Add(time.Since(p.at))
+		r.RoundTimer = time.AfterFunc(p.remaining, func() {
+			r.roundTimeUp()
+		})
+		r.startHintTimers()
+		if r.titleReveal != nil {
+			r.titleReveal.ticker.Reset(r.titleReveal.interval)
+		}
+		payload["round_ends_at"] = r.roundEndsAt()
+
+		// Bots that came due while paused guess shortly after, one by one
+		gameID, round := r.GameID, r.CurrentRound
+		for i, guess := range p.botGuesses {
+			time.AfterFunc(time.Duration(i+1)*time.Second, func() {
+				r.botGuess(gameID, round, guess)
+			})
+		}
+	}
+	if p.nextRound {
+		go r.startNextRound()
+	}
+	log.Printf("Room %s: game resumed after %v", r.ID, time.Since(p.at).Round(time.Second))
+
+	r.Broadcast <- Message{
+		Type:    MsgTypeGameResumed,
+		Payload: payload,
+	}
+}
+
+// autoResume resumes a game still on the pause that has run out
+func (r *GameRoom) autoResume(p *gamePause) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.paused != p || r.State != StatePlaying {
+		return
+	}
+	r.resumeGame("")
+}
+
+// clearPause drops any pause, e.g. when the game ends or is reset. Callers
+// must hold the room lock.
+func (r *GameRoom) clearPause() {
+	if r.paused == nil {
+		return
+	}
+	r.paused.timer.Stop()
+	r.paused = nil
+}
+
+// roundOpen reports whether a round is taking guesses, or would be if the
+// game weren't paused. Callers must hold the room lock.
+func (r *GameRoom) roundOpen() bool {
+	return r.State == StatePlaying && !r.revealing && r.CurrentTrack != nil
+}
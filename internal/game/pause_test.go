@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPauseAndResume verifies only the leader can pause, guesses are refused
+// while paused and resuming restores the round's remaining time
+func TestPauseAndResume(t *testing.T) {
+	h := newGameHarness(t, 9)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "a1"))
+	h.join(harnessPlayer("B", "b1"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.handlePause(PausePayload{PlayerID: "B", Paused: true})
+	h.expectQuiet(20 * time.Millisecond)
+	if h.room.paused != nil {
+		t.Fatal("Expected only the leader to be able to pause")
+	}
+
+	h.room.mu.RLock()
+	startedAt := h.room.RoundStartTime
+	h.room.mu.RUnlock()
+
+	h.room.handlePause(PausePayload{PlayerID: "A", Paused: true})
+	msgs := h.expect(MsgTypeGamePaused)
+	remaining := msgs[0].Payload.(map[string]interface{})["remaining_seconds"].(float64)
+	if remaining <= 0 || remaining > float64(h.room.Settings.RoundDuration) {
+		t.Errorf("Expected the round's remaining time in game_paused, got %v", remaining)
+	}
+
+	h.guess("B", "A", time.Second)
+	h.expectQuiet(50 * time.Millisecond)
+	if len(h.room.Guesses) != 0 {
+		t.Error("Expected guesses to be refused while paused")
+	}
+
+	h.room.handlePause(PausePayload{PlayerID: "A", Paused: false})
+	msgs = h.expect(MsgTypeGameResumed)
+	h.room.mu.RLock()
+	shifted := h.room.RoundStartTime.Sub(startedAt)
+	endsIn := time.Until(h.room.roundEndsAt()).Seconds()
+	h.room.mu.RUnlock()
+	if h.room.paused != nil || shifted < 50*time.Millisecond {
+		t.Errorf("Expected the round's start to move on by the pause, moved %v", shifted)
+	}
+	if endsIn > remaining || endsIn < remaining-1 {
+		t.Errorf("Expected about %.1fs left after resuming, got %.1fs", remaining, endsIn)
+	}
+	if _, ok := msgs[0].Payload.(map[string]interface{})["round_ends_at"]; !ok {
+		t.Error("Expected game_resumed to carry the new round end")
+	}
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	t.Logf("✓ The leader can pause a round and resume it with its time left")
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"log"
+
+	"roulettify/internal/auth"
+)
+
+// MinPlaylistTracks is the fewest tracks a playlist needs to be played
+const MinPlaylistTracks = 5
+
+// SetPlaylistPayload asks for the room's games to be played from a Spotify
+// playlist instead of the players' top tracks. The server fetches the tracks
+// before handing the request to the room.
+type SetPlaylistPayload struct {
+	PlayerID    string       `json:"player_id"`
+	PlaylistURL string       `json:"playlist_url"` // link, URI or ID; empty goes back to top tracks
+	PlaylistID  string       `json:"-"`
+	Tracks      []auth.Track `json:"-"`
+}
+
+// PlaylistInfo describes the playlist a room plays from
+type PlaylistInfo struct {
+	ID         string `json:"id"`
+	TrackCount int    `json:"track_count"`
+}
+
+// playlistPool is the playlist a room's games draw their rounds from
+type playlistPool struct {
+	id     string
+	tracks []auth.Track
+}
+
+func (r *GameRoom) handleSetPlaylist(payload SetPlaylistPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can choose a playlist")
+		return
+	}
+	if r.State == StatePlaying {
+		r.sendError(payload.PlayerID, "The playlist can't change mid-game")
+		return
+	}
+	if r.practice != nil {
+		r.sendError(payload.PlayerID, "Practice rooms play your own tracks")
+		return
+	}
+
+	if payload.PlaylistID == "" {
+		r.playlist = nil
+		log.Printf("Room %s: back to playing players' top tracks", r.ID)
+	} else {
+		seen := make(map[string]bool)
+		pool := &playlistPool{id: payload.PlaylistID}
+		for _, track := range payload.Tracks {
+			if !seen[track.ID] {
+				seen[track.ID] = true
+				pool.tracks = append(pool.tracks, track)
+			}
+		}
+		if len(pool.tracks) < MinPlaylistTracks {
+			r.sendError(payload.PlayerID, "That playlist needs at least 5 tracks")
+			return
+		}
+		r.playlist = pool
+		log.Printf("Room %s: playing playlist %s (%d tracks)", r.ID, pool.id, len(pool.tracks))
+	}
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"playlist":   r.playlistInfo(),
+			"guess_mode": r.guessMode(),
+		},
+	}
+}
+
+// playlistInfo describes the room's playlist, or nil when games use the
+// players' top tracks. Callers must hold the room lock.
+func (r *GameRoom) playlistInfo() *PlaylistInfo {
+	if r.playlist == nil {
+		return nil
+	}
+	return &PlaylistInfo{ID: r.playlist.id, TrackCount: len(r.playlist.tracks)}
+}
+
+// guessMode is what players guess: playlist tracks aren't anyone's, so
+// playlist games always guess the title or artist. Callers must hold the
+// room lock.
+func (r *GameRoom) guessMode() GuessMode {
+	if r.playlist != nil {
+		return GuessTitle
+	}
+	return r.Settings.GuessMode
+}
+
+// selectPlaylistTrack picks an unplayed track from the playlist, or nil once
+// all have been played. Callers must hold the room lock.
+func (r *GameRoom) selectPlaylistTrack() *auth.Track {
+	excluded := r.excludedTracks()
+	pool := make([]auth.Track, 0, len(r.playlist.tracks))
+	for _, track := range r.playlist.tracks {
+		if !r.PlayedTracks[track.ID] && !excluded[track.ID] && r.trackAllowed(track) {
+			pool = append(pool, track)
+		}
+	}
+	if len(pool) == 0 {
+		return nil
+	}
+	track := pool[r.pickIndex(len(pool))]
+	return &track
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// TestPlaylistGame verifies a leader's playlist replaces the players' tracks
+// as the round pool and that players guess the title or the artist
+func TestPlaylistGame(t *testing.T) {
+	h := newGameHarness(t, 9)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	playlist := make([]auth.Track, 6)
+	for i := range playlist {
+		playlist[i] = auth.Track{
+			ID:      fmt.Sprintf("p%d", i+1),
+			Name:    fmt.Sprintf("Song Number %d", i+1),
+			Artists: []string{fmt.Sprintf("The Band %d", i+1)},
+			Rank:    i + 1,
+		}
+	}
+
+	h.room.handleSetPlaylist(SetPlaylistPayload{PlayerID: "B", PlaylistID: "road-trip", Tracks: playlist})
+	h.room.handleSetPlaylist(SetPlaylistPayload{PlayerID: "A", PlaylistID: "short", Tracks: playlist[:MinPlaylistTracks-1]})
+	if h.room.playlist != nil {
+		t.Fatalf("Expected a non-leader's and a short playlist to be refused")
+	}
+
+	h.room.handleSetPlaylist(SetPlaylistPayload{PlayerID: "A", PlaylistID: "road-trip", Tracks: playlist})
+	msgs := h.expect(MsgTypeRoomUpdated)
+	update := msgs[0].Payload.(map[string]interface{})
+	if info := update["playlist"].(*PlaylistInfo); info.ID != "road-trip" || info.TrackCount != 6 || update["guess_mode"] != GuessTitle {
+		t.Errorf("Expected the playlist and title guessing to be announced, got %v", update)
+	}
+
+	h.ready("A")
+	h.ready("B")
+	msgs = h.expect(MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	if mode := msgs[3].Payload.(map[string]interface{})["guess_mode"]; mode != GuessTitle {
+		t.Errorf("Expected a title round, got %v", mode)
+	}
+
+	h.room.mu.RLock()
+	track := *h.room.CurrentTrack
+	started := h.room.RoundStartTime
+	h.room.mu.RUnlock()
+	if !strings.HasPrefix(track.ID, "p") {
+		t.Fatalf("Expected a playlist track, got %s", track.ID)
+	}
+
+	h.room.handleGuess(Guess{PlayerID: "A", GuessedTitle: track.Name, Timestamp: started.Add(time.Second)})
+	h.room.handleGuess(Guess{PlayerID: "B", GuessedTitle: strings.ToLower(track.Artists[0]), Timestamp: started.Add(2 * time.Second)})
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	if result := msgs[2].Payload.(*RoundResult); len(result.CorrectGuessers) != 2 {
+		t.Errorf("Expected the title and the artist to both count, got %v", result.CorrectGuessers)
+	}
+
+	t.Logf("✓ Playlist games draw rounds from the playlist and take titles or artists")
+}
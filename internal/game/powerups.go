@@ -0,0 +1,200 @@
+package game
+
+import (
+	"log"
+	"math/rand"
+	"slices"
+	"sort"
+)
+
+// PowerupType identifies an item a player can activate before a round
+type PowerupType string
+
+const (
+	// PowerupShield keeps the player's streak alive through one missed round
+	PowerupShield PowerupType = "shield"
+	// PowerupBooster doubles the points of the round it is active for
+	PowerupBooster PowerupType = "booster"
+	// PowerupFiftyFifty privately eliminates half the wrong choices of the
+	// round it is used in
+	PowerupFiftyFifty PowerupType = "fifty_fifty"
+)
+
+// StartingFiftyFifties is how many 50/50s every player is given per game
+const StartingFiftyFifties = 1
+
+// Streak milestones at which power-ups are earned
+const (
+	ShieldStreakMilestone  = 3
+	BoosterStreakMilestone = 5
+)
+
+func (r *GameRoom) handleUsePowerup(payload UsePowerupPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists {
+		return
+	}
+
+	if !r.PowerupsEnabled || r.State != StatePlaying {
+		r.sendError(player.ID, "Power-ups are not available right now")
+		return
+	}
+
+	if player.Powerups[payload.Powerup] <= 0 {
+		r.sendError(player.ID, "You don't have that power-up")
+		return
+	}
+
+	if _, active := r.ActivePowerups[player.ID]; active {
+		r.sendError(player.ID, "A power-up is already active for this round")
+		return
+	}
+
+	// Power-ups must be activated before the player locks in a guess
+	if _, guessed := r.Guesses[player.ID]; guessed {
+		r.sendError(player.ID, "Power-ups must be activated before guessing")
+		return
+	}
+
+	activated := map[string]interface{}{
+		"powerup": payload.Powerup,
+	}
+	if payload.Powerup == PowerupFiftyFifty {
+		eliminated, ok := r.fiftyFifty()
+		if !ok {
+			r.sendError(player.ID, "A 50/50 can't be used this round")
+			return
+		}
+		activated["eliminated"] = eliminated
+	}
+
+	player.Powerups[payload.Powerup]--
+	r.ActivePowerups[player.ID] = payload.Powerup
+	activated["powerups"] = player.Powerups
+
+	log.Printf("Player %s activated %s in room %s", player.Name, payload.Powerup, r.ID)
+
+	r.sendToPlayer(player.ID, Message{
+		Type:    MsgTypePowerupActive,
+		Payload: activated,
+	})
+}
+
+// fiftyFifty picks half the round's wrong choices, rounded down, to hide
+// from one player: player IDs, or track IDs in reverse rounds. It fails
+// between rounds, in title and bonus rounds, and when there's no more than
+// one wrong choice. Callers must hold the room lock.
+func (r *GameRoom) fiftyFifty() ([]string, bool) {
+	if r.revealing || r.CurrentTrack == nil {
+		return nil, false
+	}
+
+	var choices, answers []string
+	switch {
+	case r.activeReverse != nil:
+		for _, candidate := range r.activeReverse.Candidates {
+			choices = append(choices, candidate.ID)
+		}
+		answers = []string{r.activeReverse.Answer.ID}
+	case r.activeBonus != nil || r.guessMode() == GuessTitle:
+		return nil, false
+	default:
+		rankings, winnerID, bestRank := r.trackRankings()
+		for playerID := range rankings {
+			choices = append(choices, playerID)
+		}
+		answers = r.validAnswers(rankings, bestRank, winnerID)
+	}
+
+	wrong := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		if !slices.Contains(answers, choice) {
+			wrong = append(wrong, choice)
+		}
+	}
+	if len(wrong) < 2 {
+		return nil, false
+	}
+
+	sort.Strings(wrong)
+	rand.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+	eliminated := wrong[:len(wrong)/2]
+	sort.Strings(eliminated)
+	return eliminated, true
+}
+
+// resetPowerups clears streaks and inventories at the start of a game,
+// handing out each player's 50/50s when power-ups are on
+func (r *GameRoom) resetPowerups() {
+	r.ActivePowerups = make(map[string]PowerupType)
+	for _, player := range r.Players {
+		player.Streak = 0
+		player.Powerups = make(map[PowerupType]int)
+		if r.PowerupsEnabled && !player.IsSpectator {
+			player.Powerups[PowerupFiftyFifty] = StartingFiftyFifties
+		}
+	}
+}
+
+// applyStreaks updates streaks after a round has been scored, consuming
+// shields on misses and awarding power-ups at streak milestones. Active
+// power-ups are consumed and returned for the round result.
+func (r *GameRoom) applyStreaks(correctGuessers []string) (map[string]PowerupType, map[string]int) {
+	correct := make(map[string]bool, len(correctGuessers))
+	for _, playerID := range correctGuessers {
+		correct[playerID] = true
+	}
+
+	used := r.ActivePowerups
+	r.ActivePowerups = make(map[string]PowerupType)
+	streaks := make(map[string]int, len(r.Players))
+
+	for playerID, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		if player.Powerups == nil {
+			player.Powerups = make(map[PowerupType]int)
+		}
+
+		if !correct[playerID] {
+			if used[playerID] != PowerupShield {
+				player.Streak = 0
+			}
+			streaks[playerID] = player.Streak
+			continue
+		}
+
+		player.Streak++
+		streaks[playerID] = player.Streak
+
+		earned := make([]PowerupType, 0, 2)
+		if player.Streak%ShieldStreakMilestone == 0 {
+			earned = append(earned, PowerupShield)
+		}
+		if player.Streak%BoosterStreakMilestone == 0 {
+			earned = append(earned, PowerupBooster)
+		}
+
+		for _, powerup := range earned {
+			player.Powerups[powerup]++
+			log.Printf("Player %s earned %s in room %s (streak %d)", player.Name, powerup, r.ID, player.Streak)
+		}
+
+		if len(earned) > 0 {
+			r.sendToPlayer(playerID, Message{
+				Type: MsgTypePowerupEarned,
+				Payload: map[string]interface{}{
+					"earned":   earned,
+					"streak":   player.Streak,
+					"powerups": player.Powerups,
+				},
+			})
+		}
+	}
+
+	return used, streaks
+}
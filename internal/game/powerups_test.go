@@ -0,0 +1,120 @@
+package game
+
+import (
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+func newTestPlayer(id string) *Player {
+	return &Player{
+		Player: &auth.Player{
+			ID:        id,
+			Name:      "Player " + id,
+			SpotifyID: "spotify-" + id,
+			TopTracks: make([]auth.Track, 0),
+		},
+	}
+}
+
+// TestStreakMilestonesAwardPowerups verifies shields and boosters are earned at milestones
+func TestStreakMilestonesAwardPowerups(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.PowerupsEnabled = true
+	room.resetPowerups()
+
+	for i := 0; i < BoosterStreakMilestone; i++ {
+		room.applyStreaks([]string{"A"})
+	}
+
+	a := room.Players["A"]
+	if a.Streak != BoosterStreakMilestone {
+		t.Errorf("Expected streak %d, got %d", BoosterStreakMilestone, a.Streak)
+	}
+	if a.Powerups[PowerupShield] != 1 {
+		t.Errorf("Expected 1 shield, got %d", a.Powerups[PowerupShield])
+	}
+	if a.Powerups[PowerupBooster] != 1 {
+		t.Errorf("Expected 1 booster, got %d", a.Powerups[PowerupBooster])
+	}
+	if room.Players["B"].Streak != 0 {
+		t.Errorf("Expected B's streak to stay 0, got %d", room.Players["B"].Streak)
+	}
+
+	t.Logf("✓ Streak milestones award power-ups")
+}
+
+// TestShieldProtectsStreak verifies an active shield absorbs one missed round
+func TestShieldProtectsStreak(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.PowerupsEnabled = true
+	room.resetPowerups()
+
+	room.applyStreaks([]string{"A"})
+	room.applyStreaks([]string{"A"})
+
+	room.ActivePowerups["A"] = PowerupShield
+	used, streaks := room.applyStreaks(nil)
+
+	if used["A"] != PowerupShield {
+		t.Errorf("Expected shield to be reported as used, got %q", used["A"])
+	}
+	if streaks["A"] != 2 {
+		t.Errorf("Expected shielded streak to stay at 2, got %d", streaks["A"])
+	}
+
+	_, streaks = room.applyStreaks(nil)
+	if streaks["A"] != 0 {
+		t.Errorf("Expected unshielded miss to reset streak, got %d", streaks["A"])
+	}
+
+	t.Logf("✓ Shield protects a streak exactly once")
+}
+
+// TestFiftyFiftyEliminatesWrongChoices verifies a 50/50 privately hides half the wrong players and is handed out per game
+func TestFiftyFiftyEliminatesWrongChoices(t *testing.T) {
+	room := NewGameRoom("test-room")
+	for _, id := range []string{"A", "B", "C", "D", "E"} {
+		room.Players[id] = newTestPlayer(id)
+	}
+	room.Players["A"].TopTracks = []auth.Track{{ID: "t1", Rank: 1}}
+	room.State = StatePlaying
+	room.CurrentTrack = &auth.Track{ID: "t1"}
+	room.PowerupsEnabled = true
+	room.resetPowerups()
+
+	if room.Players["B"].Powerups[PowerupFiftyFifty] != StartingFiftyFifties {
+		t.Fatalf("Expected every player to start with a 50/50, got %v", room.Players["B"].Powerups)
+	}
+
+	room.handleUsePowerup(UsePowerupPayload{PlayerID: "B", Powerup: PowerupFiftyFifty})
+	sent := room.Players["B"].replay.entries
+	msg := sent[len(sent)-1]
+	if msg.Type != MsgTypePowerupActive {
+		t.Fatalf("Expected powerup_activated, got %s", msg.Type)
+	}
+	eliminated := msg.Payload.(map[string]interface{})["eliminated"].([]string)
+	if len(eliminated) != 2 {
+		t.Fatalf("Expected 2 of the 4 wrong players eliminated, got %v", eliminated)
+	}
+	for _, id := range eliminated {
+		if id == "A" {
+			t.Errorf("The 50/50 eliminated the right answer: %v", eliminated)
+		}
+	}
+	if room.Players["B"].Powerups[PowerupFiftyFifty] != 0 || room.ActivePowerups["B"] != PowerupFiftyFifty {
+		t.Errorf("Expected the 50/50 to be used up and active")
+	}
+
+	// Title rounds have no choices to eliminate, so the 50/50 is kept
+	room.Settings.GuessMode = GuessTitle
+	room.handleUsePowerup(UsePowerupPayload{PlayerID: "C", Powerup: PowerupFiftyFifty})
+	if room.Players["C"].Powerups[PowerupFiftyFifty] != 1 {
+		t.Errorf("Expected the 50/50 to be refused in title rounds")
+	}
+
+	t.Logf("✓ A 50/50 hides half the wrong choices from its user")
+}
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Practice rooms seat one player against this many bots
+const (
+	MinPracticeBots     = 2
+	MaxPracticeBots     = 4
+	DefaultPracticeBots = 3
+)
+
+// botNames name bots in the order a room seats them
+var botNames = []string{"Echo", "Reverb", "Tempo", "Treble", "Chorus", "Fader", "Octave", "Reprise", "Loop"}
+
+// botSeed seeds bot players and their guesses. Tests pin it so games with
+// bots replay identically.
+var botSeed = func() int64 { return time.Now().UnixNano() }
+
+// practiceSetup is how a practice room fills itself with bots
+type practiceSetup struct {
+	bots    int
+	profile BotProfile
+}
+
+// IsBot reports whether the player is a bot rather than a person
+func (p *Player) IsBot() bool {
+	return p.bot != nil
+}
+
+// validatePractice checks the practice options, filling in the default bot
+// count and profile
+func (o *RoomOptions) validatePractice() error {
+	if o.Tutorial && (o.Practice || o.Hostless) {
+		return fmt.Errorf("%w: tutorials can't be combined with practice or hostless rooms", ErrInvalidRoomOptions)
+	}
+	if !o.Practice {
+		if o.Bots != 0 || o.BotProfile != "" {
+			return fmt.Errorf("%w: bots are only available in practice rooms", ErrInvalidRoomOptions)
+		}
+		return nil
+	}
+
+	if o.Hostless {
+		return fmt.Errorf("%w: practice rooms can't be hostless", ErrInvalidRoomOptions)
+	}
+	if o.Bots == 0 {
+		o.Bots = DefaultPracticeBots
+	}
+	if o.Bots < MinPracticeBots || o.Bots > MaxPracticeBots {
+		return fmt.Errorf("%w: practice rooms need %d-%d bots", ErrInvalidRoomOptions, MinPracticeBots, MaxPracticeBots)
+	}
+	if o.BotProfile == "" {
+		o.BotProfile = DefaultBotProfile
+	}
+	if _, err := GetBotProfile(o.BotProfile); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoomOptions, err)
+	}
+	return nil
+}
+
+// humanCount is how many seated players aren't bots. Callers must hold the
+// room lock.
+func (r *GameRoom) humanCount() int {
+	count := 0
+	for _, p := range r.Players {
+		if !p.IsBot() {
+			count++
+		}
+	}
+	return count
+}
+
+// activeHumanCount is how many people are playing rather than watching.
+// Callers must hold the room lock.
+func (r *GameRoom) activeHumanCount() int {
+	count := 0
+	for _, p := range r.Players {
+		if !p.IsSpectator && !p.IsBot() {
+			count++
+		}
+	}
+	return count
+}
+
+// seatBots fills a practice room with bots once its player has joined. The
+// bots' top tracks are drawn from the player's so every round is playable.
+// Callers must hold the room lock.
+func (r *GameRoom) seatBots(human *Player) {
+	if r.practice == nil || len(r.Players) > 1 {
+		return
+	}
+	if r.tutorial != nil {
+		r.seatTutorial(human)
+		return
+	}
+
+	for i := 0; i < r.practice.bots; i++ {
+		r.seatPlayer(r.newBot(r.practice.profile, human.TopTracks))
+	}
+	log.Printf("Room %s: seated %d %s bots for %s", r.ID, r.practice.bots, r.practice.profile.Name, human.Name)
+}
+
+// dismissBots empties a room of bots once no one is left to play them.
+// Callers must hold the room lock.
+func (r *GameRoom) dismissBots() {
+	if r.humanCount() > 0 {
+		return
+	}
+	for _, id := range append([]string(nil), r.PlayerOrder...) {
+		if p, ok := r.Players[id]; ok && p.IsBot() {
+			r.removePlayer(id, "No players left")
+		}
+	}
+}
+
+// scheduleBotGuesses has every bot decide on the round that just started and
+// guess after its profile's delay. Callers must hold the room lock.
+func (r *GameRoom) scheduleBotGuesses() {
+	if r.humanCount() == len(r.Players) {
+		return
+	}
+
+	round := BotRound{
+		LeaderID: r.getWinnerID(),
+		Duration: r.currentRoundDuration(),
+	}
+	for _, id := range r.PlayerOrder {
+		if p, ok := r.Players[id]; ok && !p.IsSpectator && !r.benched(id) {
+			round.Candidates = append(round.Candidates, id)
+		}
+	}
+	if last, ok := r.roundResults[r.CurrentRound-1]; ok {
+		round.LastWinnerID = last.WinnerID
+	}
+	// Bonus rounds are about an artist, not a track, so bots guess blind
+	if r.activeBonus == nil {
+		_, round.WinnerID, round.WinnerRank = r.trackRankings()
+	}
+
+	gameID, number := r.GameID, r.CurrentRound
+	for _, id := range round.Candidates {
+		player := r.Players[id]
+		if !player.IsBot() {
+			continue
+		}
+		round.BotID = id
+
+		guess, ok := r.botDecide(player.bot, round)
+		if !ok {
+			continue
+		}
+		guess.PlayerID = id
+		time.AfterFunc(guess.delay, func() {
+			r.botGuess(gameID, number, guess.Guess)
+		})
+	}
+}
+
+// botDecision is a bot's guess and when to send it
+type botDecision struct {
+	Guess
+	delay time.Duration
+}
+
+// botDecide turns a bot's decision into a guess for the current round's
+// mode. ok is false when the bot sits the round out. Callers must hold the
+// room lock.
+func (r *GameRoom) botDecide(brain *BotBrain, round BotRound) (botDecision, bool) {
+	if r.tutorial != nil {
+		return r.tutorialGuess(round), true
+	}
+
+	decision := brain.Decide(round)
+	result := botDecision{delay: decision.Delay}
+
+	switch {
+	case r.activeReverse != nil:
+		rr := r.activeReverse
+		if round.BotID == rr.SubjectID {
+			return result, false
+		}
+		result.GuessedTrackID = rr.Candidates[brain.rng.Intn(len(rr.Candidates))].ID
+		if brain.rng.Float64() < brain.accuracy(rr.Answer.Rank) {
+			result.GuessedTrackID = rr.Answer.ID
+		}
+	case r.guessMode() == GuessTitle && r.activeBonus == nil:
+		// Only a bot that recognizes the track can name it
+		if decision.GuessedPlayerID != round.WinnerID {
+			return result, false
+		}
+		result.GuessedTitle = r.CurrentTrack.Name
+	default:
+		result.GuessedPlayerID = decision.GuessedPlayerID
+	}
+	return result, true
+}
+
+// botGuess submits a bot's guess if the round it was made for is still open
+func (r *GameRoom) botGuess(gameID string, round int, guess Guess) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != StatePlaying || r.GameID != gameID || r.CurrentRound != round || r.revealing {
+		return
+	}
+	if _, guessed := r.Guesses[guess.PlayerID]; guessed {
+		return
+	}
+	if r.paused != nil {
+		r.paused.botGuesses = append(r.paused.botGuesses, guess)
+		return
+	}
+
+	guess.Timestamp = time.Now()
+	r.acceptGuess(guess)
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPracticeRoomPlaysAgainstBots verifies a practice room seats ready bots
+// for its one player, has them guess each round and empties when they leave
+func TestPracticeRoomPlaysAgainstBots(t *testing.T) {
+	for _, opts := range []RoomOptions{
+		{Practice: true, Bots: MaxPracticeBots + 1},
+		{Practice: true, BotProfile: "nobody"},
+		{Practice: true, Hostless: true},
+		{Bots: 2},
+	} {
+		if err := opts.Validate(); !errors.Is(err, ErrInvalidRoomOptions) {
+			t.Errorf("Expected %+v to be rejected, got %v", opts, err)
+		}
+	}
+	opts := RoomOptions{Practice: true}
+	if err := opts.Validate(); err != nil || opts.Bots != DefaultPracticeBots || opts.BotProfile != DefaultBotProfile {
+		t.Errorf("Expected practice defaults to be filled in, got %+v (%v)", opts, err)
+	}
+
+	previous := botSeed
+	botSeed = func() int64 { return 42 }
+	t.Cleanup(func() { botSeed = previous })
+
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 1
+	h.room.practice = &practiceSetup{bots: 2, profile: BotProfile{
+		Name:          "perfect",
+		AccuracyCurve: []AccuracyPoint{{Rank: 1, Accuracy: 1}},
+		Latency:       LatencyProfile{Distribution: LatencyUniform, MinMs: 10, MaxMs: 20},
+	}}
+
+	human := harnessPlayer("A", "t1", "t2", "t3", "t4", "t5", "t6")
+	h.join(human)
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	h.room.mu.RLock()
+	catalogue := make(map[string]bool)
+	for _, track := range human.TopTracks {
+		catalogue[track.ID] = true
+	}
+	for _, info := range h.room.getPlayerInfoList()[1:] {
+		if !info.IsBot || !info.IsReady {
+			t.Errorf("Expected %s to be a ready bot, got %+v", info.ID, info)
+		}
+		for _, track := range h.room.Players[info.ID].TopTracks {
+			if !catalogue[track.ID] {
+				t.Errorf("Expected bot tracks from the player's library, got %s", track.ID)
+			}
+		}
+	}
+	h.room.mu.RUnlock()
+
+	h.join(harnessPlayer("B", "t1"))
+	if len(h.room.Players) != 3 {
+		t.Errorf("Expected a second person to be turned away, got %d players", len(h.room.Players))
+	}
+
+	h.ready("A")
+	h.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived)
+	h.guess("A", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	h.room.mu.RLock()
+	result := h.room.roundResults[1]
+	h.room.mu.RUnlock()
+	for _, botID := range []string{"bot-harness-room-1", "bot-harness-room-2"} {
+		found := false
+		for _, id := range result.CorrectGuessers {
+			found = found || id == botID
+		}
+		if !found {
+			t.Errorf("Expected %s to recognize the track, correct guessers were %v", botID, result.CorrectGuessers)
+		}
+	}
+
+	h.room.handlePlayerLeave("A")
+	h.expect(MsgTypePlayerLeft, MsgTypePlayerLeft, MsgTypePlayerLeft)
+	if len(h.room.Players) != 0 || h.room.State != StateWaiting {
+		t.Errorf("Expected the bots to leave with the player, %d left in %s", len(h.room.Players), h.room.State)
+	}
+
+	t.Logf("✓ Practice rooms seat ready bots that guess and leave with their player")
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Presence is the instance's session store: which room each player is in
+// right now. In strict mode a player can only be in one room at a time, so a
+// second tab or device can't join another room behind the first one's back.
+// A nil *Presence tracks nothing.
+type Presence struct {
+	mu     sync.Mutex
+	strict bool
+	rooms  map[string]presenceEntry // by player ID
+}
+
+type presenceEntry struct {
+	roomID   string
+	roomName string
+}
+
+// NewPresence creates an empty session store with strict mode off
+func NewPresence() *Presence {
+	return &Presence{rooms: make(map[string]presenceEntry)}
+}
+
+// SetStrict switches strict mode on or off. Players already in several rooms
+// keep their seats.
+func (p *Presence) SetStrict(strict bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.strict = strict
+}
+
+// Strict reports whether players are held to one room at a time
+func (p *Presence) Strict() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.strict
+}
+
+// RoomOf returns the ID of the room the player is in, if any
+func (p *Presence) RoomOf(playerID string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.rooms[playerID]
+	return entry.roomID, ok
+}
+
+// claim records the player as being in the room. In strict mode it fails if
+// they're already in a different one.
+func (p *Presence) claim(playerID, roomID, roomName string) error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.rooms[playerID]; ok && entry.roomID != roomID && p.strict {
+		return fmt.Errorf("you're already playing in %s, leave it before joining another room", entry.roomName)
+	}
+	p.rooms[playerID] = presenceEntry{roomID: roomID, roomName: roomName}
+	return nil
+}
+
+// release forgets the player's seat in the room. A seat in another room,
+// taken while strict mode was off, is left alone.
+func (p *Presence) release(playerID, roomID string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rooms[playerID].roomID == roomID {
+		delete(p.rooms, playerID)
+	}
+}
+
+// claimSeat records a person taking a seat, telling them why not when strict
+// mode holds them to another room. Callers must hold the room lock.
+func (r *GameRoom) claimSeat(player *Player) bool {
+	if player.IsBot() {
+		return true
+	}
+	err := r.presence.claim(player.ID, r.ID, r.Name)
+	if err == nil {
+		return true
+	}
+
+	log.Printf("Player %s rejected from room %s: already in another room", player.Name, r.ID)
+	roomID, _ := r.presence.RoomOf(player.ID)
+	r.sendDirect(player, Message{
+		Type: MsgTypeError,
+		Payload: map[string]interface{}{
+			"message": err.Error(),
+			"room_id": roomID,
+		},
+	})
+	return false
+}
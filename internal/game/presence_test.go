@@ -0,0 +1,48 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestStrictSessionsHoldPlayersToOneRoom verifies strict mode turns away a
+// player already in another room until they leave it, and that without
+// strict mode they can sit in both
+func TestStrictSessionsHoldPlayersToOneRoom(t *testing.T) {
+	presence := NewPresence()
+	presence.SetStrict(true)
+
+	first := newGameHarness(t, 1)
+	second := newGameHarness(t, 2)
+	second.room.ID, second.room.Name = "other-room", "Other Room"
+	first.room.presence = presence
+	second.room.presence = presence
+
+	first.join(harnessPlayer("A", "a1"))
+	first.expect(MsgTypePlayerJoined)
+	second.join(harnessPlayer("A", "a1"))
+	second.expectQuiet(20 * time.Millisecond)
+	if _, seated := second.room.Players["A"]; seated {
+		t.Fatal("Expected A to be turned away from a second room")
+	}
+	if roomID, _ := presence.RoomOf("A"); roomID != first.room.ID {
+		t.Errorf("Expected A to still be recorded in the first room, got %q", roomID)
+	}
+
+	first.room.handlePlayerLeave("A")
+	first.expect(MsgTypePlayerLeft)
+	second.join(harnessPlayer("A", "a1"))
+	second.expect(MsgTypePlayerJoined)
+	if roomID, _ := presence.RoomOf("A"); roomID != second.room.ID {
+		t.Errorf("Expected A to be recorded in the second room after leaving the first, got %q", roomID)
+	}
+
+	presence.SetStrict(false)
+	first.join(harnessPlayer("A", "a1"))
+	first.expect(MsgTypePlayerJoined)
+	if _, seated := first.room.Players["A"]; !seated {
+		t.Error("Expected A to join a second room with strict mode off")
+	}
+
+	t.Logf("✓ Strict mode keeps a player to one room at a time")
+}
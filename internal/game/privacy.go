@@ -0,0 +1,325 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"roulettify/internal/auth"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+)
+
+// DeletedPlayerName stands in for a deleted player's name in saved games
+const DeletedPlayerName = "Deleted player"
+
+// PlayerStats summarises a player's saved games
+type PlayerStats struct {
+	LifetimeStats
+	BestScore int          `json:"best_score"`
+	Rivals    []Rivalry    `json:"rivals"`
+	Taste     TasteProfile `json:"taste"`
+}
+
+// PlayerData is everything the game server keeps about a player
+type PlayerData struct {
+	Games        []GameRecord   `json:"games"`
+	Stats        PlayerStats    `json:"stats"`
+	Seasons      []PlayerSeason `json:"seasons"`
+	Profile      *ProfileInfo   `json:"profile,omitempty"`
+	StoredGames  []GameRecord   `json:"stored_games"` // final scores in the results database, if any
+	OwnedRooms   []string       `json:"owned_rooms"`
+	CurrentRooms []string       `json:"current_rooms"`
+	// Spotify top tracks held in memory while the player is in a room
+	TopTracks []auth.Track `json:"top_tracks"`
+}
+
+// PlayerDeletion reports what was removed when a player deleted their data
+type PlayerDeletion struct {
+	GamesAnonymized   int      `json:"games_anonymized"`
+	SeasonsAnonymized int      `json:"seasons_anonymized"`
+	ReplaysDeleted    int      `json:"replays_deleted"`
+	ProfileDeleted    bool     `json:"profile_deleted"`
+	ResultsAnonymized int      `json:"results_anonymized"` // games in the results database
+	RoomsLeft         []string `json:"rooms_left"`
+	RoomsReleased     []string `json:"rooms_released"`
+}
+
+// Stats summarises the player's saved games
+func (h *HistoryStore) Stats(playerID string) PlayerStats {
+	stats := PlayerStats{
+		LifetimeStats: h.Lifetime(playerID),
+		Rivals:        h.Rivals(playerID),
+		Taste:         h.Taste(playerID),
+	}
+	for _, game := range h.Games(playerID) {
+		score, _ := game.score(playerID)
+		stats.BestScore = max(stats.BestScore, score)
+	}
+	return stats
+}
+
+// Anonymize replaces the player's ID and name in every saved game with a
+// pseudonym, keeping the games intact for everyone else's stats. It returns
+// how many games were rewritten.
+func (h *HistoryStore) Anonymize(playerID string) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// One pseudonym per deletion so opponents' head-to-head records still add up
+	pseudonym := "deleted-" + uuid.New().String()[:8]
+
+	records := make([]GameRecord, len(h.records))
+	anonymized := 0
+	for i, record := range h.records {
+		players := make([]RecordPlayer, len(record.Players))
+		copy(players, record.Players)
+		record.Players = players
+
+		changed := false
+		for j := range record.Players {
+			if record.Players[j].ID == playerID {
+				record.Players[j].ID = pseudonym
+				record.Players[j].Name = DeletedPlayerName
+				changed = true
+			}
+		}
+		if record.WinnerID == playerID {
+			record.WinnerID = pseudonym
+		}
+		if len(record.Taste) > 0 {
+			taste := make([]TasteLink, len(record.Taste))
+			copy(taste, record.Taste)
+			for j := range taste {
+				if taste[j].GuesserID == playerID {
+					taste[j].GuesserID = pseudonym
+				}
+				if taste[j].TargetID == playerID {
+					taste[j].TargetID = pseudonym
+				}
+			}
+			record.Taste = taste
+		}
+		if changed {
+			anonymized++
+		}
+		records[i] = record
+	}
+	if anonymized == 0 {
+		return 0, nil
+	}
+
+	// Write then rename so a crash never leaves the history half-rewritten
+	tmp := h.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return 0, err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return 0, err
+	}
+
+	h.records = records
+	return anonymized, nil
+}
+
+// Delete removes a room's record and its leaderboard
+func (s *RoomStore) Delete(roomID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range []string{s.path(roomID), s.leaderboardPath(roomID)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportPlayer collects everything the server keeps about a player
+func (rm *RoomManager) ExportPlayer(playerID string) (PlayerData, error) {
+	data := PlayerData{
+		Games:        make([]GameRecord, 0),
+		Seasons:      make([]PlayerSeason, 0),
+		StoredGames:  make([]GameRecord, 0),
+		OwnedRooms:   make([]string, 0),
+		CurrentRooms: make([]string, 0),
+		TopTracks:    make([]auth.Track, 0),
+	}
+
+	for _, room := range rm.allRooms() {
+		room.mu.RLock()
+		if room.OwnerID == playerID {
+			data.OwnedRooms = append(data.OwnedRooms, room.ID)
+		}
+		if player, present := room.Players[playerID]; present {
+			data.CurrentRooms = append(data.CurrentRooms, room.ID)
+			if len(data.TopTracks) == 0 {
+				data.TopTracks = append(data.TopTracks, player.TopTracks...)
+			}
+		}
+		room.mu.RUnlock()
+	}
+
+	if history, err := rm.History(); err == nil {
+		data.Games = history.Games(playerID)
+		data.Stats = history.Stats(playerID)
+	}
+	if seasons, err := rm.Seasons(); err == nil {
+		data.Seasons = seasons.PlayerSeasons(playerID)
+	}
+	if profiles, err := rm.Profiles(); err == nil {
+		if info, ok := profiles.Info(playerID); ok {
+			data.Profile = &info
+		}
+	}
+	if results, ok := rm.playerResults(); ok {
+		games, err := results.PlayerGames(playerID)
+		if err != nil {
+			return data, err
+		}
+		data.StoredGames = games
+	}
+	return data, nil
+}
+
+// ForgetPlayer removes a player from every room, releases rooms they own,
+// anonymizes their saved games, stored results and season standings and
+// deletes replays of games they played and their profile
+func (rm *RoomManager) ForgetPlayer(playerID string) (PlayerDeletion, error) {
+	deletion := PlayerDeletion{
+		RoomsLeft:     make([]string, 0),
+		RoomsReleased: make([]string, 0),
+	}
+
+	for _, room := range rm.allRooms() {
+		left, released := room.forget(playerID)
+		if left {
+			deletion.RoomsLeft = append(deletion.RoomsLeft, room.ID)
+		}
+		if released {
+			deletion.RoomsReleased = append(deletion.RoomsReleased, room.ID)
+		}
+	}
+
+	if history, err := rm.History(); err == nil {
+		anonymized, err := history.Anonymize(playerID)
+		if err != nil {
+			return deletion, err
+		}
+		deletion.GamesAnonymized = anonymized
+	}
+	if seasons, err := rm.Seasons(); err == nil {
+		anonymized, err := seasons.Anonymize(playerID)
+		if err != nil {
+			return deletion, err
+		}
+		deletion.SeasonsAnonymized = anonymized
+	}
+	if replays, err := rm.Replays(); err == nil {
+		deleted, err := replays.Forget(playerID)
+		if err != nil {
+			return deletion, err
+		}
+		deletion.ReplaysDeleted = deleted
+	}
+	if profiles, err := rm.Profiles(); err == nil {
+		deleted, err := profiles.Forget(playerID)
+		if err != nil {
+			return deletion, err
+		}
+		deletion.ProfileDeleted = deleted
+	}
+	if results, ok := rm.playerResults(); ok {
+		anonymized, err := results.AnonymizePlayer(playerID)
+		if err != nil {
+			return deletion, err
+		}
+		deletion.ResultsAnonymized = anonymized
+	}
+
+	log.Printf("Deleted data for player %s: left %d rooms, released %d, anonymized %d games",
+		playerID, len(deletion.RoomsLeft), len(deletion.RoomsReleased), deletion.GamesAnonymized)
+	return deletion, nil
+}
+
+// allRooms returns a snapshot of every room
+func (rm *RoomManager) allRooms() []*GameRoom {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	rooms := make([]*GameRoom, 0, len(rm.rooms))
+	for _, room := range rm.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// forget removes every trace of a player from the room: their seat and
+// session (closing the connection and invalidating the resume token), their
+// place in the queue, their session standing, recent events, their place on
+// the room's leaderboard, ownership and the owner's webhook.
+// It reports whether they were in the room and whether they owned it.
+func (r *GameRoom) forget(playerID string) (left, released bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, present := r.Players[playerID]; present {
+		r.removePlayer(playerID, "Account deleted")
+		left = true
+	}
+
+	for _, queued := range r.queue {
+		if queued.ID == playerID && queued.Connection != nil {
+			queued.Connection.Close(websocket.StatusNormalClosure, "Account deleted")
+		}
+	}
+	r.dequeue(playerID, nil)
+
+	delete(r.SessionScores, playerID)
+	delete(r.hiddenTracks, playerID)
+	r.events.forget(playerID)
+	r.forgetStanding(playerID)
+
+	if r.OwnerID == playerID {
+		r.OwnerID = ""
+		r.OwnerName = ""
+		r.leaderboard = nil
+		r.webhook = nil
+		if r.store != nil {
+			if err := r.store.Delete(r.ID); err != nil {
+				log.Printf("Room %s: failed to delete record: %v", r.ID, err)
+			}
+		}
+		released = true
+	}
+
+	if r.Settings.Endless && r.State == StatePlaying {
+		r.saveEndlessCheckpoint()
+	}
+	return left, released
+}
+
+// forget drops a player's ID and name from recent events
+func (l *eventLog) forget(playerID string) {
+	for i := range l.events {
+		if l.events[i].PlayerID != playerID {
+			continue
+		}
+		l.events[i].PlayerID = ""
+		if _, named := l.events[i].Data["name"]; named {
+			l.events[i].Data["name"] = DeletedPlayerName
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// fakePlayerResults is a result sink that can export and anonymize players
+type fakePlayerResults struct {
+	fakeSink
+}
+
+func (s *fakePlayerResults) PlayerGames(playerID string) ([]GameRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	games := make([]GameRecord, 0)
+	for _, record := range s.games {
+		if _, ok := record.score(playerID); ok {
+			games = append(games, record)
+		}
+	}
+	return games, nil
+}
+
+func (s *fakePlayerResults) AnonymizePlayer(playerID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	anonymized := 0
+	for i := range s.games {
+		s.games[i].Players = slices.Clone(s.games[i].Players)
+		for j, player := range s.games[i].Players {
+			if player.ID == playerID {
+				s.games[i].Players[j] = RecordPlayer{ID: "deleted", Name: DeletedPlayerName, Score: player.Score}
+				anonymized++
+			}
+		}
+	}
+	return anonymized, nil
+}
+
+// TestForgetPlayer verifies deleting a player's data removes them from rooms, releases owned rooms and anonymizes saved games and stored results
+func TestForgetPlayer(t *testing.T) {
+	historyDir := t.TempDir()
+
+	manager := NewRoomManager()
+	if err := manager.EnableRoomStore(t.TempDir()); err != nil {
+		t.Fatalf("Failed to enable room store: %v", err)
+	}
+	if err := manager.EnableHistory(historyDir); err != nil {
+		t.Fatalf("Failed to enable history: %v", err)
+	}
+	history, _ := manager.History()
+	recordResult(t, history, RecordPlayer{ID: "gone", Name: "Gone", Score: 30}, RecordPlayer{ID: "B", Score: 10})
+	recordResult(t, history, RecordPlayer{ID: "gone", Name: "Gone", Score: 5}, RecordPlayer{ID: "B", Score: 10})
+	results := &fakePlayerResults{}
+	results.RecordGame(GameRecord{GameID: "g1", Players: []RecordPlayer{{ID: "gone", Name: "Gone", Score: 5}, {ID: "B", Score: 10}}})
+	manager.SetResultSink(results)
+
+	room, err := manager.CreateRoom(RoomOptions{Name: "Mine"})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	room.handlePlayerJoin(newTestPlayer("gone"))
+	room.handlePlayerJoin(newTestPlayer("B"))
+	if _, err := manager.ClaimRoom(room.ID, "gone"); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	export, err := manager.ExportPlayer("gone")
+	if err != nil {
+		t.Fatalf("ExportPlayer failed: %v", err)
+	}
+	if len(export.Games) != 2 || export.Stats.Wins != 1 || len(export.StoredGames) != 1 || len(export.OwnedRooms) != 1 || len(export.CurrentRooms) != 1 {
+		t.Fatalf("Unexpected export: %+v", export)
+	}
+
+	deletion, err := manager.ForgetPlayer("gone")
+	if err != nil {
+		t.Fatalf("ForgetPlayer failed: %v", err)
+	}
+	if deletion.GamesAnonymized != 2 || deletion.ResultsAnonymized != 1 || len(deletion.RoomsLeft) != 1 || len(deletion.RoomsReleased) != 1 {
+		t.Errorf("Unexpected deletion report: %+v", deletion)
+	}
+
+	room.mu.RLock()
+	_, present := room.Players["gone"]
+	ownerID := room.OwnerID
+	room.mu.RUnlock()
+	if present || ownerID != "" {
+		t.Errorf("Expected the player removed and the room released, present=%v owner=%q", present, ownerID)
+	}
+
+	if after, _ := manager.ExportPlayer("gone"); len(after.Games) != 0 || len(after.StoredGames) != 0 || len(after.OwnedRooms) != 0 {
+		t.Errorf("Expected nothing left to export, got %+v", after)
+	}
+
+	// B keeps their record against the now-anonymous opponent
+	rivals := history.Rivals("B")
+	if len(rivals) != 1 || rivals[0].Games != 2 || rivals[0].OpponentName != DeletedPlayerName {
+		t.Errorf("Expected B's record against an anonymized opponent, got %+v", rivals)
+	}
+	reloaded, err := NewHistoryStore(historyDir)
+	if err != nil || len(reloaded.Games("gone")) != 0 {
+		t.Errorf("Expected anonymization to be persisted, err=%v", err)
+	}
+
+	t.Logf("✓ Deleting a player's data removes, releases and anonymizes everything kept about them")
+}
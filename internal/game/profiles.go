@@ -0,0 +1,205 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MaxProfileGames is how many recent games a profile lists
+const MaxProfileGames = 5
+
+// ErrNoProfile is returned for a player with no saved games who hasn't
+// signed in, and for players whose profile is private, so opting out
+// doesn't reveal that they play
+var ErrNoProfile = errors.New("profile not found")
+
+// ProfileInfo is what's kept about a player from their Spotify account,
+// with their profile settings
+type ProfileInfo struct {
+	PlayerID  string    `json:"player_id"`
+	Name      string    `json:"name"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	Private   bool      `json:"private"` // opted out of a public profile and stats
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PlayerProfile is a player's public profile
+type PlayerProfile struct {
+	PlayerID     string        `json:"player_id"`
+	Name         string        `json:"name"`
+	AvatarURL    string        `json:"avatar_url,omitempty"`
+	Stats        LifetimeStats `json:"stats"`
+	BestScore    int           `json:"best_score"`
+	Achievements []Achievement `json:"achievements"`
+	RecentGames  []ProfileGame `json:"recent_games"`
+}
+
+// ProfileGame is one of a player's recent games, from their side
+type ProfileGame struct {
+	GameID     string    `json:"game_id"`
+	RoomID     string    `json:"room_id"`
+	FinishedAt time.Time `json:"finished_at"`
+	Score      int       `json:"score"`
+	Place      int       `json:"place"` // players level on points share a place
+	Players    int       `json:"players"`
+	Won        bool      `json:"won"`
+}
+
+// Profiles keeps each signed-in player's name, avatar and privacy setting
+// in a JSON file, and builds their public profiles from the history
+type Profiles struct {
+	history *HistoryStore
+	path    string
+
+	mu      sync.RWMutex
+	players map[string]ProfileInfo
+}
+
+// NewProfiles opens the profiles kept in dir
+func NewProfiles(history *HistoryStore, dir string) (*Profiles, error) {
+	p := &Profiles{
+		history: history,
+		path:    filepath.Join(dir, "profiles.json"),
+		players: make(map[string]ProfileInfo),
+	}
+
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profiles: %w", err)
+	}
+	if err := json.Unmarshal(data, &p.players); err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	return p, nil
+}
+
+// Remember keeps the name and avatar a player signed in with
+func (p *Profiles) Remember(playerID, name, avatarURL string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, ok := p.players[playerID]
+	if ok && info.Name == name && info.AvatarURL == avatarURL {
+		return nil
+	}
+	info.PlayerID = playerID
+	info.Name = name
+	info.AvatarURL = avatarURL
+	info.UpdatedAt = time.Now().UTC()
+	return p.put(info)
+}
+
+// SetPrivate opts the player out of (or back into) a public profile
+func (p *Profiles) SetPrivate(playerID string, private bool) (ProfileInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info := p.players[playerID]
+	info.PlayerID = playerID
+	info.Private = private
+	info.UpdatedAt = time.Now().UTC()
+	return info, p.put(info)
+}
+
+// Info returns what's kept about the player, if anything
+func (p *Profiles) Info(playerID string) (ProfileInfo, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	info, ok := p.players[playerID]
+	return info, ok
+}
+
+// Public reports whether the player's profile and stats can be shown
+func (p *Profiles) Public(playerID string) bool {
+	info, _ := p.Info(playerID)
+	return !info.Private
+}
+
+// Profile builds the player's public profile from their saved games
+func (p *Profiles) Profile(playerID string) (PlayerProfile, error) {
+	info, known := p.Info(playerID)
+	if info.Private {
+		return PlayerProfile{}, ErrNoProfile
+	}
+	games := p.history.Games(playerID)
+	if !known && len(games) == 0 {
+		return PlayerProfile{}, ErrNoProfile
+	}
+
+	stats := p.history.Stats(playerID)
+	profile := PlayerProfile{
+		PlayerID:     playerID,
+		Name:         info.Name,
+		AvatarURL:    info.AvatarURL,
+		Stats:        stats.LifetimeStats,
+		BestScore:    stats.BestScore,
+		Achievements: achievements(playerID, games),
+		RecentGames:  make([]ProfileGame, 0, MaxProfileGames),
+	}
+	if profile.Name == "" && len(games) > 0 {
+		profile.Name = games[len(games)-1].name(playerID)
+	}
+
+	for i := len(games) - 1; i >= 0 && len(profile.RecentGames) < MaxProfileGames; i-- {
+		game := games[i]
+		score, _ := game.score(playerID)
+		place := 1
+		for _, other := range game.Players {
+			if other.Score > score {
+				place++
+			}
+		}
+		profile.RecentGames = append(profile.RecentGames, ProfileGame{
+			GameID:     game.GameID,
+			RoomID:     game.RoomID,
+			FinishedAt: game.FinishedAt,
+			Score:      score,
+			Place:      place,
+			Players:    len(game.Players),
+			Won:        game.WinnerID == playerID,
+		})
+	}
+	return profile, nil
+}
+
+// Forget removes what's kept about the player, reporting whether there was
+// anything
+func (p *Profiles) Forget(playerID string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.players[playerID]; !ok {
+		return false, nil
+	}
+	delete(p.players, playerID)
+	return true, p.save()
+}
+
+// put stores info and saves. Callers must hold the lock.
+func (p *Profiles) put(info ProfileInfo) error {
+	p.players[info.PlayerID] = info
+	return p.save()
+}
+
+// save writes every profile. Callers must hold the lock.
+func (p *Profiles) save() error {
+	data, err := json.Marshal(p.players)
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves the file half-written
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestPlayerProfile verifies a profile combines the player's Spotify details,
+// stats, achievements and recent games, and can be made private
+func TestPlayerProfile(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	start := time.Date(2026, 9, 1, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		// A wins 3 in a row from the 3rd game, the 4th perfectly
+		winner := "B"
+		if i >= 2 && i <= 4 {
+			winner = "A"
+		}
+		correct := 2
+		if i == 3 {
+			correct = 5
+		}
+		history.Append(GameRecord{
+			GameID:     fmt.Sprintf("game-%d", i),
+			RoomID:     "Room 1",
+			FinishedAt: start.Add(time.Duration(i) * time.Hour),
+			Rounds:     5,
+			WinnerID:   winner,
+			Players: []RecordPlayer{
+				{ID: "A", Name: "Alice", Score: 100 * i, Guesses: 5, Correct: correct},
+				{ID: "B", Name: "Bob", Score: 250},
+				{ID: "C", Name: "Cat", Score: 50},
+			},
+		})
+	}
+
+	profiles, err := NewProfiles(history, dir)
+	if err != nil {
+		t.Fatalf("Failed to open profiles: %v", err)
+	}
+	if _, err := profiles.Profile("nobody"); !errors.Is(err, ErrNoProfile) {
+		t.Errorf("Expected no profile for an unknown player, got %v", err)
+	}
+
+	// Before signing in, the name comes from their games
+	profile, err := profiles.Profile("A")
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+	if profile.Name != "Alice" || profile.AvatarURL != "" || profile.Stats.GamesPlayed != 7 ||
+		profile.Stats.Wins != 3 || profile.BestScore != 600 {
+		t.Errorf("Expected Alice's stats, got %+v", profile)
+	}
+	if len(profile.RecentGames) != MaxProfileGames || profile.RecentGames[0].GameID != "game-6" {
+		t.Fatalf("Expected the 5 latest games, newest first, got %+v", profile.RecentGames)
+	}
+	if latest := profile.RecentGames[0]; latest.Place != 1 || latest.Won || latest.Players != 3 {
+		t.Errorf("Expected the best score in game 6 to be 1st without the win, got %+v", latest)
+	}
+	if game2 := profile.RecentGames[4]; game2.GameID != "game-2" || game2.Place != 2 || !game2.Won {
+		t.Errorf("Expected 2nd place on points in game 2, got %+v", game2)
+	}
+
+	earned := make(map[string]string)
+	for _, achievement := range profile.Achievements {
+		earned[achievement.ID] = achievement.GameID
+	}
+	want := map[string]string{"first_game": "game-0", "first_win": "game-2", "hat_trick": "game-4", "perfect_ear": "game-3"}
+	if len(earned) != len(want) {
+		t.Errorf("Expected %v, got %v", want, earned)
+	}
+	for id, gameID := range want {
+		if earned[id] != gameID {
+			t.Errorf("Expected %s earned in %s, got %q", id, gameID, earned[id])
+		}
+	}
+
+	if err := profiles.Remember("A", "Alice S", "https://i.scdn.co/image/alice"); err != nil {
+		t.Fatalf("Failed to remember: %v", err)
+	}
+	if _, err := profiles.SetPrivate("A", true); err != nil {
+		t.Fatalf("Failed to make private: %v", err)
+	}
+
+	// Settings survive a restart
+	reopened, _ := NewProfiles(history, dir)
+	if _, err := reopened.Profile("A"); !errors.Is(err, ErrNoProfile) || reopened.Public("A") {
+		t.Errorf("Expected a private profile to be hidden, got %v", err)
+	}
+	reopened.SetPrivate("A", false)
+	profile, _ = reopened.Profile("A")
+	if profile.Name != "Alice S" || profile.AvatarURL != "https://i.scdn.co/image/alice" {
+		t.Errorf("Expected the signed-in name and avatar, got %+v", profile)
+	}
+
+	if deleted, err := reopened.Forget("A"); err != nil || !deleted {
+		t.Errorf("Expected A's profile to be deleted, got %v", err)
+	}
+	if _, ok := reopened.Info("A"); ok {
+		t.Error("Expected nothing kept about A after deletion")
+	}
+
+	t.Logf("✓ Profiles combine stats, achievements and recent games, and can be made private")
+}
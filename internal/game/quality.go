@@ -0,0 +1,96 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionQuality is a coarse grade of a player's connection shown in the
+// player list, so slow guesses can be told apart from a bad connection
+type ConnectionQuality string
+
+const (
+	QualityGood ConnectionQuality = "good"
+	QualityFair ConnectionQuality = "fair"
+	QualityPoor ConnectionQuality = "poor"
+)
+
+// qualityWindow is how many recent pings the loss rate is measured over
+const qualityWindow = 10
+
+// connectionStats tracks round-trip times and lost pings for one connection.
+// It is updated from the connection's goroutine, so it has its own lock.
+type connectionStats struct {
+	mu      sync.Mutex
+	rtt     time.Duration // smoothed round-trip time
+	results []bool        // recent ping outcomes, true when answered
+	quality ConnectionQuality
+}
+
+// RecordPing adds a ping outcome to the player's connection stats and
+// reports whether their quality grade changed
+func (p *Player) RecordPing(rtt time.Duration, answered bool) bool {
+	s := &p.connStats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if answered {
+		if s.rtt == 0 {
+			s.rtt = rtt
+		} else {
+			s.rtt = (s.rtt*7 + rtt) / 8
+		}
+	}
+
+	s.results = append(s.results, answered)
+	if len(s.results) > qualityWindow {
+		s.results = s.results[1:]
+	}
+
+	lost := 0
+	for _, ok := range s.results {
+		if !ok {
+			lost++
+		}
+	}
+	lossRate := float64(lost) / float64(len(s.results))
+
+	quality := QualityGood
+	switch {
+	case lossRate >= 0.3 || s.rtt > 400*time.Millisecond:
+		quality = QualityPoor
+	case lost > 0 || s.rtt > 150*time.Millisecond:
+		quality = QualityFair
+	}
+
+	changed := quality != s.quality
+	s.quality = quality
+	return changed
+}
+
+// ConnectionQuality returns the player's current grade, or "" before the
+// first measurement
+func (p *Player) ConnectionQuality() ConnectionQuality {
+	p.connStats.mu.Lock()
+	defer p.connStats.mu.Unlock()
+	return p.connStats.quality
+}
+
+func (r *GameRoom) handleQualityChange(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[playerID]
+	if !exists {
+		return
+	}
+
+	r.Broadcast <- Message{
+		Type: MsgTypeConnectionQuality,
+		Payload: map[string]interface{}{
+			"player_id": playerID,
+			"quality":   player.ConnectionQuality(),
+			"players":   r.getPlayerInfoList(),
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestConnectionQualityGrades verifies RTT and lost pings map to coarse grades
+func TestConnectionQualityGrades(t *testing.T) {
+	player := newTestPlayer("A")
+	if player.ConnectionQuality() != "" {
+		t.Errorf("Expected no grade before the first ping")
+	}
+
+	if !player.RecordPing(40*time.Millisecond, true) || player.ConnectionQuality() != QualityGood {
+		t.Errorf("Expected a fast answered ping to grade good, got %s", player.ConnectionQuality())
+	}
+	if player.RecordPing(50*time.Millisecond, true) {
+		t.Error("An unchanged grade should not be reported as a change")
+	}
+	player.RecordPing(40*time.Millisecond, true)
+	player.RecordPing(40*time.Millisecond, true)
+
+	player.RecordPing(0, false)
+	if player.ConnectionQuality() != QualityFair {
+		t.Errorf("Expected a lost ping to grade fair, got %s", player.ConnectionQuality())
+	}
+
+	player.RecordPing(0, false)
+	if player.ConnectionQuality() != QualityPoor {
+		t.Errorf("Expected 2 of 6 lost pings to grade poor, got %s", player.ConnectionQuality())
+	}
+
+	t.Logf("✓ Connection quality is graded from RTT and loss")
+}
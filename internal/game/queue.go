@@ -0,0 +1,88 @@
+package game
+
+import (
+	"log"
+
+	"github.com/coder/websocket"
+)
+
+// MaxQueueLength bounds how many players can wait for a seat in a full room
+const MaxQueueLength = 20
+
+// enqueue puts a player in line for a seat in a full room.
+// Callers must hold the room lock.
+func (r *GameRoom) enqueue(player *Player) {
+	// A player re-joining the queue keeps their place with the new connection
+	for i, queued := range r.queue {
+		if queued.ID == player.ID {
+			r.queue[i] = player
+			r.notifyQueuePositions()
+			return
+		}
+	}
+
+	if len(r.queue) >= MaxQueueLength {
+		r.sendDirect(player, Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": "Room is full and its queue is full, try another room",
+			},
+		})
+		return
+	}
+
+	r.queue = append(r.queue, player)
+	log.Printf("Player %s queued for room %s at position %d", player.Name, r.ID, len(r.queue))
+
+	r.notifyQueuePositions()
+}
+
+// dequeue removes a queued player, e.g. when they leave or disconnect. A nil
+// conn matches any connection. Callers must hold the room lock.
+func (r *GameRoom) dequeue(playerID string, conn *websocket.Conn) {
+	for i, queued := range r.queue {
+		if queued.ID != playerID || (conn != nil && queued.Connection != conn) {
+			continue
+		}
+		r.queue = append(r.queue[:i], r.queue[i+1:]...)
+		log.Printf("Player %s left the queue for room %s", queued.Name, r.ID)
+		r.notifyQueuePositions()
+		return
+	}
+}
+
+// admitFromQueue seats queued players while the room has free seats.
+// Callers must hold the room lock.
+func (r *GameRoom) admitFromQueue() {
+	admitted := false
+	for len(r.queue) > 0 && len(r.Players) < MaxPlayersPerRoom {
+		player := r.queue[0]
+		r.queue = r.queue[1:]
+		if r.Banned[player.ID] || !r.claimSeat(player) {
+			continue
+		}
+
+		log.Printf("Admitting queued player %s to room %s", player.Name, r.ID)
+		r.seatPlayer(player)
+		admitted = true
+	}
+
+	if admitted {
+		r.notifyQueuePositions()
+	}
+}
+
+// notifyQueuePositions tells every queued player where they stand.
+// Callers must hold the room lock.
+func (r *GameRoom) notifyQueuePositions() {
+	for i, player := range r.queue {
+		r.sendDirect(player, Message{
+			Type: MsgTypeQueuePosition,
+			Payload: map[string]interface{}{
+				"room_id":      r.ID,
+				"position":     i + 1,
+				"queue_length": len(r.queue),
+			},
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestQueueForFullRoom verifies opted-in players wait in line and are admitted in order when seats open
+func TestQueueForFullRoom(t *testing.T) {
+	manager := NewRoomManager()
+	room, err := manager.CreateRoom(RoomOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	for i := 0; i < MaxPlayersPerRoom; i++ {
+		room.handlePlayerJoin(newTestPlayer(fmt.Sprintf("P%d", i)))
+	}
+
+	rejected := newTestPlayer("rejected")
+	room.handlePlayerJoin(rejected)
+
+	for _, id := range []string{"Q1", "Q2", "Q3"} {
+		player := newTestPlayer(id)
+		player.QueueIfFull = true
+		room.handlePlayerJoin(player)
+	}
+
+	room.mu.RLock()
+	queued := len(room.queue)
+	_, rejectedSeated := room.Players["rejected"]
+	room.mu.RUnlock()
+	if queued != 3 || rejectedSeated {
+		t.Fatalf("Expected 3 queued players and no overflow seat, got %d queued", queued)
+	}
+
+	// Q2 gives up; Q1 gets the next seat
+	room.handlePlayerLeave("Q2")
+	room.handlePlayerLeave("P0")
+
+	room.mu.RLock()
+	_, q1Seated := room.Players["Q1"]
+	remaining := len(room.queue)
+	next := room.queue[0].ID
+	room.mu.RUnlock()
+
+	if !q1Seated {
+		t.Error("Expected the head of the queue to be admitted when a seat opened")
+	}
+	if remaining != 1 || next != "Q3" {
+		t.Errorf("Expected only Q3 left in the queue, got %d (head %s)", remaining, next)
+	}
+
+	t.Logf("✓ Full rooms queue opted-in players and admit them in order")
+}
@@ -0,0 +1,297 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxRecordedEvents bounds a game's recording, so an endless game can't
+	// grow one forever. Events past it aren't recorded.
+	MaxRecordedEvents = 5000
+	// MaxReplayGap caps the wait between two events in playback, so a game
+	// that sat paused doesn't stall its replay
+	MaxReplayGap = 30 * time.Second
+	// MaxReplaySpeed is the fastest a replay can be played back
+	MaxReplaySpeed = 8
+)
+
+// ErrNoReplay is returned for a game that has no saved recording
+var ErrNoReplay = errors.New("replay not found")
+
+// ReplayEvent is one message of a recorded game, as the room saw it
+type ReplayEvent struct {
+	At      int64           `json:"at_ms"` // since the game started
+	Type    MessageType     `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// GameReplay is a finished game's full event stream: everything broadcast
+// to the room from the start to game over, plus each guess as it was made
+type GameReplay struct {
+	GameID    string        `json:"game_id"`
+	RoomID    string        `json:"room_id"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  int64         `json:"duration_ms"`
+	Truncated bool          `json:"truncated,omitempty"` // hit MaxRecordedEvents
+	Players   []string      `json:"players"`             // everyone seated during the game
+	Events    []ReplayEvent `json:"events"`
+}
+
+// gameRecorder collects a game's events as they happen. It has its own lock
+// because broadcasts only hold the room's read lock.
+type gameRecorder struct {
+	mu       sync.Mutex
+	replay   GameReplay
+	players  map[string]bool
+	finished bool
+}
+
+// add records msg at the current time, noting who was seated
+func (rec *gameRecorder) add(msg Message, players map[string]*Player) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.finished {
+		return
+	}
+	for playerID, player := range players {
+		if !player.IsBot() {
+			rec.players[playerID] = true
+		}
+	}
+	if len(rec.replay.Events) >= MaxRecordedEvents {
+		rec.replay.Truncated = true
+		return
+	}
+
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		log.Printf("Game %s: failed to record %s: %v", rec.replay.GameID, msg.Type, err)
+		return
+	}
+	rec.replay.Events = append(rec.replay.Events, ReplayEvent{
+		At:      time.Since(rec.replay.StartedAt).Milliseconds(),
+		Type:    msg.Type,
+		Payload: payload,
+	})
+}
+
+// finish stops the recording and returns it
+func (rec *gameRecorder) finish() GameReplay {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.finished = true
+	replay := rec.replay
+	replay.Duration = time.Since(replay.StartedAt).Milliseconds()
+	replay.Players = make([]string, 0, len(rec.players))
+	for playerID := range rec.players {
+		replay.Players = append(replay.Players, playerID)
+	}
+	slices.Sort(replay.Players)
+	return replay
+}
+
+// startRecording begins recording the game that's starting. Practice and
+// tutorial games aren't recorded, nor is anything without history enabled.
+// Callers must hold the room lock.
+func (r *GameRoom) startRecording() {
+	r.recorder = nil
+	if r.replays == nil || r.practice != nil || r.tutorial != nil {
+		return
+	}
+	r.recorder = &gameRecorder{
+		replay: GameReplay{
+			GameID:    r.GameID,
+			RoomID:    r.ID,
+			StartedAt: time.Now().UTC(),
+		},
+		players: make(map[string]bool),
+	}
+}
+
+// recordMessage adds a message to the game's recording, saving the
+// recording once the game is over. Callers must hold the room lock, at
+// least for reading.
+func (r *GameRoom) recordMessage(msg Message) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.add(msg, r.Players)
+	if msg.Type != MsgTypeGameOver {
+		return
+	}
+
+	replay := r.recorder.finish()
+	if err := r.replays.Save(replay); err != nil {
+		log.Printf("Room %s: failed to save replay of game %s: %v", r.ID, replay.GameID, err)
+	}
+}
+
+// recordGuess adds a guess, with what was guessed, to the game's recording.
+// Guesses are only broadcast as having been made, so this is the one event
+// players never see live. Callers must hold the room lock.
+func (r *GameRoom) recordGuess(guess Guess) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.add(Message{
+		Type: MsgTypeGuessMade,
+		Payload: map[string]interface{}{
+			"round":             r.CurrentRound,
+			"player_id":         guess.PlayerID,
+			"guessed_player_id": guess.GuessedPlayerID,
+			"guessed_title":     guess.GuessedTitle,
+			"guessed_track_id":  guess.GuessedTrackID,
+			"elapsed_ms":        guess.Timestamp.Sub(r.RoundStartTime).Milliseconds(),
+		},
+	}, r.Players)
+}
+
+// Play sends each event in order, waiting between them as long as the game
+// did (divided by speed, and at most MaxReplayGap). It stops early when ctx
+// is cancelled or send fails.
+func (replay GameReplay) Play(ctx context.Context, speed float64, send func(ReplayEvent) error) error {
+	if speed <= 0 || speed > MaxReplaySpeed {
+		return fmt.Errorf("speed must be above 0 and at most %d", MaxReplaySpeed)
+	}
+
+	var previous int64
+	for _, event := range replay.Events {
+		gap := time.Duration(float64(event.At-previous) * float64(time.Millisecond) / speed)
+		previous = event.At
+		if gap > 0 {
+			timer := time.NewTimer(min(gap, MaxReplayGap))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayStore keeps each recorded game as a JSON file in a directory
+type ReplayStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewReplayStore opens the replays kept in dir, creating it if needed
+func NewReplayStore(dir string) (*ReplayStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create replay dir: %w", err)
+	}
+	return &ReplayStore{dir: dir}, nil
+}
+
+func (s *ReplayStore) path(gameID string) (string, bool) {
+	if gameID == "" || strings.ContainsAny(gameID, `/\.`) {
+		return "", false
+	}
+	return filepath.Join(s.dir, gameID+".json"), true
+}
+
+// Save writes a game's recording
+func (s *ReplayStore) Save(replay GameReplay) error {
+	path, ok := s.path(replay.GameID)
+	if !ok {
+		return fmt.Errorf("invalid game ID %q", replay.GameID)
+	}
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load returns a game's recording, or ErrNoReplay
+func (s *ReplayStore) Load(gameID string) (GameReplay, error) {
+	var replay GameReplay
+	path, ok := s.path(gameID)
+	if !ok {
+		return replay, ErrNoReplay
+	}
+
+	s.mu.Lock()
+	data, err := os.ReadFile(path)
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return replay, ErrNoReplay
+	}
+	if err != nil {
+		return replay, err
+	}
+	err = json.Unmarshal(data, &replay)
+	return replay, err
+}
+
+// Forget deletes every recording the player was seated in, returning how
+// many. Recordings hold chat and names verbatim, so they're removed rather
+// than anonymized.
+func (s *ReplayStore) Forget(playerID string) (int, error) {
+	return s.remove(func(replay GameReplay) bool {
+		return slices.Contains(replay.Players, playerID)
+	})
+}
+
+// Prune deletes recordings of games started before cutoff, returning how
+// many
+func (s *ReplayStore) Prune(cutoff time.Time) (int, error) {
+	return s.remove(func(replay GameReplay) bool {
+		return replay.StartedAt.Before(cutoff)
+	})
+}
+
+func (s *ReplayStore) remove(match func(GameReplay) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return removed, err
+		}
+		var replay GameReplay
+		if err := json.Unmarshal(data, &replay); err != nil {
+			log.Printf("Skipping unreadable replay %s: %v", path, err)
+			continue
+		}
+		if !match(replay) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGameRecording verifies a finished game's broadcasts and guesses are
+// saved in order and play back with their timing
+func TestGameRecording(t *testing.T) {
+	replays, err := NewReplayStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open replays: %v", err)
+	}
+	h := newGameHarness(t, 5)
+	h.room.replays = replays
+	h.room.Settings.TotalRounds = 1
+
+	// The harness reads broadcasts off the channel, so hand them on to the
+	// room as its run loop would
+	relay := func(msgs []Message) {
+		for _, msg := range msgs {
+			h.room.broadcastToAll(msg)
+		}
+	}
+
+	h.join(harnessPlayer("A", "a1"))
+	h.join(harnessPlayer("B", "b1"))
+	h.ready("A")
+	h.ready("B")
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady,
+		MsgTypeGameStarted, MsgTypeRoundStarted)
+	relay(msgs[4:])
+
+	h.room.mu.RLock()
+	owner := harnessOwner(h.room.CurrentTrack.ID)
+	gameID := h.room.GameID
+	h.room.mu.RUnlock()
+	h.guess("A", owner, time.Second)
+	h.guess("B", "A", 2*time.Second)
+	relay(h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver))
+
+	replay, err := replays.Load(gameID)
+	if err != nil {
+		t.Fatalf("Expected the game's replay to be saved: %v", err)
+	}
+	want := []MessageType{MsgTypeGameStarted, MsgTypeRoundStarted, MsgTypeGuessMade, MsgTypeGuessMade,
+		MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver}
+	if len(replay.Events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(replay.Events))
+	}
+	for i, event := range replay.Events {
+		if event.Type != want[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, want[i], event.Type)
+		}
+		if i > 0 && event.At < replay.Events[i-1].At {
+			t.Errorf("Event %d: expected events in time order", i)
+		}
+	}
+	var guess map[string]interface{}
+	json.Unmarshal(replay.Events[3].Payload, &guess)
+	if guess["player_id"] != "B" || guess["guessed_player_id"] != "A" || guess["elapsed_ms"] != float64(2000) {
+		t.Errorf("Expected B's guess with what and when they guessed, got %v", guess)
+	}
+	if len(replay.Players) != 2 || replay.Players[0] != "A" || replay.Players[1] != "B" {
+		t.Errorf("Expected A and B as the replay's players, got %v", replay.Players)
+	}
+
+	// Late broadcasts, e.g. a voided round, don't change a saved replay
+	h.room.broadcastToAll(Message{Type: MsgTypeRoundVoided})
+	if again, _ := replays.Load(gameID); len(again.Events) != len(want) {
+		t.Errorf("Expected the replay to end at game over")
+	}
+
+	var played []MessageType
+	if err := replay.Play(context.Background(), MaxReplaySpeed, func(event ReplayEvent) error {
+		played = append(played, event.Type)
+		return nil
+	}); err != nil || len(played) != len(want) {
+		t.Errorf("Expected every event to be played back, got %v (%v)", played, err)
+	}
+	if err := replay.Play(context.Background(), 0, nil); err == nil {
+		t.Error("Expected a speed of 0 to be refused")
+	}
+
+	// Events are spaced out as they were in the game
+	paced := GameReplay{Events: []ReplayEvent{{At: 0}, {At: 200}}}
+	start := time.Now()
+	paced.Play(context.Background(), 2, func(ReplayEvent) error { return nil })
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected a 200ms gap at double speed to take 100ms, took %v", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := paced.Play(ctx, 1, func(ReplayEvent) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled playback to stop, got %v", err)
+	}
+
+	if deleted, err := replays.Forget("B"); err != nil || deleted != 1 {
+		t.Errorf("Expected B's replay to be deleted, got %d (%v)", deleted, err)
+	}
+	if _, err := replays.Load(gameID); !errors.Is(err, ErrNoReplay) {
+		t.Errorf("Expected the deleted replay to be gone, got %v", err)
+	}
+	if _, err := replays.Load("../games"); !errors.Is(err, ErrNoReplay) {
+		t.Errorf("Expected a path to be refused as a game ID, got %v", err)
+	}
+
+	t.Logf("✓ Games are recorded from start to game over and play back with their timing")
+}
@@ -0,0 +1,235 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// RematchOptOutWindow is how long players have to sit out a quick rematch
+// before it starts
+const RematchOptOutWindow = 10 * time.Second
+
+// pendingRematch is a quick rematch counting down to its start
+type pendingRematch struct {
+	Gen      int
+	StartsAt time.Time
+	timer    *time.Timer
+}
+
+// rememberGame keeps what a game was started with so a quick rematch can
+// reuse it. Callers must hold the room lock.
+func (r *GameRoom) rememberGame(payload StartGamePayload) {
+	r.lastGame = &StartGamePayload{
+		RoomID:        r.ID,
+		TotalRounds:   payload.TotalRounds,
+		Powerups:      payload.Powerups,
+		RoundDuration: payload.RoundDuration,
+	}
+	r.lastSettings = r.Settings
+}
+
+// handleQuickRematch lets the leader start another game with the last game's
+// settings in one step. Everyone still connected is readied; anyone who
+// unreadies within the opt-out window sits the rematch out as a spectator.
+func (r *GameRoom) handleQuickRematch(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if playerID != r.LeaderID {
+		r.sendError(playerID, "Only the leader can start a rematch")
+		return
+	}
+	if r.Hostless {
+		r.sendError(playerID, "Games start automatically in this room")
+		return
+	}
+	if r.lastGame == nil {
+		r.sendError(playerID, "There's no previous game to rematch")
+		return
+	}
+	if r.State != StateWaiting && r.State != StateGameOver {
+		r.sendError(playerID, "A rematch can only start once the game is over")
+		return
+	}
+	if r.rematch != nil {
+		return
+	}
+
+	if r.State == StateGameOver {
+		r.State = StateWaiting
+		r.CurrentRound = 0
+		r.Scores = make(map[string]int)
+		r.promoteSpectators()
+		for pid := range r.Players {
+			r.Scores[pid] = 0
+		}
+	}
+	if r.countdownActive {
+		r.cancelCountdown()
+	}
+
+	r.Settings = r.lastSettings
+	for _, p := range r.Players {
+		p.IsReady = !p.Disconnected
+	}
+
+	r.rematchGen++
+	gen := r.rematchGen
+	r.rematch = &pendingRematch{
+		Gen:      gen,
+		StartsAt: time.Now().Add(RematchOptOutWindow),
+		timer: time.AfterFunc(RematchOptOutWindow, func() {
+			r.startRematch(gen)
+		}),
+	}
+	log.Printf("Room %s: quick rematch starting in %v", r.ID, RematchOptOutWindow)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRematchPending,
+		Payload: map[string]interface{}{
+			"starts_at":    r.rematch.StartsAt,
+			"seconds_left": int(RematchOptOutWindow.Seconds()),
+			"total_rounds": r.lastGame.TotalRounds,
+			"settings":     r.Settings,
+			"players":      r.getPlayerInfoList(),
+		},
+	}
+}
+
+// startRematch starts a pending rematch once its opt-out window closes
+func (r *GameRoom) startRematch(gen int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rematch == nil || r.rematch.Gen != gen || r.State != StateWaiting {
+		return
+	}
+	r.rematch = nil
+
+	playing := 0
+	for _, p := range r.Players {
+		if !p.IsReady || p.Disconnected {
+			p.IsSpectator = true
+			continue
+		}
+		playing++
+	}
+
+	if playing < MinPlayersToStart {
+		r.promoteSpectators()
+		log.Printf("Room %s: quick rematch cancelled, not enough players", r.ID)
+		r.Broadcast <- Message{
+			Type: MsgTypeRematchCancelled,
+			Payload: map[string]interface{}{
+				"reason": "Not enough players stayed in for the rematch",
+			},
+		}
+		return
+	}
+
+	r.beginGame(*r.lastGame)
+}
+
+// cancelRematch drops a pending rematch. Callers must hold the room lock.
+func (r *GameRoom) cancelRematch() {
+	if r.rematch == nil {
+		return
+	}
+	r.rematch.timer.Stop()
+	r.rematch = nil
+	r.rematchGen++
+}
+
+// handleRematchVote records a player accepting a rematch after a game. Once
+// every player still seated has accepted, the next game starts straight away
+// with the last game's settings, without a ready-up.
+func (r *GameRoom) handleRematchVote(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.Players[playerID]; !exists {
+		return
+	}
+	if r.Hostless {
+		r.sendError(playerID, "Games start automatically in this room")
+		return
+	}
+	if r.State != StateGameOver || r.lastGame == nil {
+		r.sendError(playerID, "A rematch can only be voted for once the game is over")
+		return
+	}
+	if r.rematchVotes[playerID] {
+		return
+	}
+	r.rematchVotes[playerID] = true
+
+	voters := r.rematchVoters()
+	accepted := make([]string, 0, len(voters))
+	for _, id := range voters {
+		if r.rematchVotes[id] {
+			accepted = append(accepted, id)
+		}
+	}
+	r.Broadcast <- Message{
+		Type: MsgTypeRematchVote,
+		Payload: map[string]interface{}{
+			"player_id": playerID,
+			"accepted":  accepted,
+			"needed":    len(voters),
+		},
+	}
+	r.checkRematchVotes()
+}
+
+// rematchVoters are the players a rematch vote waits on: everyone seated,
+// spectators included, apart from bots. Callers must hold the room lock.
+func (r *GameRoom) rematchVoters() []string {
+	voters := make([]string, 0, len(r.PlayerOrder))
+	for _, id := range r.PlayerOrder {
+		if player, ok := r.Players[id]; ok && !player.IsBot() {
+			voters = append(voters, id)
+		}
+	}
+	return voters
+}
+
+// checkRematchVotes starts the rematch once every voter has accepted. The
+// tracks played so far stay out of the pool, so the rematch can't repeat
+// them. Callers must hold the room lock.
+func (r *GameRoom) checkRematchVotes() {
+	if r.State != StateGameOver || r.lastGame == nil || len(r.rematchVotes) == 0 {
+		return
+	}
+	voters := r.rematchVoters()
+	for _, id := range voters {
+		if !r.rematchVotes[id] {
+			return
+		}
+	}
+	playing := 0
+	for _, p := range r.Players {
+		if !p.Disconnected {
+			playing++
+		}
+	}
+	if playing < MinPlayersToStart {
+		return
+	}
+
+	r.State = StateWaiting
+	r.CurrentRound = 0
+	r.Scores = make(map[string]int)
+	r.promoteSpectators()
+	r.Settings = r.lastSettings
+	for pid, p := range r.Players {
+		r.Scores[pid] = 0
+		// Players who dropped out since accepting sit the rematch out
+		p.IsReady = !p.Disconnected
+		p.IsSpectator = p.Disconnected
+	}
+
+	log.Printf("Room %s: everyone accepted a rematch, %d tracks stay out of the pool", r.ID, len(r.PlayedTracks))
+	r.rematchPlayed = r.PlayedTracks
+	r.beginGame(*r.lastGame)
+	r.rematchPlayed = nil
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// playOneRoundGame plays a single-round game between A, B and C, with A leading
+func playOneRoundGame(t *testing.T, h *gameHarness) {
+	t.Helper()
+
+	h.room.Settings.TotalRounds = 1
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.join(harnessPlayer("C", "t3"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.guess("C", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+		MsgTypeRoundComplete, MsgTypeGameOver)
+}
+
+// TestQuickRematch verifies the leader can restart with the last settings and players can opt out
+func TestQuickRematch(t *testing.T) {
+	h := newGameHarness(t, 5)
+	playOneRoundGame(t, h)
+
+	// Changes after the game don't carry into the rematch
+	h.room.Settings.TotalRounds = 7
+
+	h.room.handleQuickRematch("B")
+	if h.room.rematch != nil {
+		t.Fatal("Expected only the leader to be able to start a rematch")
+	}
+	h.room.handleQuickRematch("A")
+	msgs := h.expect(MsgTypeRematchPending)
+	if got := msgs[0].Payload.(map[string]interface{})["settings"].(RoomSettings).TotalRounds; got != 1 {
+		t.Errorf("Expected the rematch to reuse the last game's 1 round, got %d", got)
+	}
+
+	h.room.mu.RLock()
+	allReady := h.room.Players["A"].IsReady && h.room.Players["B"].IsReady && h.room.Players["C"].IsReady
+	gen := h.room.rematch.Gen
+	h.room.rematch.timer.Stop()
+	h.room.mu.RUnlock()
+	if !allReady {
+		t.Error("Expected everyone connected to be readied for the rematch")
+	}
+
+	// C opts out; everyone being ready must not trigger the normal countdown
+	h.room.handlePlayerReady(ReadyPayload{PlayerID: "C", IsReady: false})
+	h.expect(MsgTypePlayerReady)
+
+	h.room.startRematch(gen)
+	h.expect(MsgTypeGameStarted)
+
+	h.room.mu.RLock()
+	defer h.room.mu.RUnlock()
+	if !h.room.Players["C"].IsSpectator || h.room.Players["A"].IsSpectator {
+		t.Error("Expected only the player who opted out to sit the rematch out")
+	}
+	if h.room.TotalRounds != 1 {
+		t.Errorf("Expected the rematch to play 1 round, got %d", h.room.TotalRounds)
+	}
+
+	t.Logf("✓ Quick rematch reuses the last game and honours opt-outs")
+}
+
+// TestQuickRematchCancelledWithoutPlayers verifies a rematch doesn't start when too many opt out
+func TestQuickRematchCancelledWithoutPlayers(t *testing.T) {
+	h := newGameHarness(t, 5)
+	playOneRoundGame(t, h)
+
+	h.room.handleQuickRematch("A")
+	h.expect(MsgTypeRematchPending)
+	h.room.mu.RLock()
+	gen := h.room.rematch.Gen
+	h.room.rematch.timer.Stop()
+	h.room.mu.RUnlock()
+
+	h.room.handlePlayerReady(ReadyPayload{PlayerID: "B", IsReady: false})
+	h.room.handlePlayerReady(ReadyPayload{PlayerID: "C", IsReady: false})
+	h.expect(MsgTypePlayerReady, MsgTypePlayerReady)
+
+	h.room.startRematch(gen)
+	h.expect(MsgTypeRematchCancelled)
+
+	if h.room.State != StateWaiting || h.room.Players["B"].IsSpectator {
+		t.Errorf("Expected a waiting room with nobody benched, got %s", h.room.State)
+	}
+
+	t.Logf("✓ A rematch without enough players is cancelled")
+}
+
+// TestRematchVote verifies a rematch starts once every remaining player has
+// accepted, without a ready-up, and doesn't repeat the last game's tracks
+func TestRematchVote(t *testing.T) {
+	h := newGameHarness(t, 8)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "a1", "a2"))
+	h.join(harnessPlayer("B", "b1", "b2"))
+	h.join(harnessPlayer("C", "c1"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	h.room.mu.RLock()
+	played := h.room.CurrentTrack.ID
+	h.room.mu.RUnlock()
+
+	h.guess("A", "nobody", time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.guess("C", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+		MsgTypeRoundComplete, MsgTypeGameOver)
+
+	h.room.handleRematchVote("A")
+	h.room.handleRematchVote("A")
+	msgs := h.expect(MsgTypeRematchVote)
+	if vote := msgs[0].Payload.(map[string]interface{}); vote["needed"] != 3 || len(vote["accepted"].([]string)) != 1 {
+		t.Errorf("Expected 1 of 3 accepted, got %v", vote)
+	}
+	h.room.handleRematchVote("B")
+	h.expect(MsgTypeRematchVote)
+	if h.room.State != StateGameOver {
+		t.Fatal("Expected the rematch to wait for C")
+	}
+
+	// C leaving leaves everyone remaining in agreement
+	h.room.handlePlayerLeave("C")
+	h.expect(MsgTypePlayerLeft, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	defer h.room.mu.RUnlock()
+	if h.room.State != StatePlaying || h.room.CurrentTrack.ID == played || !h.room.PlayedTracks[played] {
+		t.Errorf("Expected a new game that skips %s, got %s", played, h.room.CurrentTrack.ID)
+	}
+
+	t.Logf("✓ A rematch starts once everyone accepts, without repeating tracks")
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"log"
+	"sync"
+)
+
+// ReplayBufferSize is how many recent messages are kept per player so a
+// client that reconnects quickly can catch up without a full resync
+const ReplayBufferSize = 50
+
+// replayBuffer numbers every message sent to a player and keeps the most
+// recent ones. It has its own lock because broadcasts only hold the room's
+// read lock.
+type replayBuffer struct {
+	mu      sync.Mutex
+	seq     int64
+	entries []Message
+}
+
+// record stamps msg with the player's next sequence number and keeps it
+func (b *replayBuffer) record(msg Message) Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	msg.Seq = b.seq
+	b.entries = append(b.entries, msg)
+	if len(b.entries) > ReplayBufferSize {
+		b.entries = b.entries[len(b.entries)-ReplayBufferSize:]
+	}
+	return msg
+}
+
+// since returns the messages sent after seq, or false when some of them are
+// no longer buffered (or seq is from another session)
+func (b *replayBuffer) since(seq int64) ([]Message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if seq <= 0 || seq > b.seq {
+		return nil, false
+	}
+	missed := int(b.seq - seq)
+	if missed > len(b.entries) {
+		return nil, false
+	}
+	return append([]Message(nil), b.entries[len(b.entries)-missed:]...), true
+}
+
+// deliver records msg in the player's replay buffer and sends it if they're
+// connected. Messages sent while a player is disconnected are only buffered.
+func (r *GameRoom) deliver(player *Player, msg Message) error {
+	msg = player.replay.record(r.stampGame(msg))
+	if player.Connection == nil {
+		return nil
+	}
+	return r.writeMessage(player, msg)
+}
+
+// replayMissed re-sends what a resuming client missed since lastSeq. It
+// reports false when the gap can't be filled and a full snapshot is needed.
+// Callers must hold the room lock.
+func (r *GameRoom) replayMissed(player *Player, lastSeq int64) bool {
+	missed, ok := player.replay.since(lastSeq)
+	if !ok {
+		return false
+	}
+
+	for _, msg := range missed {
+		if err := r.writeMessage(player, msg); err != nil {
+			log.Printf("Error replaying to player %s: %v", player.ID, err)
+			return false
+		}
+	}
+	log.Printf("Replayed %d missed messages to player %s in room %s", len(missed), player.Name, r.ID)
+
+	r.sendToPlayer(player.ID, Message{
+		Type: MsgTypeResumed,
+		Payload: map[string]interface{}{
+			"replayed": len(missed),
+		},
+	})
+	return true
+}
@@ -0,0 +1,16 @@
+package game
+
+import "log"
+
+// rerollUnguessed puts the round's track back into the pool when the rule is
+// on and nobody placed it, so it can come up again later in the game. Rounds
+// only pay out for correct guesses, so such a round has already awarded
+// nothing. Callers must hold the room lock.
+func (r *GameRoom) rerollUnguessed(result *RoundResult) {
+	if !r.Settings.RerollUnguessed || len(result.CorrectGuessers) > 0 {
+		return
+	}
+	delete(r.PlayedTracks, result.Track.ID)
+	result.Rerolled = true
+	log.Printf("Room %s: nobody got round %d, %s goes back into the pool", r.ID, result.Round, result.Track.Name)
+}
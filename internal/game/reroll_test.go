@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRerollUnguessed verifies a track nobody gets right goes back into the
+// pool, and that a round somebody gets right doesn't
+func TestRerollUnguessed(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 2
+	h.room.Settings.RerollUnguessed = true
+
+	// One shared track, so round 2 can only be played if round 1's comes back
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t1"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady,
+		MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.guess("A", "nobody", time.Second)
+	h.guess("B", "nobody", time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+	result := msgs[2].Payload.(*RoundResult)
+	if !result.Rerolled || len(result.PointsAwarded) != 0 {
+		t.Errorf("Expected an unguessed round to be re-rolled without points, got %+v", result)
+	}
+
+	msgs = h.expect(MsgTypeRoundStarted)
+	if round := msgs[0].Payload.(map[string]interface{}); round["round"] != 2 {
+		t.Errorf("Expected round 2 to play the re-rolled track, got %v", round)
+	}
+	h.guess("A", "A", time.Second)
+	h.guess("B", "nobody", time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	if result := msgs[2].Payload.(*RoundResult); result.Rerolled || !h.room.PlayedTracks["t1"] {
+		t.Errorf("Expected a guessed round to stay played, got %+v", result)
+	}
+
+	t.Logf("✓ Tracks nobody gets right go back into the pool")
+}
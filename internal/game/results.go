@@ -0,0 +1,77 @@
+package game
+
+import "time"
+
+// ResultSink durably stores completed rounds and finished games, e.g. in a
+// database. Rooms call it with their lock held, so it must not block.
+type ResultSink interface {
+	RecordRound(RoundRecord)
+	RecordGame(GameRecord)
+}
+
+// PlayerResults is a ResultSink that can also read back and anonymize what
+// it stored about a player, for data exports and deletions
+type PlayerResults interface {
+	ResultSink
+	PlayerGames(playerID string) ([]GameRecord, error)
+	AnonymizePlayer(playerID string) (int, error)
+}
+
+// playerResults returns the result sink if it can look up players
+func (rm *RoomManager) playerResults() (PlayerResults, bool) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	results, ok := rm.results.(PlayerResults)
+	return results, ok
+}
+
+// RoundRecord is a completed round as handed to a ResultSink. A round voided
+// later is recorded again with Voided set.
+type RoundRecord struct {
+	GameID          string         `json:"game_id"`
+	RoomID          string         `json:"room_id"`
+	Round           int            `json:"round"`
+	CompletedAt     time.Time      `json:"completed_at"`
+	TrackID         string         `json:"track_id"`
+	TrackName       string         `json:"track_name"`
+	Artists         []string       `json:"artists"`
+	WinnerID        string         `json:"winner_id"` // whose track it was
+	CorrectGuessers []string       `json:"correct_guessers"`
+	PointsAwarded   map[string]int `json:"points_awarded"`
+	Voided          bool           `json:"voided"`
+}
+
+// storeRound hands a completed round to the result sink.
+// Callers must hold the room lock.
+func (r *GameRoom) storeRound(result *RoundResult, voided bool) {
+	// Practice games against bots aren't kept
+	if r.results == nil || r.practice != nil {
+		return
+	}
+	r.results.RecordRound(RoundRecord{
+		GameID:          r.GameID,
+		RoomID:          r.ID,
+		Round:           result.Round,
+		CompletedAt:     time.Now().UTC(),
+		TrackID:         result.Track.ID,
+		TrackName:       result.Track.Name,
+		Artists:         result.Track.Artists,
+		WinnerID:        result.WinnerID,
+		CorrectGuessers: result.CorrectGuessers,
+		PointsAwarded:   result.PointsAwarded,
+		Voided:          voided,
+	})
+}
+
+// storeGame hands the finished game to the result sink. It's called again
+// when a round is voided after the game, to correct the final scores.
+// Callers must hold the room lock.
+func (r *GameRoom) storeGame(winnerID string) {
+	if r.results == nil || r.practice != nil {
+		return
+	}
+	if record, ok := r.gameRecord(winnerID); ok {
+		r.results.RecordGame(record)
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSink collects what a room hands to its result sink
+type fakeSink struct {
+	mu     sync.Mutex
+	rounds []RoundRecord
+	games  []GameRecord
+}
+
+func (s *fakeSink) RecordRound(round RoundRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rounds = append(s.rounds, round)
+}
+
+func (s *fakeSink) RecordGame(record GameRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games = append(s.games, record)
+}
+
+// TestResultSink verifies rounds reach the sink as they complete and the
+// game when it's over, and that voiding a round afterwards corrects both
+func TestResultSink(t *testing.T) {
+	h := newGameHarness(t, 2)
+	sink := &fakeSink{}
+	h.room.results = sink
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady,
+		MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	owner := map[string]string{"t1": "A", "t2": "B"}[h.room.CurrentTrack.ID]
+	h.room.mu.RUnlock()
+	h.guess("A", owner, time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	if len(sink.rounds) != 1 || sink.rounds[0].WinnerID != owner || sink.rounds[0].PointsAwarded["A"] == 0 {
+		t.Fatalf("Expected round 1 with A's points, got %+v", sink.rounds)
+	}
+	if len(sink.games) != 1 || sink.games[0].GameID != sink.rounds[0].GameID || sink.games[0].WinnerID != "A" {
+		t.Fatalf("Expected the game won by A, got %+v", sink.games)
+	}
+
+	h.room.handleVoidRound(VoidRoundPayload{PlayerID: h.room.LeaderID, Round: 1})
+	h.expect(MsgTypeRoundVoided)
+	if len(sink.rounds) != 2 || !sink.rounds[1].Voided || len(sink.games) != 2 || sink.games[1].Players[0].Score != 0 {
+		t.Errorf("Expected the voided round and corrected scores to be stored, got %+v and %+v", sink.rounds, sink.games)
+	}
+
+	t.Logf("✓ Rounds and games reach the result sink, and voiding corrects them")
+}
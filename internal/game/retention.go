@@ -0,0 +1,194 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy bounds how long a JSON lines store keeps records hot.
+// A zero MaxAge keeps everything; records past it are moved to gzipped JSON
+// lines in ArchiveDir, or dropped when ArchiveDir is empty.
+type RetentionPolicy struct {
+	MaxAge     time.Duration
+	ArchiveDir string
+}
+
+// Enabled reports whether the policy ever removes records
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0
+}
+
+// Cutoff is the time before which records are due for archival
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.Add(-p.MaxAge)
+}
+
+// CompactionResult reports what a compaction did
+type CompactionResult struct {
+	Kept        int    `json:"kept"`
+	Archived    int    `json:"archived"` // moved to ArchivePath, or dropped without an archive dir
+	ArchivePath string `json:"archive_path,omitempty"`
+}
+
+// CompactJSONL moves the records in a JSON lines file older than cutoff to a
+// timestamped gzip archive in archiveDir (or drops them when archiveDir is
+// empty) and rewrites the file with the rest. recordTime reads a record's
+// timestamp; records it can't read are kept. The caller must stop writers to
+// the file for the duration.
+func CompactJSONL(path string, cutoff time.Time, archiveDir string, recordTime func(line []byte) (time.Time, error)) (CompactionResult, error) {
+	var result CompactionResult
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	var kept, expired [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.Clone(scanner.Bytes())
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if at, err := recordTime(line); err == nil && at.Before(cutoff) {
+			expired = append(expired, line)
+		} else {
+			kept = append(kept, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	result.Kept = len(kept)
+	if len(expired) == 0 {
+		return result, nil
+	}
+
+	// Archive before rewriting so a failure never loses records
+	if archiveDir != "" {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if result.ArchivePath, err = writeArchive(archiveDir, name, expired); err != nil {
+			return result, err
+		}
+	}
+	if err := rewriteJSONL(path, kept); err != nil {
+		return result, err
+	}
+	result.Archived = len(expired)
+	return result, nil
+}
+
+// writeArchive gzips lines into a new timestamped file in dir
+func writeArchive(dir, name string, lines [][]byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create archive dir: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.jsonl.gz", name, time.Now().UTC().Format("20060102-150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	zw := gzip.NewWriter(file)
+	for _, line := range lines {
+		if _, err := zw.Write(append(line, '\n')); err != nil {
+			file.Close()
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
+
+// rewriteJSONL replaces the file's contents with lines, writing then renaming
+// so a crash never leaves it half-rewritten
+func rewriteJSONL(path string, lines [][]byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		writer.Write(line)
+		writer.WriteByte('\n')
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// FileSize returns the size of a file in bytes, or 0 when it doesn't exist
+func FileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// Compact archives games that finished before the policy's cutoff and drops
+// them from memory
+func (h *HistoryStore) Compact(policy RetentionPolicy) (CompactionResult, error) {
+	if !policy.Enabled() {
+		return CompactionResult{}, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := policy.Cutoff(time.Now())
+	result, err := CompactJSONL(h.path, cutoff, policy.ArchiveDir, func(line []byte) (time.Time, error) {
+		var record struct {
+			FinishedAt time.Time `json:"finished_at"`
+		}
+		err := json.Unmarshal(line, &record)
+		return record.FinishedAt, err
+	})
+	if err != nil || result.Archived == 0 {
+		return result, err
+	}
+
+	kept := make([]GameRecord, 0, result.Kept)
+	for _, record := range h.records {
+		if !record.FinishedAt.Before(cutoff) {
+			kept = append(kept, record)
+		}
+	}
+	h.records = kept
+	return result, nil
+}
+
+// Size reports how many games the history holds and its size on disk
+func (h *HistoryStore) Size() (int, int64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.records), FileSize(h.path)
+}
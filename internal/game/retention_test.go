@@ -0,0 +1,74 @@
+package game
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHistoryCompaction verifies games past the retention window are archived to gzip and dropped from the hot store
+func TestHistoryCompaction(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	history, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	players := []RecordPlayer{{ID: "A", Score: 20}, {ID: "B", Score: 10}}
+	history.Append(GameRecord{RoomID: "Room 1", FinishedAt: time.Now().Add(-100 * 24 * time.Hour), WinnerID: "A", Players: players})
+	history.Append(GameRecord{RoomID: "Room 1", FinishedAt: time.Now().Add(-95 * 24 * time.Hour), WinnerID: "A", Players: players})
+	history.Append(GameRecord{RoomID: "Room 2", FinishedAt: time.Now().Add(-time.Hour), WinnerID: "A", Players: players})
+
+	policy := RetentionPolicy{MaxAge: 90 * 24 * time.Hour, ArchiveDir: archiveDir}
+	result, err := history.Compact(policy)
+	if err != nil {
+		t.Fatalf("Compaction failed: %v", err)
+	}
+	if result.Archived != 2 || result.Kept != 1 {
+		t.Errorf("Expected 2 archived and 1 kept, got %+v", result)
+	}
+	if games, _ := history.Size(); games != 1 || len(history.Games("A")) != 1 {
+		t.Errorf("Expected one game left in memory, got %d", games)
+	}
+
+	// The archive holds the expired games as gzipped JSON lines
+	file, err := os.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Archive isn't gzip: %v", err)
+	}
+	lines := 0
+	for scanner := bufio.NewScanner(zr); scanner.Scan(); {
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("Expected 2 archived games, got %d", lines)
+	}
+
+	// The rewritten file survives a restart
+	reopened, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen history: %v", err)
+	}
+	if games, _ := reopened.Size(); games != 1 {
+		t.Errorf("Expected one game after a restart, got %d", games)
+	}
+
+	// Nothing left to archive, and no policy means no compaction
+	if result, _ := history.Compact(policy); result.Archived != 0 {
+		t.Errorf("Expected nothing more to archive, got %+v", result)
+	}
+	if result, _ := history.Compact(RetentionPolicy{}); result.Kept != 0 || result.Archived != 0 {
+		t.Errorf("Expected a zero policy to leave the store alone, got %+v", result)
+	}
+
+	t.Logf("✓ Games older than the retention window move to a gzip archive")
+}
@@ -0,0 +1,243 @@
+package game
+
+import (
+	"log"
+	"sort"
+
+	"roulettify/internal/auth"
+)
+
+// ReverseRoundPrompt is shown to players during a reverse round
+const ReverseRoundPrompt = "Which of these is in their top 5?"
+
+// Reverse rounds show one of the subject's top ReverseAnswerMaxRank tracks
+// alongside ReverseRoundDecoys tracks from other players
+const (
+	ReverseAnswerMaxRank = 5
+	ReverseRoundDecoys   = 2
+)
+
+// GuessTrack is the guess mode of reverse rounds: pick a track, not a player.
+// It can't be chosen as a room's guess mode.
+const GuessTrack GuessMode = "track"
+
+// reverseRound is a round that names a player and asks which of the
+// candidate tracks is one of their favourites
+type reverseRound struct {
+	SubjectID   string
+	SubjectName string
+	Answer      auth.Track
+	Candidates  []auth.Track // the answer and decoys, in display order
+}
+
+// ReverseCandidate is a track players can pick in a reverse round
+type ReverseCandidate struct {
+	TrackID  string   `json:"track_id"`
+	Name     string   `json:"name"`
+	Artists  []string `json:"artists"`
+	ImageURL string   `json:"image_url"`
+}
+
+// ReverseRoundInfo is sent in round_started for reverse rounds
+type ReverseRoundInfo struct {
+	SubjectID   string             `json:"subject_id"`
+	SubjectName string             `json:"subject_name"`
+	Prompt      string             `json:"prompt"`
+	Candidates  []ReverseCandidate `json:"candidates"`
+}
+
+func (rr *reverseRound) info() ReverseRoundInfo {
+	candidates := make([]ReverseCandidate, len(rr.Candidates))
+	for i, track := range rr.Candidates {
+		candidates[i] = ReverseCandidate{
+			TrackID:  track.ID,
+			Name:     track.Name,
+			Artists:  track.Artists,
+			ImageURL: track.ImageURL,
+		}
+	}
+	return ReverseRoundInfo{
+		SubjectID:   rr.SubjectID,
+		SubjectName: rr.SubjectName,
+		Prompt:      ReverseRoundPrompt,
+		Candidates:  candidates,
+	}
+}
+
+func (rr *reverseRound) isCandidate(trackID string) bool {
+	for _, track := range rr.Candidates {
+		if track.ID == trackID {
+			return true
+		}
+	}
+	return false
+}
+
+// isReverseRound reports whether the current round number is due a reverse
+// round. Callers must hold the room lock.
+func (r *GameRoom) isReverseRound() bool {
+	interval := r.Settings.ReverseRoundInterval
+	return interval > 0 && r.CurrentRound%interval == 0
+}
+
+// pickReverseRound sets up a reverse round when one is due: a subject with an
+// unplayed top-5 track, and decoys from other players. It returns nil when
+// no round is due or there aren't enough tracks. Callers must hold the room lock.
+func (r *GameRoom) pickReverseRound() *reverseRound {
+	if !r.isReverseRound() {
+		return nil
+	}
+
+	// Subjects and answers are picked in a stable order so seeded games replay
+	subjects := make([]string, 0, len(r.Players))
+	for playerID, player := range r.Players {
+		if player.IsSpectator || len(r.reverseAnswers(player)) == 0 {
+			continue
+		}
+		subjects = append(subjects, playerID)
+	}
+	if len(subjects) == 0 {
+		return nil
+	}
+	sort.Strings(subjects)
+	subject := r.Players[subjects[r.pickIndex(len(subjects))]]
+
+	answers := r.reverseAnswers(subject)
+	answer := answers[r.pickIndex(len(answers))]
+
+	decoys := r.pickDecoys(subject, ReverseRoundDecoys)
+	if len(decoys) < ReverseRoundDecoys {
+		log.Printf("Room %s: not enough decoys for a reverse round, playing a normal one", r.ID)
+		return nil
+	}
+
+	position := r.pickIndex(len(decoys) + 1)
+	candidates := make([]auth.Track, 0, len(decoys)+1)
+	candidates = append(candidates, decoys[:position]...)
+	candidates = append(candidates, answer)
+	candidates = append(candidates, decoys[position:]...)
+
+	return &reverseRound{
+		SubjectID:   subject.ID,
+		SubjectName: subject.Name,
+		Answer:      answer,
+		Candidates:  candidates,
+	}
+}
+
+// reverseAnswers returns the player's unplayed top-5 tracks, best first.
+// Callers must hold the room lock.
+func (r *GameRoom) reverseAnswers(player *Player) []auth.Track {
+	answers := make([]auth.Track, 0, ReverseAnswerMaxRank)
+	for _, track := range player.TopTracks {
+		if track.Rank <= ReverseAnswerMaxRank && !r.PlayedTracks[track.ID] && !player.ExcludedTracks[track.ID] {
+			answers = append(answers, track)
+		}
+	}
+	sort.Slice(answers, func(i, j int) bool { return answers[i].Rank < answers[j].Rank })
+	return answers
+}
+
+// pickDecoys picks up to n tracks from other active players' top tracks that
+// aren't anywhere in the subject's, so only one candidate is right.
+// Callers must hold the room lock.
+func (r *GameRoom) pickDecoys(subject *Player, n int) []auth.Track {
+	owned := make(map[string]bool, len(subject.TopTracks))
+	for _, track := range subject.TopTracks {
+		owned[track.ID] = true
+	}
+
+	pool := make(map[string]auth.Track)
+	for _, player := range r.Players {
+		if player.ID == subject.ID || player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if owned[track.ID] || player.ExcludedTracks[track.ID] {
+				continue
+			}
+			pool[track.ID] = track
+		}
+	}
+
+	ids := make([]string, 0, len(pool))
+	for trackID := range pool {
+		ids = append(ids, trackID)
+	}
+	sort.Strings(ids)
+
+	decoys := make([]auth.Track, 0, n)
+	for len(decoys) < n && len(ids) > 0 {
+		i := r.pickIndex(len(ids))
+		decoys = append(decoys, pool[ids[i]])
+		ids = append(ids[:i], ids[i+1:]...)
+	}
+	return decoys
+}
+
+// guessersNeeded is how many guesses end the round early: the subject of a
+// reverse round sits it out, and only the tied players guess in overtime.
+// Callers must hold the room lock.
+func (r *GameRoom) guessersNeeded() int {
+	if r.overtime != nil {
+		return r.overtimeGuessers()
+	}
+	if r.activeReverse != nil {
+		return r.activePlayerCount() - 1
+	}
+	return r.activePlayerCount()
+}
+
+// calculateReverseResults scores a reverse round: a correct guess is the
+// subject's own track. Callers must hold the room lock.
+func (r *GameRoom) calculateReverseResults() *RoundResult {
+	rr := r.activeReverse
+
+	correctGuessers := make([]string, 0)
+	for playerID, guess := range r.Guesses {
+		if guess.GuessedTrackID == rr.Answer.ID {
+			correctGuessers = append(correctGuessers, playerID)
+		}
+	}
+	sort.Slice(correctGuessers, func(i, j int) bool {
+		return r.Guesses[correctGuessers[i]].Timestamp.Before(
+			r.Guesses[correctGuessers[j]].Timestamp,
+		)
+	})
+
+	pointsAwarded := make(map[string]int)
+	guessDurations := make(map[string]float64)
+	score := r.scoring()
+	roundLength := r.currentRoundDuration()
+	for idx, playerID := range correctGuessers {
+		elapsed := r.Guesses[playerID].Timestamp.Sub(r.RoundStartTime)
+		points := score(CorrectGuess{Order: idx, Elapsed: elapsed, RoundLength: roundLength})
+		if r.ActivePowerups[playerID] == PowerupBooster {
+			points *= 2
+		}
+		points = r.handicapped(playerID, points)
+		pointsAwarded[playerID] = points
+		r.Scores[playerID] += points
+		guessDurations[playerID] = elapsed.Seconds()
+	}
+
+	var powerupsUsed map[string]PowerupType
+	var streaks map[string]int
+	if r.PowerupsEnabled {
+		powerupsUsed, streaks = r.applyStreaks(correctGuessers)
+	}
+
+	return &RoundResult{
+		Round:           r.CurrentRound,
+		Track:           rr.Answer,
+		WinnerID:        rr.SubjectID,
+		WinnerRank:      rr.Answer.Rank,
+		CorrectGuessers: correctGuessers,
+		PointsAwarded:   pointsAwarded,
+		UpdatedScores:   r.Scores,
+		GuessDurations:  guessDurations,
+		PowerupsUsed:    powerupsUsed,
+		Streaks:         streaks,
+		Reverse:         true,
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReverseRound verifies a reverse round offers one of the subject's top 5 among decoys and scores track picks
+func TestReverseRound(t *testing.T) {
+	h := newGameHarness(t, 13)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.ReverseRoundInterval = 1 // not a valid setting, but makes round 1 a reverse round
+
+	h.join(harnessPlayer("A", "a1", "a2", "a3"))
+	h.join(harnessPlayer("B", "b1", "b2", "b3"))
+	h.join(harnessPlayer("C", "c1", "c2", "c3"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	started := msgs[7].Payload.(map[string]interface{})
+	if _, leaked := started["audio_path"]; leaked {
+		t.Error("Expected reverse rounds not to play the answer's audio")
+	}
+	info := started["reverse"].(ReverseRoundInfo)
+	if len(info.Candidates) != 1+ReverseRoundDecoys {
+		t.Fatalf("Expected 3 candidates, got %+v", info.Candidates)
+	}
+
+	subject := h.room.Players[info.SubjectID]
+	answer := ""
+	for _, candidate := range info.Candidates {
+		for _, track := range subject.TopTracks {
+			if track.ID == candidate.TrackID {
+				if answer != "" {
+					t.Fatal("Expected exactly one candidate from the subject's tracks")
+				}
+				answer = track.ID
+			}
+		}
+	}
+	if answer == "" {
+		t.Fatal("Expected one candidate to be the subject's")
+	}
+
+	// The subject sits out; the other two guess, one right and one wrong
+	others := make([]string, 0, 2)
+	for _, id := range []string{"A", "B", "C"} {
+		if id != info.SubjectID {
+			others = append(others, id)
+		}
+	}
+	wrong := info.Candidates[0].TrackID
+	if wrong == answer {
+		wrong = info.Candidates[1].TrackID
+	}
+
+	h.room.handleGuess(Guess{PlayerID: info.SubjectID, GuessedTrackID: answer, Timestamp: time.Now()})
+	h.room.handleGuess(Guess{PlayerID: others[0], GuessedTrackID: answer, Timestamp: time.Now()})
+	h.room.handleGuess(Guess{PlayerID: others[1], GuessedTrackID: wrong, Timestamp: time.Now()})
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	result := msgs[2].Payload.(*RoundResult)
+	if !result.Reverse || result.WinnerID != info.SubjectID || result.Track.ID != answer {
+		t.Errorf("Unexpected reverse result: %+v", result)
+	}
+	if len(result.CorrectGuessers) != 1 || result.CorrectGuessers[0] != others[0] {
+		t.Errorf("Expected only %s to be right, got %v", others[0], result.CorrectGuessers)
+	}
+
+	t.Logf("✓ Reverse rounds mix one of the subject's top 5 with decoys and score track picks")
+}
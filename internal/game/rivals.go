@@ -0,0 +1,213 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// MinRivalryGames is how many games two players must have played against
+	// each other before their rivalry is announced
+	MinRivalryGames = 3
+	// MaxMatchupGames is how many of their latest shared games a matchup lists
+	MaxMatchupGames = 10
+)
+
+// Rivalry is a player's head-to-head record against one opponent
+type Rivalry struct {
+	PlayerID     string `json:"player_id"`
+	OpponentID   string `json:"opponent_id"`
+	OpponentName string `json:"opponent_name"`
+	Games        int    `json:"games"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+	Ties         int    `json:"ties"`
+	// AverageMargin is the player's score minus the opponent's, per game
+	AverageMargin float64 `json:"average_margin"`
+}
+
+// Rivals returns the player's head-to-head records against everyone they
+// have played, most frequent opponents first
+func (h *HistoryStore) Rivals(playerID string) []Rivalry {
+	byOpponent := make(map[string]*Rivalry)
+	margins := make(map[string]int)
+
+	for _, game := range h.Games(playerID) {
+		score, _ := game.score(playerID)
+		for _, opponent := range game.Players {
+			if opponent.ID == playerID {
+				continue
+			}
+			rivalry, ok := byOpponent[opponent.ID]
+			if !ok {
+				rivalry = &Rivalry{PlayerID: playerID, OpponentID: opponent.ID}
+				byOpponent[opponent.ID] = rivalry
+			}
+			rivalry.OpponentName = opponent.Name // most recent name
+			rivalry.Games++
+			switch {
+			case score > opponent.Score:
+				rivalry.Wins++
+			case score < opponent.Score:
+				rivalry.Losses++
+			default:
+				rivalry.Ties++
+			}
+			margins[opponent.ID] += score - opponent.Score
+		}
+	}
+
+	rivals := make([]Rivalry, 0, len(byOpponent))
+	for opponentID, rivalry := range byOpponent {
+		rivalry.AverageMargin = float64(margins[opponentID]) / float64(rivalry.Games)
+		rivals = append(rivals, *rivalry)
+	}
+	sort.Slice(rivals, func(i, j int) bool {
+		if rivals[i].Games != rivals[j].Games {
+			return rivals[i].Games > rivals[j].Games
+		}
+		return rivals[i].OpponentID < rivals[j].OpponentID
+	})
+	return rivals
+}
+
+// HeadToHead returns a's record against b
+func (h *HistoryStore) HeadToHead(a, b string) Rivalry {
+	for _, rivalry := range h.Rivals(a) {
+		if rivalry.OpponentID == b {
+			return rivalry
+		}
+	}
+	return Rivalry{PlayerID: a, OpponentID: b}
+}
+
+// Matchup compares two players across the games they played together, from
+// the first player's side
+type Matchup struct {
+	Rivalry
+	PlayerName       string `json:"player_name"`
+	GamesWon         int    `json:"games_won"`          // shared games the player won outright
+	OpponentGamesWon int    `json:"opponent_games_won"` // shared games the opponent won outright
+	// Reads is how often the player named the opponent's tracks correctly,
+	// ReadBy how often the opponent named theirs
+	Reads  TasteLink `json:"reads"`
+	ReadBy TasteLink `json:"read_by"`
+	// BetterReader is whichever of the two reads the other more accurately,
+	// empty when they're level or either has never guessed the other's tracks
+	BetterReader string        `json:"better_reader,omitempty"`
+	Recent       []MatchupGame `json:"recent"` // latest shared games, newest first
+}
+
+// MatchupGame is one game two players played together
+type MatchupGame struct {
+	GameID        string    `json:"game_id"`
+	FinishedAt    time.Time `json:"finished_at"`
+	Score         int       `json:"score"`
+	OpponentScore int       `json:"opponent_score"`
+	WinnerID      string    `json:"winner_id"`
+}
+
+// Matchup returns a's head-to-head stats against b: their record, outright
+// wins and who guesses whose tracks correctly more often
+func (h *HistoryStore) Matchup(a, b string) Matchup {
+	matchup := Matchup{
+		Rivalry: h.HeadToHead(a, b),
+		Recent:  make([]MatchupGame, 0),
+	}
+	var reads, readBy [2]int
+
+	games := h.Games(a)
+	for i := len(games) - 1; i >= 0; i-- {
+		game := games[i]
+		opponentScore, shared := game.score(b)
+		if !shared {
+			continue
+		}
+		score, _ := game.score(a)
+		if matchup.PlayerName == "" {
+			matchup.PlayerName = game.name(a) // most recent name
+		}
+
+		switch game.WinnerID {
+		case a:
+			matchup.GamesWon++
+		case b:
+			matchup.OpponentGamesWon++
+		}
+		for _, link := range game.Taste {
+			switch {
+			case link.GuesserID == a && link.TargetID == b:
+				reads[0] += link.Rounds
+				reads[1] += link.Correct
+			case link.GuesserID == b && link.TargetID == a:
+				readBy[0] += link.Rounds
+				readBy[1] += link.Correct
+			}
+		}
+		if len(matchup.Recent) < MaxMatchupGames {
+			matchup.Recent = append(matchup.Recent, MatchupGame{
+				GameID:        game.GameID,
+				FinishedAt:    game.FinishedAt,
+				Score:         score,
+				OpponentScore: opponentScore,
+				WinnerID:      game.WinnerID,
+			})
+		}
+	}
+
+	matchup.Reads = TasteLink{GuesserID: a, TargetID: b}
+	if reads[0] > 0 {
+		matchup.Reads = newTasteLink(tastePair{guesser: a, target: b}, reads[0], reads[1])
+	}
+	matchup.ReadBy = TasteLink{GuesserID: b, TargetID: a}
+	if readBy[0] > 0 {
+		matchup.ReadBy = newTasteLink(tastePair{guesser: b, target: a}, readBy[0], readBy[1])
+	}
+	if reads[0] > 0 && readBy[0] > 0 {
+		switch {
+		case matchup.Reads.Accuracy > matchup.ReadBy.Accuracy:
+			matchup.BetterReader = a
+		case matchup.ReadBy.Accuracy > matchup.Reads.Accuracy:
+			matchup.BetterReader = b
+		}
+	}
+	return matchup
+}
+
+// announceRivalries broadcasts the head-to-head records of frequent
+// opponents about to play each other. Callers must hold the room lock.
+func (r *GameRoom) announceRivalries() {
+	if r.history == nil {
+		return
+	}
+
+	playing := make([]string, 0, len(r.PlayerOrder))
+	for _, playerID := range r.PlayerOrder {
+		if player, ok := r.Players[playerID]; ok && !player.IsSpectator {
+			playing = append(playing, playerID)
+		}
+	}
+
+	rivalries := make([]Rivalry, 0)
+	for i, a := range playing {
+		for _, b := range playing[i+1:] {
+			if rivalry := r.history.HeadToHead(a, b); rivalry.Games >= MinRivalryGames {
+				rivalries = append(rivalries, rivalry)
+			}
+		}
+	}
+	if len(rivalries) == 0 {
+		return
+	}
+
+	sort.SliceStable(rivalries, func(i, j int) bool {
+		return rivalries[i].Games > rivalries[j].Games
+	})
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRivalry,
+		Payload: map[string]interface{}{
+			"rivalries": rivalries,
+		},
+	}
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func recordResult(t *testing.T, history *HistoryStore, scores ...RecordPlayer) {
+	t.Helper()
+	record := GameRecord{RoomID: "room-1", FinishedAt: time.Now(), Players: scores}
+	for _, p := range scores {
+		if best, _ := record.score(record.WinnerID); record.WinnerID == "" || p.Score > best {
+			record.WinnerID = p.ID
+		}
+	}
+	if err := history.Append(record); err != nil {
+		t.Fatalf("Failed to record game: %v", err)
+	}
+}
+
+// TestRivalsHeadToHead verifies records are tallied per opponent and survive a reload
+func TestRivalsHeadToHead(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	recordResult(t, history, RecordPlayer{ID: "A", Score: 30}, RecordPlayer{ID: "B", Score: 10})
+	recordResult(t, history, RecordPlayer{ID: "A", Score: 20}, RecordPlayer{ID: "B", Score: 25}, RecordPlayer{ID: "C", Score: 5})
+	recordResult(t, history, RecordPlayer{ID: "A", Score: 15}, RecordPlayer{ID: "B", Name: "Bee", Score: 15})
+
+	reloaded, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to reload history: %v", err)
+	}
+
+	rivals := reloaded.Rivals("A")
+	if len(rivals) != 2 || rivals[0].OpponentID != "B" || rivals[1].OpponentID != "C" {
+		t.Fatalf("Expected rivals B then C, got %+v", rivals)
+	}
+	b := rivals[0]
+	if b.Games != 3 || b.Wins != 1 || b.Losses != 1 || b.Ties != 1 {
+		t.Errorf("Unexpected record against B: %+v", b)
+	}
+	if b.AverageMargin != 5 || b.OpponentName != "Bee" {
+		t.Errorf("Expected margin 5 against Bee, got %v against %q", b.AverageMargin, b.OpponentName)
+	}
+
+	if flipped := reloaded.HeadToHead("B", "A"); flipped.Wins != 1 || flipped.AverageMargin != -5 {
+		t.Errorf("Expected B's side to mirror A's, got %+v", flipped)
+	}
+
+	t.Logf("✓ Head-to-head records are tallied per opponent and persist")
+}
+
+// TestRivalryAnnouncedAtGameStart verifies frequent opponents get a rivalry banner
+func TestRivalryAnnouncedAtGameStart(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	h := newGameHarness(t, 1)
+	h.room.history = history
+	h.room.Settings.TotalRounds = 1
+
+	for i := 0; i < MinRivalryGames; i++ {
+		recordResult(t, history, RecordPlayer{ID: "A", Score: 20}, RecordPlayer{ID: "B", Score: 10})
+	}
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.ready("A")
+	h.ready("B")
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeRivalry, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	rivalries := msgs[4].Payload.(map[string]interface{})["rivalries"].([]Rivalry)
+	if len(rivalries) != 1 || rivalries[0].Wins != MinRivalryGames {
+		t.Errorf("Expected A's winning record against B, got %+v", rivalries)
+	}
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	if games := history.Games("B"); len(games) != MinRivalryGames+1 {
+		t.Errorf("Expected the finished game to be recorded, got %d games", len(games))
+	}
+
+	t.Logf("✓ Frequent opponents get a rivalry banner and finished games are recorded")
+}
+
+// TestMatchup verifies two players' matchup counts outright wins and who
+// reads whose taste better, over only the games they shared
+func TestMatchup(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	games := []GameRecord{
+		{GameID: "g1", WinnerID: "A", Players: []RecordPlayer{{ID: "A", Name: "Ay", Score: 30}, {ID: "B", Score: 10}},
+			Taste: []TasteLink{{GuesserID: "A", TargetID: "B", Rounds: 2, Correct: 2}, {GuesserID: "B", TargetID: "A", Rounds: 2, Correct: 0}}},
+		{GameID: "g2", WinnerID: "C", Players: []RecordPlayer{{ID: "A", Name: "Ace", Score: 20}, {ID: "B", Score: 25}, {ID: "C", Score: 40}},
+			Taste: []TasteLink{{GuesserID: "A", TargetID: "B", Rounds: 2, Correct: 0}, {GuesserID: "B", TargetID: "A", Rounds: 2, Correct: 1}, {GuesserID: "A", TargetID: "C", Rounds: 3, Correct: 3}}},
+		{GameID: "g3", WinnerID: "A", Players: []RecordPlayer{{ID: "A", Score: 50}, {ID: "C", Score: 0}}},
+	}
+	for _, game := range games {
+		game.FinishedAt = time.Now()
+		if err := history.Append(game); err != nil {
+			t.Fatalf("Failed to record game: %v", err)
+		}
+	}
+
+	matchup := history.Matchup("A", "B")
+	if matchup.Games != 2 || matchup.Wins != 1 || matchup.Losses != 1 || matchup.PlayerName != "Ace" {
+		t.Errorf("Expected a 1-1 record over 2 shared games as Ace, got %+v", matchup.Rivalry)
+	}
+	if matchup.GamesWon != 1 || matchup.OpponentGamesWon != 0 {
+		t.Errorf("Expected A to have won one shared game outright and B none, got %d and %d", matchup.GamesWon, matchup.OpponentGamesWon)
+	}
+	if matchup.Reads.Rounds != 4 || matchup.Reads.Correct != 2 || matchup.ReadBy.Correct != 1 || matchup.BetterReader != "A" {
+		t.Errorf("Expected A to read B better, 2 of 4 against 1 of 4, got %+v and %+v (%q)", matchup.Reads, matchup.ReadBy, matchup.BetterReader)
+	}
+	if len(matchup.Recent) != 2 || matchup.Recent[0].GameID != "g2" || matchup.Recent[0].OpponentScore != 25 {
+		t.Errorf("Expected the 2 shared games newest first, got %+v", matchup.Recent)
+	}
+
+	strangers := history.Matchup("B", "D")
+	if strangers.Games != 0 || strangers.BetterReader != "" || strangers.Reads.Accuracy != 0 || len(strangers.Recent) != 0 {
+		t.Errorf("Expected an empty matchup between strangers, got %+v", strangers)
+	}
+
+	t.Logf("✓ Matchups compare records, outright wins and taste over shared games")
+}
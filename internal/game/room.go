@@ -1,22 +1,44 @@
 package game
 
 import (
-	"context"
+	"fmt"
 	"log"
+	"maps"
 	"math/rand"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"roulettify/internal/auth"
 
-	"github.com/coder/websocket/wsjson"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
 )
 
 const MaxPlayersPerRoom = 10
 
 type GameRoom struct {
 	ID           string
+	Name         string // creator-provided label for dynamic rooms
+	Description  string
+	Region       string // latency grouping label shown in the room list
+	CreatedRegion string // region of the instance that created the room
+	Instance     string // instance running the room, for sticky routing
+	Endpoint     string // WebSocket URL that reaches Instance directly
+	OwnerID      string // account that claimed the room, always leader when present
+	OwnerName    string
+	store        *RoomStore
+	leaderboard  map[string]*RoomStanding // all-time standings, loaded from store when needed
+	history      *HistoryStore
+	replays      *ReplayStore
+	recorder     *gameRecorder // the running game's recording, if it's recorded
+	presence     *Presence
+	diagnostics  *Diagnostics
+	results      ResultSink
+	stateSink    RoomStateSink // mirrors the running game so it survives a restart
+	webhooks     *Webhooks
+	webhook      *Webhook // where a claimed room posts its finished games
 	Players      map[string]*Player
 	PlayerOrder  []string
 	Scores       map[string]int
@@ -26,9 +48,82 @@ type GameRoom struct {
 	Guesses      map[string]Guess
 	PlayedTracks map[string]bool
 	State        GameState
+	GameID       string // unique per game; the room ID is reused across games
 	RoundTimer   *time.Timer
 	LeaderID     string
 	RoundStartTime time.Time
+	ScoreHistory map[string][]int
+	Password     string
+	Hostless     bool
+	Settings     RoomSettings
+	EndVotes     map[string]bool
+	SessionScores map[string]*SessionStanding
+	KickVotes    map[string]*KickVote
+	Banned       map[string]bool
+	hiddenTracks map[string]map[string]bool // by player ID, kept across rejoins
+	CheckpointDir string
+	Limits       *Limits
+	Flags        *FeatureFlags
+	holdsGameSlot bool
+	AutoStartCountdown int // seconds; 0 starts immediately
+	countdownActive bool
+	countdownGen  int
+	countdownEndsAt time.Time
+	checkpointScores map[string]int
+	PowerupsEnabled bool
+	ActivePowerups  map[string]PowerupType
+	lastActivity    time.Time
+	revealing       bool // between a round's results and the next round
+	observers       map[*websocket.Conn]string // admin observers by actor
+	bonus           *bonusRound // prepared for the next bonus round
+	activeBonus     *bonusRound // set while the current round is a bonus round
+	activeReverse   *reverseRound // set while the current round is a reverse round
+	bonusGen        int
+	queue           []*Player // waiting for a seat in a full room
+	filterActive    bool      // language/market filters apply to this game
+	Predictions     map[string]string // spectator -> predicted round winner
+	events          eventLog
+	SpectatorScores map[string]int    // spectator prediction leaderboard
+	roundResults    map[int]*RoundResult // recent completed rounds, for voiding
+	voidedRounds    map[int]bool
+	miniGame        *miniGame // open between rounds when mini-games are on
+	miniGameGen     int
+	lastGame        *StartGamePayload // what the last game started with, for quick rematches
+	pendingGame     *StartGamePayload // the leader's start_game options, for a game that starts on its own
+	lastSettings    RoomSettings
+	rematch         *pendingRematch
+	rematchGen      int
+	rematchVotes    map[string]bool // players who accepted a rematch since the game ended
+	rematchPlayed   map[string]bool // tracks a voted rematch keeps out of its pool
+	revealTimer     *time.Timer // suspense mode: reveals the round's winner
+	hintTimers      []*time.Timer // reveal the current round's hints
+	hints           []Hint        // revealed so far this round
+	titleReveal     *titleReveal  // fills in the masked title over the round
+	practice        *practiceSetup // set for practice rooms, which seat bots
+	tutorial        *tutorial      // set for scripted tutorial rooms
+	overtime        *overtime      // set once a tied game goes to sudden death
+	playlist        *playlistPool  // set when games are played from a playlist
+	roundEra        int            // the current round's decade on era rounds, else 0
+	lastEra         int            // the last era round's decade, to rotate from
+	eraFilter       int            // decade selectTrack is limited to while picking an era round's track
+	roundExtension  time.Duration  // grace period added to the current round, see extendRound
+	trackReplayed   bool           // the current round's track was replayed after its results
+	paused          *gamePause     // set while the leader has the game paused
+	roundSummaries  []RoundSummary // this game's rounds so far, for the game over summary
+	botSeq          int            // bots seated so far, for their IDs and names
+	overlayToken    string         // reads the stream overlay; created on first request
+	roundPlan       []auth.Track  // tracks picked at game start for the rounds still to come
+	roundSeconds    int                    // this game's round duration
+	tallies         map[string]*guessTally // correct guesses this game, for tie-breaks
+	taste           map[tastePair][2]int   // rounds guessed and correct, per guesser and round owner
+	outcomes        map[string]*TrackOutcome // guesses and wrong guesses per track, for deceiving stats
+	// pickIndex makes the room's random picks: tracks, reverse round
+	// subjects and decoys. Tests give each room a seeded source so games
+	// replay identically.
+	pickIndex       func(n int) int
+	stopped         bool
+	done            chan struct{} // closed once the room has shut down
+	emptySince      time.Time     // when the manager's reaper first found the room empty
 
 	// Channels
 	Join      chan *Player
@@ -36,6 +131,30 @@ type GameRoom struct {
 	Ready     chan ReadyPayload
 	Guess     chan Guess
 	StartGame chan StartGamePayload
+	Powerup   chan UsePowerupPayload
+	SetPassword chan SetPasswordPayload
+	UpdateSettings chan UpdateSettingsPayload
+	VoteEnd   chan string
+	Disconnect chan DisconnectPayload
+	Resume    chan ResumeRequest
+	ResetSession chan string
+	VoteKick  chan VoteKickPayload
+	Emote     chan EmotePayload
+	Admin     chan AdminCommand
+	Predict   chan PredictionPayload
+	Exclude   chan ExcludeTracksPayload
+	QualityChanged chan string
+	Chat      chan ChatPayload
+	VoidRound chan VoidRoundPayload
+	MiniGameAnswer chan MiniGameAnswerPayload
+	QuickRematch chan string
+	Rematch     chan string
+	ReplayTrack chan string
+	AddBot    chan AddBotPayload
+	SetHandicap chan SetHandicapPayload
+	SetPlaylist chan SetPlaylistPayload
+	Pause       chan PausePayload
+	Stop      chan string // shutdown reason
 	Broadcast chan Message
 
 	mu sync.RWMutex
@@ -49,21 +168,73 @@ func NewGameRoom(id string) *GameRoom {
 		Scores:       make(map[string]int),
 		Guesses:      make(map[string]Guess),
 		PlayedTracks: make(map[string]bool),
+		ScoreHistory: make(map[string][]int),
+		ActivePowerups: make(map[string]PowerupType),
+		EndVotes:     make(map[string]bool),
+		SessionScores: make(map[string]*SessionStanding),
+		KickVotes:    make(map[string]*KickVote),
+		Banned:       make(map[string]bool),
+		hiddenTracks: make(map[string]map[string]bool),
 		State:        StateWaiting,
+		Settings:     DefaultRoomSettings(),
+		AutoStartCountdown: DefaultAutoStartCountdown,
 		Join:         make(chan *Player, 10),
 		Leave:        make(chan string, 10),
 		Ready:        make(chan ReadyPayload, 10),
 		Guess:        make(chan Guess, 10),
 		StartGame:    make(chan StartGamePayload, 1),
+		Powerup:      make(chan UsePowerupPayload, 10),
+		SetPassword:  make(chan SetPasswordPayload, 10),
+		UpdateSettings: make(chan UpdateSettingsPayload, 10),
+		VoteEnd:      make(chan string, 10),
+		Disconnect:   make(chan DisconnectPayload, 10),
+		Resume:       make(chan ResumeRequest, 10),
+		ResetSession: make(chan string, 10),
+		VoteKick:     make(chan VoteKickPayload, 10),
+		Emote:        make(chan EmotePayload, 10),
+		Admin:        make(chan AdminCommand, 10),
+		Predict:      make(chan PredictionPayload, 10),
+		Exclude:      make(chan ExcludeTracksPayload, 10),
+		QualityChanged: make(chan string, 10),
+		Chat:         make(chan ChatPayload, 10),
+		VoidRound:    make(chan VoidRoundPayload, 10),
+		MiniGameAnswer: make(chan MiniGameAnswerPayload, 10),
+		QuickRematch: make(chan string, 10),
+		Rematch:      make(chan string, 10),
+		ReplayTrack:  make(chan string, 10),
+		rematchVotes: make(map[string]bool),
+		AddBot:       make(chan AddBotPayload, 10),
+		SetHandicap:  make(chan SetHandicapPayload, 10),
+		SetPlaylist:  make(chan SetPlaylistPayload, 10),
+		Pause:        make(chan PausePayload, 10),
+		roundResults: make(map[int]*RoundResult),
+		voidedRounds: make(map[int]bool),
+		tallies:      make(map[string]*guessTally),
+		taste:        make(map[tastePair][2]int),
+		outcomes:     make(map[string]*TrackOutcome),
+		Predictions:  make(map[string]string),
+		SpectatorScores: make(map[string]int),
+		observers:    make(map[*websocket.Conn]string),
+		done:         make(chan struct{}),
+		Stop:         make(chan string, 1),
 		Broadcast:    make(chan Message, 10),
+		pickIndex:    rand.Intn,
 	}
 }
 
 func (r *GameRoom) Run() {
+	watchdog := time.NewTicker(idleCheckInterval)
+
 	defer func() {
+		watchdog.Stop()
 		if r.RoundTimer != nil {
 			r.RoundTimer.Stop()
 		}
+		// A bad message must not take the whole server down; the
+		// manager restarts the loop
+		if p := recover(); p != nil {
+			log.Printf("Room %s: run loop panicked: %v", r.ID, p)
+		}
 		log.Printf("Room %s: Goroutine stopped", r.ID)
 	}()
 
@@ -84,8 +255,84 @@ func (r *GameRoom) Run() {
 		case guess := <-r.Guess:
 			r.handleGuess(guess)
 
+		case payload := <-r.Powerup:
+			r.handleUsePowerup(payload)
+
+		case payload := <-r.SetPassword:
+			r.handleSetPassword(payload)
+
+		case payload := <-r.UpdateSettings:
+			r.handleUpdateSettings(payload)
+
+		case playerID := <-r.VoteEnd:
+			r.handleVoteEnd(playerID)
+
+		case payload := <-r.Disconnect:
+			r.handleDisconnect(payload)
+
+		case req := <-r.Resume:
+			r.handleResume(req)
+
+		case playerID := <-r.ResetSession:
+			r.handleResetSession(playerID)
+
+		case payload := <-r.VoteKick:
+			r.handleVoteKick(payload)
+
+		case payload := <-r.Emote:
+			r.handleEmote(payload)
+
+		case cmd := <-r.Admin:
+			r.handleAdminCommand(cmd)
+
+		case payload := <-r.Predict:
+			r.handlePrediction(payload)
+
+		case payload := <-r.Exclude:
+			r.handleExcludeTracks(payload)
+
+		case playerID := <-r.QualityChanged:
+			r.handleQualityChange(playerID)
+
+		case payload := <-r.Chat:
+			r.handleChat(payload)
+
+		case payload := <-r.VoidRound:
+			r.handleVoidRound(payload)
+
+		case payload := <-r.MiniGameAnswer:
+			r.handleMiniGameAnswer(payload)
+
+		case playerID := <-r.QuickRematch:
+			r.handleQuickRematch(playerID)
+
+		case playerID := <-r.Rematch:
+			r.handleRematchVote(playerID)
+
+		case playerID := <-r.ReplayTrack:
+			r.handleReplayTrack(playerID)
+
+		case payload := <-r.AddBot:
+			r.handleAddBot(payload)
+
+		case payload := <-r.SetHandicap:
+			r.handleSetHandicap(payload)
+
+		case payload := <-r.SetPlaylist:
+			r.handleSetPlaylist(payload)
+
+		case payload := <-r.Pause:
+			r.handlePause(payload)
+
 		case msg := <-r.Broadcast:
 			r.broadcastToAll(msg)
+
+		case <-watchdog.C:
+			r.checkIdle()
+
+		case reason := <-r.Stop:
+			r.shutdown(reason)
+			return
 		}
 	}
 }
@@ -94,8 +341,49 @@ func (r *GameRoom) handlePlayerJoin(player *Player) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.Banned[player.ID] {
+		log.Printf("Player %s rejected from room %s: banned", player.Name, r.ID)
+		r.sendDirect(player, Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": "You were removed from this room",
+			},
+		})
+		return
+	}
+
+	// Check room password
+	if r.Password != "" && !player.Invited && player.RoomPassword != r.Password {
+		log.Printf("Player %s rejected from room %s: wrong password", player.Name, r.ID)
+		r.sendDirect(player, Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": "Incorrect room password",
+			},
+		})
+		return
+	}
+
+	if r.practice != nil && r.humanCount() > 0 {
+		r.sendDirect(player, Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": "Practice rooms are for one player",
+			},
+		})
+		return
+	}
+
+	// Bots give up their seats to people
+	for len(r.Players) >= MaxPlayersPerRoom && r.yieldBotSeat() {
+	}
+
 	// Check room capacity
 	if len(r.Players) >= MaxPlayersPerRoom {
+		if player.QueueIfFull {
+			r.enqueue(player)
+			return
+		}
 		log.Printf("Room %s is full (%d/%d players)", r.ID, len(r.Players), MaxPlayersPerRoom)
 		r.Broadcast <- Message{
 			Type: MsgTypeError,
@@ -106,43 +394,88 @@ func (r *GameRoom) handlePlayerJoin(player *Player) {
 		return
 	}
 
-	// Add player
-	player.IsReady = false
+	if !r.claimSeat(player) {
+		return
+	}
+	r.seatPlayer(player)
+	r.seatBots(player)
+	r.suggestCompanions(player)
+}
+
+// seatPlayer adds a player who passed the join checks to the room.
+// Callers must hold the room lock.
+func (r *GameRoom) seatPlayer(player *Player) {
+	r.trimToTrackPool(player)
+	r.restoreHiddenTracks(player)
+
+	// Add player; bots are always ready
+	player.IsReady = player.IsBot()
 	player.IsLeader = false
+
+	// Players joining mid-game watch until the room is back to waiting
+	player.IsSpectator = r.State != StateWaiting
 	
 	// Assign leader if room is empty
-	if len(r.Players) == 0 {
+	if len(r.Players) == 0 && !r.Hostless {
 		player.IsLeader = true
 		r.LeaderID = player.ID
 		log.Printf("Player %s assigned as leader of room %s", player.Name, r.ID)
 	}
 
 	r.Players[player.ID] = player
+
+	// The owner always leads their room when present
+	if player.ID == r.OwnerID && !r.Hostless {
+		r.transferLeadership(player)
+	}
 	r.PlayerOrder = append(r.PlayerOrder, player.ID)
 	r.Scores[player.ID] = 0
+	if r.Settings.Endless && r.State == StatePlaying {
+		r.Scores[player.ID] = r.checkpointScores[player.ID]
+	}
 
 	log.Printf("Player %s joined room %s", player.Name, r.ID)
+	r.recordEvent(EventPlayerJoined, player.ID, map[string]interface{}{
+		"name":         player.Name,
+		"is_spectator": player.IsSpectator,
+	})
+
+	r.issueResumeToken(player)
 
 	// Broadcast player joined
 	r.Broadcast <- Message{
 		Type: MsgTypePlayerJoined,
 		Payload: map[string]interface{}{
 			"player": PlayerInfo{
-				ID:       player.ID,
-				Name:     player.Name,
-				Score:    0,
-				IsLeader: player.IsLeader,
+				ID:          player.ID,
+				Name:        player.Name,
+				Score:       r.Scores[player.ID],
+				IsLeader:    player.IsLeader,
+				IsSpectator: player.IsSpectator,
 			},
 			"player_count": len(r.Players),
 			"players":      r.getPlayerInfoList(),
+			"settings":     r.Settings,
 		},
 	}
+
+	r.evaluateAutoStart()
 }
 
 func (r *GameRoom) handlePlayerLeave(playerID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, seated := r.Players[playerID]; !seated {
+		r.dequeue(playerID, nil)
+		return
+	}
+	r.removePlayer(playerID, "Player left")
+}
+
+// removePlayer drops a player from the room, closing their connection with
+// the given reason. Callers must hold the room lock.
+func (r *GameRoom) removePlayer(playerID, reason string) {
 	player, exists := r.Players[playerID]
 	if !exists {
 		return
@@ -150,12 +483,19 @@ func (r *GameRoom) handlePlayerLeave(playerID string) {
 
 	// Close WebSocket connection
 	if player.Connection != nil {
-		player.Connection.Close(1000, "Player left")
+		player.Connection.Close(1000, reason)
+	}
+	if player.resumeTimer != nil {
+		player.resumeTimer.Stop()
 	}
 
 	delete(r.Players, playerID)
 	delete(r.Scores, playerID)
+	r.presence.release(playerID, r.ID)
 	delete(r.Guesses, playerID)
+	delete(r.EndVotes, playerID)
+	delete(r.rematchVotes, playerID)
+	r.clearKickVotes(playerID)
 
 	// Remove from order
 	for i, id := range r.PlayerOrder {
@@ -178,6 +518,10 @@ func (r *GameRoom) handlePlayerLeave(playerID string) {
 	}
 
 	log.Printf("Player %s left room %s", player.Name, r.ID)
+	r.recordEvent(EventPlayerLeft, playerID, map[string]interface{}{
+		"name":   player.Name,
+		"reason": reason,
+	})
 
 	// Broadcast player left
 	r.Broadcast <- Message{
@@ -189,8 +533,21 @@ func (r *GameRoom) handlePlayerLeave(playerID string) {
 		},
 	}
 
+	// Bots don't play on their own
+	if !player.IsBot() {
+		r.dismissBots()
+	}
+
+	// An empty room ends the session
+	if len(r.Players) == 0 {
+		r.SessionScores = make(map[string]*SessionStanding)
+	}
+
 	// If room becomes empty during a game, reset to waiting state
 	if len(r.Players) == 0 && r.State != StateWaiting {
+		r.releaseGameSlot()
+		r.forgetState()
+		r.recorder = nil
 		r.State = StateWaiting
 		r.CurrentRound = 0
 		r.Scores = make(map[string]int)
@@ -198,6 +555,10 @@ func (r *GameRoom) handlePlayerLeave(playerID string) {
 			r.RoundTimer.Stop()
 		}
 	}
+
+	r.admitFromQueue()
+	r.evaluateAutoStart()
+	r.checkRematchVotes()
 }
 
 func (r *GameRoom) handlePlayerReady(payload ReadyPayload) {
@@ -214,10 +575,11 @@ func (r *GameRoom) handlePlayerReady(payload ReadyPayload) {
 		r.State = StateWaiting
 		r.CurrentRound = 0
 		r.Scores = make(map[string]int)
+		r.promoteSpectators()
 		for pid := range r.Players {
 			r.Scores[pid] = 0
 			if p, ok := r.Players[pid]; ok {
-				p.IsReady = false
+				p.IsReady = p.IsBot()
 			}
 		}
 
@@ -241,6 +603,8 @@ func (r *GameRoom) handlePlayerReady(payload ReadyPayload) {
 			"is_ready":  payload.IsReady,
 		},
 	}
+
+	r.evaluateAutoStart()
 }
 
 func (r *GameRoom) handleGameStart(payload StartGamePayload) {
@@ -252,6 +616,7 @@ func (r *GameRoom) handleGameStart(payload StartGamePayload) {
 		r.State = StateWaiting
 		r.CurrentRound = 0
 		r.Scores = make(map[string]int)
+		r.promoteSpectators()
 		for pid := range r.Players {
 			r.Scores[pid] = 0
 		}
@@ -260,6 +625,22 @@ func (r *GameRoom) handleGameStart(payload StartGamePayload) {
 	if r.State != StateWaiting {
 		return
 	}
+
+	if r.Hostless {
+		r.sendError(payload.PlayerID, "Games start automatically in this room")
+		return
+	}
+
+	if payload.RoundDuration != 0 && (payload.RoundDuration < MinRoundDuration || payload.RoundDuration > MaxRoundDuration) {
+		r.sendError(payload.PlayerID, fmt.Sprintf("Round duration must be between %d and %d seconds", MinRoundDuration, MaxRoundDuration))
+		return
+	}
+
+	// If not everyone is ready yet, the countdown starts the game with these
+	if payload.PlayerID == r.LeaderID {
+		pending := payload
+		r.pendingGame = &pending
+	}
 	
 	if len(r.Players) < 2 {
 		r.Broadcast <- Message{
@@ -284,29 +665,112 @@ func (r *GameRoom) handleGameStart(payload StartGamePayload) {
 		}
 	}
 
+	r.beginGame(payload)
+}
+
+// beginGame moves the room into StatePlaying and schedules the first round.
+// Callers must hold the room lock and have validated the start conditions.
+func (r *GameRoom) beginGame(payload StartGamePayload) {
+	if !r.Flags.Enabled(FeatureNewGames) {
+		log.Printf("Room %s could not start: %v", r.ID, ErrNewGamesDisabled)
+		r.Broadcast <- Message{
+			Type: MsgTypeError,
+			Payload: map[string]interface{}{
+				"message": ErrNewGamesDisabled.Error(),
+			},
+		}
+		return
+	}
+	if r.Limits != nil && !r.holdsGameSlot {
+		if !r.Limits.AcquireGame() {
+			log.Printf("Room %s could not start: %v", r.ID, ErrGameLimit)
+			r.Broadcast <- Message{
+				Type: MsgTypeError,
+				Payload: map[string]interface{}{
+					"message": ErrGameLimit.Error(),
+				},
+			}
+			return
+		}
+		r.holdsGameSlot = true
+	}
+
+	r.countdownActive = false
+	r.cancelRematch()
+	r.roundSeconds = r.Settings.RoundDuration
+	if payload.RoundDuration > 0 {
+		r.roundSeconds = payload.RoundDuration
+	}
+	r.rememberGame(payload)
+	r.pendingGame = nil
 	r.TotalRounds = payload.TotalRounds
 	if r.TotalRounds <= 0 {
-		r.TotalRounds = 10 // Default
+		r.TotalRounds = r.Settings.TotalRounds
+	}
+	if r.Settings.Endless {
+		r.TotalRounds = 0
 	}
 	
 	r.CurrentRound = 0
 	r.State = StatePlaying
+	r.GameID = uuid.New().String()
+	r.revealing = false
+	r.touchActivity()
 	r.PlayedTracks = make(map[string]bool) // Reset played tracks
+	maps.Copy(r.PlayedTracks, r.rematchPlayed)
+	r.rematchVotes = make(map[string]bool)
+	r.ScoreHistory = make(map[string][]int)
+	r.PowerupsEnabled = payload.Powerups
+	r.resetPowerups()
+	r.EndVotes = make(map[string]bool)
+	r.SpectatorScores = make(map[string]int)
+	r.roundResults = make(map[int]*RoundResult)
+	r.voidedRounds = make(map[int]bool)
+	r.roundSummaries = nil
+	r.tallies = make(map[string]*guessTally)
+	r.taste = make(map[tastePair][2]int)
+	r.outcomes = make(map[string]*TrackOutcome)
+	r.overtime = nil
+	r.clearPause()
+	r.roundEra, r.lastEra = 0, 0
+	r.cancelMiniGame()
+	r.checkpointScores = nil
+	if r.Settings.Endless {
+		r.resumeEndlessCheckpoint()
+	}
+	r.applyTrackFilter()
+	r.planRounds()
+	r.prepareBonusRound()
+
+	log.Printf("Game %s started in room %s with %d rounds", 
+		r.GameID, r.ID, r.TotalRounds)
+	r.recordEvent(EventGameStarted, "", map[string]interface{}{
+		"total_rounds": r.TotalRounds,
+		"endless":      r.Settings.Endless,
+	})
 
-	log.Printf("Game started in room %s with %d rounds", 
-		r.ID, payload.TotalRounds)
+	r.announceRivalries()
+	r.mirrorState()
+	r.startRecording()
 
 	r.Broadcast <- Message{
 		Type: MsgTypeGameStarted,
 		Payload: map[string]interface{}{
-			"total_rounds": payload.TotalRounds,
-			"players":      r.getPlayerInfoList(),
+			"total_rounds":    r.TotalRounds,
+			"round_duration":  r.roundSeconds,
+			"scoring_mode":    r.Settings.ScoringMode,
+			"scoring_weights": r.Settings.scoringWeights(),
+			"powerups":        r.PowerupsEnabled,
+			"settings":        r.Settings,
+			"players":         r.getPlayerInfoList(),
 		},
 	}
+	r.tutorialGameStarted()
 
-	// Start first round after 5 seconds (intermission)
+	// Start first round after the intermission
+	intermission := r.Settings.intermission()
 	go func() {
-		time.Sleep(5 * time.Second)
+		time.Sleep(intermission)
 		r.startNextRound()
 	}()
 }
@@ -315,12 +779,57 @@ func (r *GameRoom) startNextRound() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.State != StatePlaying {
+		return
+	}
+	// A paused game starts the round when it resumes
+	if r.paused != nil {
+		r.paused.nextRound = true
+		return
+	}
+
+	// Endless games never return to waiting, so spectators join at round boundaries
+	if r.Settings.Endless {
+		r.promoteSpectators()
+	}
+
+	// A mini-game still open is settled before the next round
+	r.finishMiniGame()
+
 	r.CurrentRound++
 	r.RoundStartTime = time.Now()
+	r.revealing = false
+	r.touchActivity()
 	r.Guesses = make(map[string]Guess)
-
-	// Select track
-	track := r.selectTrack()
+	r.Predictions = make(map[string]string)
+
+	// Select track, playing the prepared artist track on bonus rounds and
+	// one of the subject's favourites on reverse rounds. Overtime rounds are
+	// always regular ones, as are playlist games' rounds. Era rounds pick
+	// their own track from the round's decade.
+	var track *auth.Track
+	r.activeBonus = nil
+	r.activeReverse = nil
+	r.roundEra = 0
+	r.roundExtension = 0
+	r.trackReplayed = false
+	if r.overtime != nil || r.playlist != nil {
+		track = r.nextTrack()
+	} else if r.isBonusRound() && r.bonus != nil {
+		r.activeBonus = r.bonus
+		track = &r.activeBonus.Track
+	} else if r.activeReverse = r.pickReverseRound(); r.activeReverse != nil {
+		track = &r.activeReverse.Answer
+	} else if r.Settings.EraRounds {
+		track = r.selectEraTrack()
+	} else {
+		track = r.nextTrack()
+	}
+	if track == nil && r.Settings.Endless && len(r.PlayedTracks) > 0 {
+		// Endless games recycle the pool once every track has been played
+		r.PlayedTracks = make(map[string]bool)
+		track = r.selectTrack()
+	}
 	if track == nil {
 		r.Broadcast <- Message{
 			Type: MsgTypeError,
@@ -333,44 +842,114 @@ func (r *GameRoom) startNextRound() {
 
 	r.CurrentTrack = track
 	r.PlayedTracks[track.ID] = true
+	if r.activeBonus != nil {
+		r.prepareBonusRound()
+	}
 
 	log.Printf("Round %d/%d started in room %s - Track: %s", r.CurrentRound, r.TotalRounds, r.ID, track.Name)
 
-	broadcastTrack := *track
-	broadcastTrack.Name = "???"
-	broadcastTrack.Artists = []string{"???"}
-	broadcastTrack.ImageURL = "" // Hide album art
-	// Keep PreviewURL and ID
+	r.startTitleReveal()
+	broadcastTrack := maskedTrack(*track)
+
+	payload := map[string]interface{}{
+		"round":        r.CurrentRound,
+		"total_rounds": r.TotalRounds,
+		"track":        broadcastTrack,
+		"audio_path":   "/audio/" + track.ID,
+		"players":      r.getPlayerInfoList(),
+	}
+	// Clients run their timer from this; speed rounds shrink it every round
+	payload["round_duration"] = int(r.currentRoundDuration().Seconds())
+	payload["guess_mode"] = r.guessMode()
+	if mask := r.titleMask(); mask != "" {
+		payload["title_mask"] = mask
+	}
+	if r.activeBonus != nil {
+		// Bonus rounds always ask who knows the artist best
+		payload["guess_mode"] = GuessPlayer
+		payload["bonus"] = true
+		payload["artist"] = r.activeBonus.ArtistName
+		payload["prompt"] = BonusRoundPrompt
+	}
+	if r.roundEra != 0 {
+		payload["era"] = eraLabel(r.roundEra)
+	}
+	if r.overtime != nil {
+		payload["overtime"] = true
+		payload["contenders"] = r.overtime.order
+	}
+	if r.activeReverse != nil {
+		// The candidates are shown instead; the audio would give the answer away
+		delete(payload, "track")
+		delete(payload, "audio_path")
+		payload["guess_mode"] = GuessTrack
+		payload["reverse"] = r.activeReverse.info()
+	}
 
 	r.Broadcast <- Message{
-		Type: MsgTypeRoundStarted,
-		Payload: map[string]interface{}{
-			"round":        r.CurrentRound,
-			"total_rounds": r.TotalRounds,
-			"track":        broadcastTrack,
-			"players":      r.getPlayerInfoList(),
-		},
+		Type:    MsgTypeRoundStarted,
+		Payload: payload,
 	}
+	r.tutorialRoundStep(false)
 
-	// Set timer for 30 seconds
+	// Set timer for this round's duration
 	if r.RoundTimer != nil {
 		r.RoundTimer.Stop()
 	}
-	r.RoundTimer = time.AfterFunc(30*time.Second, func() {
-		r.endRound()
+	r.RoundTimer = time.AfterFunc(r.currentRoundDuration(), func() {
+		r.roundTimeUp()
 	})
+	r.scheduleHints()
+	r.scheduleBotGuesses()
 }
 
 func (r *GameRoom) handleGuess(guess Guess) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.acceptGuess(guess)
+}
+
+// acceptGuess validates and records a guess for the current round, ending
+// it once everyone has guessed. Callers must hold the room lock.
+func (r *GameRoom) acceptGuess(guess Guess) {
 	if r.State != StatePlaying {
 		return
 	}
 
+	if player, exists := r.Players[guess.PlayerID]; !exists || player.IsSpectator {
+		return
+	}
+	if r.benched(guess.PlayerID) {
+		r.sendError(guess.PlayerID, "Only the players tied for the lead play overtime")
+		return
+	}
+	if r.paused != nil {
+		r.sendError(guess.PlayerID, "The game is paused")
+		return
+	}
+
+	if r.activeReverse != nil {
+		if guess.PlayerID == r.activeReverse.SubjectID {
+			r.sendError(guess.PlayerID, "These are your tracks, sit this one out")
+			return
+		}
+		if !r.activeReverse.isCandidate(guess.GuessedTrackID) {
+			r.sendError(guess.PlayerID, "Pick one of the tracks to guess")
+			return
+		}
+	} else if r.guessMode() == GuessTitle && r.activeBonus == nil {
+		guess.GuessedTitle = strings.TrimSpace(guess.GuessedTitle)
+		if guess.GuessedTitle == "" || len(guess.GuessedTitle) > MaxTitleGuessLength {
+			r.sendError(guess.PlayerID, "Type the song title to guess")
+			return
+		}
+	}
+
 	// Store guess
 	r.Guesses[guess.PlayerID] = guess
+	r.touchActivity()
+	r.recordGuess(guess)
 
 	log.Printf("Player %s guessed %s in room %s", guess.PlayerID, guess.GuessedPlayerID, r.ID)
 
@@ -380,12 +959,12 @@ func (r *GameRoom) handleGuess(guess Guess) {
 		Payload: map[string]interface{}{
 			"player_id":     guess.PlayerID,
 			"guesses_count": len(r.Guesses),
-			"total_players": len(r.Players),
+			"total_players": r.guessersNeeded(),
 		},
 	}
 
 	// End round early if all players guessed
-	if len(r.Guesses) == len(r.Players) {
+	if len(r.Guesses) >= r.guessersNeeded() {
 		if r.RoundTimer != nil {
 			r.RoundTimer.Stop()
 		}
@@ -400,61 +979,178 @@ func (r *GameRoom) endRound() {
 	if r.State != StatePlaying {
 		return
 	}
-
-	result := r.calculateRoundResults()
+	r.stopHints()
+	r.stopTitleReveal()
+
+	var result *RoundResult
+	switch {
+	case r.activeBonus != nil:
+		result = r.calculateBonusResults()
+	case r.activeReverse != nil:
+		result = r.calculateReverseResults()
+	default:
+		result = r.calculateRoundResults()
+	}
+	r.attributeSources(result)
+	r.guessDistribution(result)
+	r.rerollUnguessed(result)
+	r.recordScoreHistory()
+	r.recordRoundResult(result)
+	r.storeRound(result, false)
+	r.summarizeRound(result)
+	for playerID := range r.Guesses {
+		result.guessers = append(result.guessers, playerID)
+	}
+	r.tallyGuesses(result, 1)
+	r.tallyTaste(result, 1)
+	r.tallyOutcome(result, 1)
+	r.scorePredictions(result)
+	r.revealing = true
+	if r.Settings.Endless {
+		result.RollingScores = r.rollingScores()
+	}
+	r.mirrorState()
 
 	log.Printf("Round %d complete in room %s - Winner: %s", r.CurrentRound, r.ID, result.WinnerID)
+	r.recordEvent(EventRoundComplete, result.WinnerID, map[string]interface{}{
+		"round":            result.Round,
+		"track_id":         result.Track.ID,
+		"correct_guessers": result.CorrectGuessers,
+		"points_awarded":   result.PointsAwarded,
+	})
+
+	if r.Settings.SuspenseDelay > 0 {
+		r.holdReveal(result)
+		return
+	}
 
 	r.Broadcast <- Message{
 		Type:    MsgTypeRoundComplete,
 		Payload: result,
 	}
+	r.tutorialRoundStep(true)
+	r.afterReveal()
+}
+
+// afterReveal schedules what follows a round's results: the next round, or
+// the game over screen after the last one. Callers must hold the room lock.
+func (r *GameRoom) afterReveal() {
+	intermission := r.Settings.intermission()
+
+	if r.Settings.Endless {
+		r.checkpointEndless()
+	}
 
-	// Check if game is over
-	if r.CurrentRound >= r.TotalRounds {
-		// Wait 5 seconds before showing game over screen
+	// Check if game is over, unless a tied lead sends it to overtime
+	if !r.Settings.Endless && r.CurrentRound >= r.TotalRounds && !r.extendOvertime() {
+		// Wait for the intermission before showing game over screen
 		go func() {
-			time.Sleep(5 * time.Second)
+			time.Sleep(intermission)
 			r.mu.Lock()
-			r.State = StateGameOver
-			
-			winnerID := r.getWinnerID()
-			log.Printf("Game over in room %s - Winner: %s", r.ID, winnerID)
-
-			r.Broadcast <- Message{
-				Type: MsgTypeGameOver,
-				Payload: map[string]interface{}{
-					"winner_id":    winnerID,
-					"final_scores": r.Scores,
-					"players":      r.getPlayerInfoList(),
-				},
+			defer r.mu.Unlock()
+			if r.State == StatePlaying {
+				r.finishGame()
 			}
-			r.mu.Unlock()
 		}()
 	} else {
-		// Start next round after 5 seconds
+		r.broadcastIntermissionContent()
+		r.startMiniGame()
+
+		// Start next round after the intermission
 		go func() {
-			time.Sleep(5 * time.Second)
+			time.Sleep(intermission)
 			r.startNextRound()
 		}()
 	}
 }
 
+// finishGame moves the room to StateGameOver and broadcasts the final results.
+// Callers must hold the room lock.
+func (r *GameRoom) finishGame() {
+	r.State = StateGameOver
+	r.clearPause()
+	r.releaseGameSlot()
+	r.forgetState()
+	if r.RoundTimer != nil {
+		r.RoundTimer.Stop()
+	}
+	if r.Settings.Endless {
+		r.clearEndlessCheckpoint()
+	}
+
+	winnerID := r.getWinnerID()
+	log.Printf("Game over in room %s - Winner: %s", r.ID, winnerID)
+	r.recordEvent(EventGameOver, winnerID, map[string]interface{}{
+		"final_scores": maps.Clone(r.Scores),
+	})
+
+	r.recordSessionScores(winnerID)
+	r.recordGame(winnerID)
+	r.notifyWebhook(winnerID)
+
+	payload := map[string]interface{}{
+		"winner_id":         winnerID,
+		"final_scores":      r.Scores,
+		"standings":         r.standings(),
+		"taste_matrix":      r.tasteMatrix(),
+		"score_history":     r.ScoreHistory,
+		"session_scores":    r.SessionScores,
+		"session_leader_id": r.sessionLeaderID(),
+		"summary":           r.gameSummary(),
+		"players":           r.getPlayerInfoList(),
+	}
+	// With history on, everyone sees how the game moved their lifetime stats
+	if stats := r.lifetimeStats(); stats != nil {
+		payload["lifetime_stats"] = stats
+	}
+	r.Broadcast <- Message{
+		Type:    MsgTypeGameOver,
+		Payload: payload,
+	}
+	r.finishTutorial()
+	r.rotateLeader()
+
+	if r.Hostless {
+		time.AfterFunc(HostlessResultsDuration, r.startNextHostlessGame)
+	}
+}
+
 func (r *GameRoom) selectTrack() *auth.Track {
+	if r.playlist != nil {
+		return r.selectPlaylistTrack()
+	}
+
 	// Build map of all tracks
 	trackCounts := make(map[string]int)
 	trackMap := make(map[string]*auth.Track)
+	copyOwners := make(map[string]string) // whose copy of each track trackMap holds
+	sourceWeights := make(map[string]int)
+	excluded := r.excludedTracks()
 
 	for _, player := range r.Players {
+		if player.IsSpectator || r.benched(player.ID) {
+			continue
+		}
 		for _, track := range player.TopTracks {
-			// Skip if already played
-			if r.PlayedTracks[track.ID] {
+			// Skip if already played, hidden or filtered out
+			if r.PlayedTracks[track.ID] || excluded[track.ID] || !r.trackAllowed(track) || !matchesDecade(track, r.eraFilter) {
 				continue
 			}
+			// Skip tracks from sources the room no longer draws on
+			weight := r.Settings.sourceWeight(track)
+			if weight == 0 {
+				continue
+			}
+			sourceWeights[track.ID] = max(sourceWeights[track.ID], weight)
 			trackCounts[track.ID]++
-			if _, exists := trackMap[track.ID]; !exists {
+			// Players' copies of a shared track can differ in rank, sources
+			// and even name, so keep the best-ranked one, then the lowest
+			// player ID, whatever order the players are visited in
+			kept, exists := trackMap[track.ID]
+			if !exists || track.Rank < kept.Rank || (track.Rank == kept.Rank && player.ID < copyOwners[track.ID]) {
 				t := track
 				trackMap[track.ID] = &t
+				copyOwners[track.ID] = player.ID
 			}
 		}
 	}
@@ -462,14 +1158,24 @@ func (r *GameRoom) selectTrack() *auth.Track {
 	// Weighted selection: tracks appearing for multiple users get higher weight
 	// Create a pool where tracks are added 'count' times (or count^2 for more weight)
 	weightedPool := make([]string, 0)
-	
-	for trackID, count := range trackCounts {
+
+	// Build the pool in a stable order so a seeded pick is reproducible
+	trackIDs := make([]string, 0, len(trackCounts))
+	for trackID := range trackCounts {
+		trackIDs = append(trackIDs, trackID)
+	}
+	sort.Strings(trackIDs)
+
+	for _, trackID := range trackIDs {
+		count := trackCounts[trackID]
 		// Base weight is 1
 		weight := 1
 		// If track appears for multiple users, increase weight significantly
 		if count > 1 {
 			weight = count * 5 // Give 5x weight per occurrence if shared
 		}
+		// Scaled by the most favoured source the track came from
+		weight *= sourceWeights[trackID]
 		
 		for i := 0; i < weight; i++ {
 			weightedPool = append(weightedPool, trackID)
@@ -481,14 +1187,19 @@ func (r *GameRoom) selectTrack() *auth.Track {
 	}
 
 	// Select random track from weighted pool
-	selectedID := weightedPool[rand.Intn(len(weightedPool))]
+	selectedID := weightedPool[r.pickIndex(len(weightedPool))]
 	return trackMap[selectedID]
 }
 
-func (r *GameRoom) calculateRoundResults() *RoundResult {
-	// Find all rankings
+// trackRankings finds where the current track sits in each player's top
+// tracks (999 when it isn't there) and the round's winner, the player with
+// the best rank. Callers must hold the room lock.
+func (r *GameRoom) trackRankings() (map[string]int, string, int) {
 	allRankings := make(map[string]int)
 	for playerID, player := range r.Players {
+		if player.IsSpectator || r.benched(playerID) {
+			continue
+		}
 		rank := 999 // Default rank if track not found
 		for _, track := range player.TopTracks {
 			if track.ID == r.CurrentTrack.ID {
@@ -503,16 +1214,22 @@ func (r *GameRoom) calculateRoundResults() *RoundResult {
 	winnerID := ""
 	bestRank := 999
 	for playerID, rank := range allRankings {
-		if rank < bestRank {
+		if rank < bestRank || (rank == bestRank && rank < 999 && playerID < winnerID) {
 			bestRank = rank
 			winnerID = playerID
 		}
 	}
+	return allRankings, winnerID, bestRank
+}
+
+func (r *GameRoom) calculateRoundResults() *RoundResult {
+	allRankings, winnerID, bestRank := r.trackRankings()
+	validAnswers := r.validAnswers(allRankings, bestRank, winnerID)
 
 	// Find correct guessers
 	correctGuessers := make([]string, 0)
 	for playerID, guess := range r.Guesses {
-		if guess.GuessedPlayerID == winnerID {
+		if r.guessCorrect(guess, validAnswers) {
 			correctGuessers = append(correctGuessers, playerID)
 		}
 	}
@@ -527,24 +1244,35 @@ func (r *GameRoom) calculateRoundResults() *RoundResult {
 	// Award points and calculate durations
 	pointsAwarded := make(map[string]int)
 	guessDurations := make(map[string]float64)
+	score := r.scoring()
+	roundLength := r.currentRoundDuration()
 	
 	for idx, playerID := range correctGuessers {
-		basePoints := 10
-		speedBonus := 0
-		if idx == 0 {
-			speedBonus = 5
+		elapsed := r.Guesses[playerID].Timestamp.Sub(r.RoundStartTime)
+
+		total := score(CorrectGuess{
+			Order:       idx,
+			Elapsed:     elapsed,
+			RoundLength: roundLength,
+		})
+		if r.ActivePowerups[playerID] == PowerupBooster {
+			total *= 2
 		}
-
-		total := basePoints + speedBonus
+		total = r.handicapped(playerID, total)
 		pointsAwarded[playerID] = total
 		r.Scores[playerID] += total
 		
 		// Calculate duration
-		duration := r.Guesses[playerID].Timestamp.Sub(r.RoundStartTime).Seconds()
-		guessDurations[playerID] = duration
+		guessDurations[playerID] = elapsed.Seconds()
+	}
+
+	var powerupsUsed map[string]PowerupType
+	var streaks map[string]int
+	if r.PowerupsEnabled {
+		powerupsUsed, streaks = r.applyStreaks(correctGuessers)
 	}
 
-	return &RoundResult{
+	result := &RoundResult{
 		Round:           r.CurrentRound,
 		Track:           *r.CurrentTrack,
 		WinnerID:        winnerID,
@@ -554,19 +1282,37 @@ func (r *GameRoom) calculateRoundResults() *RoundResult {
 		AllRankings:     allRankings,
 		UpdatedScores:   r.Scores,
 		GuessDurations:  guessDurations,
+		PowerupsUsed:    powerupsUsed,
+		Streaks:         streaks,
+	}
+	if r.Settings.ShareTies {
+		result.ValidAnswers = validAnswers
 	}
+	return result
 }
 
-func (r *GameRoom) getWinnerID() string {
-	maxScore := -1
-	winnerID := ""
-	for playerID, score := range r.Scores {
-		if score > maxScore {
-			maxScore = score
-			winnerID = playerID
+// recordScoreHistory appends every player's cumulative score after the current
+// round. Players who joined late are padded with zeros so all series line up
+// with the round number.
+func (r *GameRoom) recordScoreHistory() {
+	for playerID, player := range r.Players {
+		if player.IsSpectator {
+			continue
 		}
+		history := r.ScoreHistory[playerID]
+		for len(history) < r.CurrentRound-1 {
+			history = append(history, 0)
+		}
+		r.ScoreHistory[playerID] = append(history, r.Scores[playerID])
+	}
+}
+
+// getWinnerID returns the player at the top of the standings
+func (r *GameRoom) getWinnerID() string {
+	if standings := r.standings(); len(standings) > 0 {
+		return standings[0].PlayerID
 	}
-	return winnerID
+	return ""
 }
 
 func (r *GameRoom) getPlayerInfoList() []PlayerInfo {
@@ -574,28 +1320,131 @@ func (r *GameRoom) getPlayerInfoList() []PlayerInfo {
 	for _, id := range r.PlayerOrder {
 		if player, exists := r.Players[id]; exists {
 			players = append(players, PlayerInfo{
-				ID:       player.ID,
-				Name:     player.Name,
-				Score:    r.Scores[player.ID],
-				IsReady:  player.IsReady,
-				IsLeader: player.IsLeader,
+				ID:           player.ID,
+				Name:         player.Name,
+				Score:        r.Scores[player.ID],
+				IsReady:      player.IsReady,
+				IsLeader:     player.IsLeader,
+				Streak:       player.Streak,
+				Powerups:     player.Powerups,
+				Disconnected: player.Disconnected,
+				IsSpectator:  player.IsSpectator,
+				Quality:      player.ConnectionQuality(),
+				IsBot:        player.IsBot(),
+				Handicap:     player.Handicap,
 			})
 		}
 	}
 	return players
 }
 
+// currentGameID is the ID of the game being played or just finished, or ""
+// in the lobby. Callers must hold the room lock.
+func (r *GameRoom) currentGameID() string {
+	if r.State == StateWaiting {
+		return ""
+	}
+	return r.GameID
+}
+
+// stampGame tags a message with the current game's ID.
+// Callers must hold the room lock.
+func (r *GameRoom) stampGame(msg Message) Message {
+	if msg.GameID == "" {
+		msg.GameID = r.currentGameID()
+	}
+	return msg
+}
+
 func (r *GameRoom) broadcastToAll(msg Message) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	msg = r.stampGame(msg)
+	r.recordMessage(msg)
 	for _, player := range r.Players {
-		if player.Connection != nil {
-			ctx := context.Background()
-			err := wsjson.Write(ctx, player.Connection, msg)
-			if err != nil {
-				log.Printf("Error broadcasting to player %s: %v", player.ID, err)
-			}
+		if err := r.deliver(player, msg); err != nil {
+			log.Printf("Error broadcasting to player %s: %v", player.ID, err)
 		}
 	}
-}
\ No newline at end of file
+	r.broadcastToObservers(msg)
+}
+
+// Info returns a snapshot of the room for the lobby browser
+func (r *GameRoom) Info() RoomInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	leaderName := ""
+	if leader, ok := r.Players[r.LeaderID]; ok {
+		leaderName = leader.Name
+	}
+
+	return RoomInfo{
+		ID:           r.ID,
+		Name:         r.Name,
+		Description:  r.Description,
+		PlayerCount:  len(r.Players),
+		MaxPlayers:   MaxPlayersPerRoom,
+		State:        r.State,
+		CurrentRound: r.CurrentRound,
+		TotalRounds:  r.TotalRounds,
+		LeaderName:   leaderName,
+		HasPassword:  r.Password != "",
+		Region:       r.Region,
+		CreatedIn:    r.CreatedRegion,
+		OwnerName:    r.OwnerName,
+		Queued:       len(r.queue),
+		Hostless:     r.Hostless,
+		Practice:     r.practice != nil,
+		Tutorial:     r.tutorial != nil,
+		Instance:     r.Instance,
+		Endpoint:     r.Endpoint,
+		GameID:       r.currentGameID(),
+	}
+}
+
+func (r *GameRoom) handleSetPassword(payload SetPasswordPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can change the room password")
+		return
+	}
+
+	r.Password = payload.Password
+	log.Printf("Room %s password updated (protected: %v)", r.ID, r.Password != "")
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"has_password": r.Password != "",
+		},
+	}
+}
+
+// sendToPlayer writes a message to a single player's connection
+func (r *GameRoom) sendToPlayer(playerID string, msg Message) {
+	if player, exists := r.Players[playerID]; exists {
+		r.sendDirect(player, msg)
+	}
+}
+
+// sendDirect writes a message to a player who may not be part of the room,
+// e.g. when rejecting a join
+func (r *GameRoom) sendDirect(player *Player, msg Message) {
+	if err := r.deliver(player, msg); err != nil {
+		log.Printf("Error sending to player %s: %v", player.ID, err)
+	}
+}
+
+// sendError sends an error message to a single player
+func (r *GameRoom) sendError(playerID, message string) {
+	r.sendToPlayer(playerID, Message{
+		Type: MsgTypeError,
+		Payload: map[string]interface{}{
+			"message": message,
+		},
+	})
+}
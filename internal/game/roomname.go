@@ -0,0 +1,90 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Bounds for creator-provided room labels
+const (
+	MaxRoomNameLength        = 40
+	MaxRoomDescriptionLength = 200
+)
+
+// regionPattern matches region labels such as "us-east" or "eu1"
+var regionPattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// ErrInvalidRoomOptions wraps every room option validation failure
+var ErrInvalidRoomOptions = errors.New("invalid room options")
+
+// blockedWords are rejected in room names and descriptions so the lobby list
+// stays usable. Matching is per word after undoing common character swaps.
+var blockedWords = map[string]bool{
+	"fuck": true, "fucker": true, "fucking": true, "motherfucker": true,
+	"shit": true, "bullshit": true, "cunt": true, "bitch": true,
+	"asshole": true, "dick": true, "cock": true, "pussy": true,
+	"slut": true, "whore": true, "bastard": true, "wank": true,
+	"nigger": true, "nigga": true, "faggot": true, "fag": true,
+	"retard": true, "tranny": true, "spic": true, "kike": true,
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i",
+)
+
+// containsProfanity reports whether any word in text is on the block list
+func containsProfanity(text string) bool {
+	normalized := leetReplacer.Replace(strings.ToLower(text))
+	words := strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, word := range words {
+		if blockedWords[word] || blockedWords[strings.TrimSuffix(word, "s")] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoomLabel trims a name or description and checks its length,
+// characters and language
+func validateRoomLabel(field, value string, maxLength int) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if utf8.RuneCountInString(value) > maxLength {
+		return "", fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidRoomOptions, field, maxLength)
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("%w: %s contains invalid characters", ErrInvalidRoomOptions, field)
+		}
+	}
+	if containsProfanity(value) {
+		return "", fmt.Errorf("%w: %s contains blocked language", ErrInvalidRoomOptions, field)
+	}
+
+	return value, nil
+}
+
+// Validate normalizes the creator-provided name, description, region and
+// practice options
+func (o *RoomOptions) Validate() error {
+	var err error
+	if o.Name, err = validateRoomLabel("name", o.Name, MaxRoomNameLength); err != nil {
+		return err
+	}
+	if o.Description, err = validateRoomLabel("description", o.Description, MaxRoomDescriptionLength); err != nil {
+		return err
+	}
+
+	o.Region = strings.ToLower(strings.TrimSpace(o.Region))
+	if o.Region != "" && !regionPattern.MatchString(o.Region) {
+		return fmt.Errorf("%w: region must be a short label like \"us-east\"", ErrInvalidRoomOptions)
+	}
+	return o.validatePractice()
+}
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"log"
+	"maps"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+const (
+	// RoomStateTTL is how long a mirrored room's state outlives its last
+	// update, so a game abandoned without a clean reset isn't restored
+	RoomStateTTL = 10 * time.Minute
+	// RestoreDelay is how long a restored game waits for its players to
+	// reconnect before replaying the round that was interrupted
+	RestoreDelay = 20 * time.Second
+)
+
+// RoomState is the part of a running game that's mirrored outside the
+// process, so the game can be restored after a restart or crash. It's taken
+// between rounds: a round in progress is replayed from the start.
+type RoomState struct {
+	RoomID       string
+	GameID       string
+	Settings     RoomSettings
+	TotalRounds  int
+	CurrentRound int // rounds completed
+	RoundSeconds int
+	Powerups     bool
+	LeaderID     string
+	Scores       map[string]int
+	ScoreHistory map[string][]int
+	PlayedTracks []string
+	Players      []RoomStatePlayer // in seat order
+	SavedAt      time.Time
+}
+
+// RoomStatePlayer is a seated player in a mirrored room. Their Spotify
+// access token isn't kept; their tracks were fetched when they joined.
+type RoomStatePlayer struct {
+	ID             string
+	Name           string
+	SpotifyID      string
+	TopTracks      []auth.Track
+	ResumeToken    string
+	IsSpectator    bool
+	Streak         int
+	Powerups       map[PowerupType]int
+	Handicap       float64
+	ExcludedTracks []string
+}
+
+// RoomStateSink mirrors running games somewhere that outlives the process.
+// Both methods are called with the room lock held and must not block.
+type RoomStateSink interface {
+	SaveRoomState(state RoomState)
+	DeleteRoomState(roomID string)
+}
+
+// mirrorState hands the running game's state to the sink. Practice and
+// tutorial games aren't mirrored, nor are bots. Callers must hold the room
+// lock.
+func (r *GameRoom) mirrorState() {
+	if r.stateSink == nil || r.State != StatePlaying || r.practice != nil || r.tutorial != nil {
+		return
+	}
+
+	state := RoomState{
+		RoomID:       r.ID,
+		GameID:       r.GameID,
+		Settings:     r.Settings,
+		TotalRounds:  r.TotalRounds,
+		CurrentRound: r.CurrentRound,
+		RoundSeconds: r.roundSeconds,
+		Powerups:     r.PowerupsEnabled,
+		LeaderID:     r.LeaderID,
+		Scores:       maps.Clone(r.Scores),
+		ScoreHistory: make(map[string][]int, len(r.ScoreHistory)),
+		PlayedTracks: make([]string, 0, len(r.PlayedTracks)),
+		SavedAt:      time.Now(),
+	}
+	for playerID, history := range r.ScoreHistory {
+		state.ScoreHistory[playerID] = append([]int(nil), history...)
+	}
+	for trackID := range r.PlayedTracks {
+		state.PlayedTracks = append(state.PlayedTracks, trackID)
+	}
+	for _, playerID := range r.PlayerOrder {
+		player, ok := r.Players[playerID]
+		if !ok || player.IsBot() {
+			continue
+		}
+		saved := RoomStatePlayer{
+			ID:          player.ID,
+			Name:        player.Name,
+			SpotifyID:   player.SpotifyID,
+			TopTracks:   player.TopTracks,
+			ResumeToken: player.ResumeToken,
+			IsSpectator: player.IsSpectator,
+			Streak:      player.Streak,
+			Powerups:    maps.Clone(player.Powerups),
+			Handicap:    player.Handicap,
+		}
+		for trackID := range player.ExcludedTracks {
+			saved.ExcludedTracks = append(saved.ExcludedTracks, trackID)
+		}
+		state.Players = append(state.Players, saved)
+	}
+	r.stateSink.SaveRoomState(state)
+}
+
+// forgetState drops the room's mirrored state once its game is over or
+// abandoned. Callers must hold the room lock.
+func (r *GameRoom) forgetState() {
+	if r.stateSink != nil {
+		r.stateSink.DeleteRoomState(r.ID)
+	}
+}
+
+// restoreState brings back a game mirrored before a restart. Everyone is
+// seated as disconnected, with the usual grace period to resume with their
+// token, and after delay the interrupted round is replayed. It reports
+// false when the room is already in use.
+func (r *GameRoom) restoreState(state RoomState, delay time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != StateWaiting || len(r.Players) > 0 || len(state.Players) == 0 {
+		return false
+	}
+	if r.Limits != nil {
+		if !r.Limits.AcquireGame() {
+			return false
+		}
+		r.holdsGameSlot = true
+	}
+
+	r.GameID = state.GameID
+	r.Settings = state.Settings
+	r.TotalRounds = state.TotalRounds
+	r.CurrentRound = state.CurrentRound
+	r.roundSeconds = state.RoundSeconds
+	r.PowerupsEnabled = state.Powerups
+	r.Scores = maps.Clone(state.Scores)
+	r.ScoreHistory = state.ScoreHistory
+	r.PlayedTracks = make(map[string]bool, len(state.PlayedTracks))
+	for _, trackID := range state.PlayedTracks {
+		r.PlayedTracks[trackID] = true
+	}
+	r.ActivePowerups = make(map[string]PowerupType)
+	r.rematchVotes = make(map[string]bool)
+	r.EndVotes = make(map[string]bool)
+	r.SpectatorScores = make(map[string]int)
+	r.roundResults = make(map[int]*RoundResult)
+	r.voidedRounds = make(map[int]bool)
+	r.roundSummaries = nil
+	r.tallies = make(map[string]*guessTally)
+	r.taste = make(map[tastePair][2]int)
+	r.outcomes = make(map[string]*TrackOutcome)
+	r.revealing = false
+	r.State = StatePlaying
+	r.touchActivity()
+
+	for _, saved := range state.Players {
+		player := &Player{
+			Player: &auth.Player{
+				ID:        saved.ID,
+				Name:      saved.Name,
+				SpotifyID: saved.SpotifyID,
+				TopTracks: saved.TopTracks,
+			},
+			JoinedAt:       time.Now(),
+			ResumeToken:    saved.ResumeToken,
+			Disconnected:   true,
+			IsSpectator:    saved.IsSpectator,
+			Streak:         saved.Streak,
+			Powerups:       saved.Powerups,
+			Handicap:       saved.Handicap,
+			ExcludedTracks: make(map[string]bool, len(saved.ExcludedTracks)),
+		}
+		if player.Powerups == nil {
+			player.Powerups = make(map[PowerupType]int)
+		}
+		for _, trackID := range saved.ExcludedTracks {
+			player.ExcludedTracks[trackID] = true
+		}
+		if len(player.ExcludedTracks) > 0 {
+			r.hiddenTracks[player.ID] = player.ExcludedTracks
+		}
+		if player.ID == state.LeaderID {
+			r.transferLeadership(player)
+		}
+
+		token := player.ResumeToken
+		playerID := player.ID
+		player.resumeTimer = time.AfterFunc(delay+ResumeGracePeriod, func() {
+			r.expireSeat(playerID, token)
+		})
+		r.Players[player.ID] = player
+		r.PlayerOrder = append(r.PlayerOrder, player.ID)
+	}
+	if r.LeaderID == "" || r.Players[r.LeaderID] == nil {
+		r.transferLeadership(r.Players[r.PlayerOrder[0]])
+	}
+
+	log.Printf("Room %s: restored game %s after round %d with %d players", r.ID, r.GameID, r.CurrentRound, len(r.Players))
+	gameID := r.GameID
+	time.AfterFunc(delay, func() {
+		r.resumeRestoredGame(gameID)
+	})
+	return true
+}
+
+// resumeRestoredGame replays the interrupted round, unless the restored
+// game has ended since, e.g. because nobody came back
+func (r *GameRoom) resumeRestoredGame(gameID string) {
+	r.mu.RLock()
+	current := r.State == StatePlaying && r.GameID == gameID && r.CurrentTrack == nil
+	r.mu.RUnlock()
+
+	if current {
+		r.startNextRound()
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStateSink keeps the latest mirrored state of each room
+type fakeStateSink struct {
+	mu     sync.Mutex
+	states map[string]RoomState
+}
+
+func (s *fakeStateSink) SaveRoomState(state RoomState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.RoomID] = state
+}
+
+func (s *fakeStateSink) DeleteRoomState(roomID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, roomID)
+}
+
+func (s *fakeStateSink) state(roomID string) (RoomState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[roomID]
+	return state, ok
+}
+
+// TestRoomStateRestore verifies a running game is mirrored between rounds
+// and can be restored after a restart, with players resuming their seats
+func TestRoomStateRestore(t *testing.T) {
+	sink := &fakeStateSink{states: make(map[string]RoomState)}
+	h := newGameHarness(t, 5)
+	h.room.stateSink = sink
+	h.room.Settings.TotalRounds = 2
+
+	h.join(harnessPlayer("A", "a1", "a2"))
+	h.join(harnessPlayer("B", "b1", "b2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.room.mu.RLock()
+	owner := harnessOwner(h.room.CurrentTrack.ID)
+	firstTrack := h.room.CurrentTrack.ID
+	h.room.mu.RUnlock()
+	h.guess("A", owner, time.Second)
+	h.guess("B", "nobody", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+
+	state, ok := sink.state("harness-room")
+	if !ok || state.CurrentRound != 1 || state.Scores["A"] == 0 || len(state.Players) != 2 || len(state.PlayedTracks) != 1 {
+		t.Fatalf("Expected round 1's state to be mirrored, got %+v", state)
+	}
+
+	// The first process ends the game and goes away before a new one
+	// restores it, so none of its pending rounds outlive it
+	h.room.mu.Lock()
+	h.room.finishGame()
+	h.room.mu.Unlock()
+	if _, ok := sink.state("harness-room"); ok {
+		t.Errorf("Expected a finished game's state to be dropped")
+	}
+	h.stop()
+
+	// A new process restores the game; A comes back, B never does
+	restarted := newGameHarness(t, 6)
+	if !restarted.room.restoreState(state, 50*time.Millisecond) {
+		t.Fatal("Expected the game to be restored into an empty room")
+	}
+	if restarted.room.restoreState(state, 0) {
+		t.Error("Expected a room already in use not to be restored into")
+	}
+	a := restarted.room.Players["A"]
+	if !a.Disconnected || restarted.room.Scores["A"] != state.Scores["A"] || len(a.TopTracks) != 2 || restarted.room.LeaderID != "A" {
+		t.Errorf("Expected A seated as disconnected with their score and tracks, got %+v", a)
+	}
+
+	result := make(chan *Player, 1)
+	restarted.room.handleResume(ResumeRequest{Token: a.ResumeToken, Result: result})
+	if player := <-result; player != a || a.Disconnected {
+		t.Errorf("Expected A to resume their seat with their old token")
+	}
+	msgs := restarted.expect(MsgTypePlayerReconnected, MsgTypeRoundStarted)
+	if round := msgs[1].Payload.(map[string]interface{})["round"]; round != 2 {
+		t.Errorf("Expected the game to carry on with round 2, got %v", round)
+	}
+	restarted.room.mu.RLock()
+	replayed := restarted.room.CurrentTrack.ID == firstTrack
+	restarted.room.mu.RUnlock()
+	if replayed {
+		t.Errorf("Expected round 1's track not to be played again")
+	}
+
+	t.Logf("✓ Running games are mirrored between rounds and restored after a restart")
+}
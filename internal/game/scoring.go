@@ -0,0 +1,113 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ScoringTimeDecay awards more points the faster a correct guess comes in
+const ScoringTimeDecay ScoringMode = "time_decay"
+
+// Time-decayed scoring bounds: a guess at 0s earns the most, one as the timer
+// runs out earns the least
+const (
+	TimeDecayMaxPoints = 15
+	TimeDecayMinPoints = 5
+)
+
+// MaxScoringWeight caps each of the standard scoring weights
+const MaxScoringWeight = 100
+
+// ScoringWeights tune standard scoring per room
+type ScoringWeights struct {
+	BasePoints      int `json:"base_points"`       // every correct guess
+	SpeedBonus      int `json:"speed_bonus"`       // on top for a guess at 0s, falling to 0 as the timer runs out
+	FirstGuessBonus int `json:"first_guess_bonus"` // on top for the fastest correct guess
+}
+
+// DefaultScoringWeights are the classic flat 10 points, plus 5 for the
+// fastest correct guess
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{BasePoints: 10, FirstGuessBonus: 5}
+}
+
+func (w ScoringWeights) validate() error {
+	if w.BasePoints < 1 || w.BasePoints > MaxScoringWeight {
+		return fmt.Errorf("base points must be between 1 and %d", MaxScoringWeight)
+	}
+	if w.SpeedBonus < 0 || w.SpeedBonus > MaxScoringWeight {
+		return fmt.Errorf("speed bonus must be between 0 and %d", MaxScoringWeight)
+	}
+	if w.FirstGuessBonus < 0 || w.FirstGuessBonus > MaxScoringWeight {
+		return fmt.Errorf("first guess bonus must be between 0 and %d", MaxScoringWeight)
+	}
+	return nil
+}
+
+// CorrectGuess describes a correct guess being scored
+type CorrectGuess struct {
+	Order       int           // 0 for the fastest correct guess
+	Elapsed     time.Duration // since the round started
+	RoundLength time.Duration
+	Weights     ScoringWeights // the room's, filled in by scoring()
+}
+
+// elapsedFraction is how far through the round the guess came in, from 0 to 1
+func (g CorrectGuess) elapsedFraction() float64 {
+	if g.RoundLength <= 0 {
+		return 0
+	}
+	return min(max(g.Elapsed.Seconds()/g.RoundLength.Seconds(), 0), 1)
+}
+
+// ScoringFunc returns the points a correct guess earns, before power-ups
+type ScoringFunc func(guess CorrectGuess) int
+
+// scoringModes maps each scoring mode to how it awards points. Adding a mode
+// here makes it a valid room setting.
+var scoringModes = map[ScoringMode]ScoringFunc{
+	ScoringStandard:  standardScoring,
+	ScoringTimeDecay: timeDecayScoring,
+}
+
+// standardScoring awards the base points, a speed bonus that falls linearly
+// over the round, and a bonus for the fastest correct guess
+func standardScoring(guess CorrectGuess) int {
+	points := guess.Weights.BasePoints
+	points += int(math.Round(float64(guess.Weights.SpeedBonus) * (1 - guess.elapsedFraction())))
+	if guess.Order == 0 {
+		points += guess.Weights.FirstGuessBonus
+	}
+	return points
+}
+
+// timeDecayScoring falls linearly from TimeDecayMaxPoints at the start of the
+// round to TimeDecayMinPoints when the timer runs out
+func timeDecayScoring(guess CorrectGuess) int {
+	span := float64(TimeDecayMaxPoints - TimeDecayMinPoints)
+	return TimeDecayMaxPoints - int(math.Round(span*guess.elapsedFraction()))
+}
+
+// scoring returns the room's scoring function, fed the room's weights.
+// Callers must hold the room lock.
+func (r *GameRoom) scoring() ScoringFunc {
+	score, ok := scoringModes[r.Settings.ScoringMode]
+	if !ok {
+		score = standardScoring
+	}
+	weights := r.Settings.scoringWeights()
+	return func(guess CorrectGuess) int {
+		guess.Weights = weights
+		return score(guess)
+	}
+}
+
+// scoringWeights returns the room's weights, or the defaults for settings
+// saved before weights existed
+func (s RoomSettings) scoringWeights() ScoringWeights {
+	if s.ScoringWeights == (ScoringWeights{}) {
+		return DefaultScoringWeights()
+	}
+	return s.ScoringWeights
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimeDecayScoring verifies points fall linearly from 15 to 5 over the round
+func TestTimeDecayScoring(t *testing.T) {
+	round := 30 * time.Second
+	cases := []struct {
+		elapsed time.Duration
+		points  int
+	}{
+		{0, 15},
+		{3 * time.Second, 14},
+		{15 * time.Second, 10},
+		{30 * time.Second, 5},
+		{45 * time.Second, 5}, // a late guess still gets the floor
+	}
+	for _, tc := range cases {
+		got := timeDecayScoring(CorrectGuess{Order: 1, Elapsed: tc.elapsed, RoundLength: round})
+		if got != tc.points {
+			t.Errorf("At %v: expected %d points, got %d", tc.elapsed, tc.points, got)
+		}
+	}
+
+	settings := DefaultRoomSettings()
+	settings.ScoringMode = ScoringTimeDecay
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Expected time_decay to be a valid scoring mode: %v", err)
+	}
+	settings.ScoringMode = "made_up"
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected an unknown scoring mode to be rejected")
+	}
+
+	t.Logf("✓ Time-decayed scoring falls from 15 to 5 over the round")
+}
+
+// TestRoomUsesItsScoringMode verifies a round is scored with the room's scoring function
+func TestRoomUsesItsScoringMode(t *testing.T) {
+	h := newGameHarness(t, 9)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.ScoringMode = ScoringTimeDecay
+
+	// Only t1 can play, and it's A's favourite
+	b := harnessPlayer("B", "t1")
+	b.TopTracks[0].Rank = 2
+	h.join(harnessPlayer("A", "t1"))
+	h.join(b)
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.guess("A", "A", 0)
+	h.guess("B", "A", 15*time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+
+	result := msgs[2].Payload.(*RoundResult)
+	if result.PointsAwarded["A"] != 15 || result.PointsAwarded["B"] != 10 {
+		t.Errorf("Expected 15 and 10 points, got %v", result.PointsAwarded)
+	}
+
+	t.Logf("✓ Rounds are scored with the room's scoring mode")
+}
+
+// TestScoringWeights verifies standard scoring follows the room's base, speed and first-guess weights
+func TestScoringWeights(t *testing.T) {
+	room := NewGameRoom("test-room")
+	round := 30 * time.Second
+
+	score := room.scoring()
+	if got := score(CorrectGuess{Order: 0, RoundLength: round}); got != 15 {
+		t.Errorf("Expected the default fastest guess to earn 15, got %d", got)
+	}
+	if got := score(CorrectGuess{Order: 1, RoundLength: round}); got != 10 {
+		t.Errorf("Expected a default later guess to earn 10, got %d", got)
+	}
+
+	// Party play: a big speed bonus and no first-guess bonus
+	room.Settings.ScoringWeights = ScoringWeights{BasePoints: 5, SpeedBonus: 40}
+	if err := room.Settings.Validate(); err != nil {
+		t.Fatalf("Expected party weights to be valid: %v", err)
+	}
+	score = room.scoring()
+	cases := []struct {
+		order   int
+		elapsed time.Duration
+		points  int
+	}{
+		{0, 0, 45},
+		{1, 15 * time.Second, 25},
+		{2, 30 * time.Second, 5},
+	}
+	for _, tc := range cases {
+		if got := score(CorrectGuess{Order: tc.order, Elapsed: tc.elapsed, RoundLength: round}); got != tc.points {
+			t.Errorf("At %v: expected %d points, got %d", tc.elapsed, tc.points, got)
+		}
+	}
+
+	settings := DefaultRoomSettings()
+	settings.ScoringWeights.SpeedBonus = MaxScoringWeight + 1
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected an oversized speed bonus to be rejected")
+	}
+	settings.ScoringWeights = ScoringWeights{SpeedBonus: 10}
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected zero base points to be rejected")
+	}
+
+	t.Logf("✓ Standard scoring follows the room's weights")
+}
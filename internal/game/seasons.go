@@ -0,0 +1,367 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSeasonMonths is how long a season lasts unless configured
+	DefaultSeasonMonths = 1
+	// MaxSeasonStandings is how many players a season's leaderboard lists
+	MaxSeasonStandings = 100
+	// SeasonIDLayout formats a season's ID from the month it starts in
+	SeasonIDLayout = "2006-01"
+)
+
+var (
+	// ErrUnknownSeason is returned for a season that hasn't been archived
+	ErrUnknownSeason = errors.New("season not found")
+	// ErrSeasonLength is returned for a season length that doesn't divide a year
+	ErrSeasonLength = errors.New("season length must be 1, 2, 3, 4, 6 or 12 months")
+)
+
+// Season is one leaderboard period. Seasons line up with the calendar year,
+// so with 3-month seasons they start in January, April, July and October.
+type Season struct {
+	ID       string    `json:"id"` // the month it starts, e.g. "2026-10"
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// SeasonStanding is a player's place on a season's leaderboard
+type SeasonStanding struct {
+	Rank        int    `json:"rank"`
+	PlayerID    string `json:"player_id"`
+	Name        string `json:"name"`
+	GamesPlayed int    `json:"games_played"`
+	Wins        int    `json:"wins"`
+	Points      int    `json:"points"` // total score across the season's games
+}
+
+// SeasonStandings is a season's leaderboard: live for the current season,
+// final once it's archived
+type SeasonStandings struct {
+	Season
+	Final     bool             `json:"final"`
+	Standings []SeasonStanding `json:"standings"`
+}
+
+// PlayerSeason is a player's final standing in an archived season
+type PlayerSeason struct {
+	Season   Season         `json:"season"`
+	Standing SeasonStanding `json:"standing"`
+}
+
+// Seasons scopes leaderboards to seasons of saved games. When a season ends
+// its final standings are archived, so they outlive the games themselves
+// being compacted away.
+type Seasons struct {
+	mu      sync.Mutex
+	history *HistoryStore
+	months  int
+	path    string
+	archive []SeasonStandings // oldest first
+	through time.Time         // seasons before this have been archived
+	now     func() time.Time
+}
+
+// NewSeasons keeps season archives in dir, loading any earlier ones
+func NewSeasons(history *HistoryStore, dir string) (*Seasons, error) {
+	s := &Seasons{
+		history: history,
+		months:  DefaultSeasonMonths,
+		path:    filepath.Join(dir, "seasons.jsonl"),
+		now:     time.Now,
+	}
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open seasons: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var season SeasonStandings
+		if err := json.Unmarshal(scanner.Bytes(), &season); err != nil {
+			log.Printf("Skipping unreadable season: %v", err)
+			continue
+		}
+		s.archive = append(s.archive, season)
+		if season.EndsAt.After(s.through) {
+			s.through = season.EndsAt
+		}
+	}
+	return s, scanner.Err()
+}
+
+// SetLength changes how many months a season lasts. Seasons already
+// archived keep the length they had.
+func (s *Seasons) SetLength(months int) error {
+	if months <= 0 || 12%months != 0 {
+		return ErrSeasonLength
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.months = months
+	return nil
+}
+
+// seasonAt returns the season t falls in. Callers must hold the lock.
+func (s *Seasons) seasonAt(t time.Time) Season {
+	t = t.UTC()
+	month := (int(t.Month())-1)/s.months*s.months + 1
+	start := time.Date(t.Year(), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return Season{
+		ID:       start.Format(SeasonIDLayout),
+		StartsAt: start,
+		EndsAt:   start.AddDate(0, s.months, 0),
+	}
+}
+
+// Current returns the current season's live leaderboard
+func (s *Seasons) Current() SeasonStandings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollover()
+	season := s.seasonAt(s.now())
+	return SeasonStandings{Season: season, Standings: s.standings(season)}
+}
+
+// Archived lists the archived seasons, newest first
+func (s *Seasons) Archived() []Season {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollover()
+	seasons := make([]Season, 0, len(s.archive))
+	for i := len(s.archive) - 1; i >= 0; i-- {
+		seasons = append(seasons, s.archive[i].Season)
+	}
+	return seasons
+}
+
+// Get returns a season's leaderboard by ID: final for an archived season,
+// live for the current one
+func (s *Seasons) Get(id string) (SeasonStandings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollover()
+	if current := s.seasonAt(s.now()); current.ID == id {
+		return SeasonStandings{Season: current, Standings: s.standings(current)}, nil
+	}
+	for _, season := range s.archive {
+		if season.ID == id {
+			return season, nil
+		}
+	}
+	return SeasonStandings{}, ErrUnknownSeason
+}
+
+// rollover archives the final standings of every season that has ended
+// since the last one was archived. Seasons nobody played in are skipped.
+// Callers must hold the lock.
+func (s *Seasons) rollover() {
+	current := s.seasonAt(s.now())
+	if !s.through.Before(current.StartsAt) {
+		return
+	}
+
+	from := s.through
+	if from.IsZero() {
+		earliest, ok := s.history.earliest()
+		if !ok {
+			s.through = current.StartsAt
+			return
+		}
+		from = earliest
+	}
+
+	for season := s.seasonAt(from); season.StartsAt.Before(current.StartsAt); season = s.seasonAt(season.EndsAt) {
+		standings := s.standings(season)
+		if len(standings) == 0 {
+			continue
+		}
+		final := SeasonStandings{Season: season, Final: true, Standings: standings}
+		if err := s.append(final); err != nil {
+			log.Printf("Failed to archive season %s: %v", season.ID, err)
+			return // try again next time
+		}
+		s.archive = append(s.archive, final)
+		log.Printf("Season %s archived with %d players", season.ID, len(standings))
+	}
+	s.through = current.StartsAt
+}
+
+// append writes an archived season to disk. Callers must hold the lock.
+func (s *Seasons) append(season SeasonStandings) error {
+	data, err := json.Marshal(season)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// standings ranks everyone who played in the season by wins, then points.
+// Players level on both share a rank. Callers must hold the lock.
+func (s *Seasons) standings(season Season) []SeasonStanding {
+	byPlayer := make(map[string]*SeasonStanding)
+	for _, game := range s.history.between(season.StartsAt, season.EndsAt) {
+		for _, p := range game.Players {
+			standing, ok := byPlayer[p.ID]
+			if !ok {
+				standing = &SeasonStanding{PlayerID: p.ID}
+				byPlayer[p.ID] = standing
+			}
+			standing.Name = p.Name // the latest name they played under
+			standing.GamesPlayed++
+			standing.Points += p.Score
+			if game.WinnerID == p.ID {
+				standing.Wins++
+			}
+		}
+	}
+
+	standings := make([]SeasonStanding, 0, len(byPlayer))
+	for _, standing := range byPlayer {
+		standings = append(standings, *standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.PlayerID < b.PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if prev := i - 1; prev >= 0 && standings[i].Wins == standings[prev].Wins && standings[i].Points == standings[prev].Points {
+			standings[i].Rank = standings[prev].Rank
+		}
+	}
+	if len(standings) > MaxSeasonStandings {
+		standings = standings[:MaxSeasonStandings]
+	}
+	return standings
+}
+
+// PlayerSeasons returns the player's final standing in every archived
+// season they placed in, newest first
+func (s *Seasons) PlayerSeasons(playerID string) []PlayerSeason {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seasons := make([]PlayerSeason, 0)
+	for i := len(s.archive) - 1; i >= 0; i-- {
+		for _, standing := range s.archive[i].Standings {
+			if standing.PlayerID == playerID {
+				seasons = append(seasons, PlayerSeason{Season: s.archive[i].Season, Standing: standing})
+			}
+		}
+	}
+	return seasons
+}
+
+// Anonymize replaces the player's ID and name in archived standings with a
+// pseudonym, returning how many seasons were rewritten
+func (s *Seasons) Anonymize(playerID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pseudonym := "deleted-" + uuid.New().String()[:8]
+	archive := make([]SeasonStandings, len(s.archive))
+	anonymized := 0
+	for i, season := range s.archive {
+		standings := make([]SeasonStanding, len(season.Standings))
+		copy(standings, season.Standings)
+		season.Standings = standings
+		for j := range standings {
+			if standings[j].PlayerID == playerID {
+				standings[j].PlayerID = pseudonym
+				standings[j].Name = DeletedPlayerName
+				anonymized++
+			}
+		}
+		archive[i] = season
+	}
+	if anonymized == 0 {
+		return 0, nil
+	}
+
+	// Write then rename so a crash never leaves the archive half-rewritten
+	tmp := s.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(file)
+	for _, season := range archive {
+		if err := encoder.Encode(season); err != nil {
+			file.Close()
+			return 0, err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return 0, err
+	}
+
+	s.archive = archive
+	return anonymized, nil
+}
+
+// between returns the saved games that finished in [start, end)
+func (h *HistoryStore) between(start, end time.Time) []GameRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	games := make([]GameRecord, 0)
+	for _, record := range h.records {
+		if !record.FinishedAt.Before(start) && record.FinishedAt.Before(end) {
+			games = append(games, record)
+		}
+	}
+	return games
+}
+
+// earliest returns when the oldest saved game finished
+func (h *HistoryStore) earliest() (time.Time, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var earliest time.Time
+	for _, record := range h.records {
+		if earliest.IsZero() || record.FinishedAt.Before(earliest) {
+			earliest = record.FinishedAt
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSeasonsRollover verifies the current season ranks only its own games,
+// and that finished seasons are archived with their final standings
+func TestSeasonsRollover(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	seasons, err := NewSeasons(history, dir)
+	if err != nil {
+		t.Fatalf("Failed to open seasons: %v", err)
+	}
+	now := time.Date(2026, 9, 20, 12, 0, 0, 0, time.UTC)
+	seasons.now = func() time.Time { return now }
+
+	record := func(finished time.Time, winner string, scores ...RecordPlayer) {
+		t.Helper()
+		game := GameRecord{RoomID: "room-1", FinishedAt: finished, WinnerID: winner, Players: scores}
+		if err := history.Append(game); err != nil {
+			t.Fatalf("Failed to record game: %v", err)
+		}
+	}
+	september := time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC)
+	record(september, "A", RecordPlayer{ID: "A", Name: "Ay", Score: 30}, RecordPlayer{ID: "B", Score: 10})
+	record(september, "B", RecordPlayer{ID: "A", Name: "Ay", Score: 10}, RecordPlayer{ID: "B", Score: 30})
+	record(september, "C", RecordPlayer{ID: "C", Score: 50}, RecordPlayer{ID: "B", Score: 0})
+	record(time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC), "C", RecordPlayer{ID: "C", Score: 5})
+
+	current := seasons.Current()
+	if current.ID != "2026-09" || current.Final || len(current.Standings) != 3 {
+		t.Fatalf("Expected September's live standings for 3 players, got %+v", current)
+	}
+	if top := current.Standings[0]; top.PlayerID != "C" || top.Rank != 1 || top.Wins != 1 || top.Points != 50 {
+		t.Errorf("Expected C to lead on points, got %+v", top)
+	}
+	if a, b := current.Standings[1], current.Standings[2]; a.PlayerID != "A" || a.Name != "Ay" || a.Rank != 2 || b.PlayerID != "B" || b.Rank != 2 || b.GamesPlayed != 3 {
+		t.Errorf("Expected A and B to share second, got %+v and %+v", a, b)
+	}
+	if archived := seasons.Archived(); len(archived) != 1 || archived[0].ID != "2026-07" {
+		t.Errorf("Expected only July to be archived, skipping empty August, got %+v", archived)
+	}
+
+	// October rolls September over
+	now = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if current := seasons.Current(); current.ID != "2026-10" || len(current.Standings) != 0 {
+		t.Errorf("Expected an empty October, got %+v", current)
+	}
+	reloaded, err := NewSeasons(history, dir)
+	if err != nil {
+		t.Fatalf("Failed to reload seasons: %v", err)
+	}
+	reloaded.now = seasons.now
+	september2, err := reloaded.Get("2026-09")
+	if err != nil || !september2.Final || september2.Standings[0].PlayerID != "C" {
+		t.Errorf("Expected September's final standings after a reload, got %+v (%v)", september2, err)
+	}
+	if archived := reloaded.Archived(); len(archived) != 2 || archived[0].ID != "2026-09" {
+		t.Errorf("Expected September then July, got %+v", archived)
+	}
+	if _, err := reloaded.Get("2026-08"); err != ErrUnknownSeason {
+		t.Errorf("Expected August to be unknown, got %v", err)
+	}
+
+	if n, err := reloaded.Anonymize("C"); err != nil || n != 2 {
+		t.Errorf("Expected C's 2 archived standings anonymized, got %d (%v)", n, err)
+	}
+	if len(reloaded.PlayerSeasons("C")) != 0 || len(reloaded.PlayerSeasons("B")) != 1 {
+		t.Errorf("Expected C's standings gone and B's kept")
+	}
+
+	if err := reloaded.SetLength(5); err != ErrSeasonLength {
+		t.Errorf("Expected 5-month seasons to be refused, got %v", err)
+	}
+	reloaded.SetLength(3)
+	if current := reloaded.Current(); current.ID != "2026-10" || !current.EndsAt.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a quarter-long season, got %+v", current.Season)
+	}
+
+	t.Logf("✓ Seasons rank their own games and archive final standings at rollover")
+}
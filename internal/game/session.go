@@ -0,0 +1,210 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"roulettify/internal/auth"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+)
+
+// ResumeGracePeriod is how long a dropped player keeps their seat mid-game
+const ResumeGracePeriod = 60 * time.Second
+
+// DisconnectPayload reports that a player's connection dropped
+type DisconnectPayload struct {
+	PlayerID   string
+	Connection *websocket.Conn
+}
+
+// ResumeRequest asks the room to re-bind a new connection to an existing
+// player. The matched player (or nil) is sent on Result.
+type ResumeRequest struct {
+	Token      string
+	Connection *websocket.Conn
+	LastSeq    int64 // replay messages after this one instead of a snapshot
+	Result     chan *Player
+}
+
+// issueResumeToken gives a newly joined player a token they can present to
+// reclaim their seat after a dropped connection
+func (r *GameRoom) issueResumeToken(player *Player) {
+	player.ResumeToken = uuid.New().String()
+	if player.Capabilities.Debug && player.debug == nil {
+		player.debug = r.diagnostics.open(player, r.ID)
+	}
+
+	session := map[string]interface{}{
+		"player_id":     player.ID,
+		"resume_token":  player.ResumeToken,
+		"grace_seconds": int(ResumeGracePeriod.Seconds()),
+		"capabilities":  player.Capabilities,
+	}
+	if player.debug != nil {
+		session["debug_connection_id"] = player.debug.id
+	}
+	r.sendToPlayer(player.ID, Message{
+		Type:    MsgTypeSession,
+		Payload: session,
+	})
+}
+
+func (r *GameRoom) handleDisconnect(payload DisconnectPayload) {
+	r.mu.Lock()
+
+	player, exists := r.Players[payload.PlayerID]
+	if !exists {
+		r.dequeue(payload.PlayerID, payload.Connection)
+		r.mu.Unlock()
+		return
+	}
+	if player.Connection != payload.Connection {
+		// Already left, or the seat was re-bound to a newer connection
+		r.mu.Unlock()
+		return
+	}
+
+	// Nothing to preserve between games
+	if r.State == StateWaiting {
+		r.mu.Unlock()
+		r.handlePlayerLeave(payload.PlayerID)
+		return
+	}
+	defer r.mu.Unlock()
+
+	player.Connection = nil
+	player.Disconnected = true
+	log.Printf("Player %s disconnected from room %s, holding seat for %v", player.Name, r.ID, ResumeGracePeriod)
+
+	token := player.ResumeToken
+	player.resumeTimer = time.AfterFunc(ResumeGracePeriod, func() {
+		r.expireSeat(payload.PlayerID, token)
+	})
+
+	r.Broadcast <- Message{
+		Type: MsgTypePlayerDisconnected,
+		Payload: map[string]interface{}{
+			"player_id":     payload.PlayerID,
+			"grace_seconds": int(ResumeGracePeriod.Seconds()),
+			"players":       r.getPlayerInfoList(),
+		},
+	}
+}
+
+// expireSeat removes a disconnected player whose grace period ran out
+func (r *GameRoom) expireSeat(playerID, token string) {
+	r.mu.RLock()
+	player, exists := r.Players[playerID]
+	expired := exists && player.Disconnected && player.ResumeToken == token
+	r.mu.RUnlock()
+
+	if expired {
+		log.Printf("Player %s did not reconnect to room %s in time", playerID, r.ID)
+		r.Leave <- playerID
+	}
+}
+
+func (r *GameRoom) handleResume(req ResumeRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var player *Player
+	for _, p := range r.Players {
+		if p.ResumeToken != "" && p.ResumeToken == req.Token {
+			player = p
+			break
+		}
+	}
+
+	if player == nil {
+		req.Result <- nil
+		return
+	}
+
+	if player.resumeTimer != nil {
+		player.resumeTimer.Stop()
+		player.resumeTimer = nil
+	}
+
+	// A still-open old connection (e.g. a stale tab) is replaced
+	if player.Connection != nil && player.Connection != req.Connection {
+		player.Connection.Close(websocket.StatusNormalClosure, "Session resumed elsewhere")
+	}
+
+	player.Connection = req.Connection
+	player.Disconnected = false
+	player.liteBaseline = nil // the new connection starts from a full list
+
+	log.Printf("Player %s resumed session in room %s", player.Name, r.ID)
+
+	if !r.replayMissed(player, req.LastSeq) {
+		r.sendToPlayer(player.ID, Message{
+			Type:    MsgTypeStateSnapshot,
+			Payload: r.stateSnapshot(player.ID),
+		})
+	}
+
+	r.Broadcast <- Message{
+		Type: MsgTypePlayerReconnected,
+		Payload: map[string]interface{}{
+			"player_id": player.ID,
+			"players":   r.getPlayerInfoList(),
+		},
+	}
+
+	req.Result <- player
+}
+
+// stateSnapshot captures everything a reconnecting client needs to redraw
+// the room without having seen earlier messages
+func (r *GameRoom) stateSnapshot(playerID string) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"room_id":       r.ID,
+		"state":         r.State,
+		"round":         r.CurrentRound,
+		"total_rounds":  r.TotalRounds,
+		"scores":        r.Scores,
+		"score_history": r.ScoreHistory,
+		"settings":      r.Settings,
+		"players":       r.getPlayerInfoList(),
+	}
+	if r.playlist != nil {
+		snapshot["playlist"] = r.playlistInfo()
+	}
+	if r.paused != nil {
+		snapshot["paused"] = true
+	}
+
+	if r.State == StatePlaying && r.CurrentTrack != nil {
+		_, guessed := r.Guesses[playerID]
+		snapshot["track"] = maskedTrack(*r.CurrentTrack)
+		if r.paused == nil {
+			snapshot["round_ends_at"] = r.roundEndsAt()
+		}
+		snapshot["has_guessed"] = guessed
+		snapshot["hints"] = r.hints
+		if mask := r.titleMask(); mask != "" {
+			snapshot["title_mask"] = mask
+		}
+		if r.activeReverse != nil {
+			delete(snapshot, "track")
+			snapshot["reverse"] = r.activeReverse.info()
+		}
+	}
+
+	return snapshot
+}
+
+// maskedTrack hides everything but the preview so the track can't be identified
+func maskedTrack(track auth.Track) auth.Track {
+	track.Name = "???"
+	track.Artists = []string{"???"}
+	track.ImageURL = ""  // Hide album art
+	track.Popularity = 0 // Saved for mini-games
+	track.Sources = nil  // Attributed in the reveal
+	track.Year = 0       // A hint, and announced on era rounds anyway
+	// Keep PreviewURL and ID
+	return track
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"log"
+)
+
+// SessionStanding is a player's cumulative result across the games played
+// in a room since the scoreboard was last reset
+type SessionStanding struct {
+	PlayerID    string `json:"player_id"`
+	Name        string `json:"name"`
+	TotalScore  int    `json:"total_score"`
+	GamesPlayed int    `json:"games_played"`
+	Wins        int    `json:"wins"`
+}
+
+// recordSessionScores folds a finished game into the room's cumulative
+// scoreboard. Callers must hold the room lock.
+func (r *GameRoom) recordSessionScores(winnerID string) {
+	for playerID, score := range r.Scores {
+		standing, exists := r.SessionScores[playerID]
+		if !exists {
+			standing = &SessionStanding{PlayerID: playerID}
+			r.SessionScores[playerID] = standing
+		}
+		if player, ok := r.Players[playerID]; ok {
+			standing.Name = player.Name
+		}
+		standing.TotalScore += score
+		standing.GamesPlayed++
+		if playerID == winnerID {
+			standing.Wins++
+		}
+	}
+}
+
+// sessionLeaderID returns the player with the highest cumulative score
+func (r *GameRoom) sessionLeaderID() string {
+	leaderID := ""
+	best := -1
+	for playerID, standing := range r.SessionScores {
+		if standing.TotalScore > best {
+			best = standing.TotalScore
+			leaderID = playerID
+		}
+	}
+	return leaderID
+}
+
+func (r *GameRoom) handleResetSession(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if playerID != r.LeaderID {
+		r.sendError(playerID, "Only the leader can reset the session scoreboard")
+		return
+	}
+
+	r.SessionScores = make(map[string]*SessionStanding)
+	log.Printf("Room %s session scoreboard reset", r.ID)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeSessionReset,
+		Payload: map[string]interface{}{
+			"session_scores": r.SessionScores,
+		},
+	}
+}
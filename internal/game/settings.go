@@ -0,0 +1,286 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+var marketPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Bounds for how long a round lasts, in seconds
+const (
+	MinRoundDuration = 10
+	MaxRoundDuration = 120
+)
+
+// ScoringMode selects how points are awarded for correct guesses
+type ScoringMode string
+
+const (
+	ScoringStandard ScoringMode = "standard"
+)
+
+// TimeRange selects which Spotify listening window players' tracks come from
+type TimeRange string
+
+const (
+	TimeRangeShort  TimeRange = "short_term"
+	TimeRangeMedium TimeRange = "medium_term"
+	TimeRangeLong   TimeRange = "long_term"
+)
+
+// RoomSettings holds the leader-configurable options for a room's games
+type RoomSettings struct {
+	RoundDuration        int                      `json:"round_duration"`      // seconds
+	IntermissionLength   int                      `json:"intermission_length"` // seconds
+	TotalRounds          int                      `json:"total_rounds"`
+	ScoringMode          ScoringMode              `json:"scoring_mode"` // standard or time_decay
+	TimeRange            TimeRange                `json:"time_range"`
+	Endless              bool                     `json:"endless"`
+	RollingWindow        int                      `json:"rolling_window"` // rounds in the endless leaderboard
+	DisableEmotes        bool                     `json:"disable_emotes"`
+	BonusRoundInterval   int                      `json:"bonus_round_interval"`     // every Nth round is an artist bonus round, 0 = off
+	ReverseRoundInterval int                      `json:"reverse_round_interval"`   // every Nth round asks which track is a player's, 0 = off
+	Language             TrackLanguage            `json:"language"`                 // restrict the pool to tracks in this language
+	Market               string                   `json:"market"`                   // ISO country code tracks must be playable in
+	Genres               []string                 `json:"genres"`                   // keep tracks whose artists play any of these genres
+	Decade               int                      `json:"decade"`                   // keep tracks released in this decade, e.g. 2010 for the 2010s
+	EraRounds            bool                     `json:"era_rounds"`               // each round is themed on a decade, rotating through the pool's
+	GuessMode            GuessMode                `json:"guess_mode"`               // pick the player, or type the song title
+	MiniGames            bool                     `json:"mini_games"`               // higher-or-lower questions between rounds
+	SpeedRounds          bool                     `json:"speed_rounds"`             // the round timer shrinks every round
+	SpeedRoundMinimum    int                      `json:"speed_round_minimum"`      // seconds the last speed round lasts
+	SuspenseDelay        int                      `json:"suspense_delay"`           // seconds between the track and winner reveals, 0 = off
+	ShareTies            bool                     `json:"share_ties"`               // guessing any player tied for the best rank counts
+	Overtime             bool                     `json:"overtime"`                 // a tied lead at the end plays sudden-death rounds
+	RotateLeader         bool                     `json:"rotate_leader"`            // leadership passes to the next player after each game
+	ExtendSlowRounds     bool                     `json:"extend_slow_rounds"`       // a round with under half the guesses in gets one grace period
+	RerollUnguessed      bool                     `json:"reroll_unguessed"`         // a track nobody gets right goes back into the pool
+	IntermissionContent  []IntermissionSlot       `json:"intermission_content"`     // shown between rounds, in this order
+	ScoringWeights       ScoringWeights           `json:"scoring_weights"`          // standard scoring's points
+	HintMarks            []int                    `json:"hint_marks"`               // seconds into a round to reveal the album art, then the title's first letter
+	TitleReveal          bool                     `json:"title_reveal"`             // the masked title fills in letter by letter over the round
+	RoundOrder           RoundOrder               `json:"round_order"`              // random, or ramp from easy tracks to hard ones
+	Sources              []auth.TrackSource       `json:"sources"`                  // where players' tracks come from, fetched when they join
+	SourceWeights        map[auth.TrackSource]int `json:"source_weights,omitempty"` // selection weight per source, 1 when unset
+}
+
+// DefaultRoomSettings returns the settings every room starts with
+func DefaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		RoundDuration:      30,
+		IntermissionLength: 5,
+		TotalRounds:        10,
+		ScoringMode:        ScoringStandard,
+		TimeRange:          TimeRangeMedium,
+		RollingWindow:      10,
+		GuessMode:          GuessPlayer,
+		SpeedRoundMinimum:  DefaultSpeedRoundMinimum,
+		ScoringWeights:     DefaultScoringWeights(),
+		RoundOrder:         RoundOrderRandom,
+		Sources:            []auth.TrackSource{auth.SourceTop},
+		Genres:             []string{},
+	}
+}
+
+// Validate fills unset fields with defaults and rejects invalid values
+func (s *RoomSettings) Validate() error {
+	defaults := DefaultRoomSettings()
+
+	if s.RoundDuration == 0 {
+		s.RoundDuration = defaults.RoundDuration
+	}
+	if s.IntermissionLength == 0 {
+		s.IntermissionLength = defaults.IntermissionLength
+	}
+	if s.TotalRounds == 0 {
+		s.TotalRounds = defaults.TotalRounds
+	}
+	if s.ScoringMode == "" {
+		s.ScoringMode = defaults.ScoringMode
+	}
+	if s.TimeRange == "" {
+		s.TimeRange = defaults.TimeRange
+	}
+	if s.RollingWindow == 0 {
+		s.RollingWindow = defaults.RollingWindow
+	}
+	if s.GuessMode == "" {
+		s.GuessMode = defaults.GuessMode
+	}
+	if s.SpeedRoundMinimum == 0 {
+		s.SpeedRoundMinimum = defaults.SpeedRoundMinimum
+	}
+	if s.ScoringWeights == (ScoringWeights{}) {
+		s.ScoringWeights = defaults.ScoringWeights
+	}
+	if s.RoundOrder == "" {
+		s.RoundOrder = defaults.RoundOrder
+	}
+	if len(s.Sources) == 0 {
+		s.Sources = defaults.Sources
+	}
+
+	if s.RoundDuration < MinRoundDuration || s.RoundDuration > MaxRoundDuration {
+		return fmt.Errorf("round duration must be between %d and %d seconds", MinRoundDuration, MaxRoundDuration)
+	}
+	if s.IntermissionLength < 0 || s.IntermissionLength > 60 {
+		return fmt.Errorf("intermission length must be between 1 and 60 seconds")
+	}
+	if s.TotalRounds < 0 || s.TotalRounds > 50 {
+		return fmt.Errorf("total rounds must be between 1 and 50")
+	}
+
+	if s.BonusRoundInterval < 0 || s.BonusRoundInterval == 1 || s.BonusRoundInterval > 20 {
+		return fmt.Errorf("bonus round interval must be 0 (off) or between 2 and 20")
+	}
+	if s.ReverseRoundInterval < 0 || s.ReverseRoundInterval == 1 || s.ReverseRoundInterval > 20 {
+		return fmt.Errorf("reverse round interval must be 0 (off) or between 2 and 20")
+	}
+
+	if s.SpeedRounds && (s.SpeedRoundMinimum < 5 || s.SpeedRoundMinimum > s.RoundDuration) {
+		return fmt.Errorf("speed round minimum must be between 5 seconds and the round duration")
+	}
+
+	if s.SuspenseDelay < 0 || s.SuspenseDelay > MaxSuspenseDelay {
+		return fmt.Errorf("suspense delay must be between 0 (off) and %d seconds", MaxSuspenseDelay)
+	}
+
+	if s.MiniGames && s.IntermissionLength < MinIntermissionForMiniGames {
+		return fmt.Errorf("mini-games need an intermission of at least %d seconds", MinIntermissionForMiniGames)
+	}
+
+	if err := validateIntermissionContent(s.IntermissionContent); err != nil {
+		return err
+	}
+	if err := validateHintMarks(s.HintMarks); err != nil {
+		return err
+	}
+	if err := validateSources(s.Sources, s.SourceWeights); err != nil {
+		return err
+	}
+
+	if s.RollingWindow < 0 {
+		return fmt.Errorf("rolling window must be positive")
+	}
+
+	if _, ok := scoringModes[s.ScoringMode]; !ok {
+		return fmt.Errorf("unknown scoring mode %q", s.ScoringMode)
+	}
+	if err := s.ScoringWeights.validate(); err != nil {
+		return err
+	}
+
+	switch s.GuessMode {
+	case GuessPlayer, GuessTitle:
+	default:
+		return fmt.Errorf("unknown guess mode %q", s.GuessMode)
+	}
+
+	switch s.RoundOrder {
+	case RoundOrderRandom, RoundOrderRamp:
+	default:
+		return fmt.Errorf("unknown round order %q", s.RoundOrder)
+	}
+
+	switch s.Language {
+	case LanguageAny, LanguageSpanish, LanguagePortuguese, LanguageFrench,
+		LanguageGerman, LanguageJapanese, LanguageKorean, LanguageChinese:
+	default:
+		return fmt.Errorf("unknown language %q", s.Language)
+	}
+
+	s.Market = strings.ToUpper(s.Market)
+	if s.Market != "" && !marketPattern.MatchString(s.Market) {
+		return fmt.Errorf("market must be a two-letter country code")
+	}
+
+	genres, err := normalizeGenres(s.Genres)
+	if err != nil {
+		return err
+	}
+	s.Genres = genres
+
+	if err := validateDecade(s.Decade); err != nil {
+		return err
+	}
+
+	switch s.TimeRange {
+	case TimeRangeShort, TimeRangeMedium, TimeRangeLong:
+	default:
+		return fmt.Errorf("unknown time range %q", s.TimeRange)
+	}
+
+	return nil
+}
+
+// currentRoundDuration is how long the current round lasts: the game's round
+// duration, shrunk in speed mode. Callers must hold the room lock.
+func (r *GameRoom) currentRoundDuration() time.Duration {
+	seconds := r.roundSeconds
+	if seconds == 0 {
+		seconds = r.Settings.RoundDuration
+	}
+	if r.Settings.SpeedRounds {
+		seconds = r.Settings.speedRoundDuration(seconds, r.CurrentRound, r.TotalRounds)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s RoomSettings) intermission() time.Duration {
+	return time.Duration(s.IntermissionLength) * time.Second
+}
+
+// CurrentSettings returns a copy of the room's settings
+func (r *GameRoom) CurrentSettings() RoomSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Settings
+}
+
+func (r *GameRoom) handleUpdateSettings(payload UpdateSettingsPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can change room settings")
+		return
+	}
+
+	if r.State != StateWaiting && r.State != StateGameOver {
+		r.sendError(payload.PlayerID, "Settings can only be changed before the game starts")
+		return
+	}
+
+	if r.tutorial != nil {
+		r.sendError(payload.PlayerID, "The tutorial's settings can't be changed")
+		return
+	}
+
+	settings := payload.Settings
+	if err := settings.Validate(); err != nil {
+		r.sendError(payload.PlayerID, "Invalid settings: "+err.Error())
+		return
+	}
+
+	r.Settings = settings
+	r.persist()
+	log.Printf("Room %s settings updated: %+v", r.ID, settings)
+
+	update := map[string]interface{}{
+		"settings": r.Settings,
+	}
+	if r.Settings.hasTrackFilter() {
+		update["matching_tracks"] = r.filteredPoolSize()
+	}
+
+	r.Broadcast <- Message{
+		Type:    MsgTypeSettingsUpdated,
+		Payload: update,
+	}
+}
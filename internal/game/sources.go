@@ -0,0 +1,104 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+
+	"roulettify/internal/auth"
+)
+
+// MaxSourceWeight bounds how strongly one source can be favoured
+const MaxSourceWeight = 10
+
+// validateSources checks the enabled track sources and their weights
+func validateSources(sources []auth.TrackSource, weights map[auth.TrackSource]int) error {
+	for i, source := range sources {
+		if !slices.Contains(auth.TrackSources, source) {
+			return fmt.Errorf("unknown track source %q", source)
+		}
+		if slices.Contains(sources[:i], source) {
+			return fmt.Errorf("track source %q is listed twice", source)
+		}
+	}
+	for source, weight := range weights {
+		if !slices.Contains(auth.TrackSources, source) {
+			return fmt.Errorf("unknown track source %q", source)
+		}
+		if weight < 1 || weight > MaxSourceWeight {
+			return fmt.Errorf("source weights must be between 1 and %d", MaxSourceWeight)
+		}
+	}
+	return nil
+}
+
+// trackSources are the sources a player's copy of a track came from. Tracks
+// from before sources existed are top tracks.
+func trackSources(track auth.Track) []auth.TrackSource {
+	if len(track.Sources) == 0 {
+		return []auth.TrackSource{auth.SourceTop}
+	}
+	return track.Sources
+}
+
+// sourceWeight is how heavily a track is weighted in selection for the
+// sources it came from: the highest weight among its enabled sources, or 0
+// when none of them are enabled any more
+func (s RoomSettings) sourceWeight(track auth.Track) int {
+	enabled := s.Sources
+	if len(enabled) == 0 {
+		enabled = []auth.TrackSource{auth.SourceTop}
+	}
+
+	best := 0
+	for _, source := range trackSources(track) {
+		if !slices.Contains(enabled, source) {
+			continue
+		}
+		weight := s.SourceWeights[source]
+		if weight == 0 {
+			weight = 1
+		}
+		best = max(best, weight)
+	}
+	return best
+}
+
+// mixedSources reports whether the room draws on more than one source, when
+// the reveal explains where a track came from
+func (s RoomSettings) mixedSources() bool {
+	return len(s.Sources) > 1
+}
+
+// attributeSources records which of each player's sources the round's track
+// came from, and tags the revealed track with all of them. Callers must hold
+// the room lock.
+func (r *GameRoom) attributeSources(result *RoundResult) {
+	if !r.Settings.mixedSources() {
+		return
+	}
+
+	result.Track.Sources = nil
+	result.SourceAttribution = make(map[string][]auth.TrackSource)
+	for playerID, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if track.ID != result.Track.ID {
+				continue
+			}
+			sources := trackSources(track)
+			result.SourceAttribution[playerID] = sources
+			for _, source := range sources {
+				if !slices.Contains(result.Track.Sources, source) {
+					result.Track.Sources = append(result.Track.Sources, source)
+				}
+			}
+			break
+		}
+	}
+	// Keep the tags in the standard order whichever player was seen first
+	slices.SortFunc(result.Track.Sources, func(a, b auth.TrackSource) int {
+		return slices.Index(auth.TrackSources, a) - slices.Index(auth.TrackSources, b)
+	})
+}
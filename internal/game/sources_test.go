@@ -0,0 +1,80 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// TestTrackSourcesWeightAndAttribute verifies source weights scale the pool,
+// disabled sources drop out and the reveal says where the track came from
+func TestTrackSourcesWeightAndAttribute(t *testing.T) {
+	h := newGameHarness(t, 4)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.Sources = []auth.TrackSource{auth.SourceTop, auth.SourceSaved}
+	h.room.Settings.SourceWeights = map[auth.TrackSource]int{auth.SourceSaved: 3}
+
+	a, b := harnessPlayer("A", "t1", "t2"), harnessPlayer("B", "t1")
+	a.TopTracks[1].Sources = []auth.TrackSource{auth.SourceSaved}
+	b.TopTracks[0].Sources = []auth.TrackSource{auth.SourceSaved}
+	h.join(a)
+	h.join(b)
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	poolSize := func() int {
+		previous := h.room.pickIndex
+		defer func() { h.room.pickIndex = previous }()
+		size := 0
+		h.room.pickIndex = func(n int) int { size = n; return 0 }
+		h.room.mu.RLock()
+		h.room.selectTrack()
+		h.room.mu.RUnlock()
+		return size
+	}
+	// t1 is shared (2*5) and saved for B (x3); t2 is saved for A (x3)
+	if size := poolSize(); size != 2*5*3+3 {
+		t.Errorf("Expected a weighted pool of 33, got %d", size)
+	}
+	h.room.Settings.Sources = []auth.TrackSource{auth.SourceTop}
+	if size := poolSize(); size != 1 {
+		t.Errorf("Expected only A's top track once saved songs are off, got a pool of %d", size)
+	}
+	h.room.Settings.Sources = []auth.TrackSource{auth.SourceTop, auth.SourceSaved}
+	h.room.Settings.SourceWeights = nil
+
+	h.room.PlayedTracks["t2"] = true
+	h.ready("A")
+	h.ready("B")
+	msgs := h.expect(MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	if track := msgs[3].Payload.(map[string]interface{})["track"].(auth.Track); track.Sources != nil {
+		t.Errorf("Expected sources hidden until the reveal, got %v", track.Sources)
+	}
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	result := msgs[2].Payload.(*RoundResult)
+	want := map[string][]auth.TrackSource{"A": {auth.SourceTop}, "B": {auth.SourceSaved}}
+	if !reflect.DeepEqual(result.SourceAttribution, want) {
+		t.Errorf("Expected attribution %v, got %v", want, result.SourceAttribution)
+	}
+	if !reflect.DeepEqual(result.Track.Sources, []auth.TrackSource{auth.SourceTop, auth.SourceSaved}) {
+		t.Errorf("Expected the revealed track tagged top and saved, got %v", result.Track.Sources)
+	}
+
+	settings := DefaultRoomSettings()
+	for _, bad := range []RoomSettings{
+		{Sources: []auth.TrackSource{"radio"}},
+		{Sources: []auth.TrackSource{auth.SourceTop, auth.SourceTop}},
+		{SourceWeights: map[auth.TrackSource]int{auth.SourceRecent: MaxSourceWeight + 1}},
+	} {
+		settings.Sources, settings.SourceWeights = bad.Sources, bad.SourceWeights
+		if settings.Validate() == nil {
+			t.Errorf("Expected sources %v with weights %v to be rejected", bad.Sources, bad.SourceWeights)
+		}
+	}
+
+	t.Logf("✓ Source weights shape the pool and the reveal attributes each copy")
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"log"
+)
+
+// activePlayerCount returns the number of players taking part in the
+// current game, i.e. everyone but spectators
+func (r *GameRoom) activePlayerCount() int {
+	count := 0
+	for _, p := range r.Players {
+		if !p.IsSpectator {
+			count++
+		}
+	}
+	return count
+}
+
+// promoteSpectators turns players who joined mid-game into full players.
+// A score they already have, e.g. restored from an endless checkpoint, is
+// kept. Callers must hold the room lock.
+func (r *GameRoom) promoteSpectators() {
+	for _, p := range r.Players {
+		if p.IsSpectator {
+			p.IsSpectator = false
+			if _, ok := r.Scores[p.ID]; !ok {
+				r.Scores[p.ID] = 0
+			}
+			log.Printf("Spectator %s promoted to player in room %s", p.Name, r.ID)
+		}
+	}
+}
+
+// SpectatorPredictionPoints are awarded on the spectator leaderboard for
+// predicting a round's winner. They never count towards the real game.
+const SpectatorPredictionPoints = 1
+
+func (r *GameRoom) handlePrediction(payload PredictionPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spectator, exists := r.Players[payload.PlayerID]
+	if !exists {
+		return
+	}
+
+	if !spectator.IsSpectator {
+		r.sendError(spectator.ID, "Only spectators can make predictions")
+		return
+	}
+
+	if r.State != StatePlaying || r.revealing || r.CurrentTrack == nil {
+		r.sendError(spectator.ID, "Predictions are only open while a round is playing")
+		return
+	}
+
+	if target, ok := r.Players[payload.PredictedPlayerID]; !ok || target.IsSpectator {
+		r.sendError(spectator.ID, "You can only predict a player in this game")
+		return
+	}
+
+	// Predictions can change until the round ends
+	r.Predictions[spectator.ID] = payload.PredictedPlayerID
+
+	r.sendToPlayer(spectator.ID, Message{
+		Type: MsgTypePredictionPlaced,
+		Payload: map[string]interface{}{
+			"round":               r.CurrentRound,
+			"predicted_player_id": payload.PredictedPlayerID,
+		},
+	})
+}
+
+// scorePredictions settles spectator predictions against the round winner
+// and attaches them to the result. Callers must hold the room lock.
+func (r *GameRoom) scorePredictions(result *RoundResult) {
+	if len(r.Predictions) == 0 {
+		return
+	}
+
+	for spectatorID, predictedID := range r.Predictions {
+		if _, present := r.Players[spectatorID]; !present {
+			continue
+		}
+		if _, tracked := r.SpectatorScores[spectatorID]; !tracked {
+			r.SpectatorScores[spectatorID] = 0
+		}
+		if predictedID == result.WinnerID {
+			r.SpectatorScores[spectatorID] += SpectatorPredictionPoints
+		}
+	}
+
+	result.SpectatorPredictions = r.Predictions
+	result.SpectatorScores = r.SpectatorScores
+	r.Predictions = make(map[string]string)
+}
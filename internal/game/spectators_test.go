@@ -0,0 +1,40 @@
+package game
+
+import (
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestSpectatorPredictions verifies spectator predictions score on their own leaderboard only
+func TestSpectatorPredictions(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.State = StatePlaying
+	room.CurrentTrack = &auth.Track{ID: "t1"}
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	watcher := newTestPlayer("S")
+	watcher.IsSpectator = true
+	room.Players["S"] = watcher
+
+	room.handlePrediction(PredictionPayload{PlayerID: "A", PredictedPlayerID: "B"})
+	room.handlePrediction(PredictionPayload{PlayerID: "S", PredictedPlayerID: "A"})
+	if len(room.Predictions) != 1 || room.Predictions["S"] != "A" {
+		t.Fatalf("Expected only the spectator's prediction to be recorded, got %v", room.Predictions)
+	}
+
+	result := &RoundResult{WinnerID: "A"}
+	room.scorePredictions(result)
+
+	if result.SpectatorScores["S"] != SpectatorPredictionPoints {
+		t.Errorf("Expected spectator to earn a prediction point, got %v", result.SpectatorScores)
+	}
+	if room.Scores["S"] != 0 {
+		t.Errorf("Predictions must not affect game scores, got %d", room.Scores["S"])
+	}
+	if len(room.Predictions) != 0 {
+		t.Error("Predictions should be cleared after the round")
+	}
+
+	t.Logf("✓ Spectator predictions are scored separately from the game")
+}
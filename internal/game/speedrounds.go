@@ -0,0 +1,26 @@
+package game
+
+// DefaultSpeedRoundMinimum is the shortest a speed round gets, in seconds
+const DefaultSpeedRoundMinimum = 10
+
+// EndlessSpeedRoundStep is how many seconds endless speed rounds lose each
+// round, since there's no last round to shrink towards
+const EndlessSpeedRoundStep = 2
+
+// speedRoundDuration is how long the given round lasts in speed mode: the
+// game's round duration for round 1, shrinking evenly to the minimum by the
+// last round of the game
+func (s RoomSettings) speedRoundDuration(start, round, totalRounds int) int {
+	end := s.SpeedRoundMinimum
+	if round <= 1 || start <= end {
+		return start
+	}
+
+	var seconds int
+	if totalRounds <= 1 {
+		seconds = start - (round-1)*EndlessSpeedRoundStep
+	} else {
+		seconds = start - (start-end)*(round-1)/(totalRounds-1)
+	}
+	return max(seconds, end)
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSpeedRoundDurations verifies the timer shrinks evenly from the round duration to the minimum
+func TestSpeedRoundDurations(t *testing.T) {
+	settings := DefaultRoomSettings()
+	settings.SpeedRounds = true
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("Default speed round settings should be valid: %v", err)
+	}
+
+	want := []int{30, 28, 26, 24, 22, 19, 17, 15, 13, 10}
+	for round, expected := range want {
+		if got := settings.speedRoundDuration(settings.RoundDuration, round+1, 10); got != expected {
+			t.Errorf("Round %d: expected %ds, got %ds", round+1, expected, got)
+		}
+	}
+
+	// Endless games step down and bottom out at the minimum
+	if got := settings.speedRoundDuration(settings.RoundDuration, 3, 0); got != 26 {
+		t.Errorf("Expected endless round 3 to last 26s, got %ds", got)
+	}
+	if got := settings.speedRoundDuration(settings.RoundDuration, 100, 0); got != 10 {
+		t.Errorf("Expected endless rounds to stop at the minimum, got %ds", got)
+	}
+
+	settings.SpeedRoundMinimum = 40
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected a minimum longer than the round duration to be rejected")
+	}
+
+	t.Logf("✓ Speed rounds shrink from 30s to 10s")
+}
+
+// TestSpeedRoundBroadcastsDuration verifies round_started carries each round's shrinking duration
+func TestSpeedRoundBroadcastsDuration(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 3
+	h.room.Settings.SpeedRounds = true
+
+	h.join(harnessPlayer("A", "t1", "t2", "t3"))
+	h.join(harnessPlayer("B", "t4", "t5", "t6"))
+	h.ready("A")
+	h.ready("B")
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+	if got := msgs[5].Payload.(map[string]interface{})["round_duration"]; got != 30 {
+		t.Errorf("Expected round 1 to last 30s, got %v", got)
+	}
+
+	h.guess("A", "A", 0)
+	h.guess("B", "A", 0)
+	msgs = h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeRoundStarted)
+	if got := msgs[3].Payload.(map[string]interface{})["round_duration"]; got != 20 {
+		t.Errorf("Expected round 2 to last 20s, got %v", got)
+	}
+	h.room.mu.Lock()
+	h.room.RoundTimer.Stop()
+	h.room.mu.Unlock()
+
+	t.Logf("✓ round_started carries the shrinking speed round duration")
+}
+
+// TestStartGameRoundDuration verifies a game can override the room's round duration within bounds
+func TestStartGameRoundDuration(t *testing.T) {
+	h := newGameHarness(t, 3)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1", "t2", "t3"))
+	h.join(harnessPlayer("B", "t4", "t5", "t6"))
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined)
+
+	// Ready up without the auto-start so the game waits for start_game
+	h.room.mu.Lock()
+	for _, p := range h.room.Players {
+		p.IsReady = true
+	}
+	h.room.mu.Unlock()
+
+	h.room.handleGameStart(StartGamePayload{PlayerID: "A", RoomID: h.room.ID, TotalRounds: 1, RoundDuration: 5})
+	h.expectQuiet(50 * time.Millisecond)
+	h.room.mu.Lock()
+	state := h.room.State
+	h.room.mu.Unlock()
+	if state != StateWaiting {
+		t.Fatalf("Expected a 5s round duration to be rejected, room is %s", state)
+	}
+
+	h.room.handleGameStart(StartGamePayload{PlayerID: "A", RoomID: h.room.ID, TotalRounds: 1, RoundDuration: 45})
+	msgs := h.expect(MsgTypeGameStarted, MsgTypeRoundStarted)
+	if got := msgs[0].Payload.(map[string]interface{})["round_duration"]; got != 45 {
+		t.Errorf("Expected game_started to carry 45s, got %v", got)
+	}
+	if got := msgs[1].Payload.(map[string]interface{})["round_duration"]; got != 45 {
+		t.Errorf("Expected round 1 to last 45s, got %v", got)
+	}
+	h.room.mu.Lock()
+	h.room.RoundTimer.Stop()
+	h.room.mu.Unlock()
+
+	settings := DefaultRoomSettings()
+	settings.RoundDuration = 121
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected a 121s round duration setting to be rejected")
+	}
+
+	t.Logf("✓ Games override the round duration within 10–120s")
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"slices"
+	"sort"
+)
+
+// guessTally counts a player's guesses over a game, for breaking ties and
+// for their lifetime stats
+type guessTally struct {
+	FirstCorrect int            // rounds where they were the fastest correct guess
+	Correct      int            // correct guesses
+	GuessSeconds float64        // total time taken by correct guesses
+	Guesses      int            // rounds they guessed in
+	Giveaways    map[string]int // artist -> rounds someone guessed their track by them
+}
+
+// Standing is a player's place in a game's final results
+type Standing struct {
+	Rank                int     `json:"rank"`
+	PlayerID            string  `json:"player_id"`
+	Name                string  `json:"name"`
+	Score               int     `json:"score"`
+	FirstCorrect        int     `json:"first_correct"`
+	AverageGuessSeconds float64 `json:"average_guess_seconds"`
+	// Still level with another player after every tie-break; they share the rank
+	SuddenDeath bool `json:"sudden_death,omitempty"`
+}
+
+// tallyGuesses adds a round's guesses to the game's tallies, or takes them
+// back out when sign is -1. Callers must hold the room lock.
+func (r *GameRoom) tallyGuesses(result *RoundResult, sign int) {
+	for _, playerID := range result.guessers {
+		r.tally(playerID).Guesses += sign
+	}
+	for i, playerID := range result.CorrectGuessers {
+		tally := r.tally(playerID)
+		if i == 0 {
+			tally.FirstCorrect += sign
+		}
+		tally.Correct += sign
+		tally.GuessSeconds += float64(sign) * result.GuessDurations[playerID]
+	}
+
+	// The track's artists gave its owner away if anyone else got it. Bonus
+	// rounds ask about an artist rather than a player's track.
+	if result.Bonus || result.WinnerID == "" || !slices.ContainsFunc(result.CorrectGuessers, func(id string) bool {
+		return id != result.WinnerID
+	}) {
+		return
+	}
+	owner := r.tally(result.WinnerID)
+	for _, artist := range result.Track.Artists {
+		owner.Giveaways[artist] += sign
+		if owner.Giveaways[artist] <= 0 {
+			delete(owner.Giveaways, artist)
+		}
+	}
+}
+
+// tally returns the player's tally for the game, starting one if needed.
+// Callers must hold the room lock.
+func (r *GameRoom) tally(playerID string) *guessTally {
+	tally, ok := r.tallies[playerID]
+	if !ok {
+		tally = &guessTally{Giveaways: make(map[string]int)}
+		r.tallies[playerID] = tally
+	}
+	return tally
+}
+
+// standings ranks everyone with a score: highest score first, then most
+// rounds won by the fastest correct guess, then the fastest average correct
+// guess. Players still level after that share a rank and are flagged for
+// sudden death. Callers must hold the room lock.
+func (r *GameRoom) standings() []Standing {
+	standings := make([]Standing, 0, len(r.Scores))
+	correct := make(map[string]int, len(r.Scores))
+	for playerID, score := range r.Scores {
+		standing := Standing{PlayerID: playerID, Score: score}
+		if player, ok := r.Players[playerID]; ok {
+			standing.Name = player.Name
+		}
+		if tally, ok := r.tallies[playerID]; ok && tally.Correct > 0 {
+			standing.FirstCorrect = tally.FirstCorrect
+			standing.AverageGuessSeconds = tally.GuessSeconds / float64(tally.Correct)
+			correct[playerID] = tally.Correct
+		}
+		standings = append(standings, standing)
+	}
+
+	// level reports whether a and b can't be separated; less orders them
+	level := func(a, b Standing) bool {
+		return a.Score == b.Score && a.FirstCorrect == b.FirstCorrect &&
+			a.AverageGuessSeconds == b.AverageGuessSeconds && (correct[a.PlayerID] > 0) == (correct[b.PlayerID] > 0)
+	}
+	less := func(a, b Standing) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.FirstCorrect != b.FirstCorrect {
+			return a.FirstCorrect > b.FirstCorrect
+		}
+		// Without a correct guess there's no average, which ranks last
+		aGuessed, bGuessed := correct[a.PlayerID] > 0, correct[b.PlayerID] > 0
+		if aGuessed != bGuessed {
+			return aGuessed
+		}
+		if a.AverageGuessSeconds != b.AverageGuessSeconds {
+			return a.AverageGuessSeconds < b.AverageGuessSeconds
+		}
+		return a.PlayerID < b.PlayerID
+	}
+	sort.Slice(standings, func(i, j int) bool { return less(standings[i], standings[j]) })
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && level(standings[i-1], standings[i]) {
+			standings[i].Rank = standings[i-1].Rank
+			standings[i].SuddenDeath = true
+			standings[i-1].SuddenDeath = true
+		}
+	}
+	return standings
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"testing"
+)
+
+// TestStandingsTieBreaks verifies ties are broken by first-correct guesses, then average guess time, then flagged
+func TestStandingsTieBreaks(t *testing.T) {
+	room := NewGameRoom("standings-room")
+	room.Scores = map[string]int{"A": 30, "B": 30, "C": 30, "D": 30, "E": 40, "F": 0}
+
+	rounds := []*RoundResult{
+		{CorrectGuessers: []string{"B", "A"}, GuessDurations: map[string]float64{"B": 2, "A": 4}},
+		{CorrectGuessers: []string{"C", "A"}, GuessDurations: map[string]float64{"C": 3, "A": 3}},
+		{CorrectGuessers: []string{"D", "B"}, GuessDurations: map[string]float64{"D": 3, "B": 6}},
+		{CorrectGuessers: []string{"B"}, GuessDurations: map[string]float64{"B": 5}},
+		{CorrectGuessers: []string{"E"}, GuessDurations: map[string]float64{"E": 1}},
+	}
+	for _, result := range rounds {
+		room.tallyGuesses(result, 1)
+	}
+
+	standings := room.standings()
+	order := make([]string, len(standings))
+	for i, s := range standings {
+		order[i] = s.PlayerID
+	}
+	// E leads on points; B has the most first-correct guesses; C and D are
+	// level on everything; A has no first-correct guess; F never guessed
+	want := []string{"E", "B", "C", "D", "A", "F"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, order)
+		}
+	}
+	if standings[2].Rank != 3 || standings[3].Rank != 3 || !standings[2].SuddenDeath || !standings[3].SuddenDeath {
+		t.Errorf("Expected C and D to share 3rd for sudden death, got %+v %+v", standings[2], standings[3])
+	}
+	if standings[4].Rank != 5 || standings[4].SuddenDeath {
+		t.Errorf("Expected A alone in 5th, got %+v", standings[4])
+	}
+	if room.getWinnerID() != "E" {
+		t.Errorf("Expected E to win, got %s", room.getWinnerID())
+	}
+
+	// Voiding E's round takes its first-correct guess back out
+	room.Scores["E"] = 25
+	room.tallyGuesses(rounds[4], -1)
+	if winner := room.getWinnerID(); winner != "B" {
+		t.Errorf("Expected B to win once E's round is voided, got %s", winner)
+	}
+
+	t.Logf("✓ Final standings break ties deterministically")
+}
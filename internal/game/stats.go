@@ -0,0 +1,85 @@
+package game
+
+import "sort"
+
+// MaxGiveawayArtists is how many giveaway artists lifetime stats list
+const MaxGiveawayArtists = 5
+
+// LifetimeStats sums up how a player has done across their saved games.
+// Guess counts and giveaways only cover games saved since they were added.
+type LifetimeStats struct {
+	GamesPlayed         int           `json:"games_played"`
+	Wins                int           `json:"wins"`
+	Guesses             int           `json:"guesses"`
+	CorrectGuesses      int           `json:"correct_guesses"`
+	CorrectRate         float64       `json:"correct_rate"`          // correct guesses per guess, 0-1
+	AverageGuessSeconds float64       `json:"average_guess_seconds"` // over correct guesses
+	GiveawayArtists     []ArtistCount `json:"giveaway_artists"`      // whose tracks get the player guessed most
+}
+
+// ArtistCount is how many rounds an artist's tracks gave a player away in
+type ArtistCount struct {
+	Artist string `json:"artist"`
+	Rounds int    `json:"rounds"`
+}
+
+// Lifetime sums up the player's saved games
+func (h *HistoryStore) Lifetime(playerID string) LifetimeStats {
+	stats := LifetimeStats{GiveawayArtists: make([]ArtistCount, 0)}
+	var guessSeconds float64
+	giveaways := make(map[string]int)
+
+	for _, game := range h.Games(playerID) {
+		stats.GamesPlayed++
+		if game.WinnerID == playerID {
+			stats.Wins++
+		}
+		for _, p := range game.Players {
+			if p.ID != playerID {
+				continue
+			}
+			stats.Guesses += p.Guesses
+			stats.CorrectGuesses += p.Correct
+			guessSeconds += p.GuessSeconds
+			for artist, rounds := range p.Giveaways {
+				giveaways[artist] += rounds
+			}
+		}
+	}
+
+	if stats.Guesses > 0 {
+		stats.CorrectRate = float64(stats.CorrectGuesses) / float64(stats.Guesses)
+	}
+	if stats.CorrectGuesses > 0 {
+		stats.AverageGuessSeconds = guessSeconds / float64(stats.CorrectGuesses)
+	}
+	for artist, rounds := range giveaways {
+		stats.GiveawayArtists = append(stats.GiveawayArtists, ArtistCount{Artist: artist, Rounds: rounds})
+	}
+	sort.Slice(stats.GiveawayArtists, func(i, j int) bool {
+		a, b := stats.GiveawayArtists[i], stats.GiveawayArtists[j]
+		if a.Rounds != b.Rounds {
+			return a.Rounds > b.Rounds
+		}
+		return a.Artist < b.Artist
+	})
+	if len(stats.GiveawayArtists) > MaxGiveawayArtists {
+		stats.GiveawayArtists = stats.GiveawayArtists[:MaxGiveawayArtists]
+	}
+	return stats
+}
+
+// lifetimeStats returns every recorded player's lifetime stats, for the game
+// over screen, or nil without history. Callers must hold the room lock.
+func (r *GameRoom) lifetimeStats() map[string]LifetimeStats {
+	if r.history == nil || r.practice != nil {
+		return nil
+	}
+	stats := make(map[string]LifetimeStats)
+	for _, playerID := range r.PlayerOrder {
+		if player, ok := r.Players[playerID]; ok && !player.IsSpectator && !player.IsBot() {
+			stats[playerID] = r.history.Lifetime(playerID)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLifetimeStats verifies a finished game adds to the players' lifetime
+// stats, and that the game over screen shows them
+func TestLifetimeStats(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	h := newGameHarness(t, 6)
+	h.room.history = history
+	h.room.Settings.TotalRounds = 2
+
+	a, b := harnessPlayer("A", "a1"), harnessPlayer("B", "b1")
+	a.TopTracks[0].Artists = []string{"Artist A"}
+	b.TopTracks[0].Artists = []string{"Artist B"}
+	h.join(a)
+	h.join(b)
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted)
+
+	// A always names the owner; B always names A, so only gets A's track
+	var over map[string]interface{}
+	for round := 1; round <= 2; round++ {
+		h.expect(MsgTypeRoundStarted)
+		h.room.mu.RLock()
+		owner := harnessOwner(h.room.CurrentTrack.ID)
+		h.room.mu.RUnlock()
+		h.guess("A", owner, 2*time.Second)
+		h.guess("B", "A", time.Second)
+		msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+		if round == 2 {
+			msgs = h.expect(MsgTypeGameOver)
+			over = msgs[0].Payload.(map[string]interface{})
+		}
+	}
+
+	stats := over["lifetime_stats"].(map[string]LifetimeStats)
+	if got := stats["A"]; got.GamesPlayed != 1 || got.Guesses != 2 || got.CorrectRate != 1 ||
+		got.AverageGuessSeconds < 1.9 || got.AverageGuessSeconds > 2.1 ||
+		len(got.GiveawayArtists) != 1 || got.GiveawayArtists[0] != (ArtistCount{Artist: "Artist A", Rounds: 1}) {
+		t.Errorf("Unexpected lifetime stats for A: %+v", got)
+	}
+	if got := stats["B"]; got.CorrectGuesses != 1 || got.CorrectRate != 0.5 ||
+		len(got.GiveawayArtists) != 1 || got.GiveawayArtists[0].Artist != "Artist B" {
+		t.Errorf("Unexpected lifetime stats for B: %+v", got)
+	}
+	if got := history.Stats("B"); got.GamesPlayed != 1 || got.CorrectGuesses != 1 {
+		t.Errorf("Expected the export stats to include lifetime stats, got %+v", got)
+	}
+
+	t.Logf("✓ Lifetime stats add up guesses, speed and giveaway artists")
+}
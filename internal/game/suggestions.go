@@ -0,0 +1,96 @@
+package game
+
+import (
+	"fmt"
+)
+
+// MinSuggestionGames is how many games together make someone worth
+// suggesting to play with again
+const MinSuggestionGames = 3
+
+// MaxSuggestions caps how many people are suggested at once
+const MaxSuggestions = 5
+
+// Suggestion is someone a player often plays with, to help regulars get the
+// group back together
+type Suggestion struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Games    int    `json:"games"`
+	RoomID   string `json:"room_id,omitempty"` // where they're playing right now
+	RoomName string `json:"room_name,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Suggestions lists the people the player has played the most games with,
+// leaving out anyone in skip and deleted accounts
+func (h *HistoryStore) Suggestions(playerID string, skip map[string]bool) []Suggestion {
+	suggestions := make([]Suggestion, 0)
+	// Rivals come most frequent first
+	for _, rivalry := range h.Rivals(playerID) {
+		if len(suggestions) == MaxSuggestions || rivalry.Games < MinSuggestionGames {
+			break
+		}
+		if skip[rivalry.OpponentID] || rivalry.OpponentName == DeletedPlayerName {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			PlayerID: rivalry.OpponentID,
+			Name:     rivalry.OpponentName,
+			Games:    rivalry.Games,
+			Message:  fmt.Sprintf("You've played %d games with %s — invite them?", rivalry.Games, rivalry.OpponentName),
+		})
+	}
+	return suggestions
+}
+
+// Suggestions lists the people the player plays with most, with the room
+// each is in right now so the player can join them
+func (rm *RoomManager) Suggestions(playerID string) ([]Suggestion, error) {
+	history, err := rm.History()
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := history.Suggestions(playerID, nil)
+	for _, room := range rm.allRooms() {
+		room.mu.RLock()
+		for i, suggestion := range suggestions {
+			_, present := room.Players[suggestion.PlayerID]
+			if !present || suggestions[i].RoomID != "" {
+				continue
+			}
+			suggestions[i].RoomID = room.ID
+			suggestions[i].RoomName = room.Name
+			suggestions[i].Message = fmt.Sprintf("You've played %d games with %s — they're in %s now, join them?",
+				suggestion.Games, suggestion.Name, room.Name)
+		}
+		room.mu.RUnlock()
+	}
+	return suggestions, nil
+}
+
+// suggestCompanions tells a player who just joined which regulars they could
+// invite. People already in the room aren't suggested. Callers must hold the
+// room lock.
+func (r *GameRoom) suggestCompanions(player *Player) {
+	if r.history == nil || player.IsBot() {
+		return
+	}
+
+	present := make(map[string]bool, len(r.Players))
+	for id := range r.Players {
+		present[id] = true
+	}
+	suggestions := r.history.Suggestions(player.ID, present)
+	if len(suggestions) == 0 {
+		return
+	}
+
+	r.sendToPlayer(player.ID, Message{
+		Type: MsgTypeSuggestions,
+		Payload: map[string]interface{}{
+			"suggestions": suggestions,
+		},
+	})
+}
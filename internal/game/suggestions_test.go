@@ -0,0 +1,47 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSuggestionsFromHistory verifies regular co-players are suggested most
+// frequent first, with the room they're in when they're playing now
+func TestSuggestionsFromHistory(t *testing.T) {
+	manager := NewRoomManager()
+	if err := manager.EnableHistory(t.TempDir()); err != nil {
+		t.Fatalf("Failed to enable history: %v", err)
+	}
+	history, _ := manager.History()
+
+	for i := 0; i < 5; i++ {
+		recordResult(t, history, RecordPlayer{ID: "A", Score: 10}, RecordPlayer{ID: "J", Name: "Jordan", Score: 5})
+	}
+	for i := 0; i < 3; i++ {
+		recordResult(t, history, RecordPlayer{ID: "A", Score: 10}, RecordPlayer{ID: "K", Name: "Kai", Score: 5})
+	}
+	recordResult(t, history, RecordPlayer{ID: "A", Score: 10}, RecordPlayer{ID: "L", Name: "Lee", Score: 5})
+
+	suggestions := history.Suggestions("A", nil)
+	if len(suggestions) != 2 || suggestions[0].PlayerID != "J" || suggestions[1].PlayerID != "K" {
+		t.Fatalf("Expected Jordan then Kai, got %+v", suggestions)
+	}
+	if suggestions[0].Message != "You've played 5 games with Jordan — invite them?" {
+		t.Errorf("Unexpected message %q", suggestions[0].Message)
+	}
+	if skipped := history.Suggestions("A", map[string]bool{"J": true}); len(skipped) != 1 || skipped[0].PlayerID != "K" {
+		t.Errorf("Expected Jordan to be left out when skipped, got %+v", skipped)
+	}
+
+	room, _ := manager.GetRoom("Room 1")
+	room.handlePlayerJoin(newTestPlayer("K"))
+	suggestions, err := manager.Suggestions("A")
+	if err != nil {
+		t.Fatalf("Failed to get suggestions: %v", err)
+	}
+	if suggestions[1].RoomID != "Room 1" || !strings.Contains(suggestions[1].Message, "join them?") || suggestions[0].RoomID != "" {
+		t.Errorf("Expected Kai's current room to be suggested, got %+v", suggestions)
+	}
+
+	t.Logf("✓ Regular co-players are suggested, with where to find them")
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"slices"
+	"sort"
+)
+
+// MaxSummaryRounds caps the rounds a game summary lists; long endless games
+// keep the latest
+const MaxSummaryRounds = 100
+
+const (
+	SuperlativeMostPredictable  = "most_predictable"  // their tracks were guessed the most
+	SuperlativeLeastPredictable = "least_predictable" // their tracks were guessed the least
+	SuperlativeBestReader       = "best_reader"       // guessed everyone else's tracks best
+)
+
+// GameSummary is the post-game breakdown sent with game_over
+type GameSummary struct {
+	Rounds       []RoundSummary `json:"rounds"`
+	FastestGuess *FastestGuess  `json:"fastest_guess,omitempty"`
+	Superlatives []Superlative  `json:"superlatives"`
+}
+
+// RoundSummary is one round of a game summary
+type RoundSummary struct {
+	Round           int                `json:"round"`
+	TrackID         string             `json:"track_id"`
+	TrackName       string             `json:"track_name"`
+	Artists         []string           `json:"artists"`
+	ImageURL        string             `json:"image_url"`
+	OwnerID         string             `json:"owner_id"` // whose track it was
+	CorrectGuessers []string           `json:"correct_guessers"`
+	GuessDurations  map[string]float64 `json:"guess_durations"` // correct guesses, in seconds
+	Voided          bool               `json:"voided,omitempty"`
+}
+
+// FastestGuess is the quickest correct guess of a game
+type FastestGuess struct {
+	PlayerID  string  `json:"player_id"`
+	Name      string  `json:"name"`
+	Round     int     `json:"round"`
+	TrackName string  `json:"track_name"`
+	Seconds   float64 `json:"seconds"`
+}
+
+// Superlative names the player who stood out in one way over a game, from
+// the game's who-knows-whom counts
+type Superlative struct {
+	ID       string  `json:"id"`
+	PlayerID string  `json:"player_id"`
+	Name     string  `json:"name"`
+	Rounds   int     `json:"rounds"`   // guesses it's based on
+	Accuracy float64 `json:"accuracy"` // share of them that were right
+}
+
+// summarizeRound adds a completed round to the game summary.
+// Callers must hold the room lock.
+func (r *GameRoom) summarizeRound(result *RoundResult) {
+	r.roundSummaries = append(r.roundSummaries, RoundSummary{
+		Round:           result.Round,
+		TrackID:         result.Track.ID,
+		TrackName:       result.Track.Name,
+		Artists:         result.Track.Artists,
+		ImageURL:        result.Track.ImageURL,
+		OwnerID:         result.WinnerID,
+		CorrectGuessers: result.CorrectGuessers,
+		GuessDurations:  result.GuessDurations,
+	})
+	if len(r.roundSummaries) > MaxSummaryRounds {
+		r.roundSummaries = r.roundSummaries[1:]
+	}
+}
+
+// voidRoundSummary marks a voided round in the game summary.
+// Callers must hold the room lock.
+func (r *GameRoom) voidRoundSummary(round int) {
+	for i := range r.roundSummaries {
+		if r.roundSummaries[i].Round == round {
+			r.roundSummaries[i].Voided = true
+		}
+	}
+}
+
+// gameSummary breaks the game down round by round, with its fastest guess
+// and superlatives. Callers must hold the room lock.
+func (r *GameRoom) gameSummary() GameSummary {
+	summary := GameSummary{
+		Rounds:       slices.Clone(r.roundSummaries),
+		Superlatives: make([]Superlative, 0),
+	}
+	if summary.Rounds == nil {
+		summary.Rounds = make([]RoundSummary, 0)
+	}
+
+	for _, round := range summary.Rounds {
+		if round.Voided {
+			continue
+		}
+		for _, playerID := range round.CorrectGuessers {
+			seconds := round.GuessDurations[playerID]
+			if summary.FastestGuess == nil || seconds < summary.FastestGuess.Seconds {
+				summary.FastestGuess = &FastestGuess{
+					PlayerID:  playerID,
+					Name:      r.playerName(playerID),
+					Round:     round.Round,
+					TrackName: round.TrackName,
+					Seconds:   seconds,
+				}
+			}
+		}
+	}
+
+	// Who was read best and worst, and who read others best
+	guessedOn := make(map[string][2]int)
+	guessing := make(map[string][2]int)
+	for pair, counts := range r.taste {
+		on, by := guessedOn[pair.target], guessing[pair.guesser]
+		on[0], on[1] = on[0]+counts[0], on[1]+counts[1]
+		by[0], by[1] = by[0]+counts[0], by[1]+counts[1]
+		guessedOn[pair.target], guessing[pair.guesser] = on, by
+	}
+	for _, pick := range []struct {
+		id     string
+		counts map[string][2]int
+		most   bool
+	}{
+		{SuperlativeMostPredictable, guessedOn, true},
+		{SuperlativeLeastPredictable, guessedOn, false},
+		{SuperlativeBestReader, guessing, true},
+	} {
+		if best, ok := r.superlative(pick.id, pick.counts, pick.most); ok {
+			summary.Superlatives = append(summary.Superlatives, best)
+		}
+	}
+	return summary
+}
+
+// superlative picks the player with the highest (or lowest) accuracy among
+// those still in the room, breaking ties by more rounds and then by ID. It
+// takes at least two candidates to stand out. Callers must hold the room lock.
+func (r *GameRoom) superlative(id string, counts map[string][2]int, most bool) (Superlative, bool) {
+	candidates := make([]Superlative, 0, len(counts))
+	for playerID, c := range counts {
+		if _, ok := r.Players[playerID]; !ok || c[0] <= 0 {
+			continue
+		}
+		candidates = append(candidates, Superlative{
+			ID:       id,
+			PlayerID: playerID,
+			Name:     r.playerName(playerID),
+			Rounds:   c[0],
+			Accuracy: float64(c[1]) / float64(c[0]),
+		})
+	}
+	if len(candidates) < 2 {
+		return Superlative{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Accuracy != b.Accuracy {
+			return (a.Accuracy > b.Accuracy) == most
+		}
+		if a.Rounds != b.Rounds {
+			return a.Rounds > b.Rounds
+		}
+		return a.PlayerID < b.PlayerID
+	})
+	return candidates[0], true
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGameSummary verifies game_over breaks the game down by round and
+// picks out the fastest guess and the superlatives
+func TestGameSummary(t *testing.T) {
+	h := newGameHarness(t, 9)
+	h.room.Settings.TotalRounds = 3
+
+	h.join(harnessPlayer("A", "a1"))
+	h.join(harnessPlayer("B", "b1"))
+	h.join(harnessPlayer("C", "c1"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted)
+
+	// A always names the owner; B and C always name A, B faster
+	var summary GameSummary
+	for round := 1; round <= 3; round++ {
+		h.expect(MsgTypeRoundStarted)
+		h.room.mu.RLock()
+		owner := harnessOwner(h.room.CurrentTrack.ID)
+		h.room.mu.RUnlock()
+		h.guess("A", owner, time.Second)
+		h.guess("B", "A", 500*time.Millisecond)
+		h.guess("C", "A", 2*time.Second)
+		h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+		if round == 3 {
+			msgs := h.expect(MsgTypeGameOver)
+			summary = msgs[0].Payload.(map[string]interface{})["summary"].(GameSummary)
+		}
+	}
+
+	if len(summary.Rounds) != 3 || summary.Rounds[0].Round != 1 || summary.Rounds[0].OwnerID != harnessOwner(summary.Rounds[0].TrackID) {
+		t.Fatalf("Expected 3 rounds with their owners, got %+v", summary.Rounds)
+	}
+	for _, round := range summary.Rounds {
+		if want := map[string]int{"A": 3, "B": 1, "C": 1}[round.OwnerID]; len(round.CorrectGuessers) != want {
+			t.Errorf("Expected %d right on %s's track, got %v", want, round.OwnerID, round.CorrectGuessers)
+		}
+	}
+	if fastest := summary.FastestGuess; fastest == nil || fastest.PlayerID != "B" || fastest.TrackName != "Track a1" {
+		t.Errorf("Expected B's guess on A's track to be the fastest, got %+v", fastest)
+	}
+
+	want := map[string]string{
+		SuperlativeMostPredictable:  "A",
+		SuperlativeLeastPredictable: "B",
+		SuperlativeBestReader:       "A",
+	}
+	if len(summary.Superlatives) != len(want) {
+		t.Fatalf("Expected %d superlatives, got %+v", len(want), summary.Superlatives)
+	}
+	for _, s := range summary.Superlatives {
+		if want[s.ID] != s.PlayerID {
+			t.Errorf("Expected %s to go to %s, got %+v", s.ID, want[s.ID], s)
+		}
+	}
+
+	t.Logf("✓ Game over carries the round breakdown, fastest guess and superlatives")
+}
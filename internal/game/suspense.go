@@ -0,0 +1,60 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// MaxSuspenseDelay bounds how long a room can hold back a round's winner, in seconds
+const MaxSuspenseDelay = 10
+
+func (s RoomSettings) suspenseDelay() time.Duration {
+	return time.Duration(s.SuspenseDelay) * time.Second
+}
+
+// holdReveal shows only the track when a round ends and reveals who owned it
+// and who guessed right once the suspense delay has passed. The intermission
+// starts after the reveal. Callers must hold the room lock.
+func (r *GameRoom) holdReveal(result *RoundResult) {
+	delay := r.Settings.suspenseDelay()
+
+	payload := map[string]interface{}{
+		"round":     result.Round,
+		"track":     result.Track,
+		"suspense":  r.Settings.SuspenseDelay,
+		"reveal_at": time.Now().Add(delay),
+	}
+	if result.Bonus {
+		payload["bonus"] = true
+	}
+	r.Broadcast <- Message{
+		Type:    MsgTypeRoundComplete,
+		Payload: payload,
+	}
+
+	round := r.CurrentRound
+	if r.revealTimer != nil {
+		r.revealTimer.Stop()
+	}
+	r.revealTimer = time.AfterFunc(delay, func() {
+		r.revealWinner(round, result)
+	})
+}
+
+// revealWinner broadcasts a held-back round's full results
+func (r *GameRoom) revealWinner(round int, result *RoundResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A reset or restart while in suspense leaves nothing to reveal
+	if r.State != StatePlaying || r.CurrentRound != round {
+		return
+	}
+
+	log.Printf("Room %s: revealing round %d winner", r.ID, round)
+	r.Broadcast <- Message{
+		Type:    MsgTypeRoundWinner,
+		Payload: result,
+	}
+	r.afterReveal()
+}
@@ -0,0 +1,42 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSuspenseHoldsBackWinner verifies round_complete shows only the track until round_winner reveals the rest
+func TestSuspenseHoldsBackWinner(t *testing.T) {
+	h := newGameHarness(t, 11)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.SuspenseDelay = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "B", time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+
+	teaser := msgs[2].Payload.(map[string]interface{})
+	if _, leaked := teaser["winner_id"]; leaked {
+		t.Error("Expected round_complete to hold back the winner")
+	}
+	if teaser["suspense"] != 1 || teaser["round"] != 1 {
+		t.Errorf("Unexpected teaser: %v", teaser)
+	}
+
+	// Nothing more until the suspense delay passes
+	h.expectQuiet(500 * time.Millisecond)
+
+	msgs = h.expect(MsgTypeRoundWinner, MsgTypeGameOver)
+	if result := msgs[0].Payload.(*RoundResult); result.WinnerID == "" || len(result.CorrectGuessers) != 1 {
+		t.Errorf("Expected round_winner to carry the full results, got %+v", result)
+	}
+
+	t.Logf("✓ Suspense mode reveals the track first and the winner after the delay")
+}
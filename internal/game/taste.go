@@ -0,0 +1,121 @@
+package game
+
+import (
+	"sort"
+)
+
+// TasteLink is how well one player read another's taste: of the rounds the
+// target owned that the guesser guessed, how many they got right
+type TasteLink struct {
+	GuesserID string  `json:"guesser_id"`
+	TargetID  string  `json:"target_id"`
+	Rounds    int     `json:"rounds"`
+	Correct   int     `json:"correct"`
+	Accuracy  float64 `json:"accuracy"`
+}
+
+// TasteProfile is a player's "who knows whom" stats across saved games
+type TasteProfile struct {
+	PlayerID string      `json:"player_id"`
+	Knows    []TasteLink `json:"knows"`    // how well they read each opponent
+	KnownBy  []TasteLink `json:"known_by"` // how well each opponent reads them
+}
+
+type tastePair struct {
+	guesser, target string
+}
+
+// tallyTaste adds a round's guesses to the game's who-knows-whom counts, or
+// takes them back out when sign is -1. Callers must hold the room lock.
+func (r *GameRoom) tallyTaste(result *RoundResult, sign int) {
+	if result.WinnerID == "" {
+		return
+	}
+	for _, guesserID := range result.guessers {
+		if guesserID == result.WinnerID {
+			continue
+		}
+		pair := tastePair{guesser: guesserID, target: result.WinnerID}
+		counts := r.taste[pair]
+		counts[0] += sign
+		for _, correctID := range result.CorrectGuessers {
+			if correctID == guesserID {
+				counts[1] += sign
+				break
+			}
+		}
+		r.taste[pair] = counts
+	}
+}
+
+// tasteMatrix returns the game's who-knows-whom links, ordered by guesser
+// then target. Callers must hold the room lock.
+func (r *GameRoom) tasteMatrix() []TasteLink {
+	links := make([]TasteLink, 0, len(r.taste))
+	for pair, counts := range r.taste {
+		if counts[0] <= 0 {
+			continue
+		}
+		links = append(links, newTasteLink(pair, counts[0], counts[1]))
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].GuesserID != links[j].GuesserID {
+			return links[i].GuesserID < links[j].GuesserID
+		}
+		return links[i].TargetID < links[j].TargetID
+	})
+	return links
+}
+
+func newTasteLink(pair tastePair, rounds, correct int) TasteLink {
+	return TasteLink{
+		GuesserID: pair.guesser,
+		TargetID:  pair.target,
+		Rounds:    rounds,
+		Correct:   correct,
+		Accuracy:  float64(correct) / float64(rounds),
+	}
+}
+
+// Taste aggregates who-knows-whom links involving the player across saved
+// games, best readers first
+func (h *HistoryStore) Taste(playerID string) TasteProfile {
+	counts := make(map[tastePair][2]int)
+	for _, game := range h.Games(playerID) {
+		for _, link := range game.Taste {
+			if link.GuesserID != playerID && link.TargetID != playerID {
+				continue
+			}
+			pair := tastePair{guesser: link.GuesserID, target: link.TargetID}
+			c := counts[pair]
+			c[0] += link.Rounds
+			c[1] += link.Correct
+			counts[pair] = c
+		}
+	}
+
+	profile := TasteProfile{
+		PlayerID: playerID,
+		Knows:    make([]TasteLink, 0),
+		KnownBy:  make([]TasteLink, 0),
+	}
+	for pair, c := range counts {
+		link := newTasteLink(pair, c[0], c[1])
+		if pair.guesser == playerID {
+			profile.Knows = append(profile.Knows, link)
+		} else {
+			profile.KnownBy = append(profile.KnownBy, link)
+		}
+	}
+	byAccuracy := func(links []TasteLink) func(i, j int) bool {
+		return func(i, j int) bool {
+			if links[i].Accuracy != links[j].Accuracy {
+				return links[i].Accuracy > links[j].Accuracy
+			}
+			return links[i].Rounds > links[j].Rounds
+		}
+	}
+	sort.SliceStable(profile.Knows, byAccuracy(profile.Knows))
+	sort.SliceStable(profile.KnownBy, byAccuracy(profile.KnownBy))
+	return profile
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTasteMatrix verifies who-knows-whom counts land in game_over and the saved history
+func TestTasteMatrix(t *testing.T) {
+	history, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	h := newGameHarness(t, 21)
+	h.room.history = history
+	h.room.Settings.TotalRounds = 1
+
+	// Only t1 can play and it's A's
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B"))
+	h.join(harnessPlayer("C"))
+	h.ready("A")
+	h.ready("B")
+	h.ready("C")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.guess("C", "B", time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+		MsgTypeRoundComplete, MsgTypeGameOver)
+
+	matrix := msgs[4].Payload.(map[string]interface{})["taste_matrix"].([]TasteLink)
+	if len(matrix) != 2 {
+		t.Fatalf("Expected links for B and C reading A, got %+v", matrix)
+	}
+	if matrix[0].GuesserID != "B" || matrix[0].TargetID != "A" || matrix[0].Accuracy != 1 {
+		t.Errorf("Expected B to have read A perfectly, got %+v", matrix[0])
+	}
+	if matrix[1].GuesserID != "C" || matrix[1].Correct != 0 || matrix[1].Rounds != 1 {
+		t.Errorf("Expected C to have missed A once, got %+v", matrix[1])
+	}
+
+	profile := history.Taste("A")
+	if len(profile.KnownBy) != 2 || profile.KnownBy[0].GuesserID != "B" || len(profile.Knows) != 0 {
+		t.Errorf("Expected A to be known best by B, got %+v", profile)
+	}
+
+	t.Logf("✓ Who-knows-whom is sent at game over and saved for stats")
+}
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"sort"
+)
+
+// validAnswers returns the players a guess may name to be correct. Normally
+// that's only the round's winner; with shared ties on, it's everyone whose
+// rank for the track equals the best. Callers must hold the room lock.
+func (r *GameRoom) validAnswers(allRankings map[string]int, bestRank int, winnerID string) []string {
+	if winnerID == "" {
+		return []string{}
+	}
+	if !r.Settings.ShareTies {
+		return []string{winnerID}
+	}
+
+	answers := make([]string, 0, 1)
+	for playerID, rank := range allRankings {
+		if rank == bestRank {
+			answers = append(answers, playerID)
+		}
+	}
+	sort.Strings(answers)
+	return answers
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSharedTies verifies guessing any player tied for the best rank counts when shared ties are on
+func TestSharedTies(t *testing.T) {
+	for _, share := range []bool{false, true} {
+		h := newGameHarness(t, 17)
+		h.room.Settings.TotalRounds = 1
+		h.room.Settings.ShareTies = share
+
+		// A and B both have t1 as their #1; C doesn't have it
+		h.join(harnessPlayer("A", "t1"))
+		h.join(harnessPlayer("B", "t1"))
+		c := harnessPlayer("C", "t1")
+		c.TopTracks[0].Rank = 9
+		h.join(c)
+		h.ready("A")
+		h.ready("B")
+		h.ready("C")
+		h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+			MsgTypePlayerReady, MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+		h.guess("A", "B", time.Second)
+		h.guess("B", "A", 2*time.Second)
+		h.guess("C", "C", 3*time.Second)
+		msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeGuessReceived,
+			MsgTypeRoundComplete, MsgTypeGameOver)
+
+		result := msgs[3].Payload.(*RoundResult)
+		if result.WinnerID != "A" {
+			t.Errorf("Expected ties to break to the first player ID, got %s", result.WinnerID)
+		}
+		if !share {
+			if len(result.CorrectGuessers) != 1 || result.ValidAnswers != nil {
+				t.Errorf("Without shared ties only the winner counts, got %v (valid %v)", result.CorrectGuessers, result.ValidAnswers)
+			}
+			continue
+		}
+		if len(result.CorrectGuessers) != 2 || result.PointsAwarded["A"] != 15 || result.PointsAwarded["B"] != 10 {
+			t.Errorf("Expected both tied answers to count, got %v %v", result.CorrectGuessers, result.PointsAwarded)
+		}
+		if len(result.ValidAnswers) != 2 || result.ValidAnswers[0] != "A" || result.ValidAnswers[1] != "B" {
+			t.Errorf("Expected valid answers [A B], got %v", result.ValidAnswers)
+		}
+	}
+
+	t.Logf("✓ Shared ties accept any player tied for the best rank")
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// GuessMode selects what players guess each round
+type GuessMode string
+
+const (
+	// GuessPlayer: pick the player whose top track it is
+	GuessPlayer GuessMode = "player"
+	// GuessTitle: type the song title, matched fuzzily
+	GuessTitle GuessMode = "title"
+)
+
+// MaxTitleGuessLength caps typed title guesses
+const MaxTitleGuessLength = 100
+
+var (
+	// "(feat. X)", "[with X]", "ft. X" and friends
+	featuringPattern = regexp.MustCompile(`(?i)[\(\[]\s*(feat\.?|ft\.?|featuring|with)\s[^\)\]]*[\)\]]|\s(feat\.?|ft\.?|featuring)\s.*$`)
+	// " - Remastered 2011", " - Radio Edit"
+	versionSuffixPattern = regexp.MustCompile(`\s+-\s+.*$`)
+	// any other parenthesised or bracketed part, e.g. "(Live)"
+	bracketedPattern = regexp.MustCompile(`[\(\[][^\)\]]*[\)\]]`)
+)
+
+// guessCorrect reports whether a guess is right for the current round.
+// Callers must hold the room lock.
+func (r *GameRoom) guessCorrect(guess Guess, validAnswers []string) bool {
+	if r.guessMode() == GuessTitle && r.activeBonus == nil {
+		if r.CurrentTrack == nil {
+			return false
+		}
+		if titleMatches(guess.GuessedTitle, r.CurrentTrack.Name) {
+			return true
+		}
+		// Playlist games take the artist too
+		if r.playlist != nil {
+			return slices.ContainsFunc(r.CurrentTrack.Artists, func(artist string) bool {
+				return titleMatches(guess.GuessedTitle, artist)
+			})
+		}
+		return false
+	}
+	return slices.Contains(validAnswers, guess.GuessedPlayerID)
+}
+
+// titleMatches decides whether a typed guess names the track. Case,
+// punctuation, featured artists and version suffixes are ignored, and a
+// small number of typos is allowed in longer titles.
+func titleMatches(guess, title string) bool {
+	guess = normalizeTitle(guess)
+	if guess == "" {
+		return false
+	}
+
+	candidates := []string{normalizeTitle(title)}
+	stripped := featuringPattern.ReplaceAllString(title, "")
+	stripped = versionSuffixPattern.ReplaceAllString(stripped, "")
+	candidates = append(candidates, normalizeTitle(stripped))
+	candidates = append(candidates, normalizeTitle(bracketedPattern.ReplaceAllString(stripped, "")))
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		if editDistance(guess, candidate) <= allowedTypos(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTitle lowercases a title and reduces it to letters and digits
+// separated by single spaces
+func normalizeTitle(title string) string {
+	title = strings.ReplaceAll(strings.ToLower(title), "&", " and ")
+
+	var b strings.Builder
+	space := false
+	for _, c := range title {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(c)
+		case c == '\'' || c == '’':
+			// "Don't" and "Dont" are the same guess
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// allowedTypos is the edit distance tolerated for a normalized title: none
+// for very short titles, then one per five characters, at most three
+func allowedTypos(title string) int {
+	length := len([]rune(title))
+	if length <= 4 {
+		return 0
+	}
+	return min(length/5, 3)
+}
+
+// editDistance is the Levenshtein distance between two strings, in runes
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestTitleMatches verifies typed titles are matched ignoring case, punctuation, features and small typos
+func TestTitleMatches(t *testing.T) {
+	tests := []struct {
+		guess string
+		title string
+		want  bool
+	}{
+		{"bohemian rhapsody", "Bohemian Rhapsody - Remastered 2011", true},
+		{"BOHEMIAN RAPSODY", "Bohemian Rhapsody", true},
+		{"dont start now", "Don't Start Now", true},
+		{"Peaches", "Peaches (feat. Daniel Caesar & Giveon)", true},
+		{"stay", "STAY (with Justin Bieber)", true},
+		{"old town road", "Old Town Road ft. Billy Ray Cyrus", true},
+		{"satisfaction", "(I Can't Get No) Satisfaction", true},
+		{"rock and roll", "Rock & Roll", true},
+		{"Despacito", "Despacito - Remix", true},
+		{"hello", "Halo", false},
+		{"yes", "Yet", false},
+		{"bad guy", "Bad Habits", false},
+		{"   ", "Anything", false},
+	}
+
+	for _, tt := range tests {
+		if got := titleMatches(tt.guess, tt.title); got != tt.want {
+			t.Errorf("titleMatches(%q, %q) = %v, want %v", tt.guess, tt.title, got, tt.want)
+		}
+	}
+
+	t.Logf("✓ Title guesses are fuzzily matched")
+}
+
+// TestTitleGuessMode verifies rounds in title mode are scored by typed titles
+func TestTitleGuessMode(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.Players["A"].TopTracks = []auth.Track{{ID: "t1", Name: "Levitating (feat. DaBaby)", Rank: 1}}
+	room.State = StatePlaying
+	room.Settings.GuessMode = GuessTitle
+	room.CurrentTrack = &room.Players["A"].TopTracks[0]
+
+	room.handleGuess(Guess{PlayerID: "A", GuessedTitle: "  "})
+	if len(room.Guesses) != 0 {
+		t.Fatal("An empty title guess should be rejected")
+	}
+
+	room.Guesses["A"] = Guess{PlayerID: "A", GuessedPlayerID: "A", GuessedTitle: "levitatin"}
+	room.Guesses["B"] = Guess{PlayerID: "B", GuessedPlayerID: "A", GuessedTitle: "physical"}
+
+	result := room.calculateRoundResults()
+	if len(result.CorrectGuessers) != 1 || result.CorrectGuessers[0] != "A" {
+		t.Errorf("Expected only A's title guess to count, got %v", result.CorrectGuessers)
+	}
+
+	t.Logf("✓ Title mode scores typed titles instead of player picks")
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"log"
+	"math/rand"
+	"time"
+	"unicode"
+)
+
+// titleRevealShare is the share of a title's letters shown by the end of the
+// round, so the reveal helps without ever giving the whole answer away
+const titleRevealShare = 0.5
+
+// titleMaskRune stands in for letters not revealed yet
+const titleMaskRune = '_'
+
+// titleReveal fills in the current round's masked title a letter at a time
+type titleReveal struct {
+	round    int
+	title    []rune
+	mask     []rune
+	order    []int // positions of hidden letters, in reveal order
+	interval time.Duration
+	ticker   *time.Ticker
+	done     chan struct{}
+}
+
+// maskTitle hides a title's letters and digits, keeping spaces and
+// punctuation so players can see the shape of it
+func maskTitle(title string) (runes, mask []rune, hidden []int) {
+	runes = []rune(title)
+	mask = make([]rune, len(runes))
+	for i, c := range runes {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			mask[i] = titleMaskRune
+			hidden = append(hidden, i)
+		} else {
+			mask[i] = c
+		}
+	}
+	return runes, mask, hidden
+}
+
+// startTitleReveal starts revealing the round's title on a ticker, spread
+// evenly over the round. Reverse rounds have no title to reveal. Callers
+// must hold the room lock.
+func (r *GameRoom) startTitleReveal() {
+	r.stopTitleReveal()
+	if !r.Settings.TitleReveal || r.activeReverse != nil || r.CurrentTrack == nil {
+		return
+	}
+
+	title, mask, hidden := maskTitle(r.CurrentTrack.Name)
+	rand.Shuffle(len(hidden), func(i, j int) { hidden[i], hidden[j] = hidden[j], hidden[i] })
+	letters := int(float64(len(hidden)) * titleRevealShare)
+	if letters == 0 {
+		return
+	}
+
+	interval := r.currentRoundDuration() / time.Duration(letters+1)
+	reveal := &titleReveal{
+		round:    r.CurrentRound,
+		title:    title,
+		mask:     mask,
+		order:    hidden[:letters],
+		interval: interval,
+		ticker:   time.NewTicker(interval),
+		done:     make(chan struct{}),
+	}
+	r.titleReveal = reveal
+
+	go func() {
+		for {
+			select {
+			case <-reveal.ticker.C:
+				if !r.revealTitleLetter(reveal) {
+					reveal.ticker.Stop()
+					return
+				}
+			case <-reveal.done:
+				return
+			}
+		}
+	}()
+}
+
+// stopTitleReveal stops the current round's reveal. Callers must hold the
+// room lock.
+func (r *GameRoom) stopTitleReveal() {
+	if r.titleReveal == nil {
+		return
+	}
+	r.titleReveal.ticker.Stop()
+	close(r.titleReveal.done)
+	r.titleReveal = nil
+}
+
+// revealTitleLetter shows the next letter and broadcasts the title so far.
+// It reports whether there are more letters to come.
+func (r *GameRoom) revealTitleLetter(reveal *titleReveal) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The round may have ended early, or the game been reset
+	if r.titleReveal != reveal || r.State != StatePlaying || r.revealing || len(reveal.order) == 0 {
+		return false
+	}
+
+	position := reveal.order[0]
+	reveal.order = reveal.order[1:]
+	reveal.mask[position] = reveal.title[position]
+	log.Printf("Room %s: revealed title letter %d in round %d", r.ID, position, reveal.round)
+
+	r.Broadcast <- Message{
+		Type: MsgTypeTitleReveal,
+		Payload: map[string]interface{}{
+			"round":     reveal.round,
+			"title":     string(reveal.mask),
+			"remaining": len(reveal.order),
+		},
+	}
+	return len(reveal.order) > 0
+}
+
+// titleMask is the current round's title as revealed so far, or "" when
+// it isn't being revealed. Callers must hold the room lock.
+func (r *GameRoom) titleMask() string {
+	if r.titleReveal == nil {
+		return ""
+	}
+	return string(r.titleReveal.mask)
+}
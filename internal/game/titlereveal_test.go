@@ -0,0 +1,71 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTitleRevealFillsInLetters verifies the masked title keeps its shape,
+// fills in up to half its letters and stops when the round ends
+func TestTitleRevealFillsInLetters(t *testing.T) {
+	if _, mask, hidden := maskTitle("Don't Stop (1979)"); string(mask) != "___'_ ____ (____)" || len(hidden) != 12 {
+		t.Errorf("Expected letters and digits masked, got %q with %d hidden", string(mask), len(hidden))
+	}
+
+	h := newGameHarness(t, 9)
+	h.room.Settings.TotalRounds = 1
+	h.room.Settings.TitleReveal = true
+
+	// Only A owns t1, so the round always plays A's copy with its title
+	a := harnessPlayer("A", "t1")
+	a.TopTracks[0].Name = "Blue Monday"
+	h.join(a)
+	h.join(harnessPlayer("B"))
+	h.ready("A")
+	h.ready("B")
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady,
+		MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	if mask := msgs[5].Payload.(map[string]interface{})["title_mask"]; mask != "____ ______" {
+		t.Fatalf("Expected round_started to carry the masked title, got %v", mask)
+	}
+
+	// Drive the reveal by hand rather than waiting on the ticker
+	h.room.mu.Lock()
+	reveal := h.room.titleReveal
+	reveal.ticker.Stop()
+	h.room.mu.Unlock()
+
+	var title string
+	for more := true; more; {
+		more = h.room.revealTitleLetter(reveal)
+		payload := h.expect(MsgTypeTitleReveal)[0].Payload.(map[string]interface{})
+		title = payload["title"].(string)
+	}
+	if shown := 11 - 1 - strings.Count(title, "_"); shown != 5 {
+		t.Errorf("Expected half of the 10 letters revealed, got %d in %q", shown, title)
+	}
+	for i, c := range title {
+		if c != '_' && c != rune("Blue Monday"[i]) {
+			t.Errorf("Expected revealed letters in place, got %q", title)
+			break
+		}
+	}
+
+	h.room.mu.RLock()
+	snapshot := h.room.stateSnapshot("A")
+	h.room.mu.RUnlock()
+	if snapshot["title_mask"] != title {
+		t.Errorf("Expected the snapshot to carry %q, got %v", title, snapshot["title_mask"])
+	}
+
+	h.guess("A", "A", time.Second)
+	h.guess("B", "A", time.Second)
+	h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeGameOver)
+	if h.room.revealTitleLetter(reveal) {
+		t.Errorf("Expected no more reveals after the round ended")
+	}
+
+	t.Logf("✓ Titles fill in letter by letter up to half and stop with the round")
+}
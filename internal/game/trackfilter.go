@@ -0,0 +1,229 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"roulettify/internal/auth"
+)
+
+// TrackLanguage restricts a room's pool to tracks that look like they're in
+// a given language, e.g. for a "Spanish-language night"
+type TrackLanguage string
+
+const (
+	LanguageAny        TrackLanguage = ""
+	LanguageSpanish    TrackLanguage = "spanish"
+	LanguagePortuguese TrackLanguage = "portuguese"
+	LanguageFrench     TrackLanguage = "french"
+	LanguageGerman     TrackLanguage = "german"
+	LanguageJapanese   TrackLanguage = "japanese"
+	LanguageKorean     TrackLanguage = "korean"
+	LanguageChinese    TrackLanguage = "chinese"
+)
+
+// latinLanguageHints are the accented characters and common title words that
+// mark a Latin-script language. Two or more signals are needed for a match.
+var latinLanguageHints = map[TrackLanguage]struct {
+	chars string
+	words []string
+}{
+	LanguageSpanish: {
+		chars: "ñ¿¡",
+		words: []string{"el", "los", "las", "que", "mi", "tu", "te", "amor", "corazón", "por", "con", "para", "una", "qué", "cómo", "yo", "eres", "quiero", "noche", "vida", "sin"},
+	},
+	LanguagePortuguese: {
+		chars: "ãõ",
+		words: []string{"não", "você", "meu", "minha", "coração", "do", "da", "com", "uma", "pra", "eu", "saudade", "amor", "vida", "sem"},
+	},
+	LanguageFrench: {
+		chars: "èêœ",
+		words: []string{"le", "les", "je", "et", "est", "pas", "une", "des", "mon", "ma", "pour", "avec", "qui", "dans", "moi", "toi", "amour", "c'est"},
+	},
+	LanguageGerman: {
+		chars: "äöüß",
+		words: []string{"der", "die", "das", "und", "ich", "du", "nicht", "ein", "eine", "mein", "ist", "mit", "auf", "für", "liebe"},
+	},
+}
+
+// matchesLanguage guesses whether a track is in the given language from the
+// script and words of its title and artist names
+func matchesLanguage(track auth.Track, language TrackLanguage) bool {
+	if language == LanguageAny {
+		return true
+	}
+
+	text := strings.ToLower(track.Name + " " + strings.Join(track.Artists, " "))
+
+	switch language {
+	case LanguageJapanese:
+		return containsScript(text, unicode.Hiragana, unicode.Katakana)
+	case LanguageKorean:
+		return containsScript(text, unicode.Hangul)
+	case LanguageChinese:
+		return containsScript(text, unicode.Han) && !containsScript(text, unicode.Hiragana, unicode.Katakana)
+	}
+
+	hints, ok := latinLanguageHints[language]
+	if !ok {
+		return false
+	}
+
+	signals := 0
+	for _, r := range text {
+		if strings.ContainsRune(hints.chars, r) {
+			signals++
+		}
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		for _, hint := range hints.words {
+			if word == hint {
+				signals++
+				break
+			}
+		}
+	}
+
+	return signals >= 2
+}
+
+func containsScript(text string, scripts ...*unicode.RangeTable) bool {
+	for _, r := range text {
+		if unicode.IsOneOf(scripts, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMarket reports whether a track is playable in the given market.
+// Tracks without market data are kept rather than guessed away.
+func matchesMarket(track auth.Track, market string) bool {
+	if market == "" || len(track.Markets) == 0 {
+		return true
+	}
+	for _, m := range track.Markets {
+		if m == market {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxGenres is how many genres a room can restrict its pool to
+const MaxGenres = 5
+
+// normalizeGenres lowercases the genres and drops blanks and repeats
+func normalizeGenres(genres []string) ([]string, error) {
+	normalized := []string{}
+	for _, genre := range genres {
+		genre = normalizeGenre(genre)
+		if genre == "" || slices.Contains(normalized, genre) {
+			continue
+		}
+		if len(genre) > 40 {
+			return nil, fmt.Errorf("genre %q is too long", genre)
+		}
+		normalized = append(normalized, genre)
+	}
+	if len(normalized) > MaxGenres {
+		return nil, fmt.Errorf("at most %d genres can be chosen", MaxGenres)
+	}
+	return normalized, nil
+}
+
+// normalizeGenre lowercases a genre and treats hyphens as spaces, so
+// "Hip-Hop" and "hip hop" are the same
+func normalizeGenre(genre string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(strings.ToLower(genre), "-", " ")), " ")
+}
+
+// matchesGenres reports whether any of the track's genres is one of the
+// wanted ones or a style of it: "hip hop" matches "southern hip hop". Tracks
+// without genre data never match a genre filter.
+func matchesGenres(track auth.Track, genres []string) bool {
+	if len(genres) == 0 {
+		return true
+	}
+	for _, genre := range track.Genres {
+		padded := " " + normalizeGenre(genre) + " "
+		for _, wanted := range genres {
+			if strings.Contains(padded, " "+wanted+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTrackFilter reports whether any of the track filters is set
+func (s RoomSettings) hasTrackFilter() bool {
+	return s.Language != LanguageAny || s.Market != "" || len(s.Genres) > 0 || s.Decade != 0
+}
+
+// matchesFilters applies every track filter in the settings
+func (s RoomSettings) matchesFilters(track auth.Track) bool {
+	return matchesLanguage(track, s.Language) && matchesMarket(track, s.Market) &&
+		matchesGenres(track, s.Genres) && matchesDecade(track, s.Decade)
+}
+
+// trackAllowed applies the room's language, market, genre and decade filters
+// when they are in effect for the current game. Callers must hold the room lock.
+func (r *GameRoom) trackAllowed(track auth.Track) bool {
+	if !r.filterActive {
+		return true
+	}
+	return r.Settings.matchesFilters(track)
+}
+
+// filteredPoolSize counts the distinct tracks the active players hold that
+// pass the room's filters. Callers must hold the room lock.
+func (r *GameRoom) filteredPoolSize() int {
+	matching := make(map[string]bool)
+	for _, player := range r.Players {
+		if player.IsSpectator {
+			continue
+		}
+		for _, track := range player.TopTracks {
+			if r.Settings.matchesFilters(track) {
+				matching[track.ID] = true
+			}
+		}
+	}
+	return len(matching)
+}
+
+// applyTrackFilter turns the room's filters on for a new game, falling back
+// to the full pool with a warning when too few of the group's tracks match.
+// Callers must hold the room lock.
+func (r *GameRoom) applyTrackFilter() {
+	r.filterActive = false
+	if !r.Settings.hasTrackFilter() {
+		return
+	}
+
+	needed := r.TotalRounds
+	if needed <= 0 {
+		needed = r.Settings.TotalRounds
+	}
+
+	matching := r.filteredPoolSize()
+	if matching < needed {
+		r.Broadcast <- Message{
+			Type: MsgTypeFilterWarning,
+			Payload: map[string]interface{}{
+				"message":         "Not enough of your tracks match the room's filter, playing from everyone's full library",
+				"matching_tracks": matching,
+				"needed":          needed,
+			},
+		}
+		return
+	}
+
+	r.filterActive = true
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"roulettify/internal/auth"
+)
+
+// TestLanguageHeuristics verifies title and artist heuristics classify tracks by language
+func TestLanguageHeuristics(t *testing.T) {
+	cases := []struct {
+		track    auth.Track
+		language TrackLanguage
+		want     bool
+	}{
+		{auth.Track{Name: "Qué Más Pues", Artists: []string{"J Balvin"}}, LanguageSpanish, false},
+		{auth.Track{Name: "Tú me dejaste de querer", Artists: []string{"C. Tangana"}}, LanguageSpanish, false},
+		{auth.Track{Name: "Despacito", Artists: []string{"Luis Fonsi"}}, LanguageSpanish, false},
+		{auth.Track{Name: "Vivir Mi Vida", Artists: []string{"Marc Anthony"}}, LanguageSpanish, true},
+		{auth.Track{Name: "Llorar por ti, mi amor", Artists: []string{"Someone"}}, LanguageSpanish, true},
+		{auth.Track{Name: "夜に駆ける", Artists: []string{"YOASOBI"}}, LanguageJapanese, true},
+		{auth.Track{Name: "봄날", Artists: []string{"BTS"}}, LanguageKorean, true},
+		{auth.Track{Name: "Blinding Lights", Artists: []string{"The Weeknd"}}, LanguageSpanish, false},
+		{auth.Track{Name: "Blinding Lights", Artists: []string{"The Weeknd"}}, LanguageAny, true},
+	}
+
+	for _, tc := range cases {
+		if got := matchesLanguage(tc.track, tc.language); got != tc.want {
+			t.Errorf("matchesLanguage(%q, %s) = %v, want %v", tc.track.Name, tc.language, got, tc.want)
+		}
+	}
+
+	t.Logf("✓ Language heuristics classify tracks")
+}
+
+// TestTrackFilterFallback verifies an over-aggressive filter falls back to the full pool with a warning
+func TestTrackFilterFallback(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Settings.Language = LanguageKorean
+	room.TotalRounds = 2
+
+	player := newTestPlayer("A")
+	player.TopTracks = []auth.Track{
+		{ID: "1", Name: "봄날"},
+		{ID: "2", Name: "Blinding Lights"},
+	}
+	room.Players["A"] = player
+
+	room.applyTrackFilter()
+	if room.filterActive {
+		t.Error("Filter should fall back when too few tracks match")
+	}
+	if msg := <-room.Broadcast; msg.Type != MsgTypeFilterWarning {
+		t.Errorf("Expected filter_warning, got %s", msg.Type)
+	}
+
+	room.TotalRounds = 1
+	room.applyTrackFilter()
+	if !room.filterActive {
+		t.Fatal("Filter should apply when enough tracks match")
+	}
+	if track := room.selectTrack(); track == nil || track.ID != "1" {
+		t.Errorf("Expected only the Korean track to be selectable, got %v", track)
+	}
+
+	t.Logf("✓ Track filters apply or fall back with a warning")
+}
+
+// TestGenreFilter verifies genre settings are normalized and keep tracks
+// whose artists play a chosen genre or a style of it
+func TestGenreFilter(t *testing.T) {
+	settings := DefaultRoomSettings()
+	settings.Genres = []string{" Hip-Hop", "hip hop", "", "Indie Folk"}
+	if err := settings.Validate(); err != nil || !reflect.DeepEqual(settings.Genres, []string{"hip hop", "indie folk"}) {
+		t.Errorf("Expected normalized genres, got %v (%v)", settings.Genres, err)
+	}
+	settings.Genres = []string{"a", "b", "c", "d", "e", "f"}
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected more than %d genres to be rejected", MaxGenres)
+	}
+
+	room := NewGameRoom("test-room")
+	room.Settings.Genres = []string{"hip hop"}
+	room.TotalRounds = 1
+
+	player := newTestPlayer("A")
+	player.TopTracks = []auth.Track{
+		{ID: "1", Name: "Rap", Genres: []string{"Southern Hip Hop", "trap"}},
+		{ID: "2", Name: "Folk", Genres: []string{"indie folk"}},
+		{ID: "3", Name: "Hiphopera", Genres: []string{"hiphopera"}},
+		{ID: "4", Name: "Unknown"},
+	}
+	room.Players["A"] = player
+
+	if matching := room.filteredPoolSize(); matching != 1 {
+		t.Errorf("Expected 1 hip hop track, got %d", matching)
+	}
+	room.applyTrackFilter()
+	if track := room.selectTrack(); !room.filterActive || track == nil || track.ID != "1" {
+		t.Errorf("Expected only the hip hop track to be selectable, got %v", track)
+	}
+
+	t.Logf("✓ Genre filters match genres and their sub-styles")
+}
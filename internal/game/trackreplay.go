@@ -0,0 +1,35 @@
+package game
+
+import "log"
+
+// handleReplayTrack plays the round's track again once its results are out,
+// so everyone can hear the whole preview before the next round. Each round's
+// track can be replayed once, whoever asks for it.
+func (r *GameRoom) handleReplayTrack(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.Players[playerID]; !exists {
+		return
+	}
+	if r.State != StatePlaying || !r.revealing || r.CurrentTrack == nil {
+		r.sendError(playerID, "The track can only be replayed between rounds")
+		return
+	}
+	if r.trackReplayed {
+		r.sendError(playerID, "This round's track has already been replayed")
+		return
+	}
+	r.trackReplayed = true
+
+	log.Printf("Room %s: replaying round %d's track for %s", r.ID, r.CurrentRound, playerID)
+	r.Broadcast <- Message{
+		Type: MsgTypeTrackReplay,
+		Payload: map[string]interface{}{
+			"round":        r.CurrentRound,
+			"requested_by": playerID,
+			"track":        *r.CurrentTrack,
+			"audio_path":   "/audio/" + r.CurrentTrack.ID,
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// TestReplayTrack verifies the round's track can be replayed once its
+// results are out, and only once per round
+func TestReplayTrack(t *testing.T) {
+	h := newGameHarness(t, 4)
+	h.room.Settings.TotalRounds = 1
+
+	h.join(harnessPlayer("A", "t1"))
+	h.join(harnessPlayer("B", "t2"))
+	h.ready("A")
+	h.ready("B")
+	h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerReady, MsgTypePlayerReady,
+		MsgTypeGameStarted, MsgTypeRoundStarted)
+
+	// Hold the results up so the replay lands between rounds
+	h.room.mu.Lock()
+	h.room.Settings.IntermissionLength = 60
+	h.room.mu.Unlock()
+
+	// Mid-round the track is still a secret
+	h.room.handleReplayTrack("A")
+	h.expectQuiet(50 * time.Millisecond)
+
+	h.guess("A", "nobody", time.Second)
+	h.guess("B", "nobody", time.Second)
+	msgs := h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived, MsgTypeRoundComplete)
+	played := msgs[2].Payload.(*RoundResult).Track
+
+	h.room.handleReplayTrack("B")
+	h.room.handleReplayTrack("A")
+	msgs = h.expect(MsgTypeTrackReplay)
+	replay := msgs[0].Payload.(map[string]interface{})
+	if replay["requested_by"] != "B" || replay["round"] != 1 || replay["track"].(auth.Track).Name != played.Name || replay["audio_path"] != "/audio/"+played.ID {
+		t.Errorf("Expected round 1's revealed track replayed for B, got %v", replay)
+	}
+	h.expectQuiet(50 * time.Millisecond)
+
+	t.Logf("✓ The revealed track can be replayed once between rounds")
+}
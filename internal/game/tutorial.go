@@ -0,0 +1,180 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"roulettify/internal/auth"
+)
+
+// TutorialBots is how many bots a tutorial seats alongside the new player
+const TutorialBots = 2
+
+// tutorialBotDelay is how long the first tutorial bot takes to guess; the
+// second takes twice as long. Tests shorten it.
+var tutorialBotDelay = 6 * time.Second
+
+// Canned tracks the tutorial plays. They're made up, so rounds have no audio.
+var (
+	tutorialNeonHarbor = auth.Track{ID: "tutorial-neon-harbor", Name: "Neon Harbor", Artists: []string{"The Static Tides"}}
+	tutorialPaperSats  = auth.Track{ID: "tutorial-paper-satellites", Name: "Paper Satellites", Artists: []string{"Juniper Vale"}}
+	tutorialGoldenHour = auth.Track{ID: "tutorial-golden-hour", Name: "Golden Hour Static", Artists: []string{"Marlowe & the Kites"}}
+	tutorialArcade     = auth.Track{ID: "tutorial-late-night-arcade", Name: "Late Night Arcade", Artists: []string{"Pixel Choir"}}
+)
+
+// tutorialLibraries are the top tracks of each tutorial seat: the player,
+// then each bot. Ranks are fixed so every round has a known winner.
+var tutorialLibraries = [TutorialBots + 1][]auth.Track{
+	{withRank(tutorialGoldenHour, 1), withRank(tutorialArcade, 7)},
+	{withRank(tutorialNeonHarbor, 1), withRank(tutorialPaperSats, 12)},
+	{withRank(tutorialPaperSats, 3), withRank(tutorialGoldenHour, 20), withRank(tutorialArcade, 30)},
+}
+
+func withRank(track auth.Track, rank int) auth.Track {
+	track.Rank = rank
+	return track
+}
+
+// tutorialRound is one scripted round: the track it plays, the seat that
+// wins it, and what the player is told when it starts and ends. {owner} in
+// the text is replaced with the winner's name.
+type tutorialRound struct {
+	Track auth.Track
+	Owner int // seat: 0 is the player, then each bot
+	Intro string
+	Outro string
+}
+
+var tutorialScript = []tutorialRound{
+	{
+		Track: tutorialNeonHarbor,
+		Owner: 1,
+		Intro: "Each round plays a song from someone's top tracks. Guess whose it is before the timer runs out. This one is {owner}'s #1, so tap {owner}.",
+		Outro: "Right guesses score points, and quicker guesses score more. {owner} had it, so everyone who picked {owner} scored.",
+	},
+	{
+		Track: tutorialPaperSats,
+		Owner: 2,
+		Intro: "When a song is in more than one player's top tracks, whoever ranks it highest wins the round. Two players have this one; {owner} has it at #3.",
+		Outro: "{owner} ranked it highest, so {owner} was the answer. Guessing right round after round builds a streak.",
+	},
+	{
+		Track: tutorialGoldenHour,
+		Owner: 0,
+		Intro: "Sometimes the song is one of yours. This is your #1, so guess yourself.",
+		Outro: "It was yours! Knowing your own taste counts too.",
+	},
+}
+
+// Steps outside the rounds
+const (
+	tutorialWelcome  = "Welcome to Roulettify! You'll play a short practice game against two bots. The songs are made up, so there's no audio. Press ready when you're set."
+	tutorialStarted  = "The game has started: %d rounds. The player list shows everyone's score as you go."
+	tutorialFinished = "That's the game! The player with the most points wins. Join a room to play with friends, or start another tutorial to go again."
+)
+
+// tutorial tracks a tutorial room's seats
+type tutorial struct {
+	seats []string // player IDs: the player, then each bot
+}
+
+// enableTutorial makes the room a scripted tutorial for one player. Callers
+// must hold the room lock or own the room exclusively.
+func (r *GameRoom) enableTutorial() {
+	r.tutorial = &tutorial{}
+	r.practice = &practiceSetup{bots: TutorialBots}
+	r.Settings.TotalRounds = len(tutorialScript)
+}
+
+// seatTutorial gives the player and the bots their canned libraries and
+// welcomes the player. Callers must hold the room lock.
+func (r *GameRoom) seatTutorial(human *Player) {
+	human.TopTracks = append([]auth.Track(nil), tutorialLibraries[0]...)
+	r.tutorial.seats = []string{human.ID}
+
+	profile, _ := GetBotProfile("")
+	for i := 1; i <= TutorialBots; i++ {
+		bot := r.newBot(profile, nil)
+		bot.TopTracks = append([]auth.Track(nil), tutorialLibraries[i]...)
+		r.seatPlayer(bot)
+		r.tutorial.seats = append(r.tutorial.seats, bot.ID)
+	}
+
+	r.tutorialStep(1, "welcome", tutorialWelcome)
+}
+
+// planTutorial lines the script's tracks up as the game's rounds. Callers
+// must hold the room lock.
+func (r *GameRoom) planTutorial() {
+	r.TotalRounds = len(tutorialScript)
+	r.roundPlan = nil
+	for _, round := range tutorialScript {
+		r.roundPlan = append(r.roundPlan, round.Track)
+	}
+}
+
+// tutorialGameStarted explains the game once it starts. Callers must hold
+// the room lock.
+func (r *GameRoom) tutorialGameStarted() {
+	if r.tutorial != nil {
+		r.tutorialStep(2, "game_started", fmt.Sprintf(tutorialStarted, r.TotalRounds))
+	}
+}
+
+// tutorialRoundStep explains the current round as it starts or once its
+// results are in. Callers must hold the room lock.
+func (r *GameRoom) tutorialRoundStep(ended bool) {
+	if r.tutorial == nil || r.CurrentRound < 1 || r.CurrentRound > len(tutorialScript) {
+		return
+	}
+
+	round := tutorialScript[r.CurrentRound-1]
+	step, id, text := 2*r.CurrentRound+1, fmt.Sprintf("round_%d", r.CurrentRound), round.Intro
+	if ended {
+		step, id, text = step+1, id+"_result", round.Outro
+	}
+	if owner, ok := r.Players[r.tutorial.seats[round.Owner]]; ok {
+		text = strings.ReplaceAll(text, "{owner}", owner.Name)
+	}
+	r.tutorialStep(step, id, text)
+}
+
+// finishTutorial sends the closing step. Callers must hold the room lock.
+func (r *GameRoom) finishTutorial() {
+	if r.tutorial != nil {
+		r.tutorialStep(tutorialSteps(), "finished", tutorialFinished)
+	}
+}
+
+// tutorialSteps is how many steps the tutorial has: the welcome, the start,
+// an intro and result per round, and the finish
+func tutorialSteps() int {
+	return 2*len(tutorialScript) + 3
+}
+
+func (r *GameRoom) tutorialStep(step int, id, text string) {
+	r.Broadcast <- Message{
+		Type: MsgTypeTutorialStep,
+		Payload: map[string]interface{}{
+			"step":        step,
+			"total_steps": tutorialSteps(),
+			"id":          id,
+			"round":       r.CurrentRound,
+			"text":        text,
+		},
+	}
+}
+
+// tutorialGuess is a tutorial bot's guess: always right, and the bots answer
+// one after the other. Callers must hold the room lock.
+func (r *GameRoom) tutorialGuess(round BotRound) botDecision {
+	var decision botDecision
+	for i, id := range r.tutorial.seats {
+		if id == round.BotID {
+			decision.delay = time.Duration(i) * tutorialBotDelay
+		}
+	}
+	decision.GuessedPlayerID = round.WinnerID
+	return decision
+}
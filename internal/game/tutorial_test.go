@@ -0,0 +1,59 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTutorialPlaysScriptedGame verifies the tutorial seats its bots, plays
+// its canned tracks with fixed winners and explains each step
+func TestTutorialPlaysScriptedGame(t *testing.T) {
+	previous := tutorialBotDelay
+	tutorialBotDelay = 10 * time.Millisecond
+	t.Cleanup(func() { tutorialBotDelay = previous })
+
+	h := newGameHarness(t, 8)
+	h.room.enableTutorial()
+
+	h.join(harnessPlayer("A", "real-track"))
+	msgs := h.expect(MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypePlayerJoined, MsgTypeTutorialStep)
+	step := msgs[3].Payload.(map[string]interface{})
+	if step["step"] != 1 || step["total_steps"] != 9 || step["id"] != "welcome" {
+		t.Errorf("Expected the welcome step first, got %v", step)
+	}
+
+	h.ready("A")
+	h.expect(MsgTypePlayerReady, MsgTypeGameStarted, MsgTypeTutorialStep)
+
+	seats := h.room.tutorial.seats
+	for i, round := range tutorialScript {
+		msgs = h.expect(MsgTypeRoundStarted, MsgTypeTutorialStep)
+		intro := msgs[1].Payload.(map[string]interface{})
+		if intro["step"] != 2*i+3 || strings.Contains(intro["text"].(string), "{owner}") {
+			t.Errorf("Round %d: unexpected intro %v", i+1, intro)
+		}
+
+		h.expect(MsgTypeGuessReceived, MsgTypeGuessReceived)
+		h.guess("A", seats[round.Owner], time.Second)
+		msgs = h.expect(MsgTypeGuessReceived, MsgTypeRoundComplete, MsgTypeTutorialStep)
+
+		result := msgs[1].Payload.(*RoundResult)
+		if result.Track.ID != round.Track.ID || result.WinnerID != seats[round.Owner] || len(result.CorrectGuessers) != 3 {
+			t.Errorf("Round %d: expected %s won by %s with everyone right, got %s won by %s (%v)",
+				i+1, round.Track.ID, seats[round.Owner], result.Track.ID, result.WinnerID, result.CorrectGuessers)
+		}
+	}
+
+	msgs = h.expect(MsgTypeGameOver, MsgTypeTutorialStep)
+	if step := msgs[1].Payload.(map[string]interface{}); step["id"] != "finished" || step["step"] != 9 {
+		t.Errorf("Expected the finish step last, got %v", step)
+	}
+
+	h.room.handleUpdateSettings(UpdateSettingsPayload{PlayerID: "A", Settings: DefaultRoomSettings()})
+	if h.room.Settings.TotalRounds != len(tutorialScript) {
+		t.Errorf("Expected the tutorial's settings to stay fixed")
+	}
+
+	t.Logf("✓ Tutorials play canned rounds with fixed winners and explain every step")
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"errors"
+	"log"
+)
+
+// VoidableRounds is how many recent completed rounds can still be voided
+const VoidableRounds = 20
+
+var (
+	// ErrRoundNotVoidable is returned for rounds that aren't a recent
+	// completed round of the current game
+	ErrRoundNotVoidable = errors.New("round not found among this game's completed rounds")
+	// ErrRoundAlreadyVoided is returned when voiding a round twice
+	ErrRoundAlreadyVoided = errors.New("round already voided")
+)
+
+// recordRoundResult keeps a completed round so it can be voided later.
+// Callers must hold the room lock.
+func (r *GameRoom) recordRoundResult(result *RoundResult) {
+	r.roundResults[result.Round] = result
+	delete(r.roundResults, result.Round-VoidableRounds)
+}
+
+// voidRound rolls back the points a completed round awarded, from the live
+// scores, the score history, a finished game's session standings and any
+// endless checkpoint, then broadcasts the corrected scoreboard.
+// Callers must hold the room lock.
+func (r *GameRoom) voidRound(round int, voidedBy string) error {
+	if r.State != StatePlaying && r.State != StateGameOver {
+		return ErrRoundNotVoidable
+	}
+	result, exists := r.roundResults[round]
+	if !exists {
+		return ErrRoundNotVoidable
+	}
+	if r.voidedRounds[round] {
+		return ErrRoundAlreadyVoided
+	}
+	r.voidedRounds[round] = true
+
+	previousWinner := r.getWinnerID()
+	rolledBack := make(map[string]int)
+	for playerID, points := range result.PointsAwarded {
+		if _, present := r.Scores[playerID]; !present {
+			continue // left since; their score is gone already
+		}
+		r.Scores[playerID] -= points
+		rolledBack[playerID] = points
+
+		history := r.ScoreHistory[playerID]
+		for i := round - 1; i >= 0 && i < len(history); i++ {
+			history[i] -= points
+		}
+	}
+
+	r.tallyGuesses(result, -1)
+	r.tallyTaste(result, -1)
+	r.tallyOutcome(result, -1)
+	r.storeRound(result, true)
+	r.voidRoundSummary(round)
+
+	// A finished game was already folded into the session scoreboard
+	if r.State == StateGameOver {
+		winnerID := r.getWinnerID()
+		for playerID, points := range rolledBack {
+			if standing, ok := r.SessionScores[playerID]; ok {
+				standing.TotalScore -= points
+			}
+		}
+		if winnerID != previousWinner {
+			if standing, ok := r.SessionScores[previousWinner]; ok {
+				standing.Wins--
+			}
+			if standing, ok := r.SessionScores[winnerID]; ok {
+				standing.Wins++
+			}
+		}
+		r.storeGame(winnerID)
+	}
+
+	if r.Settings.Endless {
+		r.saveEndlessCheckpoint()
+	}
+	r.mirrorState()
+
+	log.Printf("Round %d voided in room %s by %s", round, r.ID, voidedBy)
+	r.recordEvent(EventRoundVoided, voidedBy, map[string]interface{}{
+		"round":              round,
+		"points_rolled_back": rolledBack,
+	})
+
+	r.Broadcast <- Message{
+		Type: MsgTypeRoundVoided,
+		Payload: map[string]interface{}{
+			"round":              round,
+			"voided_by":          voidedBy,
+			"points_rolled_back": rolledBack,
+			"updated_scores":     r.Scores,
+			"score_history":      r.ScoreHistory,
+			"session_scores":     r.SessionScores,
+			"players":            r.getPlayerInfoList(),
+		},
+	}
+	return nil
+}
+
+func (r *GameRoom) handleVoidRound(payload VoidRoundPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payload.PlayerID != r.LeaderID {
+		r.sendError(payload.PlayerID, "Only the leader can void a round")
+		return
+	}
+
+	if err := r.voidRound(payload.Round, payload.PlayerID); err != nil {
+		r.sendError(payload.PlayerID, err.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestVoidRoundRollsBackPoints verifies voiding a round removes its points from scores, history and session standings
+func TestVoidRoundRollsBackPoints(t *testing.T) {
+	room := NewGameRoom("test-room")
+	room.Players["A"] = newTestPlayer("A")
+	room.Players["B"] = newTestPlayer("B")
+	room.PlayerOrder = []string{"A", "B"}
+	room.LeaderID = "A"
+	room.State = StatePlaying
+
+	room.Scores = map[string]int{"A": 15, "B": 25}
+	room.ScoreHistory = map[string][]int{"A": {15, 15}, "B": {10, 25}}
+	room.recordRoundResult(&RoundResult{Round: 1, PointsAwarded: map[string]int{"A": 15, "B": 10}})
+	room.recordRoundResult(&RoundResult{Round: 2, PointsAwarded: map[string]int{"B": 15}})
+
+	room.handleVoidRound(VoidRoundPayload{PlayerID: "B", Round: 1})
+	if len(room.Broadcast) != 0 || room.Scores["A"] != 15 {
+		t.Fatal("Only the leader should be able to void a round")
+	}
+
+	room.handleVoidRound(VoidRoundPayload{PlayerID: "A", Round: 1})
+	msg := <-room.Broadcast
+	if msg.Type != MsgTypeRoundVoided {
+		t.Fatalf("Expected round_voided broadcast, got %s", msg.Type)
+	}
+	if room.Scores["A"] != 0 || room.Scores["B"] != 15 {
+		t.Errorf("Expected scores A=0 B=15, got %v", room.Scores)
+	}
+	if !reflect.DeepEqual(room.ScoreHistory["B"], []int{0, 15}) {
+		t.Errorf("Expected B's history to be corrected to [0 15], got %v", room.ScoreHistory["B"])
+	}
+
+	if err := room.voidRound(1, "A"); err != ErrRoundAlreadyVoided {
+		t.Errorf("Expected voiding twice to fail, got %v", err)
+	}
+	if err := room.voidRound(3, "A"); err != ErrRoundNotVoidable {
+		t.Errorf("Expected an unplayed round to be rejected, got %v", err)
+	}
+
+	// After the game, the session scoreboard and its winner are corrected too
+	room.State = StateGameOver
+	room.SessionScores = map[string]*SessionStanding{
+		"A": {PlayerID: "A", TotalScore: 0, GamesPlayed: 1},
+		"B": {PlayerID: "B", TotalScore: 15, GamesPlayed: 1, Wins: 1},
+	}
+	if err := room.voidRound(2, "admin"); err != nil {
+		t.Fatalf("Failed to void round 2: %v", err)
+	}
+	<-room.Broadcast
+	if room.SessionScores["B"].TotalScore != 0 {
+		t.Errorf("Expected B's session total to drop to 0, got %d", room.SessionScores["B"].TotalScore)
+	}
+
+	t.Logf("✓ Voided rounds roll back their points and broadcast corrected scores")
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// KickVoteDuration is how long a vote-kick stays open
+const KickVoteDuration = 60 * time.Second
+
+// MinPlayersForKick prevents a single player from kicking the only other one
+const MinPlayersForKick = 3
+
+// KickVote tracks an open vote to remove a player
+type KickVote struct {
+	TargetID  string          `json:"target_id"`
+	Voters    map[string]bool `json:"-"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+func (r *GameRoom) handleVoteKick(payload VoteKickPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.Players[payload.PlayerID]; !exists {
+		return
+	}
+
+	target, exists := r.Players[payload.TargetID]
+	if !exists || payload.TargetID == payload.PlayerID {
+		r.sendError(payload.PlayerID, "Invalid vote-kick target")
+		return
+	}
+
+	// The owner keeps their seat, but a vote against them while they lead
+	// passes the leader role on, so an idle owner can't hold up the room
+	if payload.TargetID == r.OwnerID && payload.TargetID != r.LeaderID {
+		r.sendError(payload.PlayerID, "The room owner can't be vote-kicked")
+		return
+	}
+
+	if len(r.Players) < MinPlayersForKick {
+		r.sendError(payload.PlayerID, "Vote-kick needs at least 3 players in the room")
+		return
+	}
+
+	r.pruneKickVotes()
+
+	vote, open := r.KickVotes[payload.TargetID]
+	if !open {
+		vote = &KickVote{
+			TargetID:  payload.TargetID,
+			Voters:    make(map[string]bool),
+			ExpiresAt: time.Now().Add(KickVoteDuration),
+		}
+		r.KickVotes[payload.TargetID] = vote
+		log.Printf("Vote-kick against %s started in room %s", target.Name, r.ID)
+	}
+	vote.Voters[payload.PlayerID] = true
+
+	// Majority of everyone except the target
+	needed := (len(r.Players)-1)/2 + 1
+
+	r.Broadcast <- Message{
+		Type: MsgTypeVoteKickProgress,
+		Payload: map[string]interface{}{
+			"target_id":  payload.TargetID,
+			"votes":      len(vote.Voters),
+			"needed":     needed,
+			"expires_at": vote.ExpiresAt,
+		},
+	}
+
+	if len(vote.Voters) < needed {
+		return
+	}
+
+	if payload.TargetID == r.OwnerID {
+		r.voteOutLeader(target)
+		return
+	}
+
+	log.Printf("Player %s was vote-kicked from room %s", target.Name, r.ID)
+
+	r.Banned[payload.TargetID] = true
+	r.persist()
+	r.sendToPlayer(payload.TargetID, Message{
+		Type: MsgTypePlayerKicked,
+		Payload: map[string]interface{}{
+			"player_id": payload.TargetID,
+		},
+	})
+	r.removePlayer(payload.TargetID, "Removed by vote")
+}
+
+// voteOutLeader passes leadership from the owner to the next person in seat
+// order once a vote against them passes. They lead again when they rejoin.
+// Callers must hold the room lock.
+func (r *GameRoom) voteOutLeader(owner *Player) {
+	delete(r.KickVotes, owner.ID)
+
+	next := r.nextLeader()
+	if next == nil {
+		return
+	}
+	r.transferLeadership(next)
+	log.Printf("Leadership of room %s passed from %s to %s by vote", r.ID, owner.Name, next.Name)
+	r.Broadcast <- Message{
+		Type: MsgTypeRoomUpdated,
+		Payload: map[string]interface{}{
+			"leader_id": r.LeaderID,
+			"players":   r.getPlayerInfoList(),
+		},
+	}
+}
+
+// pruneKickVotes drops votes that have expired. Callers must hold the room lock.
+func (r *GameRoom) pruneKickVotes() {
+	now := time.Now()
+	for targetID, vote := range r.KickVotes {
+		if now.After(vote.ExpiresAt) {
+			delete(r.KickVotes, targetID)
+		}
+	}
+}
+
+// clearKickVotes removes a departing player's votes and any vote against
+// them, closing votes nobody is left voting in. Callers must hold the room lock.
+func (r *GameRoom) clearKickVotes(playerID string) {
+	delete(r.KickVotes, playerID)
+	for targetID, vote := range r.KickVotes {
+		delete(vote.Voters, playerID)
+		if len(vote.Voters) == 0 {
+			delete(r.KickVotes, targetID)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// newKickHarness seats players with the given IDs, the first one leading
+func newKickHarness(t *testing.T, ids ...string) *gameHarness {
+	h := newGameHarness(t, 1)
+	for _, id := range ids {
+		h.join(harnessPlayer(id))
+		h.expect(MsgTypePlayerJoined)
+	}
+	return h
+}
+
+func (h *gameHarness) voteKick(playerID, targetID string) {
+	h.room.handleVoteKick(VoteKickPayload{PlayerID: playerID, TargetID: targetID})
+}
+
+// lastError returns the message of the last error sent to a player, if any
+func lastError(player *Player) string {
+	message := ""
+	for _, msg := range player.replay.entries {
+		if msg.Type == MsgTypeError {
+			message, _ = msg.Payload.(map[string]interface{})["message"].(string)
+		}
+	}
+	return message
+}
+
+// TestVoteKickMajority verifies a vote needs a majority of everyone but the
+// target and bans the target once it passes
+func TestVoteKickMajority(t *testing.T) {
+	h := newKickHarness(t, "A", "B", "C", "D", "E")
+
+	for i, voter := range []string{"A", "B"} {
+		h.voteKick(voter, "E")
+		progress := h.expect(MsgTypeVoteKickProgress)[0].Payload.(map[string]interface{})
+		if progress["votes"] != i+1 || progress["needed"] != 3 {
+			t.Fatalf("Expected %d of 3 votes, got %v of %v", i+1, progress["votes"], progress["needed"])
+		}
+	}
+	if _, seated := h.room.Players["E"]; !seated {
+		t.Fatal("Expected E to stay until a majority votes")
+	}
+
+	h.voteKick("C", "E")
+	h.expect(MsgTypeVoteKickProgress, MsgTypePlayerLeft)
+	if _, seated := h.room.Players["E"]; seated || !h.room.Banned["E"] {
+		t.Errorf("Expected E to be removed and banned")
+	}
+	if len(h.room.KickVotes) != 0 {
+		t.Errorf("Expected the vote to be closed, got %v", h.room.KickVotes)
+	}
+
+	// With 4 players left, 2 of the other 3 are a majority
+	h.voteKick("A", "D")
+	progress := h.expect(MsgTypeVoteKickProgress)[0].Payload.(map[string]interface{})
+	if progress["needed"] != 2 {
+		t.Errorf("Expected 2 votes needed among 4 players, got %v", progress["needed"])
+	}
+
+	t.Logf("✓ Vote-kicks pass on a majority of the other players")
+}
+
+// TestVoteKickMinimumPlayers verifies two players can't vote each other out
+func TestVoteKickMinimumPlayers(t *testing.T) {
+	h := newKickHarness(t, "A", "B")
+
+	h.voteKick("A", "B")
+	h.expectQuiet(50 * time.Millisecond)
+	if lastError(h.room.Players["A"]) != "Vote-kick needs at least 3 players in the room" {
+		t.Errorf("Expected the minimum players error, got %q", lastError(h.room.Players["A"]))
+	}
+	if len(h.room.KickVotes) != 0 {
+		t.Errorf("Expected no vote to open, got %v", h.room.KickVotes)
+	}
+
+	t.Logf("✓ Vote-kicks need at least 3 players")
+}
+
+// TestVoteKickExpiry verifies votes lapse and a new vote starts from scratch
+func TestVoteKickExpiry(t *testing.T) {
+	h := newKickHarness(t, "A", "B", "C", "D", "E")
+
+	h.voteKick("A", "E")
+	h.voteKick("B", "E")
+	h.expect(MsgTypeVoteKickProgress, MsgTypeVoteKickProgress)
+	h.room.KickVotes["E"].ExpiresAt = time.Now().Add(-time.Second)
+
+	h.voteKick("C", "E")
+	progress := h.expect(MsgTypeVoteKickProgress)[0].Payload.(map[string]interface{})
+	if progress["votes"] != 1 {
+		t.Fatalf("Expected the lapsed votes to be dropped, got %v votes", progress["votes"])
+	}
+	if _, seated := h.room.Players["E"]; !seated {
+		t.Error("Expected E to stay after the earlier votes lapsed")
+	}
+
+	t.Logf("✓ Vote-kicks lapse after %v", KickVoteDuration)
+}
+
+// TestVoteKickClearedOnLeave verifies a leaving player's votes, and any vote
+// against them, are dropped
+func TestVoteKickClearedOnLeave(t *testing.T) {
+	h := newKickHarness(t, "A", "B", "C", "D", "E")
+
+	h.voteKick("A", "E")
+	h.voteKick("B", "E")
+	h.voteKick("E", "D")
+	h.expect(MsgTypeVoteKickProgress, MsgTypeVoteKickProgress, MsgTypeVoteKickProgress)
+
+	h.room.handlePlayerLeave("A")
+	h.expect(MsgTypePlayerLeft)
+	if voters := h.room.KickVotes["E"].Voters; len(voters) != 1 || !voters["B"] {
+		t.Errorf("Expected only B's vote against E to remain, got %v", voters)
+	}
+
+	h.room.handlePlayerLeave("E")
+	h.expect(MsgTypePlayerLeft)
+	if len(h.room.KickVotes) != 0 {
+		t.Errorf("Expected the votes for and against E to be dropped, got %v", h.room.KickVotes)
+	}
+
+	t.Logf("✓ Leaving clears a player's kick votes")
+}
+
+// TestVoteKickOwnerLeadership verifies a vote against a leading owner passes
+// leadership on instead of removing them
+func TestVoteKickOwnerLeadership(t *testing.T) {
+	h := newKickHarness(t, "A", "B", "C")
+	h.room.OwnerID = "A"
+
+	h.voteKick("B", "A")
+	h.voteKick("C", "A")
+	msgs := h.expect(MsgTypeVoteKickProgress, MsgTypeVoteKickProgress, MsgTypeRoomUpdated)
+	if leader := msgs[2].Payload.(map[string]interface{})["leader_id"]; leader != "B" {
+		t.Errorf("Expected leadership to pass to B, got %v", leader)
+	}
+	owner, seated := h.room.Players["A"]
+	if !seated || owner.IsLeader || h.room.Banned["A"] {
+		t.Fatalf("Expected A to stay seated, unbanned and no longer leading")
+	}
+	if len(h.room.KickVotes) != 0 {
+		t.Errorf("Expected the vote to be closed, got %v", h.room.KickVotes)
+	}
+
+	// Once they no longer lead, the owner can't be voted against
+	h.voteKick("B", "A")
+	h.expectQuiet(50 * time.Millisecond)
+	if lastError(h.room.Players["B"]) != "The room owner can't be vote-kicked" {
+		t.Errorf("Expected the owner error, got %q", lastError(h.room.Players["B"]))
+	}
+
+	t.Logf("✓ Voting out a leading owner passes leadership on")
+}
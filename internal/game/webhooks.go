@@ -0,0 +1,321 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// WebhookQueueSize is how many deliveries can wait before new ones are
+	// dropped, so a slow endpoint never holds up a game
+	WebhookQueueSize = 100
+	// WebhookAttempts is how many times a delivery is tried
+	WebhookAttempts = 3
+	// webhookTimeout bounds each attempt
+	webhookTimeout = 10 * time.Second
+	// MaxWebhookURLLength bounds the URLs owners can register
+	MaxWebhookURLLength = 2048
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Roulettify-Event"
+	WebhookTimestampHeader = "X-Roulettify-Timestamp"
+	WebhookSignatureHeader = "X-Roulettify-Signature"
+)
+
+// WebhookEventGameOver is the event delivered when a game ends
+const WebhookEventGameOver = "game_over"
+
+var (
+	// ErrNotRoomOwner is returned when someone other than a claimed room's
+	// owner manages its webhook
+	ErrNotRoomOwner = errors.New("only the room's owner can manage its webhook")
+	// ErrInvalidWebhookURL is returned for URLs that aren't public HTTPS
+	ErrInvalidWebhookURL = errors.New("webhook URL must be a public https:// URL")
+	// ErrNoWebhook is returned when a room has no webhook registered
+	ErrNoWebhook = errors.New("no webhook registered for this room")
+)
+
+// Webhook is where a claimed room posts its finished games. Secret signs
+// each delivery so the receiver can check it came from this server.
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the JSON posted when a game ends. Text is a one-line
+// summary, also sent as content, so Slack and Discord incoming webhooks can
+// post it as is.
+type WebhookPayload struct {
+	Event      string     `json:"event"`
+	GameID     string     `json:"game_id"`
+	RoomID     string     `json:"room_id"`
+	RoomName   string     `json:"room_name"`
+	FinishedAt time.Time  `json:"finished_at"`
+	Rounds     int        `json:"rounds"`
+	WinnerID   string     `json:"winner_id"`
+	WinnerName string     `json:"winner_name"`
+	Standings  []Standing `json:"standings"`
+	Text       string     `json:"text"`
+	Content    string     `json:"content"`
+}
+
+type webhookDelivery struct {
+	webhook Webhook
+	body    []byte
+}
+
+// Webhooks delivers rooms' webhook events in the background, retrying
+// failed deliveries with a backoff
+type Webhooks struct {
+	client  *http.Client
+	queue   chan webhookDelivery
+	backoff time.Duration // between attempts, doubling each time
+	// allowLocal lets tests deliver over plain HTTP to loopback addresses
+	allowLocal bool
+}
+
+// NewWebhooks starts a dispatcher that only delivers to public addresses
+func NewWebhooks() *Webhooks {
+	return newWebhooks(false)
+}
+
+func newWebhooks(allowLocal bool) *Webhooks {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowLocal {
+		// Checked on the resolved address, so a public name can't point
+		// deliveries at the server's own network
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("refusing to deliver webhook to %s", host)
+			}
+			return nil
+		}
+	}
+
+	w := &Webhooks{
+		client: &http.Client{
+			Timeout:   webhookTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			// A redirect could lead anywhere; deliveries go where the owner said
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queue:      make(chan webhookDelivery, WebhookQueueSize),
+		backoff:    time.Second,
+		allowLocal: allowLocal,
+	}
+	go w.run()
+	return w
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// validate checks rawURL is somewhere webhooks may be delivered
+func (w *Webhooks) validate(rawURL string) error {
+	if len(rawURL) > MaxWebhookURLLength {
+		return ErrInvalidWebhookURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	if parsed.Scheme == "https" || (w.allowLocal && parsed.Scheme == "http") {
+		return nil
+	}
+	return ErrInvalidWebhookURL
+}
+
+// send queues a delivery, dropping it when the queue is full
+func (w *Webhooks) send(webhook Webhook, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode webhook payload: %v", err)
+		return
+	}
+	select {
+	case w.queue <- webhookDelivery{webhook: webhook, body: body}:
+	default:
+		log.Printf("Webhook deliveries are behind, dropping one for %s", webhookHost(webhook.URL))
+	}
+}
+
+func (w *Webhooks) run() {
+	for delivery := range w.queue {
+		backoff := w.backoff
+		for attempt := 1; ; attempt++ {
+			err := w.deliver(delivery)
+			if err == nil {
+				break
+			}
+			if attempt == WebhookAttempts {
+				log.Printf("Webhook delivery to %s failed, giving up: %v", webhookHost(delivery.webhook.URL), err)
+				break
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+func (w *Webhooks) deliver(delivery webhookDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.webhook.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Roulettify-Webhooks")
+	req.Header.Set(WebhookEventHeader, WebhookEventGameOver)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(delivery.webhook.Secret, timestamp, delivery.body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook returns the signature header value for a delivery: the
+// hex HMAC-SHA256, keyed with the webhook's secret, of the timestamp header,
+// a dot and the body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookHost is the part of a webhook URL that's safe to log; the path
+// often holds a token
+func webhookHost(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		return parsed.Host
+	}
+	return "webhook"
+}
+
+// SetWebhook registers the URL the room posts its finished games to, with a
+// new signing secret. Only a claimed room's owner can set it.
+func (r *GameRoom) SetWebhook(playerID, rawURL string) (Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.store == nil {
+		return Webhook{}, ErrNoRoomStore
+	}
+	if r.OwnerID == "" || r.OwnerID != playerID {
+		return Webhook{}, ErrNotRoomOwner
+	}
+	if r.webhooks == nil {
+		return Webhook{}, ErrInvalidWebhookURL
+	}
+	if err := r.webhooks.validate(rawURL); err != nil {
+		return Webhook{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Webhook{}, err
+	}
+	r.webhook = &Webhook{URL: rawURL, Secret: hex.EncodeToString(secret)}
+	r.persist()
+
+	log.Printf("Room %s: webhook set to %s", r.ID, webhookHost(rawURL))
+	return *r.webhook, nil
+}
+
+// RemoveWebhook stops the room posting its finished games
+func (r *GameRoom) RemoveWebhook(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.OwnerID == "" || r.OwnerID != playerID {
+		return ErrNotRoomOwner
+	}
+	if r.webhook == nil {
+		return ErrNoWebhook
+	}
+	r.webhook = nil
+	r.persist()
+	return nil
+}
+
+// WebhookURL returns the room's webhook URL, without its secret, for the
+// owner
+func (r *GameRoom) WebhookURL(playerID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.OwnerID == "" || r.OwnerID != playerID {
+		return "", ErrNotRoomOwner
+	}
+	if r.webhook == nil {
+		return "", ErrNoWebhook
+	}
+	return r.webhook.URL, nil
+}
+
+// notifyWebhook posts the finished game to the room's webhook. Practice
+// games aren't posted. Callers must hold the room lock.
+func (r *GameRoom) notifyWebhook(winnerID string) {
+	if r.webhook == nil || r.webhooks == nil || r.practice != nil {
+		return
+	}
+
+	payload := WebhookPayload{
+		Event:      WebhookEventGameOver,
+		GameID:     r.GameID,
+		RoomID:     r.ID,
+		RoomName:   r.Name,
+		FinishedAt: time.Now().UTC(),
+		Rounds:     r.CurrentRound,
+		WinnerID:   winnerID,
+		Standings:  r.standings(),
+	}
+	if payload.RoomName == "" {
+		payload.RoomName = r.ID
+	}
+	if winner, ok := r.Players[winnerID]; ok {
+		payload.WinnerName = winner.Name
+	}
+	if payload.WinnerName != "" {
+		payload.Text = fmt.Sprintf("%s won a game in %s with %d points", payload.WinnerName, payload.RoomName, r.Scores[winnerID])
+	} else {
+		payload.Text = fmt.Sprintf("A game finished in %s", payload.RoomName)
+	}
+	payload.Content = payload.Text
+	r.webhooks.send(*r.webhook, payload)
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestWebhookOnGameOver verifies a claimed room's owner can register a
+// webhook and finished games are posted to it, signed
+func TestWebhookOnGameOver(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{header: r.Header, body: body}
+	}))
+	defer server.Close()
+
+	store, err := NewRoomStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open room store: %v", err)
+	}
+	h := newGameHarness(t, 5)
+	h.room.store = store
+	h.room.webhooks = newWebhooks(true)
+
+	if _, err := h.room.SetWebhook("A", server.URL); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected an unclaimed room to refuse a webhook, got %v", err)
+	}
+	h.room.OwnerID = "A"
+	if _, err := h.room.SetWebhook("B", server.URL); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected only the owner to set a webhook, got %v", err)
+	}
+	if _, err := h.room.SetWebhook("A", "ftp://example.com/hook"); !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("Expected a non-HTTP URL to be refused, got %v", err)
+	}
+	webhook, err := h.room.SetWebhook("A", server.URL)
+	if err != nil || webhook.Secret == "" {
+		t.Fatalf("Expected the owner's webhook to be set with a secret, got %v", err)
+	}
+
+	playOneRoundGame(t, h)
+
+	var got delivery
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the finished game to be posted")
+	}
+	timestamp := got.header.Get(WebhookTimestampHeader)
+	if got.header.Get(WebhookSignatureHeader) != SignWebhook(webhook.Secret, timestamp, got.body) {
+		t.Error("Expected the delivery to be signed with the webhook's secret")
+	}
+	if got.header.Get(WebhookEventHeader) != WebhookEventGameOver {
+		t.Errorf("Expected a game_over event, got %q", got.header.Get(WebhookEventHeader))
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if payload.GameID == "" || payload.RoomID != "harness-room" || payload.WinnerID == "" ||
+		len(payload.Standings) != 3 || payload.Text == "" || payload.Content != payload.Text {
+		t.Errorf("Expected the final results, got %+v", payload)
+	}
+
+	// The webhook is kept with the room's record, and can be removed
+	records, _ := store.Load()
+	if len(records) != 1 || records[0].Webhook == nil || records[0].Webhook.URL != server.URL {
+		t.Errorf("Expected the webhook to be persisted, got %+v", records)
+	}
+	if err := h.room.RemoveWebhook("A"); err != nil {
+		t.Errorf("Failed to remove webhook: %v", err)
+	}
+	if _, err := h.room.WebhookURL("A"); !errors.Is(err, ErrNoWebhook) {
+		t.Errorf("Expected no webhook after removal, got %v", err)
+	}
+
+	// Outside tests, only public HTTPS addresses are delivered to
+	strict := NewWebhooks()
+	if err := strict.validate(server.URL); !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("Expected plain HTTP to be refused, got %v", err)
+	}
+	if err := strict.deliver(webhookDelivery{webhook: Webhook{URL: server.URL}}); err == nil {
+		t.Error("Expected delivery to a loopback address to be refused")
+	}
+
+	t.Logf("✓ Finished games are posted, signed, to the owner's webhook")
+}
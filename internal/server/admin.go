@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/gin-gonic/gin"
+
+	"roulettify/internal/game"
+)
+
+// adminCommandTimeout bounds how long an admin request waits on a busy room
+const adminCommandTimeout = 5 * time.Second
+
+// requireAdmin authenticates support staff with the ADMIN_TOKEN, sent as a
+// bearer token or, for WebSocket upgrades, a "token" query param. Admin
+// routes don't exist when no token is configured.
+func (s *Server) requireAdmin(c *gin.Context) {
+	if s.adminToken == "" {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("token")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		s.audit.Record(AuditEntry{
+			Actor:      "unknown",
+			Action:     "auth_failed",
+			Detail:     c.Request.URL.Path,
+			RemoteAddr: c.ClientIP(),
+		})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	actor := c.GetHeader("X-Admin-User")
+	if actor == "" {
+		actor = c.DefaultQuery("admin", "admin")
+	}
+	c.Set("admin", actor)
+	c.Next()
+}
+
+// runAdminCommand sends a command to the room, writes the outcome to the
+// audit log and returns the room's result
+func (s *Server) runAdminCommand(c *gin.Context, room *game.GameRoom, cmd game.AdminCommand) error {
+	cmd.Actor = c.GetString("admin")
+	cmd.Result = make(chan error, 1)
+
+	var err error
+	select {
+	case room.Admin <- cmd:
+		select {
+		case err = <-cmd.Result:
+		case <-time.After(adminCommandTimeout):
+			err = context.DeadlineExceeded
+		}
+	case <-time.After(adminCommandTimeout):
+		err = context.DeadlineExceeded
+	}
+
+	entry := AuditEntry{
+		Actor:      cmd.Actor,
+		Action:     string(cmd.Action),
+		RoomID:     room.ID,
+		Detail:     cmd.Message,
+		RemoteAddr: c.ClientIP(),
+	}
+	if cmd.Action == game.AdminVoidRound {
+		entry.Detail = fmt.Sprintf("round %d", cmd.Round)
+	}
+	if cmd.Action == game.AdminAddBot {
+		entry.Detail = "profile " + cmd.Profile
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.audit.Record(entry)
+
+	return err
+}
+
+// AdminObserveHandler joins a room as an invisible observer over WebSocket
+func (s *Server) AdminObserveHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := websocket.Accept(c.Writer, c.Request, &websocket.AcceptOptions{
+		OriginPatterns: []string{"*"},
+	})
+	if err != nil {
+		log.Printf("Admin WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	if err := s.runAdminCommand(c, room, game.AdminCommand{
+		Action:     game.AdminObserve,
+		Connection: conn,
+	}); err != nil {
+		return
+	}
+
+	// Observers are read-only; block until the admin disconnects
+	<-conn.CloseRead(context.Background()).Done()
+
+	s.runAdminCommand(c, room, game.AdminCommand{
+		Action:     game.AdminUnobserve,
+		Connection: conn,
+	})
+}
+
+// AdminSystemMessageHandler broadcasts a system message to everyone in a room
+func (s *Server) AdminSystemMessageHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Message) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A message is required"})
+		return
+	}
+
+	if err := s.runAdminCommand(c, room, game.AdminCommand{
+		Action:  game.AdminSystemMessage,
+		Message: body.Message,
+	}); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "sent"})
+}
+
+// AdminRepairHandler triggers a state repair: "force_end_round" ends the
+// current round immediately, "rebroadcast_state" re-sends every player a
+// state snapshot
+func (s *Server) AdminRepairHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		Action game.AdminAction `json:"action"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repair request"})
+		return
+	}
+
+	switch body.Action {
+	case game.AdminForceEndRound, game.AdminRebroadcast:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be force_end_round or rebroadcast_state"})
+		return
+	}
+
+	if err := s.runAdminCommand(c, room, game.AdminCommand{Action: body.Action}); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "done"})
+}
+
+// AdminVoidRoundHandler voids a completed round of the current game, e.g.
+// when its audio was broken for half the room, rolling back its points
+func (s *Server) AdminVoidRoundHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil || round <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "round must be a positive round number"})
+		return
+	}
+
+	err = s.runAdminCommand(c, room, game.AdminCommand{
+		Action: game.AdminVoidRound,
+		Round:  round,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, game.ErrRoundNotVoidable):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "voided", "round": round})
+}
+
+// AdminAddBotHandler seats a bot in a waiting room, e.g. to fill out a
+// lobby that's one player short
+func (s *Server) AdminAddBotHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		Profile string `json:"profile"`
+	}
+	c.ShouldBindJSON(&body)
+
+	err = s.runAdminCommand(c, room, game.AdminCommand{
+		Action:  game.AdminAddBot,
+		Profile: body.Profile,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, game.ErrUnknownBotProfile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "added"})
+}
+
+// AdminRoomsHandler lists every room with the players in it
+func (s *Server) AdminRoomsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"rooms": s.roomManager.AdminRooms(),
+	})
+}
+
+// AdminResetRoomHandler abandons a room's game and returns everyone to the
+// lobby, e.g. when a game is wedged in a way repairs can't fix
+func (s *Server) AdminResetRoomHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.runAdminCommand(c, room, game.AdminCommand{Action: game.AdminResetRoom}); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "reset"})
+}
+
+// AdminFlagsHandler lists the instance's feature flags
+func (s *Server) AdminFlagsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"flags": s.roomManager.Flags().All(),
+	})
+}
+
+// AdminSetFlagHandler switches a feature flag on or off
+func (s *Server) AdminSetFlagHandler(c *gin.Context) {
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enabled must be true or false"})
+		return
+	}
+
+	flag := game.Feature(c.Param("flag"))
+	if err := s.roomManager.Flags().Set(flag, *body.Enabled); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.audit.Record(AuditEntry{
+		Actor:      c.GetString("admin"),
+		Action:     "set_flag",
+		Detail:     fmt.Sprintf("%s=%v", flag, *body.Enabled),
+		RemoteAddr: c.ClientIP(),
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"flags": s.roomManager.Flags().All(),
+	})
+}
+
+// AdminReencryptTokensHandler re-encrypts the provider token vault with its
+// primary key after a key rotation, so the old keys can be retired
+func (s *Server) AdminReencryptTokensHandler(c *gin.Context) {
+	if s.tokenVault == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "provider tokens aren't kept on disk, set TOKEN_VAULT_PATH"})
+		return
+	}
+
+	rewritten, err := s.tokenVault.Reencrypt()
+	if err != nil {
+		log.Printf("Failed to re-encrypt the token vault: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.audit.Record(AuditEntry{
+		Actor:      c.GetString("admin"),
+		Action:     "reencrypt_tokens",
+		Detail:     fmt.Sprintf("%d tokens", rewritten),
+		RemoteAddr: c.ClientIP(),
+	})
+	c.JSON(http.StatusOK, gin.H{"reencrypted": rewritten})
+}
+
+// AdminDrainHandler stops the instance creating rooms and starting games
+// ahead of a deploy, and reports how many games are still running
+func (s *Server) AdminDrainHandler(c *gin.Context) {
+	s.roomManager.Drain()
+	s.audit.Record(AuditEntry{
+		Actor:      c.GetString("admin"),
+		Action:     "drain",
+		RemoteAddr: c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "draining",
+		"metrics": s.roomManager.GetMetrics(),
+	})
+}
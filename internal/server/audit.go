@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// AuditEntry records a single privileged action
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	RoomID     string    `json:"room_id,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	RemoteAddr string    `json:"remote_addr"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// auditLog appends entries as JSON lines to a file, and always to the
+// server log so actions are visible even without a configured path
+type auditLog struct {
+	path string
+	file *os.File
+	mu   sync.Mutex
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	if path == "" {
+		return &auditLog{}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &auditLog{path: path, file: file}, nil
+}
+
+func (a *auditLog) Record(entry AuditEntry) {
+	entry.Time = time.Now().UTC()
+
+	log.Printf("AUDIT actor=%s action=%s room=%q detail=%q error=%q",
+		entry.Actor, entry.Action, entry.RoomID, entry.Detail, entry.Error)
+
+	if a.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Failed to encode audit entry: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return // lost in a failed compaction; already in the server log
+	}
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		log.Printf("Failed to write audit entry: %v", err)
+	}
+}
+
+// Compact archives entries older than the policy's cutoff, reopening the
+// file afterwards. Entries recorded meanwhile wait on the lock.
+func (a *auditLog) Compact(policy game.RetentionPolicy) (game.CompactionResult, error) {
+	if a.path == "" || !policy.Enabled() {
+		return game.CompactionResult{}, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return game.CompactionResult{}, nil
+	}
+
+	if err := a.file.Close(); err != nil {
+		return game.CompactionResult{}, err
+	}
+	result, compactErr := game.CompactJSONL(a.path, policy.Cutoff(time.Now()), policy.ArchiveDir, func(line []byte) (time.Time, error) {
+		var entry AuditEntry
+		err := json.Unmarshal(line, &entry)
+		return entry.Time, err
+	})
+
+	file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		a.file = nil
+		return result, fmt.Errorf("failed to reopen audit log: %w", err)
+	}
+	a.file = file
+	return result, compactErr
+}
+
+// Size returns the audit log's size on disk
+func (a *auditLog) Size() int64 {
+	if a.path == "" {
+		return 0
+	}
+	return game.FileSize(a.path)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"roulettify/internal/auth"
+	"roulettify/internal/game"
+)
+
+// DailyStartHandler starts, or resumes, the signed-in user's daily challenge
+// and returns its current round
+func (s *Server) DailyStartHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to play the daily challenge")
+	if !ok {
+		return
+	}
+
+	tracks, err := s.providers.ForPlayer(user.ID).FetchTopTracks(c.Request.Context(), user.ID, auth.PoolOptions{})
+	if err != nil {
+		log.Printf("Failed to fetch top tracks for daily challenge: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top tracks"})
+		return
+	}
+	user.TopTracks = tracks
+
+	round, err := s.roomManager.Daily().Start(user)
+	switch {
+	case errors.Is(err, game.ErrDailyPlayed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, game.ErrDailyNotEnoughTracks):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, round)
+	}
+}
+
+// DailyGuessHandler answers the current round of the signed-in user's daily
+// challenge
+func (s *Server) DailyGuessHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to play the daily challenge")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		TrackID string `json:"track_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.TrackID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "track_id is required"})
+		return
+	}
+
+	result, err := s.roomManager.Daily().Guess(user.ID, body.TrackID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DailyLeaderboardHandler returns a day's daily challenge results, best
+// first. Pass ?date=YYYY-MM-DD for an earlier day.
+func (s *Server) DailyLeaderboardHandler(c *gin.Context) {
+	daily := s.roomManager.Daily()
+	date := c.DefaultQuery("date", daily.Today())
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":    date,
+		"entries": daily.Leaderboard(date),
+	})
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportMeHandler returns everything stored about the signed-in Spotify user:
+// their profile, saved games and stats, stored results, owned rooms and any
+// rooms they're in
+func (s *Server) ExportMeHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to export your data")
+	if !ok {
+		return
+	}
+
+	data, err := s.roomManager.ExportPlayer(user.ID)
+	if err != nil {
+		log.Printf("Failed to export data for player %s: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export your data, please try again"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="roulettify-export.json"`)
+	c.JSON(http.StatusOK, gin.H{
+		"exported_at": time.Now().UTC(),
+		"profile":     user,
+		"data":        data,
+	})
+}
+
+// DeleteMeHandler removes the signed-in Spotify user from every room, ending
+// their sessions, releases rooms they own, anonymizes their saved games and
+// stored results and deletes replays of games they played.
+// Room bans placed on them are kept so deleting an account can't lift one.
+func (s *Server) DeleteMeHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to delete your data")
+	if !ok {
+		return
+	}
+
+	deletion, err := s.roomManager.ForgetPlayer(user.ID)
+	if err != nil {
+		log.Printf("Failed to delete data for player %s: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete your data, please try again"})
+		return
+	}
+	s.providers.ForPlayer(user.ID).Forget(user.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted": deletion,
+	})
+}
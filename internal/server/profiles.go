@@ -0,0 +1,92 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"roulettify/internal/auth"
+	"roulettify/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerProfileHandler returns a player's public profile: their name and
+// Spotify avatar, stats, achievements and recent games. Private profiles
+// are reported as not found.
+func (s *Server) PlayerProfileHandler(c *gin.Context) {
+	profiles, err := s.roomManager.Profiles()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := profiles.Profile(c.Param("id"))
+	if errors.Is(err, game.ErrNoProfile) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load profile"})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfileHandler lets the signed-in Spotify user make their profile
+// and stats private, or public again
+func (s *Server) UpdateProfileHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to change your profile")
+	if !ok {
+		return
+	}
+	profiles, err := s.roomManager.Profiles()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		Private *bool `json:"private"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Private == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "private must be true or false"})
+		return
+	}
+
+	info, err := profiles.SetPrivate(user.ID, *body.Private)
+	if err != nil {
+		log.Printf("Failed to update profile of player %s: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update your profile"})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// rememberPlayer keeps the name and avatar a player signed in with for
+// their profile, when history is enabled
+func (s *Server) rememberPlayer(player *auth.Player) {
+	profiles, err := s.roomManager.Profiles()
+	if err != nil {
+		return
+	}
+	if err := profiles.Remember(player.ID, player.Name, player.AvatarURL); err != nil {
+		log.Printf("Failed to save profile of player %s: %v", player.ID, err)
+	}
+}
+
+// publicPlayers reports whether none of the players has made their profile
+// private, writing a not found response when one has
+func (s *Server) publicPlayers(c *gin.Context, playerIDs ...string) bool {
+	profiles, err := s.roomManager.Profiles()
+	if err != nil {
+		return true
+	}
+	for _, playerID := range playerIDs {
+		if !profiles.Public(playerID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": game.ErrNoProfile.Error()})
+			return false
+		}
+	}
+	return true
+}
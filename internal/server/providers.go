@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"roulettify/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HandleAuth starts signing in with a music provider
+func (s *Server) HandleAuth(provider auth.MusicProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !provider.Enabled() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Signing in with " + provider.Name() + " isn't configured"})
+			return
+		}
+
+		state := uuid.New().String()
+		isProduction := os.Getenv("APP_ENV") == "production"
+		c.SetCookie("oauth_state", state, 600, "/", "", isProduction, true)
+
+		c.Redirect(http.StatusTemporaryRedirect, provider.AuthURL(state))
+	}
+}
+
+// HandleAuthCallback finishes signing in with a music provider. The
+// provider keeps the player's credentials; the browser only gets a session
+// naming the player.
+func (s *Server) HandleAuthCallback(provider auth.MusicProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		storedState, err := c.Cookie("oauth_state")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No state cookie found"})
+			return
+		}
+		if storedState != c.Query("state") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "State mismatch"})
+			return
+		}
+
+		player, err := provider.Exchange(c.Request.Context(), c.Request.URL.Query())
+		if err != nil {
+			log.Printf("Sign-in with %s failed: %v", provider.Name(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign in"})
+			return
+		}
+
+		log.Printf("Player signed in with %s: %s (ID: %s)", provider.Name(), player.Name, player.ID)
+		s.rememberPlayer(player)
+		s.startSession(c, player)
+	}
+}
+
+// providerPlayer sets up a signed-in player, from whichever provider they
+// signed in with, with their profile and the track pool the room draws on
+func (s *Server) providerPlayer(ctx context.Context, playerID string, opts auth.PoolOptions) (*auth.Player, error) {
+	provider := s.providers.ForPlayer(playerID)
+	player, err := provider.FetchProfile(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	s.rememberPlayer(player)
+
+	// Tracks are fetched with the room's time range and sources at join time
+	tracks, err := provider.FetchTopTracks(ctx, playerID, opts)
+	if err != nil {
+		return nil, err
+	}
+	player.TopTracks = tracks
+	return player, nil
+}
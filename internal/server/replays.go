@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"roulettify/internal/game"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+)
+
+// GameReplayHandler returns a saved game's full recording, for clients that
+// pace or scrub through it themselves
+func (s *Server) GameReplayHandler(c *gin.Context) {
+	replay, ok := s.loadReplay(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, replay)
+}
+
+// GameReplayStreamHandler plays a saved game back over a WebSocket with its
+// original timing, as the messages the room broadcast at the time. The
+// optional speed query parameter plays it faster, e.g. ?speed=2.
+func (s *Server) GameReplayStreamHandler(c *gin.Context) {
+	speed := 1.0
+	if raw := c.Query("speed"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > game.MaxReplaySpeed {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "speed must be above 0 and at most " + strconv.Itoa(game.MaxReplaySpeed)})
+			return
+		}
+		speed = parsed
+	}
+
+	replay, ok := s.loadReplay(c)
+	if !ok {
+		return
+	}
+
+	limits := s.roomManager.Limits()
+	if !limits.AcquireConnection() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": game.ErrConnectionLimit.Error()})
+		return
+	}
+	defer limits.ReleaseConnection()
+
+	conn, err := websocket.Accept(c.Writer, c.Request, &websocket.AcceptOptions{
+		OriginPatterns: []string{"*"},
+	})
+	if err != nil {
+		log.Printf("Replay WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	// Viewers only listen; stop playing once they go away
+	ctx := conn.CloseRead(context.Background())
+	err = replay.Play(ctx, speed, func(event game.ReplayEvent) error {
+		return wsjson.Write(ctx, conn, game.Message{
+			Type:    event.Type,
+			Payload: event.Payload,
+			GameID:  replay.GameID,
+		})
+	})
+	if err != nil {
+		return
+	}
+	conn.Close(websocket.StatusNormalClosure, "Replay finished")
+}
+
+// loadReplay reads the game in the request's id parameter, writing an error
+// response when it can't
+func (s *Server) loadReplay(c *gin.Context) (game.GameReplay, bool) {
+	replays, err := s.roomManager.Replays()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return game.GameReplay{}, false
+	}
+
+	replay, err := replays.Load(c.Param("id"))
+	if errors.Is(err, game.ErrNoReplay) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return replay, false
+	}
+	if err != nil {
+		log.Printf("Failed to load replay of game %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load replay"})
+		return replay, false
+	}
+	return replay, true
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// compactionInterval is how often stores are checked for records to archive
+const compactionInterval = 6 * time.Hour
+
+// retention runs the background compaction of the persisted stores and
+// keeps what it last did for the health metrics
+type retention struct {
+	history game.RetentionPolicy
+	audit   game.RetentionPolicy
+
+	mu      sync.Mutex
+	lastRun time.Time
+	last    map[string]game.CompactionResult
+}
+
+// newRetention reads HISTORY_RETENTION_DAYS and AUDIT_RETENTION_DAYS (0 keeps
+// everything) and ARCHIVE_DIR (empty drops expired records)
+func newRetention() *retention {
+	archiveDir := os.Getenv("ARCHIVE_DIR")
+	return &retention{
+		history: game.RetentionPolicy{MaxAge: days(envInt("HISTORY_RETENTION_DAYS")), ArchiveDir: archiveDir},
+		audit:   game.RetentionPolicy{MaxAge: days(envInt("AUDIT_RETENTION_DAYS")), ArchiveDir: archiveDir},
+		last:    make(map[string]game.CompactionResult),
+	}
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// startRetention compacts now and then every compactionInterval, if any
+// store has a retention limit
+func (s *Server) startRetention() {
+	if !s.retention.history.Enabled() && !s.retention.audit.Enabled() {
+		return
+	}
+
+	go func() {
+		for {
+			s.compactStores()
+			time.Sleep(compactionInterval)
+		}
+	}()
+}
+
+// compactStores archives expired records from the game history and the
+// audit log
+func (s *Server) compactStores() {
+	results := make(map[string]game.CompactionResult)
+
+	if history, err := s.roomManager.History(); err == nil {
+		result, err := history.Compact(s.retention.history)
+		if err != nil {
+			log.Printf("Failed to compact game history: %v", err)
+		} else if result.Archived > 0 {
+			log.Printf("Archived %d games to %q, %d kept", result.Archived, result.ArchivePath, result.Kept)
+		}
+		results["history"] = result
+	}
+	// Replays are deleted with the games they recorded, never archived
+	if replays, err := s.roomManager.Replays(); err == nil && s.retention.history.Enabled() {
+		deleted, err := replays.Prune(s.retention.history.Cutoff(time.Now()))
+		if err != nil {
+			log.Printf("Failed to prune replays: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Deleted %d expired replays", deleted)
+		}
+	}
+
+	result, err := s.audit.Compact(s.retention.audit)
+	if err != nil {
+		log.Printf("Failed to compact audit log: %v", err)
+	} else if result.Archived > 0 {
+		log.Printf("Archived %d audit entries to %q, %d kept", result.Archived, result.ArchivePath, result.Kept)
+	}
+	results["audit"] = result
+
+	s.retention.mu.Lock()
+	s.retention.lastRun = time.Now()
+	s.retention.last = results
+	s.retention.mu.Unlock()
+}
+
+// storeMetrics reports the persisted stores' sizes and the last compaction
+func (s *Server) storeMetrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"audit_bytes": s.audit.Size(),
+	}
+	if history, err := s.roomManager.History(); err == nil {
+		games, bytes := history.Size()
+		metrics["history_games"] = games
+		metrics["history_bytes"] = bytes
+	}
+
+	s.retention.mu.Lock()
+	defer s.retention.mu.Unlock()
+	if !s.retention.lastRun.IsZero() {
+		metrics["last_compaction"] = s.retention.lastRun.Unix()
+		metrics["last_compaction_results"] = s.retention.last
+	}
+	return metrics
+}
@@ -3,16 +3,18 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"golang.org/x/oauth2"
 
 	"roulettify/internal/auth"
 	"roulettify/internal/game"
@@ -24,9 +26,14 @@ func (s *Server) RegisterRoutes() http.Handler {
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-User")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Roulettify-Instance")
+		if s.instanceID != "" {
+			// Lets load balancers pin follow-up requests to this instance
+			c.Writer.Header().Set("X-Roulettify-Instance", s.instanceID)
+		}
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -37,14 +44,76 @@ func (s *Server) RegisterRoutes() http.Handler {
 	// Basic routes
 	r.GET("/health", s.HealthCheckHandler)
 	r.GET("/rooms", s.ListRoomsHandler)
+	r.POST("/rooms", s.CreateRoomHandler)
+	r.POST("/rooms/:id/invites", s.CreateInviteHandler)
+	r.POST("/rooms/:id/claim", s.ClaimRoomHandler)
+	r.GET("/rooms/:id/events", s.RoomEventsHandler)
+	r.GET("/rooms/:id/leaderboard", s.RoomLeaderboardHandler)
+	r.POST("/rooms/:id/overlay", s.CreateOverlayHandler)
+	r.GET("/rooms/:id/overlay", s.OverlayHandler)
+	r.PUT("/rooms/:id/webhook", s.SetWebhookHandler)
+	r.GET("/rooms/:id/webhook", s.WebhookHandler)
+	r.DELETE("/rooms/:id/webhook", s.RemoveWebhookHandler)
+	r.GET("/debug/connections/:id", s.DebugConnectionHandler)
+
+	// Your data
+	r.GET("/me/export", s.ExportMeHandler)
+	r.GET("/me/suggestions", s.SuggestionsHandler)
+	r.PUT("/me/profile", s.UpdateProfileHandler)
+	r.DELETE("/me", s.DeleteMeHandler)
+
+	// Stats
+	r.GET("/players/:id/profile", s.PlayerProfileHandler)
+	r.GET("/players/:id/stats", s.PlayerStatsHandler)
+	r.GET("/players/:id/rivals", s.PlayerRivalsHandler)
+	r.GET("/players/:id/taste", s.PlayerTasteHandler)
+	r.GET("/players/:id/vs/:opponent", s.PlayerMatchupHandler)
+	r.GET("/stats/deceiving", s.DeceivingStatsHandler)
+	r.GET("/games/:id", s.GameHandler)
+	r.GET("/games/:id/card.svg", s.GameCardHandler)
+	r.GET("/games/:id/replay", s.GameReplayHandler)
+	r.GET("/games/:id/replay/stream", s.GameReplayStreamHandler)
+
+	// Daily challenge
+	r.POST("/daily/start", s.DailyStartHandler)
+	r.POST("/daily/guess", s.DailyGuessHandler)
+	r.GET("/daily/leaderboard", s.DailyLeaderboardHandler)
 
-	// Spotify OAuth routes
-	r.GET("/auth/spotify", s.HandleSpotifyAuth)
-	r.GET("/auth/callback", s.HandleSpotifyCallback)
+	// Seasons
+	r.GET("/seasons", s.SeasonsHandler)
+	r.GET("/seasons/:id", s.SeasonHandler)
+
+	// Sign-in routes, one pair per music provider. Spotify's callback
+	// predates the others and keeps its path.
+	for _, provider := range s.providers.All() {
+		callback := "/auth/" + provider.Name() + "/callback"
+		if provider == s.providers.Spotify() {
+			callback = "/auth/callback"
+		}
+		r.GET("/auth/"+provider.Name(), s.HandleAuth(provider))
+		r.GET(callback, s.HandleAuthCallback(provider))
+	}
+
+	// Audio proxy
+	r.GET("/audio/:trackID", s.AudioProxyHandler)
 
 	// WebSocket route
 	r.GET("/ws", s.HandleWebSocket)
 
+	// Support tooling, every action is audited
+	admin := r.Group("/admin", s.requireAdmin)
+	admin.GET("/rooms", s.AdminRoomsHandler)
+	admin.POST("/rooms/:id/reset", s.AdminResetRoomHandler)
+	admin.GET("/flags", s.AdminFlagsHandler)
+	admin.PUT("/flags/:flag", s.AdminSetFlagHandler)
+	admin.POST("/drain", s.AdminDrainHandler)
+	admin.POST("/tokens/reencrypt", s.AdminReencryptTokensHandler)
+	admin.GET("/rooms/:id/observe", s.AdminObserveHandler)
+	admin.POST("/rooms/:id/messages", s.AdminSystemMessageHandler)
+	admin.POST("/rooms/:id/repair", s.AdminRepairHandler)
+	admin.POST("/rooms/:id/rounds/:round/void", s.AdminVoidRoundHandler)
+	admin.POST("/rooms/:id/bots", s.AdminAddBotHandler)
+
 	// Serve static files
 	r.Static("/assets", "./dist/assets")
 	r.StaticFile("/favicon.ico", "./dist/favicon.ico")
@@ -59,6 +128,7 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 func (s *Server) HealthCheckHandler(c *gin.Context) {
 	metrics := s.roomManager.GetMetrics()
+	metrics["stores"] = s.storeMetrics()
 	c.JSON(http.StatusOK, gin.H{
 		"status":    "healthy",
 		"timestamp": time.Now().Unix(),
@@ -67,84 +137,455 @@ func (s *Server) HealthCheckHandler(c *gin.Context) {
 }
 
 func (s *Server) ListRoomsHandler(c *gin.Context) {
-	rooms := s.roomManager.ListRooms()
+	filter := game.RoomFilter{
+		State:    game.GameState(c.Query("state")),
+		Region:   c.Query("region"),
+		OpenOnly: c.Query("open") == "true",
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "0"))
+
+	rooms, total := filter.Apply(s.roomManager.ListRooms())
 	c.JSON(http.StatusOK, gin.H{
-		"rooms": rooms,
+		"rooms":     rooms,
+		"total":     total,
+		"page":      max(filter.Page, 1),
+		"page_size": filter.PageSize,
 	})
 }
 
-// HandleSpotifyAuth initiates the Spotify OAuth flow
-func (s *Server) HandleSpotifyAuth(c *gin.Context) {
-	state := uuid.New().String()
-	isProduction := os.Getenv("APP_ENV") == "production"
-	c.SetCookie("oauth_state", state, 600, "/", "", isProduction, true)
+// CreateRoomHandler creates a dynamic room, e.g. a host-less drop-in room
+func (s *Server) CreateRoomHandler(c *gin.Context) {
+	var opts game.RoomOptions
+	if err := c.ShouldBindJSON(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room options"})
+		return
+	}
+
+	room, err := s.roomManager.CreateRoom(opts)
+	if errors.Is(err, game.ErrInvalidRoomOptions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("Created room %s %q in region %q (hostless: %v)", room.ID, room.Name, room.Region, opts.Hostless)
+	c.JSON(http.StatusCreated, gin.H{
+		"room": room.Info(),
+	})
+}
+
+// CreateInviteHandler issues a signed, single-use invite link for a room to
+// a signed-in player who is in it or owns it.
+// The optional "ttl" query param is in minutes (default 60, max 1440).
+func (s *Server) CreateInviteHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to invite people")
+	if !ok {
+		return
+	}
+
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := room.CanInvite(user.ID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttlMinutes, err := strconv.Atoi(c.DefaultQuery("ttl", "60"))
+	if err != nil || ttlMinutes <= 0 || ttlMinutes > 1440 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ttl must be between 1 and 1440 minutes"})
+		return
+	}
+
+	token, expiresAt := s.invites.Issue(room.ID, time.Duration(ttlMinutes)*time.Minute)
+
+	inviteURL := frontendURL() + "/?" + url.Values{
+		"room":   {room.ID},
+		"invite": {token},
+	}.Encode()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invite_url": inviteURL,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+// ClaimRoomHandler makes the signed-in Spotify user the owner of a dynamic
+// room they are in
+func (s *Server) ClaimRoomHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to claim a room")
+	if !ok {
+		return
+	}
+
+	room, err := s.roomManager.ClaimRoom(c.Param("id"), user.ID)
+	switch {
+	case err == nil:
+	case errors.Is(err, game.ErrNoRoomStore):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, game.ErrRoomAlreadyOwned):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, game.ErrRoomNotClaimable), errors.Is(err, game.ErrNotInRoom):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room": room.Info(),
+	})
+}
+
+// signedInPlayer identifies the Spotify user from their session token, sent
+// as a bearer token or in the session cookie, responding 401 and returning
+// false when there isn't a valid one
+func (s *Server) signedInPlayer(c *gin.Context, missing string) (*auth.Player, bool) {
+	sessionToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if sessionToken == "" {
+		sessionToken, _ = c.Cookie(auth.SessionCookie)
+	}
+	if sessionToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": missing})
+		return nil, false
+	}
+
+	session, err := s.sessions.Verify(sessionToken)
+	if errors.Is(err, auth.ErrSessionExpired) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Your session has expired, sign in again"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session"})
+		return nil, false
+	}
+	user, err := s.providers.ForPlayer(session.Subject).FetchProfile(c.Request.Context(), session.Subject)
+	if err != nil {
+		log.Printf("%s %s rejected: %v", c.Request.Method, c.Request.URL.Path, err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Your sign-in has lapsed, sign in again"})
+		return nil, false
+	}
+	s.rememberPlayer(user)
+	return user, true
+}
+
+// CreateOverlayHandler gives a signed-in player in the room the URL of its
+// stream overlay feed
+func (s *Server) CreateOverlayHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to stream a room")
+	if !ok {
+		return
+	}
+
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := room.OverlayToken(user.ID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"overlay_url": "/rooms/" + room.ID + "/overlay?" + url.Values{"token": {token}}.Encode(),
+		"token":       token,
+	})
+}
+
+// OverlayHandler returns a compact view of a room for stream overlays such
+// as an OBS browser source: the round, when it ends, the scoreboard and the
+// last reveal. The token from CreateOverlayHandler is sent as a "token"
+// query param or a bearer token.
+func (s *Server) OverlayHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	overlay, err := room.Overlay(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Overlays poll, so every response should be fresh
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, overlay)
+}
+
+// RoomLeaderboardHandler returns the all-time leaderboard of a persistent or
+// claimed room, best first
+func (s *Server) RoomLeaderboardHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	standings, err := room.Leaderboard()
+	switch {
+	case errors.Is(err, game.ErrNoRoomStore):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, game.ErrNoLeaderboard):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room_id":     room.ID,
+		"leaderboard": standings,
+	})
+}
+
+// DebugConnectionHandler returns the diagnostics report for a debug client's
+// connection. The ID is only given to that client, in its session message.
+func (s *Server) DebugConnectionHandler(c *gin.Context) {
+	report, err := s.roomManager.Diagnostics().Report(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, report)
+}
+
+// RoomEventsHandler returns a room's recent history: joins, leaves and game
+// and round results. Pass ?since=<seq> to get only newer events.
+func (s *Server) RoomEventsHandler(c *gin.Context) {
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a non-negative event sequence number"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room_id": room.ID,
+		"events":  room.Events(since),
+	})
+}
 
-	authURL := s.spotifyAuth.GetAuthURL(state)
+// PlayerStatsHandler returns a player's lifetime stats across saved games
+func (s *Server) PlayerStatsHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.Redirect(http.StatusTemporaryRedirect, authURL)
+	if !s.publicPlayers(c, c.Param("id")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player_id": c.Param("id"),
+		"stats":     history.Lifetime(c.Param("id")),
+	})
 }
 
-// HandleSpotifyCallback handles the OAuth callback from Spotify
-func (s *Server) HandleSpotifyCallback(c *gin.Context) {
-	code := c.Query("code")
-	state := c.Query("state")
+// PlayerRivalsHandler returns a player's head-to-head records against
+// everyone they've played, most frequent opponents first
+func (s *Server) PlayerRivalsHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !s.publicPlayers(c, c.Param("id")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player_id": c.Param("id"),
+		"rivals":    history.Rivals(c.Param("id")),
+	})
+}
+
+// PlayerMatchupHandler returns two players' head-to-head stats across the
+// games they played together, from the first player's side
+func (s *Server) PlayerMatchupHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Param("id") == c.Param("opponent") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pick two different players"})
+		return
+	}
+	if !s.publicPlayers(c, c.Param("id"), c.Param("opponent")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, history.Matchup(c.Param("id"), c.Param("opponent")))
+}
+
+// SuggestionsHandler returns the people the signed-in Spotify user plays
+// with most, and the rooms they're in right now
+func (s *Server) SuggestionsHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to see suggestions")
+	if !ok {
+		return
+	}
+
+	suggestions, err := s.roomManager.Suggestions(user.ID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+	})
+}
 
-	storedState, err := c.Cookie("oauth_state")
+// PlayerTasteHandler returns how well a player reads each opponent's taste,
+// and how well each opponent reads theirs, across saved games
+func (s *Server) PlayerTasteHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No state cookie found"})
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if !s.publicPlayers(c, c.Param("id")) {
 		return
 	}
 
-	if storedState != state {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "State mismatch"})
+	c.JSON(http.StatusOK, history.Taste(c.Param("id")))
+}
+
+// DeceivingStatsHandler returns the tracks and artists that draw the most
+// wrong guesses across every saved game
+func (s *Server) DeceivingStatsHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
 
-	token, err := s.spotifyAuth.ExchangeCode(c.Request.Context(), code)
+	c.JSON(http.StatusOK, history.Deceiving())
+}
+
+// GameHandler returns a saved game by its game ID
+func (s *Server) GameHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
 	if err != nil {
-		log.Printf("Token exchange failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange code"})
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
 
-	spotifyClient := s.spotifyAuth.NewClient(c.Request.Context(), token)
+	record, ok := history.Game(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
 
-	player, err := auth.FetchPlayerInfo(c.Request.Context(), spotifyClient)
+// GameCardHandler renders a saved game's final standings as a shareable SVG
+// card, with the winning track's cover art embedded
+func (s *Server) GameCardHandler(c *gin.Context) {
+	history, err := s.roomManager.History()
 	if err != nil {
-		log.Printf("Failed to fetch player info: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player info"})
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	record, ok := history.Game(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
 		return
 	}
 
-	log.Printf("Player info fetched: %s (ID: %s)", player.Name, player.ID)
+	var art []byte
+	var artType string
+	if record.WinningTrack != nil && record.WinningTrack.ImageURL != "" {
+		art, artType, err = auth.FetchAlbumArt(c.Request.Context(), record.WinningTrack.ImageURL)
+		if err != nil {
+			log.Printf("Result card for game %s without cover art: %v", record.GameID, err)
+		}
+	}
 
-	topTracks, err := auth.FetchPlayerTopTracks(c.Request.Context(), spotifyClient)
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "image/svg+xml", game.RenderResultCard(record, art, artType))
+}
+
+// AudioProxyHandler serves a track's preview audio. Clients on constrained
+// connections get a low-bitrate variant with ?quality=low or Save-Data: on.
+func (s *Server) AudioProxyHandler(c *gin.Context) {
+	quality := auth.AudioQualityStandard
+	if c.Query("quality") == string(auth.AudioQualityLow) || c.GetHeader("Save-Data") == "on" {
+		quality = auth.AudioQualityLow
+	}
+
+	data, err := s.providers.PreviewAudio(c.Request.Context(), c.Param("trackID"), quality)
 	if err != nil {
-		log.Printf("Failed to fetch top tracks: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top tracks"})
+		log.Printf("Audio proxy failed for track %s: %v", c.Param("trackID"), err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Preview not available"})
 		return
 	}
 
-	player.AccessToken = token.AccessToken
-	player.TopTracks = topTracks
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "audio/mpeg", data)
+}
 
+// startSession signs the player in: their session goes in an HttpOnly
+// cookie and their profile in one the UI can read. The browser is sent back
+// to the frontend.
+func (s *Server) startSession(c *gin.Context, player *auth.Player) {
+	sessionToken, _ := s.sessions.Issue(player.ID, player.Name, auth.SessionTTL)
 	playerJSON, _ := json.Marshal(map[string]interface{}{
-		"id":           player.ID,
-		"name":         player.Name,
-		"spotify_id":   player.SpotifyID,
-		"access_token": token.AccessToken,
+		"id":         player.ID,
+		"name":       player.Name,
+		"spotify_id": player.SpotifyID,
 	})
 
 	c.SetCookie("oauth_state", "", -1, "/", "", false, true)
 	isProduction := os.Getenv("APP_ENV") == "production"
-	c.SetCookie("player_session", string(playerJSON), 3600, "/", "", isProduction, false)
+	maxAge := int(auth.SessionTTL.Seconds())
+	// Lax keeps the session cookie off cross-site requests, WebSocket
+	// upgrades included. player_session is only the profile, for the UI.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(auth.SessionCookie, sessionToken, maxAge, "/", "", isProduction, true)
+	c.SetCookie("player_session", string(playerJSON), maxAge, "/", "", isProduction, false)
 
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://127.0.0.1:5173"
-	}
+	c.Redirect(http.StatusTemporaryRedirect, frontendURL()+"/?auth=success")
+}
 
-	c.Redirect(http.StatusTemporaryRedirect, frontendURL+"/?auth=success")
+func frontendURL() string {
+	if u := os.Getenv("FRONTEND_URL"); u != "" {
+		return u
+	}
+	return "http://127.0.0.1:5173"
 }
 
 // HandleWebSocket handles WebSocket connections for the game
@@ -152,6 +593,14 @@ func (s *Server) HandleWebSocket(c *gin.Context) {
 	w := c.Writer
 	r := c.Request
 
+	limits := s.roomManager.Limits()
+	if !limits.AcquireConnection() {
+		log.Printf("Rejecting WebSocket connection: %v", game.ErrConnectionLimit)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": game.ErrConnectionLimit.Error()})
+		return
+	}
+	defer limits.ReleaseConnection()
+
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		OriginPatterns: []string{"*"},
 	})
@@ -162,10 +611,17 @@ func (s *Server) HandleWebSocket(c *gin.Context) {
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
 
+	// Browsers send their session cookie with the upgrade
+	sessionCookie, _ := c.Cookie(auth.SessionCookie)
+
 	ctx := context.Background()
 	var currentRoom *game.GameRoom
 	var currentPlayer *game.Player
 
+	// Connection quality is measured for whichever seat the connection holds
+	stopMonitor := func() {}
+	defer func() { stopMonitor() }()
+
 	// Message handling loop
 	for {
 		var msg game.Message
@@ -177,26 +633,103 @@ func (s *Server) HandleWebSocket(c *gin.Context) {
 
 		switch msg.Type {
 		case game.MsgTypeJoinRoom:
-			currentRoom, currentPlayer = s.handleJoinRoom(ctx, conn, msg.Payload)
+			currentRoom, currentPlayer = s.handleJoinRoom(ctx, conn, msg.Payload, sessionCookie)
+			stopMonitor()
+			if currentPlayer != nil {
+				monitorCtx, cancel := context.WithCancel(ctx)
+				stopMonitor = cancel
+				go monitorConnection(monitorCtx, conn, currentRoom, currentPlayer)
+			}
 
 		case game.MsgTypeReady:
 			s.handlePlayerReady(currentRoom, currentPlayer, msg.Payload)
 			
 		case game.MsgTypeStartGame:
-			s.handleStartGame(currentRoom, msg.Payload)
+			s.handleStartGame(currentRoom, currentPlayer, msg.Payload)
 			
 		case game.MsgTypeSubmitGuess:
 			s.handleSubmitGuess(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeUsePowerup:
+			s.handleUsePowerup(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeSetPassword:
+			s.handleSetPassword(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeUpdateSettings:
+			s.handleUpdateSettings(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeVoteEnd:
+			s.handleVoteEnd(currentRoom, currentPlayer)
+
+		case game.MsgTypeResetSession:
+			s.handleResetSession(currentRoom, currentPlayer)
+
+		case game.MsgTypeQuickRematch:
+			s.handleQuickRematch(currentRoom, currentPlayer)
+
+		case game.MsgTypeRematch:
+			s.handleRematch(currentRoom, currentPlayer)
+
+		case game.MsgTypeReplayTrack:
+			s.handleReplayTrack(currentRoom, currentPlayer)
+
+		case game.MsgTypeVoteKick:
+			s.handleVoteKick(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeSendEmote:
+			s.handleSendEmote(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypePredict:
+			s.handlePredict(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeExcludeTracks:
+			s.handleExcludeTracks(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeChat:
+			s.handleChat(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeVoidRound:
+			s.handleVoidRound(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeMiniGameAnswer:
+			s.handleMiniGameAnswer(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeAddBot:
+			s.handleAddBot(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeSetHandicap:
+			s.handleSetHandicap(currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypeSetPlaylist:
+			s.handleSetPlaylist(ctx, conn, currentRoom, currentPlayer, msg.Payload)
+
+		case game.MsgTypePauseGame, game.MsgTypeResumeGame:
+			s.handlePause(currentRoom, currentPlayer, msg.Type == game.MsgTypePauseGame)
+
+		case game.MsgTypeDebugReport:
+			s.handleDebugReport(currentPlayer, msg.Payload)
+
+		case game.MsgTypeLeaveRoom:
+			if currentRoom != nil && currentPlayer != nil {
+				currentRoom.Leave <- currentPlayer.ID
+				currentRoom, currentPlayer = nil, nil
+				stopMonitor()
+			}
 		}
 	}
 
-	// Clean up on disconnect
+	// Clean up on disconnect; mid-game the room holds the seat for a while
+	// so the player can resume with their token
 	if currentRoom != nil && currentPlayer != nil {
-		currentRoom.Leave <- currentPlayer.ID
+		currentRoom.Disconnect <- game.DisconnectPayload{
+			PlayerID:   currentPlayer.ID,
+			Connection: conn,
+		}
 	}
 }
 
-func (s *Server) handleJoinRoom(ctx context.Context, conn *websocket.Conn, payload interface{}) (*game.GameRoom, *game.Player) {
+func (s *Server) handleJoinRoom(ctx context.Context, conn *websocket.Conn, payload interface{}, sessionCookie string) (*game.GameRoom, *game.Player) {
 	data, _ := json.Marshal(payload)
 	var joinPayload game.JoinRoomPayload
 	json.Unmarshal(data, &joinPayload)
@@ -206,41 +739,57 @@ func (s *Server) handleJoinRoom(ctx context.Context, conn *websocket.Conn, paylo
 	if err != nil {
 		log.Printf("Failed to get room: %v", err)
 		// Send error to client
-		errorMsg := game.Message{
-			Type: game.MsgTypeError,
-			Payload: map[string]interface{}{
-				"message": err.Error(),
-			},
-		}
-		if sendErr := wsjson.Write(ctx, conn, errorMsg); sendErr != nil {
-			log.Printf("Failed to send error message: %v", sendErr)
-		}
+		sendError(ctx, conn, err.Error())
 		return nil, nil
 	}
 
-	// Create player - fetch real player data from Spotify
-	spotifyClient := s.spotifyAuth.NewClient(ctx, &oauth2.Token{
-		AccessToken: joinPayload.AccessToken,
-	})
-	
-	authPlayer, err := auth.FetchPlayerInfo(ctx, spotifyClient)
+	// Reclaim an existing seat if the client presents a resume token
+	if joinPayload.ResumeToken != "" {
+		if player := s.resumeSession(room, conn, joinPayload.ResumeToken, joinPayload.LastSeq); player != nil {
+			return room, player
+		}
+		log.Printf("Resume token rejected for room %s, joining as new player", room.ID)
+	}
+
+	// Other clients send their session token in the join message
+	sessionToken := joinPayload.SessionToken
+	if sessionToken == "" {
+		sessionToken = sessionCookie
+	}
+	session, err := s.sessions.Verify(sessionToken)
 	if err != nil {
-		log.Printf("Failed to fetch player info: %v", err)
+		log.Printf("Join rejected for room %s: %v", room.ID, err)
+		sendError(ctx, conn, "Sign in to join")
 		return nil, nil
 	}
-	
-	tracks, err := auth.FetchPlayerTopTracks(ctx, spotifyClient)
+	settings := room.CurrentSettings()
+
+	authPlayer, err := s.providerPlayer(ctx, session.Subject, auth.PoolOptions{
+		TimeRange:  string(settings.TimeRange),
+		Sources:    settings.Sources,
+		PlaylistID: joinPayload.PlaylistID,
+	})
 	if err != nil {
-		log.Printf("Failed to fetch top tracks: %v", err)
+		log.Printf("Failed to set up player: %v", err)
 		return nil, nil
 	}
-	authPlayer.TopTracks = tracks
-	authPlayer.AccessToken = joinPayload.AccessToken
 
 	player := &game.Player{
-		Player:     authPlayer,
-		Connection: conn,
-		JoinedAt:   time.Now(),
+		Player:       authPlayer,
+		Connection:   conn,
+		JoinedAt:     time.Now(),
+		RoomPassword: joinPayload.Password,
+		Capabilities: joinPayload.Capabilities,
+		QueueIfFull:  joinPayload.Queue,
+	}
+
+	if joinPayload.InviteToken != "" {
+		if err := s.invites.Redeem(room.ID, joinPayload.InviteToken); err != nil {
+			log.Printf("Invite rejected for room %s: %v", room.ID, err)
+			sendError(ctx, conn, "Invite link rejected: "+err.Error())
+			return nil, nil
+		}
+		player.Invited = true
 	}
 
 	// Join the persistent room (no shutdown check needed)
@@ -249,6 +798,76 @@ func (s *Server) handleJoinRoom(ctx context.Context, conn *websocket.Conn, paylo
 	return room, player
 }
 
+// Ping cadence used to grade connection quality
+const (
+	pingInterval = 10 * time.Second
+	pingTimeout  = 5 * time.Second
+)
+
+// monitorConnection pings the client periodically, recording round-trip times
+// and unanswered pings, and tells the room when the player's grade changes
+func monitorConnection(ctx context.Context, conn *websocket.Conn, room *game.GameRoom, player *game.Player) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		start := time.Now()
+		err := conn.Ping(pingCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if player.RecordPing(time.Since(start), err == nil) {
+			select {
+			case room.QualityChanged <- player.ID:
+			default:
+				// The next change will be reported; never block the ping loop
+			}
+		}
+	}
+}
+
+// sendError writes an error message to a connection that isn't bound to a room
+func sendError(ctx context.Context, conn *websocket.Conn, message string) {
+	errorMsg := game.Message{
+		Type: game.MsgTypeError,
+		Payload: map[string]interface{}{
+			"message": message,
+		},
+	}
+	if err := wsjson.Write(ctx, conn, errorMsg); err != nil {
+		log.Printf("Failed to send error message: %v", err)
+	}
+}
+
+// resumeSession asks the room to re-bind conn to the player holding token
+func (s *Server) resumeSession(room *game.GameRoom, conn *websocket.Conn, token string, lastSeq int64) *game.Player {
+	result := make(chan *game.Player, 1)
+	room.Resume <- game.ResumeRequest{
+		Token:      token,
+		Connection: conn,
+		LastSeq:    lastSeq,
+		Result:     result,
+	}
+
+	select {
+	case player := <-result:
+		return player
+	case <-time.After(5 * time.Second):
+		log.Printf("Timed out resuming session in room %s", room.ID)
+		return nil
+	}
+}
+
 func (s *Server) handlePlayerReady(room *game.GameRoom, player *game.Player, payload interface{}) {
 	if room == nil || player == nil {
 		return
@@ -262,8 +881,8 @@ func (s *Server) handlePlayerReady(room *game.GameRoom, player *game.Player, pay
 	room.Ready <- readyPayload
 }
 
-func (s *Server) handleStartGame(room *game.GameRoom, payload interface{}) {
-	if room == nil {
+func (s *Server) handleStartGame(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
 		return
 	}
 
@@ -271,9 +890,7 @@ func (s *Server) handleStartGame(room *game.GameRoom, payload interface{}) {
 	var startPayload game.StartGamePayload
 	json.Unmarshal(data, &startPayload)
 
-	if startPayload.TotalRounds <= 0 {
-		startPayload.TotalRounds = 10
-	}
+	startPayload.PlayerID = player.ID
 
 	room.StartGame <- startPayload
 }
@@ -290,10 +907,266 @@ func (s *Server) handleSubmitGuess(room *game.GameRoom, player *game.Player, pay
 	room.Guess <- game.Guess{
 		PlayerID:        player.ID,
 		GuessedPlayerID: guessPayload.GuessedPlayerID,
+		GuessedTitle:    guessPayload.GuessedTitle,
+		GuessedTrackID:  guessPayload.GuessedTrackID,
 		Timestamp:       time.Now(),
 	}
 }
 
+func (s *Server) handleUsePowerup(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var powerupPayload game.UsePowerupPayload
+	json.Unmarshal(data, &powerupPayload)
+
+	powerupPayload.PlayerID = player.ID
+	room.Powerup <- powerupPayload
+}
+
+func (s *Server) handleSetPassword(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var passwordPayload game.SetPasswordPayload
+	json.Unmarshal(data, &passwordPayload)
+
+	passwordPayload.PlayerID = player.ID
+	room.SetPassword <- passwordPayload
+}
+
+func (s *Server) handleUpdateSettings(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var settingsPayload game.UpdateSettingsPayload
+	json.Unmarshal(data, &settingsPayload)
+
+	settingsPayload.PlayerID = player.ID
+	room.UpdateSettings <- settingsPayload
+}
+
+func (s *Server) handleVoteEnd(room *game.GameRoom, player *game.Player) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.VoteEnd <- player.ID
+}
+
+func (s *Server) handleResetSession(room *game.GameRoom, player *game.Player) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.ResetSession <- player.ID
+}
+
+func (s *Server) handleQuickRematch(room *game.GameRoom, player *game.Player) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.QuickRematch <- player.ID
+}
+
+func (s *Server) handleRematch(room *game.GameRoom, player *game.Player) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.Rematch <- player.ID
+}
+
+func (s *Server) handleReplayTrack(room *game.GameRoom, player *game.Player) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.ReplayTrack <- player.ID
+}
+
+func (s *Server) handleVoteKick(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var kickPayload game.VoteKickPayload
+	json.Unmarshal(data, &kickPayload)
+
+	kickPayload.PlayerID = player.ID
+	room.VoteKick <- kickPayload
+}
+
+func (s *Server) handleSendEmote(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var emotePayload game.EmotePayload
+	json.Unmarshal(data, &emotePayload)
+
+	emotePayload.PlayerID = player.ID
+	room.Emote <- emotePayload
+}
+
+func (s *Server) handlePredict(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var predictionPayload game.PredictionPayload
+	json.Unmarshal(data, &predictionPayload)
+
+	predictionPayload.PlayerID = player.ID
+	room.Predict <- predictionPayload
+}
+
+func (s *Server) handleExcludeTracks(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var excludePayload game.ExcludeTracksPayload
+	json.Unmarshal(data, &excludePayload)
+
+	excludePayload.PlayerID = player.ID
+	room.Exclude <- excludePayload
+}
+
+func (s *Server) handleChat(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var chatPayload game.ChatPayload
+	json.Unmarshal(data, &chatPayload)
+
+	chatPayload.PlayerID = player.ID
+	room.Chat <- chatPayload
+}
+
+func (s *Server) handleAddBot(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var addBotPayload game.AddBotPayload
+	json.Unmarshal(data, &addBotPayload)
+
+	addBotPayload.PlayerID = player.ID
+	room.AddBot <- addBotPayload
+}
+
+func (s *Server) handleSetHandicap(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var handicapPayload game.SetHandicapPayload
+	json.Unmarshal(data, &handicapPayload)
+
+	handicapPayload.PlayerID = player.ID
+	room.SetHandicap <- handicapPayload
+}
+
+func (s *Server) handlePause(room *game.GameRoom, player *game.Player, paused bool) {
+	if room == nil || player == nil {
+		return
+	}
+
+	room.Pause <- game.PausePayload{
+		PlayerID: player.ID,
+		Paused:   paused,
+	}
+}
+
+// handleDebugReport records a debug client's timings straight on its
+// connection's diagnostics; the room doesn't need to see them
+func (s *Server) handleDebugReport(player *game.Player, payload interface{}) {
+	if player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var reportPayload game.DebugReportPayload
+	json.Unmarshal(data, &reportPayload)
+
+	player.RecordDebugReport(reportPayload.Timings)
+}
+
+// handleSetPlaylist fetches the playlist the leader linked, with their own
+// Spotify session, and hands its tracks to the room
+func (s *Server) handleSetPlaylist(ctx context.Context, conn *websocket.Conn, room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var playlistPayload game.SetPlaylistPayload
+	json.Unmarshal(data, &playlistPayload)
+	playlistPayload.PlayerID = player.ID
+
+	if playlistPayload.PlaylistURL != "" {
+		playlistID, err := auth.ParsePlaylistID(playlistPayload.PlaylistURL)
+		if err != nil {
+			sendError(ctx, conn, err.Error())
+			return
+		}
+
+		spotifyClient := s.spotifyAuth.Client(ctx, player.ID)
+		tracks, err := auth.FetchPlaylist(ctx, spotifyClient, playlistID)
+		if err != nil {
+			log.Printf("Failed to fetch playlist %s for room %s: %v", playlistID, room.ID, err)
+			sendError(ctx, conn, "Couldn't load that playlist")
+			return
+		}
+		playlistPayload.PlaylistID = playlistID
+		playlistPayload.Tracks = tracks
+	}
+
+	room.SetPlaylist <- playlistPayload
+}
+
+func (s *Server) handleVoidRound(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var voidPayload game.VoidRoundPayload
+	json.Unmarshal(data, &voidPayload)
+
+	voidPayload.PlayerID = player.ID
+	room.VoidRound <- voidPayload
+}
+
+func (s *Server) handleMiniGameAnswer(room *game.GameRoom, player *game.Player, payload interface{}) {
+	if room == nil || player == nil {
+		return
+	}
+
+	data, _ := json.Marshal(payload)
+	var answerPayload game.MiniGameAnswerPayload
+	json.Unmarshal(data, &answerPayload)
+
+	answerPayload.PlayerID = player.ID
+	room.MiniGameAnswer <- answerPayload
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeasonsHandler returns the current season's leaderboard and the archived
+// seasons, newest first
+func (s *Server) SeasonsHandler(c *gin.Context) {
+	seasons, err := s.roomManager.Seasons()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"current":  seasons.Current(),
+		"archived": seasons.Archived(),
+	})
+}
+
+// SeasonHandler returns one season's leaderboard by ID, e.g. 2026-09, or
+// "current" for the season in progress
+func (s *Server) SeasonHandler(c *gin.Context) {
+	seasons, err := s.roomManager.Seasons()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Param("id") == "current" {
+		c.JSON(http.StatusOK, seasons.Current())
+		return
+	}
+	season, err := seasons.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, season)
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
@@ -9,6 +12,7 @@ import (
 
 	"roulettify/internal/auth"
 	"roulettify/internal/game"
+	"roulettify/internal/store"
 
 	_ "github.com/joho/godotenv/autoload"
 )
@@ -16,7 +20,15 @@ import (
 type Server struct {
 	port        int
 	spotifyAuth *auth.SpotifyAuthenticator
+	providers   *auth.Providers
 	roomManager *game.RoomManager
+	invites     *auth.InviteSigner
+	sessions    *auth.SessionSigner
+	instanceID  string
+	adminToken  string
+	audit       *auditLog
+	retention   *retention
+	tokenVault  *auth.TokenVault // nil unless TOKEN_VAULT_PATH is set
 }
 
 func NewServer() *http.Server {
@@ -31,11 +43,121 @@ func NewServer() *http.Server {
 
 	// Initialize game room manager with 3 persistent rooms
 	roomManager := game.NewRoomManager()
+	roomManager.SetLimits(game.NewLimits(
+		envInt("MAX_ROOMS"),
+		envInt("MAX_CONCURRENT_GAMES"),
+		envInt("MAX_CONNECTIONS"),
+		envInt("MAX_TRACK_POOL"),
+	))
+	roomManager.SetRegion(os.Getenv("REGION"))
+	roomManager.SetInstance(os.Getenv("INSTANCE_ID"), os.Getenv("PUBLIC_WS_URL"))
+	if dir := os.Getenv("ROOM_STORE_DIR"); dir != "" {
+		if err := roomManager.EnableRoomStore(dir); err != nil {
+			log.Printf("Room ownership disabled: %v", err)
+		}
+	}
+	if dir := os.Getenv("HISTORY_DIR"); dir != "" {
+		if err := roomManager.EnableHistory(dir); err != nil {
+			log.Printf("Game history disabled: %v", err)
+		}
+	}
+	if months := envInt("SEASON_MONTHS"); months > 0 {
+		if seasons, err := roomManager.Seasons(); err == nil {
+			if err := seasons.SetLength(months); err != nil {
+				log.Printf("Ignoring SEASON_MONTHS: %v", err)
+			}
+		}
+	}
+	var results *store.Writer
+	var database store.Database
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		db, err := store.Open(ctx, dsn)
+		cancel()
+		if err != nil {
+			log.Printf("Result storage disabled: %v", err)
+		} else {
+			database = db
+			results = store.NewWriter(db)
+			roomManager.SetResultSink(results)
+		}
+	}
+	var redisRooms *store.RedisRooms
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		rooms, err := store.OpenRedis(ctx, redisURL)
+		if err != nil {
+			log.Printf("Room state mirroring disabled: %v", err)
+		} else {
+			redisRooms = rooms
+			if states, err := rooms.LoadRoomStates(ctx); err != nil {
+				log.Printf("Failed to load mirrored rooms: %v", err)
+			} else if restored := roomManager.RestoreRooms(states); restored > 0 {
+				log.Printf("Restored %d running games from redis", restored)
+			}
+			roomManager.SetRoomStateSink(rooms)
+		}
+		cancel()
+	}
+	if dir := os.Getenv("CHECKPOINT_DIR"); dir != "" {
+		if err := roomManager.EnableCheckpoints(dir); err != nil {
+			log.Printf("Endless checkpoints disabled: %v", err)
+		}
+	}
+
+	if os.Getenv("STRICT_SESSIONS") == "true" {
+		roomManager.Presence().SetStrict(true)
+	}
+
+	if os.Getenv("VERIFY_PREVIEWS") == "true" {
+		auth.EnablePreviewVerification()
+	}
+
+	if path := os.Getenv("BOT_PROFILES_PATH"); path != "" {
+		if err := game.LoadBotProfiles(path); err != nil {
+			log.Printf("Using default bot profiles: %v", err)
+		}
+	}
+
+	audit, err := newAuditLog(os.Getenv("AUDIT_LOG_PATH"))
+	if err != nil {
+		log.Printf("Audit log falling back to server log: %v", err)
+		audit, _ = newAuditLog("")
+	}
 
 	NewServer := &Server{
 		port:        port,
 		spotifyAuth: spotifyAuth,
+		providers: auth.NewProviders(spotifyAuth,
+			auth.NewLastFMAuthenticator(
+				os.Getenv("LASTFM_API_KEY"),
+				os.Getenv("LASTFM_SHARED_SECRET"),
+				os.Getenv("LASTFM_CALLBACK_URL"),
+				spotifyAuth,
+			),
+			auth.NewDeezerAuthenticator(
+				os.Getenv("DEEZER_APP_ID"),
+				os.Getenv("DEEZER_SECRET"),
+				os.Getenv("DEEZER_REDIRECT_URI"),
+			),
+		),
 		roomManager: roomManager,
+		invites:     auth.NewInviteSigner(os.Getenv("INVITE_SECRET")),
+		sessions:    auth.NewSessionSigner(os.Getenv("SESSION_SECRET")),
+		instanceID:  os.Getenv("INSTANCE_ID"),
+		adminToken:  os.Getenv("ADMIN_TOKEN"),
+		audit:       audit,
+		retention:   newRetention(),
+	}
+	NewServer.startRetention()
+	if path := os.Getenv("TOKEN_VAULT_PATH"); path != "" {
+		vault, err := openTokenVault(path, os.Getenv("TOKEN_VAULT_KEYS"))
+		if err != nil {
+			log.Printf("Provider tokens won't survive restarts: %v", err)
+		} else {
+			NewServer.tokenVault = vault
+			NewServer.providers.UseVault(vault)
+		}
 	}
 
 	// Declare Server config
@@ -46,6 +168,34 @@ func NewServer() *http.Server {
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
+	server.RegisterOnShutdown(func() {
+		roomManager.Shutdown("Server is restarting")
+		if results != nil {
+			results.Close()
+			database.Close()
+		}
+		if redisRooms != nil {
+			redisRooms.Close()
+		}
+	})
 
 	return server
-}
\ No newline at end of file
+}
+// envInt reads an integer environment variable, returning 0 when unset or invalid
+func envInt(key string) int {
+	value, _ := strconv.Atoi(os.Getenv(key))
+	return value
+}
+
+// openTokenVault opens the encrypted provider token file at path. Keys are
+// required: tokens are never written in the clear.
+func openTokenVault(path, keys string) (*auth.TokenVault, error) {
+	if keys == "" {
+		return nil, errors.New("TOKEN_VAULT_KEYS is required to keep tokens on disk")
+	}
+	ring, err := auth.ParseKeyRing(keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOKEN_VAULT_KEYS: %w", err)
+	}
+	return auth.OpenTokenVault(path, ring)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"roulettify/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetWebhookHandler lets the owner of a claimed room register the URL its
+// finished games are posted to. The response holds the secret deliveries
+// are signed with; it isn't shown again.
+func (s *Server) SetWebhookHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to manage the room's webhook")
+	if !ok {
+		return
+	}
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.URL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	webhook, err := room.SetWebhook(user.ID, body.URL)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// WebhookHandler returns a claimed room's webhook URL to its owner
+func (s *Server) WebhookHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to manage the room's webhook")
+	if !ok {
+		return
+	}
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	url, err := room.WebhookURL(user.ID)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// RemoveWebhookHandler stops a claimed room posting its finished games
+func (s *Server) RemoveWebhookHandler(c *gin.Context) {
+	user, ok := s.signedInPlayer(c, "Sign in to manage the room's webhook")
+	if !ok {
+		return
+	}
+	room, err := s.roomManager.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := room.RemoveWebhook(user.ID); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"removed": true})
+}
+
+func webhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, game.ErrNoRoomStore):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, game.ErrNotRoomOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, game.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, game.ErrNoWebhook):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update the room's webhook"})
+	}
+}
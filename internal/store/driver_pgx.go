@@ -0,0 +1,6 @@
+//go:build pgx
+
+package store
+
+// Building with -tags pgx registers the pgx driver for OpenPostgres
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package store
+
+// Building with -tags sqlite registers the pure-Go SQLite driver for
+// OpenSQLite, so no C toolchain is needed to cross-compile for a Raspberry Pi
+import _ "modernc.org/sqlite"
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"roulettify/internal/game"
+)
+
+// Memory keeps game results in memory, for tests and for servers that don't
+// need them to outlive a restart
+type Memory struct {
+	mu     sync.RWMutex
+	games  map[string]game.GameRecord
+	rounds map[string]map[int]game.RoundRecord // by game, then round
+}
+
+// NewMemory creates an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		games:  make(map[string]game.GameRecord),
+		rounds: make(map[string]map[int]game.RoundRecord),
+	}
+}
+
+func (m *Memory) SaveRound(ctx context.Context, round game.RoundRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rounds[round.GameID] == nil {
+		m.rounds[round.GameID] = make(map[int]game.RoundRecord)
+	}
+	// Like the databases, saving again only updates whether it was voided
+	if stored, ok := m.rounds[round.GameID][round.Round]; ok {
+		stored.Voided = round.Voided
+		m.rounds[round.GameID][round.Round] = stored
+		return nil
+	}
+	round.Artists = slices.Clone(round.Artists)
+	round.CorrectGuessers = slices.Clone(round.CorrectGuessers)
+	round.PointsAwarded = maps.Clone(round.PointsAwarded)
+	m.rounds[round.GameID][round.Round] = round
+	return nil
+}
+
+func (m *Memory) SaveGame(ctx context.Context, record game.GameRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Only what the databases keep: the game row and final scores
+	stored := game.GameRecord{
+		GameID:     record.GameID,
+		RoomID:     record.RoomID,
+		FinishedAt: record.FinishedAt,
+		Rounds:     record.Rounds,
+		WinnerID:   record.WinnerID,
+	}
+	if previous, ok := m.games[record.GameID]; ok {
+		stored.RoomID, stored.FinishedAt = previous.RoomID, previous.FinishedAt
+		stored.Players = previous.Players
+	}
+	for _, player := range record.Players {
+		entry := game.RecordPlayer{ID: player.ID, Name: player.Name, Score: player.Score}
+		if i := slices.IndexFunc(stored.Players, func(p game.RecordPlayer) bool { return p.ID == player.ID }); i >= 0 {
+			stored.Players[i].Score = player.Score
+			continue
+		}
+		stored.Players = append(stored.Players, entry)
+	}
+	sort.Slice(stored.Players, func(i, j int) bool {
+		a, b := stored.Players[i], stored.Players[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.ID < b.ID
+	})
+	m.games[record.GameID] = stored
+	return nil
+}
+
+func (m *Memory) Game(ctx context.Context, gameID string) (game.GameRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	record, ok := m.games[gameID]
+	if !ok {
+		return game.GameRecord{}, ErrNotFound
+	}
+	record.Players = slices.Clone(record.Players)
+	return record, nil
+}
+
+func (m *Memory) Rounds(ctx context.Context, gameID string) ([]game.RoundRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rounds := make([]game.RoundRecord, 0, len(m.rounds[gameID]))
+	for _, round := range m.rounds[gameID] {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].Round < rounds[j].Round
+	})
+	return rounds, nil
+}
+
+func (m *Memory) PlayerGames(ctx context.Context, playerID string, limit int) ([]game.GameRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	games := make([]game.GameRecord, 0)
+	for _, record := range m.games {
+		if slices.ContainsFunc(record.Players, func(p game.RecordPlayer) bool { return p.ID == playerID }) {
+			record.Players = slices.Clone(record.Players)
+			games = append(games, record)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].FinishedAt.After(games[j].FinishedAt)
+	})
+	if len(games) > limit {
+		games = games[:limit]
+	}
+	return games, nil
+}
+
+func (m *Memory) AnonymizePlayer(ctx context.Context, playerID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alias := pseudonym()
+	anonymized := 0
+	for gameID, record := range m.games {
+		record.Players = slices.Clone(record.Players)
+		changed := false
+		for i := range record.Players {
+			if record.Players[i].ID == playerID {
+				record.Players[i].ID = alias
+				record.Players[i].Name = game.DeletedPlayerName
+				changed = true
+			}
+		}
+		if record.WinnerID == playerID {
+			record.WinnerID = alias
+		}
+		if changed {
+			anonymized++
+		}
+		m.games[gameID] = record
+	}
+	for _, rounds := range m.rounds {
+		for number, round := range rounds {
+			round.CorrectGuessers = slices.Clone(round.CorrectGuessers)
+			round.PointsAwarded = maps.Clone(round.PointsAwarded)
+			if anonymizeRound(&round, playerID, alias) {
+				rounds[number] = round
+			}
+		}
+	}
+	return anonymized, nil
+}
+
+// Close does nothing; it's there so Memory is a Database
+func (m *Memory) Close() error {
+	return nil
+}
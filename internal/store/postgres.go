@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Driver is the database/sql driver Postgres is opened with. It's
+// registered by building with the pgx tag.
+const Driver = "pgx"
+
+// schema creates the tables on first start. Rounds are written as they
+// complete, before their game's row exists, so they aren't tied to it by a
+// foreign key.
+const schema = `
+CREATE TABLE IF NOT EXISTS games (
+	game_id     TEXT PRIMARY KEY,
+	room_id     TEXT NOT NULL,
+	finished_at TIMESTAMPTZ NOT NULL,
+	rounds      INTEGER NOT NULL,
+	winner_id   TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS game_scores (
+	game_id   TEXT NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	player_id TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	score     INTEGER NOT NULL,
+	PRIMARY KEY (game_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS game_scores_player_id ON game_scores (player_id);
+
+CREATE TABLE IF NOT EXISTS game_rounds (
+	game_id          TEXT NOT NULL,
+	round            INTEGER NOT NULL,
+	room_id          TEXT NOT NULL,
+	completed_at     TIMESTAMPTZ NOT NULL,
+	track_id         TEXT NOT NULL,
+	track_name       TEXT NOT NULL,
+	artists          JSONB NOT NULL,
+	winner_id        TEXT NOT NULL,
+	correct_guessers JSONB NOT NULL,
+	points_awarded   JSONB NOT NULL,
+	voided           BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (game_id, round)
+);
+`
+
+// Postgres stores game results in a Postgres database
+type Postgres struct {
+	sqlStore
+}
+
+// OpenPostgres connects to the database at dsn and creates any missing tables
+func OpenPostgres(ctx context.Context, dsn string) (*Postgres, error) {
+	db, err := sql.Open(Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to reach database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables: %w", err)
+	}
+	return &Postgres{sqlStore{db: db}}, nil
+}
@@ -0,0 +1,361 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// roomStateKey prefixes the Redis key each room's state is kept under
+const roomStateKey = "roulettify:room:"
+
+// redisTimeout bounds each round trip to Redis
+const redisTimeout = 5 * time.Second
+
+// RedisRooms is a game.RoomStateSink that mirrors running games to Redis,
+// so another process can pick them up after a restart. Writes happen in the
+// background and only a room's latest state is written, so a slow Redis
+// never holds up a game.
+type RedisRooms struct {
+	client *redisClient
+
+	mu      sync.Mutex
+	pending map[string]*game.RoomState // nil deletes the room's state
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+// OpenRedis connects to the Redis server at a redis:// or rediss:// URL,
+// such as redis://:password@localhost:6379/0
+func OpenRedis(ctx context.Context, rawURL string) (*RedisRooms, error) {
+	client, err := newRedisClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.do(ctx, "PING"); err != nil {
+		client.close()
+		return nil, fmt.Errorf("failed to reach redis: %w", err)
+	}
+
+	rooms := &RedisRooms{
+		client:  client,
+		pending: make(map[string]*game.RoomState),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go rooms.run()
+	return rooms, nil
+}
+
+// SaveRoomState queues the room's state to be written
+func (r *RedisRooms) SaveRoomState(state game.RoomState) {
+	r.queue(state.RoomID, &state)
+}
+
+// DeleteRoomState queues the room's state to be removed
+func (r *RedisRooms) DeleteRoomState(roomID string) {
+	r.queue(roomID, nil)
+}
+
+func (r *RedisRooms) queue(roomID string, state *game.RoomState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.pending[roomID] = state
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *RedisRooms) run() {
+	defer close(r.done)
+
+	for range r.wake {
+		r.flush()
+	}
+	r.flush()
+}
+
+// flush writes every room's latest queued state
+func (r *RedisRooms) flush() {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]*game.RoomState)
+	r.mu.Unlock()
+
+	for roomID, state := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		err := r.write(ctx, roomID, state)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to mirror room %s to redis: %v", roomID, err)
+		}
+	}
+}
+
+func (r *RedisRooms) write(ctx context.Context, roomID string, state *game.RoomState) error {
+	if state == nil {
+		_, err := r.client.do(ctx, "DEL", roomStateKey+roomID)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return err
+	}
+	ttl := strconv.Itoa(int(game.RoomStateTTL / time.Second))
+	_, err := r.client.do(ctx, "SET", roomStateKey+roomID, buf.String(), "EX", ttl)
+	return err
+}
+
+// LoadRoomStates reads every room state left in Redis, e.g. by a process
+// that was restarted mid-game
+func (r *RedisRooms) LoadRoomStates(ctx context.Context) ([]game.RoomState, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := r.client.do(ctx, "SCAN", cursor, "MATCH", roomStateKey+"*", "COUNT", "100")
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected SCAN reply %v", reply)
+		}
+		cursor, _ = page[0].(string)
+		found, _ := page[1].([]interface{})
+		for _, key := range found {
+			if key, ok := key.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			break
+		}
+	}
+
+	states := make([]game.RoomState, 0, len(keys))
+	for _, key := range keys {
+		reply, err := r.client.do(ctx, "GET", key)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := reply.(string)
+		if !ok {
+			continue // expired since the scan
+		}
+		var state game.RoomState
+		if err := gob.NewDecoder(strings.NewReader(data)).Decode(&state); err != nil {
+			log.Printf("Skipping unreadable room state %s: %v", key, err)
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Close stops taking room states, writes the queued ones and disconnects
+func (r *RedisRooms) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.wake)
+	}
+	r.mu.Unlock()
+
+	<-r.done
+	return r.client.close()
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisClient speaks just enough of the Redis protocol (RESP) for
+// RedisRooms, over one connection that's redialled after a failure
+type redisClient struct {
+	addr     string
+	tls      bool
+	password string
+	username string
+	db       string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRedisClient(rawURL string) (*redisClient, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis URL scheme %q", u.Scheme)
+	}
+
+	client := &redisClient{addr: u.Host, tls: u.Scheme == "rediss"}
+	if u.Port() == "" {
+		client.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		client.username = u.User.Username()
+		client.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" && db != "0" {
+		if _, err := strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+		client.db = db
+	}
+	return client, nil
+}
+
+// do sends a command and reads its reply: a string, int64, nil or
+// []interface{} of those
+func (c *redisClient) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection's in an unknown state; start afresh next time
+		c.conn.Close()
+		c.conn = nil
+	}
+	return reply, err
+}
+
+func (c *redisClient) dial(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	if c.tls {
+		host, _, _ := net.SplitHostPort(c.addr)
+		conn = tls.Client(conn, &tls.Config{ServerName: host})
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	var setup [][]string
+	if c.password != "" {
+		if c.username != "" {
+			setup = append(setup, []string{"AUTH", c.username, c.password})
+		} else {
+			setup = append(setup, []string{"AUTH", c.password})
+		}
+	}
+	if c.db != "" {
+		setup = append(setup, []string{"SELECT", c.db})
+	}
+	for _, args := range setup {
+		if _, err := c.roundTrip(ctx, args); err != nil {
+			conn.Close()
+			c.conn = nil
+			return fmt.Errorf("redis %s failed: %w", args[0], err)
+		}
+	}
+	return nil
+}
+
+func (c *redisClient) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	c.conn.SetDeadline(deadline)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// readReply reads one RESP reply
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
+
+func (c *redisClient) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
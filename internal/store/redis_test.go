@@ -0,0 +1,179 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// fakeRedis serves the handful of commands RedisRooms uses, from memory
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu   sync.Mutex
+	data map[string]string
+	ttls map[string]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeRedis{
+		listener: listener,
+		password: password,
+		data:     make(map[string]string),
+		ttls:     make(map[string]string),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeRedis) url() string {
+	return fmt.Sprintf("redis://:%s@%s/2", s.password, s.listener.Addr())
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := s.password == ""
+
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+		if !authed && args[0] != "AUTH" {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		s.mu.Lock()
+		switch args[0] {
+		case "AUTH":
+			authed = args[1] == s.password
+			if authed {
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "SELECT", "PING":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "SET":
+			s.data[args[1]] = args[2]
+			s.ttls[args[1]] = args[4]
+			fmt.Fprint(conn, "+OK\r\n")
+		case "GET":
+			if value, ok := s.data[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "DEL":
+			delete(s.data, args[1])
+			fmt.Fprint(conn, ":1\r\n")
+		case "SCAN":
+			prefix := strings.TrimSuffix(args[3], "*")
+			var keys []string
+			for key := range s.data {
+				if strings.HasPrefix(key, prefix) {
+					keys = append(keys, key)
+				}
+			}
+			fmt.Fprintf(conn, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+			for _, key := range keys {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(key), key)
+			}
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		s.mu.Unlock()
+	}
+}
+
+// TestRedisRooms verifies room states survive a round trip through Redis
+// and deleted ones are gone
+func TestRedisRooms(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	ctx := context.Background()
+
+	if _, err := OpenRedis(ctx, strings.Replace(server.url(), "secret", "wrong", 1)); err == nil {
+		t.Error("Expected a wrong password to be refused")
+	}
+
+	rooms, err := OpenRedis(ctx, server.url())
+	if err != nil {
+		t.Fatalf("Failed to open redis: %v", err)
+	}
+	state := game.RoomState{
+		RoomID:       "Room 1",
+		GameID:       "game-1",
+		CurrentRound: 3,
+		Scores:       map[string]int{"alice": 250, "bob": 100},
+		Players: []game.RoomStatePlayer{
+			{ID: "alice", Name: "Alice", ResumeToken: "token", Powerups: map[game.PowerupType]int{}},
+			{ID: "bob", Name: "Bob"},
+		},
+		SavedAt: time.Now().UTC(),
+	}
+	rooms.SaveRoomState(state)
+	rooms.SaveRoomState(game.RoomState{RoomID: "Room 2", GameID: "game-2"})
+	rooms.DeleteRoomState("Room 2")
+	if err := rooms.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	rooms.SaveRoomState(game.RoomState{RoomID: "Room 3"}) // ignored once closed
+
+	server.mu.Lock()
+	ttl := server.ttls["roulettify:room:Room 1"]
+	server.mu.Unlock()
+	if ttl != "600" {
+		t.Errorf("Expected states to expire after %v, got %ss", game.RoomStateTTL, ttl)
+	}
+
+	reopened, err := OpenRedis(ctx, server.url())
+	if err != nil {
+		t.Fatalf("Failed to reopen redis: %v", err)
+	}
+	defer reopened.Close()
+	states, err := reopened.LoadRoomStates(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load states: %v", err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("Expected only Room 1's state to be left, got %d", len(states))
+	}
+	got := states[0]
+	if got.GameID != "game-1" || got.CurrentRound != 3 || got.Scores["alice"] != 250 ||
+		len(got.Players) != 2 || got.Players[0].ResumeToken != "token" || !got.SavedAt.Equal(state.SavedAt) {
+		t.Errorf("Expected the state to round-trip, got %+v", got)
+	}
+
+	t.Logf("✓ Room states are mirrored to Redis, expire and are deleted")
+}
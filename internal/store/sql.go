@@ -0,0 +1,314 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// sqliteTimeLayout stores times at a fixed width so they sort as text
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// placeholderPattern matches Postgres' numbered placeholders
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// sqlStore is the GameStore and PlayerStore Postgres and SQLite share.
+// Queries are written for Postgres and adapted for SQLite as they run.
+type sqlStore struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+// query adapts a Postgres query for the database: SQLite numbers its
+// placeholders ?1, ?2... and keeps JSON as plain text
+func (s *sqlStore) query(q string) string {
+	if !s.sqlite {
+		return q
+	}
+	q = strings.ReplaceAll(q, "::jsonb", "")
+	return placeholderPattern.ReplaceAllString(q, "?$1")
+}
+
+// time encodes t for a timestamp column
+func (s *sqlStore) time(t time.Time) any {
+	if s.sqlite {
+		return t.UTC().Format(sqliteTimeLayout)
+	}
+	return t
+}
+
+func (s *sqlStore) SaveRound(ctx context.Context, round game.RoundRecord) error {
+	artists, guessers, points, err := roundJSON(round)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.query(`
+		INSERT INTO game_rounds (game_id, round, room_id, completed_at, track_id, track_name,
+			artists, winner_id, correct_guessers, points_awarded, voided)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9::jsonb, $10::jsonb, $11)
+		ON CONFLICT (game_id, round) DO UPDATE SET voided = EXCLUDED.voided`),
+		round.GameID, round.Round, round.RoomID, s.time(round.CompletedAt), round.TrackID, round.TrackName,
+		artists, round.WinnerID, guessers, points, round.Voided)
+	return err
+}
+
+func (s *sqlStore) SaveGame(ctx context.Context, record game.GameRecord) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, s.query(`
+		INSERT INTO games (game_id, room_id, finished_at, rounds, winner_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (game_id) DO UPDATE SET rounds = EXCLUDED.rounds, winner_id = EXCLUDED.winner_id`),
+		record.GameID, record.RoomID, s.time(record.FinishedAt), record.Rounds, record.WinnerID); err != nil {
+		return err
+	}
+	for _, player := range record.Players {
+		if _, err := tx.ExecContext(ctx, s.query(`
+			INSERT INTO game_scores (game_id, player_id, name, score)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (game_id, player_id) DO UPDATE SET score = EXCLUDED.score`),
+			record.GameID, player.ID, player.Name, player.Score); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) Game(ctx context.Context, gameID string) (game.GameRecord, error) {
+	record := game.GameRecord{GameID: gameID}
+	var finishedAt sqlTime
+	err := s.db.QueryRowContext(ctx, s.query(`
+		SELECT room_id, finished_at, rounds, winner_id FROM games WHERE game_id = $1`), gameID).
+		Scan(&record.RoomID, &finishedAt, &record.Rounds, &record.WinnerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return game.GameRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return game.GameRecord{}, err
+	}
+	record.FinishedAt = finishedAt.Time
+
+	rows, err := s.db.QueryContext(ctx, s.query(`
+		SELECT player_id, name, score FROM game_scores
+		WHERE game_id = $1 ORDER BY score DESC, player_id`), gameID)
+	if err != nil {
+		return game.GameRecord{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var player game.RecordPlayer
+		if err := rows.Scan(&player.ID, &player.Name, &player.Score); err != nil {
+			return game.GameRecord{}, err
+		}
+		record.Players = append(record.Players, player)
+	}
+	return record, rows.Err()
+}
+
+func (s *sqlStore) Rounds(ctx context.Context, gameID string) ([]game.RoundRecord, error) {
+	rows, err := s.db.QueryContext(ctx, s.query(`
+		SELECT round, room_id, completed_at, track_id, track_name, artists, winner_id,
+			correct_guessers, points_awarded, voided
+		FROM game_rounds WHERE game_id = $1 ORDER BY round`), gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rounds := make([]game.RoundRecord, 0)
+	for rows.Next() {
+		round := game.RoundRecord{GameID: gameID}
+		var completedAt sqlTime
+		var artists, guessers, points []byte
+		if err := rows.Scan(&round.Round, &round.RoomID, &completedAt, &round.TrackID, &round.TrackName,
+			&artists, &round.WinnerID, &guessers, &points, &round.Voided); err != nil {
+			return nil, err
+		}
+		round.CompletedAt = completedAt.Time
+		if err := errors.Join(
+			json.Unmarshal(artists, &round.Artists),
+			json.Unmarshal(guessers, &round.CorrectGuessers),
+			json.Unmarshal(points, &round.PointsAwarded),
+		); err != nil {
+			return nil, fmt.Errorf("round %d: %w", round.Round, err)
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, rows.Err()
+}
+
+func (s *sqlStore) PlayerGames(ctx context.Context, playerID string, limit int) ([]game.GameRecord, error) {
+	rows, err := s.db.QueryContext(ctx, s.query(`
+		SELECT games.game_id FROM games
+		JOIN game_scores ON game_scores.game_id = games.game_id
+		WHERE game_scores.player_id = $1
+		ORDER BY games.finished_at DESC LIMIT $2`), playerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	var gameIDs []string
+	for rows.Next() {
+		var gameID string
+		if err := rows.Scan(&gameID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	games := make([]game.GameRecord, 0, len(gameIDs))
+	for _, gameID := range gameIDs {
+		record, err := s.Game(ctx, gameID)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, record)
+	}
+	return games, nil
+}
+
+func (s *sqlStore) AnonymizePlayer(ctx context.Context, playerID string) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	alias := pseudonym()
+	// Rounds first, while their games can still be found by the player's ID.
+	// JSON is matched as text, which works the same in both databases, and
+	// checked properly once decoded.
+	quoted := "%" + `"` + playerID + `"` + "%"
+	rows, err := tx.QueryContext(ctx, s.query(`
+		SELECT game_id, round, winner_id, correct_guessers, points_awarded FROM game_rounds
+		WHERE winner_id = $1
+			OR game_id IN (SELECT game_id FROM game_scores WHERE player_id = $1)
+			OR CAST(correct_guessers AS TEXT) LIKE $2
+			OR CAST(points_awarded AS TEXT) LIKE $2`), playerID, quoted)
+	if err != nil {
+		return 0, err
+	}
+	var rounds []game.RoundRecord
+	for rows.Next() {
+		var round game.RoundRecord
+		var guessers, points []byte
+		if err := rows.Scan(&round.GameID, &round.Round, &round.WinnerID, &guessers, &points); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if err := errors.Join(
+			json.Unmarshal(guessers, &round.CorrectGuessers),
+			json.Unmarshal(points, &round.PointsAwarded),
+		); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("round %d: %w", round.Round, err)
+		}
+		if anonymizeRound(&round, playerID, alias) {
+			rounds = append(rounds, round)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, round := range rounds {
+		_, guessers, points, err := roundJSON(round)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, s.query(`
+			UPDATE game_rounds SET winner_id = $3, correct_guessers = $4::jsonb, points_awarded = $5::jsonb
+			WHERE game_id = $1 AND round = $2`),
+			round.GameID, round.Round, round.WinnerID, guessers, points); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, s.query(`
+		UPDATE games SET winner_id = $2 WHERE winner_id = $1`), playerID, alias); err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, s.query(`
+		UPDATE game_scores SET player_id = $2, name = $3 WHERE player_id = $1`),
+		playerID, alias, game.DeletedPlayerName)
+	if err != nil {
+		return 0, err
+	}
+	anonymized, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(anonymized), nil
+}
+
+// Close closes the database connection
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// sqlTime scans a timestamp column: a time from Postgres, text from SQLite
+type sqlTime struct {
+	time.Time
+}
+
+func (t *sqlTime) Scan(value any) error {
+	switch v := value.(type) {
+	case time.Time:
+		t.Time = v
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	}
+	return fmt.Errorf("can't read %T as a time", value)
+}
+
+func (t *sqlTime) parse(value string) (err error) {
+	t.Time, err = time.Parse(time.RFC3339Nano, value)
+	return err
+}
+
+// roundJSON encodes a round's lists and points for their JSON columns
+func roundJSON(round game.RoundRecord) (artists, guessers, points string, err error) {
+	artistsJSON, err := json.Marshal(nonNil(round.Artists))
+	if err != nil {
+		return "", "", "", err
+	}
+	guessersJSON, err := json.Marshal(nonNil(round.CorrectGuessers))
+	if err != nil {
+		return "", "", "", err
+	}
+	pointsJSON, err := json.Marshal(round.PointsAwarded)
+	if err != nil {
+		return "", "", "", err
+	}
+	return string(artistsJSON), string(guessersJSON), string(pointsJSON), nil
+}
+
+// nonNil keeps empty lists as [] rather than null in JSON columns
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
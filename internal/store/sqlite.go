@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SQLiteDriver is the database/sql driver SQLite is opened with. It's
+// registered by building with the sqlite tag.
+const SQLiteDriver = "sqlite"
+
+// sqliteSchema mirrors the Postgres tables. SQLite has no JSONB or
+// timestamp types, so lists and maps are JSON text and times RFC 3339 text.
+const sqliteSchema = `
+PRAGMA journal_mode = WAL;
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS games (
+	game_id     TEXT PRIMARY KEY,
+	room_id     TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	rounds      INTEGER NOT NULL,
+	winner_id   TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS game_scores (
+	game_id   TEXT NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	player_id TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	score     INTEGER NOT NULL,
+	PRIMARY KEY (game_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS game_scores_player_id ON game_scores (player_id);
+
+CREATE TABLE IF NOT EXISTS game_rounds (
+	game_id          TEXT NOT NULL,
+	round            INTEGER NOT NULL,
+	room_id          TEXT NOT NULL,
+	completed_at     TEXT NOT NULL,
+	track_id         TEXT NOT NULL,
+	track_name       TEXT NOT NULL,
+	artists          TEXT NOT NULL,
+	winner_id        TEXT NOT NULL,
+	correct_guessers TEXT NOT NULL,
+	points_awarded   TEXT NOT NULL,
+	voided           INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (game_id, round)
+);
+`
+
+// SQLite stores game results in a SQLite file, for self-hosting without a
+// database server
+type SQLite struct {
+	sqlStore
+}
+
+// OpenSQLite opens the database file at path, creating it and any missing
+// tables
+func OpenSQLite(ctx context.Context, path string) (*SQLite, error) {
+	db, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// One writer at a time is all SQLite allows, and all the Writer needs
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables: %w", err)
+	}
+	return &SQLite{sqlStore{db: db, sqlite: true}}, nil
+}
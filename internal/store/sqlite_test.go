@@ -0,0 +1,22 @@
+//go:build sqlite
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// TestSQLiteStore verifies a SQLite file behaves like every other store
+func TestSQLiteStore(t *testing.T) {
+	db, err := Open(context.Background(), "sqlite:"+filepath.Join(t.TempDir(), "roulettify.db"))
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	defer db.Close()
+
+	testDatabase(t, db)
+
+	t.Logf("✓ SQLite stores, updates and reads back games and rounds")
+}
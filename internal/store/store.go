@@ -0,0 +1,85 @@
+// Package store keeps finished games, their rounds and final scores behind
+// GameStore and PlayerStore, in memory, SQLite or Postgres, so nothing else
+// talks to a database driver directly.
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"roulettify/internal/game"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned for a game that isn't stored
+var ErrNotFound = errors.New("game not found")
+
+// pseudonym names a deleted player in stored results. There's one per
+// deletion, so opponents' head-to-head records still add up.
+func pseudonym() string {
+	return "deleted-" + uuid.New().String()[:8]
+}
+
+// anonymizeRound replaces playerID with alias in a round, reporting whether
+// the player was in it
+func anonymizeRound(round *game.RoundRecord, playerID, alias string) bool {
+	changed := false
+	if round.WinnerID == playerID {
+		round.WinnerID = alias
+		changed = true
+	}
+	for i, guesser := range round.CorrectGuessers {
+		if guesser == playerID {
+			round.CorrectGuessers[i] = alias
+			changed = true
+		}
+	}
+	if points, ok := round.PointsAwarded[playerID]; ok {
+		delete(round.PointsAwarded, playerID)
+		round.PointsAwarded[alias] = points
+		changed = true
+	}
+	return changed
+}
+
+// GameStore saves finished games and their rounds, and reads them back
+type GameStore interface {
+	// SaveRound stores a completed round, replacing it if it was stored
+	// before (e.g. when it's been voided since)
+	SaveRound(ctx context.Context, round game.RoundRecord) error
+	// SaveGame stores a finished game and its final scores, replacing them
+	// if the game was stored before (e.g. when a round was voided afterwards)
+	SaveGame(ctx context.Context, record game.GameRecord) error
+	// Game returns a stored game with its players by score, best first
+	Game(ctx context.Context, gameID string) (game.GameRecord, error)
+	// Rounds returns a game's stored rounds in order
+	Rounds(ctx context.Context, gameID string) ([]game.RoundRecord, error)
+}
+
+// PlayerStore reads a player's results across stored games
+type PlayerStore interface {
+	// PlayerGames returns up to limit of the player's games, newest first
+	PlayerGames(ctx context.Context, playerID string, limit int) ([]game.GameRecord, error)
+	// AnonymizePlayer replaces the player's ID in every stored game and
+	// round with a pseudonym and their name with game.DeletedPlayerName,
+	// and reports how many games they were in
+	AnonymizePlayer(ctx context.Context, playerID string) (int, error)
+}
+
+// Database is a GameStore and PlayerStore backed by a database connection
+type Database interface {
+	GameStore
+	PlayerStore
+	Close() error
+}
+
+// Open connects to the database at url: a SQLite file for a "sqlite:" URL
+// such as sqlite:./roulettify.db, Postgres for anything else
+func Open(ctx context.Context, url string) (Database, error) {
+	if path, ok := strings.CutPrefix(url, "sqlite:"); ok {
+		return OpenSQLite(ctx, path)
+	}
+	return OpenPostgres(ctx, url)
+}
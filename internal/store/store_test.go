@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+// testDatabase runs the behaviour every Database shares against db
+func testDatabase(t *testing.T, db Database) {
+	t.Helper()
+	ctx := context.Background()
+	start := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+
+	save := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+	}
+	save(db.SaveRound(ctx, game.RoundRecord{GameID: "g1", Round: 2, RoomID: "Room 1", CompletedAt: start, TrackID: "t2", WinnerID: "B"}))
+	save(db.SaveRound(ctx, game.RoundRecord{GameID: "g1", Round: 1, RoomID: "Room 1", CompletedAt: start, TrackID: "t1", WinnerID: "A",
+		Artists: []string{"Artist"}, CorrectGuessers: []string{"B"}, PointsAwarded: map[string]int{"B": 15}}))
+	save(db.SaveGame(ctx, game.GameRecord{GameID: "g1", RoomID: "Room 1", FinishedAt: start, Rounds: 2, WinnerID: "B",
+		Players: []game.RecordPlayer{{ID: "A", Name: "Ay", Score: 0}, {ID: "B", Name: "Bee", Score: 15}}}))
+	save(db.SaveGame(ctx, game.GameRecord{GameID: "g2", RoomID: "Room 2", FinishedAt: start.Add(time.Hour), Rounds: 1, WinnerID: "A",
+		Players: []game.RecordPlayer{{ID: "A", Name: "Ay", Score: 20}, {ID: "C", Name: "Cee", Score: 5}}}))
+
+	// Voiding round 1 afterwards rewrites it and the final scores
+	save(db.SaveRound(ctx, game.RoundRecord{GameID: "g1", Round: 1, RoomID: "Room 1", CompletedAt: start, TrackID: "t1", WinnerID: "A", Voided: true}))
+	save(db.SaveGame(ctx, game.GameRecord{GameID: "g1", RoomID: "Room 1", FinishedAt: start, Rounds: 2,
+		Players: []game.RecordPlayer{{ID: "A", Name: "Ay", Score: 0}, {ID: "B", Name: "Bee", Score: 0}}}))
+
+	record, err := db.Game(ctx, "g1")
+	if err != nil || record.WinnerID != "" || !record.FinishedAt.Equal(start) || len(record.Players) != 2 || record.Players[1].Score != 0 {
+		t.Errorf("Expected g1 with corrected scores, got %+v (%v)", record, err)
+	}
+	if _, err := db.Game(ctx, "missing"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for a missing game, got %v", err)
+	}
+
+	rounds, err := db.Rounds(ctx, "g1")
+	if err != nil || len(rounds) != 2 || rounds[0].Round != 1 || rounds[1].Round != 2 {
+		t.Fatalf("Expected g1's 2 rounds in order, got %+v (%v)", rounds, err)
+	}
+	if first := rounds[0]; !first.Voided || first.PointsAwarded["B"] != 15 || first.CorrectGuessers[0] != "B" || first.Artists[0] != "Artist" {
+		t.Errorf("Expected round 1 voided with its original result kept, got %+v", first)
+	}
+
+	games, err := db.PlayerGames(ctx, "A", 10)
+	if err != nil || len(games) != 2 || games[0].GameID != "g2" || games[0].Players[0].ID != "A" {
+		t.Errorf("Expected A's 2 games newest first, best score first, got %+v (%v)", games, err)
+	}
+	if games, _ := db.PlayerGames(ctx, "A", 1); len(games) != 1 {
+		t.Errorf("Expected the limit to apply, got %d games", len(games))
+	}
+
+	// Deleting B's data leaves their results under a pseudonym
+	anonymized, err := db.AnonymizePlayer(ctx, "B")
+	if err != nil || anonymized != 1 {
+		t.Fatalf("Expected B anonymized in 1 game, got %d (%v)", anonymized, err)
+	}
+	if games, _ := db.PlayerGames(ctx, "B", 10); len(games) != 0 {
+		t.Errorf("Expected no games left under B's ID, got %+v", games)
+	}
+	record, _ = db.Game(ctx, "g1")
+	var alias string
+	for _, player := range record.Players {
+		if player.ID == "B" || player.Name == "Bee" {
+			t.Errorf("Expected B's ID and name gone from g1, got %+v", record.Players)
+		}
+		if player.Name == game.DeletedPlayerName {
+			alias = player.ID
+		}
+	}
+	rounds, _ = db.Rounds(ctx, "g1")
+	first, second := rounds[0], rounds[1]
+	if alias == "" || second.WinnerID != alias || first.CorrectGuessers[0] != alias || first.PointsAwarded[alias] != 15 || first.PointsAwarded["B"] != 0 {
+		t.Errorf("Expected B replaced by %q in every round, got %+v and %+v", alias, first, second)
+	}
+	if games, _ := db.PlayerGames(ctx, "A", 10); len(games) != 2 {
+		t.Errorf("Expected A's games untouched, got %+v", games)
+	}
+}
+
+// TestMemoryStore verifies the in-memory store behaves like the databases
+func TestMemoryStore(t *testing.T) {
+	testDatabase(t, NewMemory())
+
+	t.Logf("✓ Memory stores, updates and reads back games and rounds")
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"roulettify/internal/game"
+)
+
+const (
+	// QueueSize is how many results can wait to be written before new ones
+	// are dropped, so a slow database never holds up a game
+	QueueSize = 1000
+	// writeAttempts is how many times a result is tried before it's dropped
+	writeAttempts = 3
+	// writeTimeout bounds each attempt
+	writeTimeout = 10 * time.Second
+)
+
+// Writer is a game.ResultSink that saves results to a GameStore in the
+// background, in the order they arrived
+type Writer struct {
+	store   GameStore
+	queue   chan func(context.Context) error
+	done    chan struct{}
+	backoff time.Duration // between attempts, doubling each time
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWriter starts a writer saving to store
+func NewWriter(store GameStore) *Writer {
+	w := &Writer{
+		store:   store,
+		queue:   make(chan func(context.Context) error, QueueSize),
+		done:    make(chan struct{}),
+		backoff: time.Second,
+	}
+	go w.run()
+	return w
+}
+
+// RecordRound queues a completed round to be saved
+func (w *Writer) RecordRound(round game.RoundRecord) {
+	w.enqueue("round", func(ctx context.Context) error {
+		return w.store.SaveRound(ctx, round)
+	})
+}
+
+// RecordGame queues a finished game to be saved
+func (w *Writer) RecordGame(record game.GameRecord) {
+	w.enqueue("game", func(ctx context.Context) error {
+		return w.store.SaveGame(ctx, record)
+	})
+}
+
+// exportLimit bounds how many of a player's stored games an export holds
+const exportLimit = 10000
+
+// PlayerGames returns the player's stored games, newest first. It reads
+// what has been written so far, so results still queued may be missing.
+func (w *Writer) PlayerGames(playerID string) ([]game.GameRecord, error) {
+	players, ok := w.store.(PlayerStore)
+	if !ok {
+		return nil, errors.New("result storage can't look up players")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	return players.PlayerGames(ctx, playerID, exportLimit)
+}
+
+// AnonymizePlayer anonymizes the player in every stored game and round once
+// the results queued before it are written, so none of them brings the
+// player's ID back, and reports how many games they were in
+func (w *Writer) AnonymizePlayer(playerID string) (int, error) {
+	players, ok := w.store.(PlayerStore)
+	if !ok {
+		return 0, errors.New("result storage can't look up players")
+	}
+
+	type outcome struct {
+		anonymized int
+		err        error
+	}
+	done := make(chan outcome, 1)
+	attempts := 0
+	anonymize := func(ctx context.Context) error {
+		anonymized, err := players.AnonymizePlayer(ctx, playerID)
+		attempts++
+		if err == nil || attempts == writeAttempts {
+			done <- outcome{anonymized, err}
+		}
+		return err
+	}
+
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return 0, errors.New("result storage is closed")
+	}
+	// Unlike a result, this waits for room in the queue rather than being dropped
+	w.queue <- anonymize
+	w.mu.RUnlock()
+
+	result := <-done
+	return result.anonymized, result.err
+}
+
+func (w *Writer) enqueue(kind string, write func(context.Context) error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- write:
+	default:
+		log.Printf("Result storage is behind, dropping a %s result", kind)
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	for write := range w.queue {
+		backoff := w.backoff
+		for attempt := 1; ; attempt++ {
+			ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+			err := write(ctx)
+			cancel()
+			if err == nil {
+				break
+			}
+			if attempt == writeAttempts {
+				log.Printf("Failed to store result, giving up: %v", err)
+				break
+			}
+			log.Printf("Failed to store result, retrying: %v", err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+// Close stops taking results and waits for the queued ones to be written
+func (w *Writer) Close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+}
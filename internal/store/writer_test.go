@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"roulettify/internal/game"
+)
+
+// fakeStore records what it saves and fails the first few writes
+type fakeStore struct {
+	*Memory
+	mu       sync.Mutex
+	saved    []string
+	failures int
+}
+
+func (s *fakeStore) save(what string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("database unavailable")
+	}
+	s.saved = append(s.saved, what)
+	return nil
+}
+
+func (s *fakeStore) SaveRound(ctx context.Context, round game.RoundRecord) error {
+	return s.save("round " + round.TrackID)
+}
+
+func (s *fakeStore) SaveGame(ctx context.Context, record game.GameRecord) error {
+	return s.save("game " + record.GameID)
+}
+
+// TestWriterSavesInOrder verifies results are saved in the order they
+// arrived, retried when the store fails, and drained on close
+func TestWriterSavesInOrder(t *testing.T) {
+	store := &fakeStore{Memory: NewMemory(), failures: 2}
+	w := NewWriter(store)
+	w.backoff = 0
+
+	w.RecordRound(game.RoundRecord{TrackID: "t1"})
+	w.RecordRound(game.RoundRecord{TrackID: "t2"})
+	w.RecordGame(game.GameRecord{GameID: "g1"})
+	w.Close()
+	w.RecordRound(game.RoundRecord{TrackID: "late"})
+
+	want := []string{"round t1", "round t2", "game g1"}
+	if len(store.saved) != len(want) {
+		t.Fatalf("Expected %v saved, got %v", want, store.saved)
+	}
+	for i := range want {
+		if store.saved[i] != want[i] {
+			t.Errorf("Expected %v saved, got %v", want, store.saved)
+			break
+		}
+	}
+
+	t.Logf("✓ Results are saved in order, retried and drained on close")
+}
+
+// TestWriterAnonymizesAfterQueuedResults verifies anonymizing a player waits
+// for the results queued before it, so none of them brings the player back
+func TestWriterAnonymizesAfterQueuedResults(t *testing.T) {
+	store := NewMemory()
+	w := NewWriter(store)
+	defer w.Close()
+
+	w.RecordGame(game.GameRecord{GameID: "g1", Players: []game.RecordPlayer{{ID: "A", Name: "Ay", Score: 10}}})
+	anonymized, err := w.AnonymizePlayer("A")
+	if err != nil || anonymized != 1 {
+		t.Fatalf("Expected A anonymized in 1 game, got %d (%v)", anonymized, err)
+	}
+	if games, err := w.PlayerGames("A"); err != nil || len(games) != 0 {
+		t.Errorf("Expected nothing left under A's ID, got %+v (%v)", games, err)
+	}
+
+	t.Logf("✓ Anonymizing a player runs after their queued results are written")
+}